@@ -79,3 +79,34 @@ func NewKeyedTransactor(key *ecdsa.PrivateKey) *TransactOpts {
 		},
 	}
 }
+
+// NewKeyedProviderSigner is a utility method to easily create a provider signer
+// from a single private key.
+func NewKeyedProviderSigner(key *ecdsa.PrivateKey) ProviderSignerFn {
+	keyAddr := crypto.PubkeyToAddress(key.PublicKey)
+	return func(signer types.Signer, providers []common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		if providers != nil && !keyAddr.InList(providers) {
+			return nil, errors.New("not a provider of the contract")
+		}
+		return types.ProviderSignTx(tx, signer, key)
+	}
+}
+
+// NewKeyStoreProviderSigner is a utility method to easily create a provider signer
+// from an unlocked account of a keystore.
+func NewKeyStoreProviderSigner(keystore *keystore.KeyStore, account accounts.Account) ProviderSignerFn {
+	return func(signer types.Signer, providers []common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		if providers != nil && !account.Address.InList(providers) {
+			return nil, errors.New("not a provider of the contract")
+		}
+		hash, err := signer.HashWithSender(tx)
+		if err != nil {
+			return nil, err
+		}
+		signature, err := keystore.SignHash(account, hash.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		return tx.WithProviderSignature(signer, signature)
+	}
+}
@@ -39,6 +39,10 @@ var (
 	// This error is returned by WaitDeployed if contract creation leaves an
 	// empty contract behind.
 	ErrNoCodeAfterDeploy = errors.New("no contract code after deployment")
+
+	// ErrNoProviderSigner is returned by transact operations to an enterprise contract
+	// if no provider signer is given to get the transaction signed by a provider.
+	ErrNoProviderSigner = errors.New("no provider signer to authorize the transaction to enterprise contract with")
 )
 
 // ContractCaller defines the methods needed to allow operating with contract on a read
@@ -84,6 +88,15 @@ type ContractTransactor interface {
 	SendTransaction(ctx context.Context, tx *types.Transaction) error
 }
 
+// EnterpriseContractTransactor defines the methods to find the providers of an enterprise
+// contract. Transact will try to discover this interface to get the transactions to an
+// enterprise contract signed by one of its providers.
+type EnterpriseContractTransactor interface {
+	// PendingProvidersAt returns the providers of the given account in the pending state,
+	// none if the account is not an enterprise contract.
+	PendingProvidersAt(ctx context.Context, account common.Address) ([]common.Address, error)
+}
+
 // ContractFilterer defines the methods needed to access log events using one-off
 // queries or continuous event subscriptions.
 type ContractFilterer interface {
@@ -46,6 +46,9 @@ import (
 // This nil assignment ensures compile time that SimulatedBackend implements bind.ContractBackend.
 var _ bind.ContractBackend = (*SimulatedBackend)(nil)
 
+// This nil assignment ensures compile time that SimulatedBackend implements bind.EnterpriseContractTransactor.
+var _ bind.EnterpriseContractTransactor = (*SimulatedBackend)(nil)
+
 var errBlockNumberUnsupported = errors.New("SimulatedBackend cannot access blocks other than the latest block")
 var errGasEstimationFailed = errors.New("gas required exceeds allowance or always failing transaction")
 
@@ -212,6 +215,17 @@ func (b *SimulatedBackend) PendingCodeAt(ctx context.Context, contract common.Ad
 	return b.pendingState.GetCode(contract), nil
 }
 
+// PendingProvidersAt returns the providers of an enterprise contract in the pending state.
+func (b *SimulatedBackend) PendingProvidersAt(ctx context.Context, contract common.Address) ([]common.Address, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.pendingState.GetOwner(contract) == nil {
+		return nil, nil
+	}
+	return b.pendingState.GetProviders(contract), nil
+}
+
 // CallContract executes a contract call.
 func (b *SimulatedBackend) CallContract(ctx context.Context, call evrynetNode.CallMsg, blockNumber *big.Int) ([]byte, error) {
 	b.mu.Lock()
@@ -316,11 +330,14 @@ func (b *SimulatedBackend) callContract(ctx context.Context, call evrynetNode.Ca
 	if call.Value == nil {
 		call.Value = new(big.Int)
 	}
-	// Set infinite balance to the fake caller account.
+	// Set infinite balance to the fake caller account and to the provider paying for it.
 	from := statedb.GetOrNewStateObject(call.From)
 	from.SetBalance(math.MaxBig256)
-	// Execute the call.
 	msg := callmsg{call}
+	if msg.HasProviderSignature() {
+		statedb.GetOrNewStateObject(msg.GasPayer()).SetBalance(math.MaxBig256)
+	}
+	// Execute the call.
 
 	evmContext := core.NewEVMContext(msg, block.Header(), b.blockchain, nil)
 	// Create a new environment which holds all relevant information
@@ -332,19 +349,23 @@ func (b *SimulatedBackend) callContract(ctx context.Context, call evrynetNode.Ca
 }
 
 // SendTransaction updates the pending block to include the given transaction.
-// It panics if the transaction is invalid.
+// It panics if the transaction is invalid and returns an error if the transaction
+// is not allowed by the owners and providers rules of enterprise contracts.
 func (b *SimulatedBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	sender, err := types.Sender(types.BaseSigner{}, tx)
+	msg, err := tx.AsMessage(types.BaseSigner{})
 	if err != nil {
 		panic(fmt.Errorf("invalid transaction: %v", err))
 	}
-	nonce := b.pendingState.GetNonce(sender)
+	nonce := b.pendingState.GetNonce(msg.From())
 	if tx.Nonce() != nonce {
 		panic(fmt.Errorf("invalid transaction nonce: got %d, want %d", tx.Nonce(), nonce))
 	}
+	if err := core.ValidateEnterpriseTx(b.pendingState, msg, b.pendingBlock.Number()); err != nil {
+		return err
+	}
 
 	blocks, _ := core.GenerateChain(b.config, b.blockchain.CurrentBlock(), ethash.NewFaker(), b.database, 1, func(number int, block *core.BlockGen) {
 		for _, tx := range b.pendingBlock.Transactions() {
@@ -470,9 +491,14 @@ type callmsg struct {
 	evrynetNode.CallMsg
 }
 
-func (m callmsg) GasPayer() common.Address      { return m.CallMsg.From }
-func (m callmsg) Owner() *common.Address        { return nil }
-func (m callmsg) Provider() *common.Address     { return nil }
+func (m callmsg) GasPayer() common.Address {
+	if m.CallMsg.GasPayer != nil {
+		return *m.CallMsg.GasPayer
+	}
+	return m.CallMsg.From
+}
+func (m callmsg) Owner() *common.Address        { return m.CallMsg.Owner }
+func (m callmsg) Provider() *common.Address     { return m.CallMsg.Provider }
 func (m callmsg) From() common.Address          { return m.CallMsg.From }
 func (m callmsg) Nonce() uint64                 { return 0 }
 func (m callmsg) CheckNonce() bool              { return false }
@@ -483,7 +509,9 @@ func (m callmsg) Value() *big.Int               { return m.CallMsg.Value }
 func (m callmsg) Data() []byte                  { return m.CallMsg.Data }
 func (m callmsg) TxType() types.TransactionType { return types.NormalTxType }
 func (m callmsg) ExtraData() interface{}        { return nil }
-func (m callmsg) HasProviderSignature() bool    { return false }
+func (m callmsg) HasProviderSignature() bool    { return m.CallMsg.GasPayer != nil }
+func (m callmsg) ProviderValidUntil() uint64    { return 0 }
+func (m callmsg) ProviderMaxFee() *big.Int      { return nil }
 
 // filterBackend implements filters.Backend to support filtering for logs without
 // taking bloom-bits acceleration structures into account.
@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	evrynetNode "github.com/Evrynetlabs/evrynet-node"
+	"github.com/Evrynetlabs/evrynet-node/accounts/abi"
 	"github.com/Evrynetlabs/evrynet-node/accounts/abi/bind"
 	"github.com/Evrynetlabs/evrynet-node/accounts/abi/bind/backends"
 	"github.com/Evrynetlabs/evrynet-node/common"
@@ -80,3 +81,64 @@ func TestSimulatedBackend(t *testing.T) {
 	}
 
 }
+
+func TestSimulatedBackend_EnterpriseContract(t *testing.T) {
+	var (
+		ownerKey, _    = crypto.GenerateKey()
+		providerKey, _ = crypto.GenerateKey()
+		senderKey, _   = crypto.GenerateKey()
+		owner          = bind.NewKeyedTransactor(ownerKey)
+		provider       = crypto.PubkeyToAddress(providerKey.PublicKey)
+		sender         = bind.NewKeyedTransactor(senderKey)
+		code           = common.FromHex(`6060604052600a8060106000396000f360606040526008565b00`)
+		funds          = big.NewInt(9223372036854775807)
+	)
+	sim := backends.NewSimulatedBackend(core.GenesisAlloc{
+		owner.From: {Balance: funds},
+		provider:   {Balance: funds},
+	}, 8000029)
+
+	owner.Enterprise = &types.CreateAccountOption{OwnerAddress: &owner.From, ProviderAddress: &provider}
+	address, _, _, err := bind.DeployContract(owner, abi.ABI{}, code, sim)
+	if err != nil {
+		t.Fatalf("failed to deploy enterprise contract: %v", err)
+	}
+	providers, err := sim.PendingProvidersAt(context.Background(), address)
+	if err != nil || len(providers) != 1 || providers[0] != provider {
+		t.Fatalf("providers mismatch: have %v (%v), want %v", providers, err, []common.Address{provider})
+	}
+
+	// the transactions without provider signature are rejected
+	tx, _ := types.SignTx(types.NewTransaction(0, address, new(big.Int), 100000, big.NewInt(1), nil), types.BaseSigner{}, senderKey)
+	if err := sim.SendTransaction(context.Background(), tx); err != core.ErrProviderSignatureIsRequired {
+		t.Fatalf("error mismatch: have %v, want %v", err, core.ErrProviderSignatureIsRequired)
+	}
+	contract := bind.NewBoundContract(address, abi.ABI{}, sim, sim, sim)
+	sender.GasLimit = 100000
+	if _, err := contract.Transfer(sender); err != bind.ErrNoProviderSigner {
+		t.Fatalf("error mismatch: have %v, want %v", err, bind.ErrNoProviderSigner)
+	}
+	sender.ProviderSigner = bind.NewKeyedProviderSigner(senderKey)
+	if _, err := contract.Transfer(sender); err == nil {
+		t.Fatal("transaction signed by a non provider should fail")
+	}
+
+	// the calls carry the provider paying for the gas
+	call := evrynetNode.CallMsg{From: sender.From, To: &address, GasPrice: big.NewInt(1), GasPayer: &provider}
+	if _, err := sim.EstimateGas(context.Background(), call); err != nil {
+		t.Fatalf("failed to estimate gas of sponsored call: %v", err)
+	}
+	if _, err := sim.CallContract(context.Background(), call, nil); err != nil {
+		t.Fatalf("failed to execute sponsored call: %v", err)
+	}
+
+	// the transactions signed by a provider are sponsored by the provider, the sender has no funds
+	sender.ProviderSigner = bind.NewKeyedProviderSigner(providerKey)
+	tx, err = contract.Transfer(sender)
+	if err != nil {
+		t.Fatalf("failed to send sponsored transaction: %v", err)
+	}
+	if payer := tx.GasPayer(types.BaseSigner{}); payer != provider {
+		t.Fatalf("gas payer mismatch: have %v, want %v", payer, provider)
+	}
+}
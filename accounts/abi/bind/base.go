@@ -34,6 +34,11 @@ import (
 // sign the transaction before submission.
 type SignerFn func(types.Signer, common.Address, *types.Transaction) (*types.Transaction, error)
 
+// ProviderSignerFn is a signer function callback when a transaction to an enterprise
+// contract requires the signature of one of its providers before submission. The
+// providers of the contract are nil if the backend can not tell them.
+type ProviderSignerFn func(signer types.Signer, providers []common.Address, tx *types.Transaction) (*types.Transaction, error)
+
 // CallOpts is the collection of options to fine tune a contract call request.
 type CallOpts struct {
 	Pending     bool            // Whether to operate on the pending state or the last known one
@@ -53,7 +58,8 @@ type TransactOpts struct {
 	GasPrice *big.Int // Gas price to use for the transaction execution (nil = gas price oracle)
 	GasLimit uint64   // Gas limit to set for the transaction execution (0 = estimate)
 
-	Enterprise *types.CreateAccountOption // Evrynet account option for enterprise contract feature (optional)
+	Enterprise     *types.CreateAccountOption // Evrynet account option for enterprise contract feature (optional)
+	ProviderSigner ProviderSignerFn           // Method to use for signing the transaction to an enterprise contract by a provider (optional)
 
 	Context context.Context // Network context to support cancellation and timeouts (nil = no timeout)
 }
@@ -221,6 +227,9 @@ func (c *BoundContract) transact(opts *TransactOpts, contract *common.Address, i
 		}
 		// If the contract surely has code (or code is not needed), estimate the transaction
 		msg := evrynetNode.CallMsg{From: opts.From, To: contract, Value: value, Data: input}
+		if contract == nil && opts.Enterprise != nil {
+			msg.Owner, msg.Provider = opts.Enterprise.OwnerAddress, opts.Enterprise.ProviderAddress
+		}
 		gasLimit, err = c.transactor.EstimateGas(ensureContext(opts.Context), msg)
 		if err != nil {
 			return nil, fmt.Errorf("failed to estimate gas needed: %v", err)
@@ -245,12 +254,40 @@ func (c *BoundContract) transact(opts *TransactOpts, contract *common.Address, i
 	if err != nil {
 		return nil, err
 	}
+	if contract != nil {
+		if signedTx, err = c.providerSign(opts, signedTx); err != nil {
+			return nil, err
+		}
+	}
 	if err := c.transactor.SendTransaction(ensureContext(opts.Context), signedTx); err != nil {
 		return nil, err
 	}
 	return signedTx, nil
 }
 
+// providerSign gets a transaction to the contract signed by one of its providers if the
+// contract is an enterprise contract. If the backend can not tell the providers of the
+// contract, the transaction is signed only if a provider signer is given.
+func (c *BoundContract) providerSign(opts *TransactOpts, tx *types.Transaction) (*types.Transaction, error) {
+	var providers []common.Address
+	if transactor, ok := c.transactor.(EnterpriseContractTransactor); ok {
+		var err error
+		if providers, err = transactor.PendingProvidersAt(ensureContext(opts.Context), c.address); err != nil {
+			return nil, fmt.Errorf("failed to retrieve contract providers: %v", err)
+		}
+		if len(providers) == 0 {
+			return tx, nil
+		}
+		if opts.ProviderSigner == nil {
+			return nil, ErrNoProviderSigner
+		}
+	}
+	if opts.ProviderSigner == nil {
+		return tx, nil
+	}
+	return opts.ProviderSigner(types.BaseSigner{}, providers, tx)
+}
+
 // FilterLogs filters contract logs for past blocks, returning the necessary
 // channels to construct a strongly typed bound iterator on top of them.
 func (c *BoundContract) FilterLogs(opts *FilterOpts, name string, query ...[]interface{}) (chan types.Log, event.Subscription, error) {
@@ -0,0 +1,153 @@
+// Copyright 2020 The evrynet-node Authors
+// This file is part of evrynet-node.
+//
+// evrynet-node is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// evrynet-node is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with evrynet-node. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/common/hexutil"
+)
+
+// Config is the rules of the gas station, loaded from a JSON file.
+type Config struct {
+	Contracts []*ContractRule `json:"contracts"`
+}
+
+// ContractRule is the rule to sponsor the transactions to an enterprise contract.
+type ContractRule struct {
+	Contract   common.Address  `json:"contract"`
+	Provider   common.Address  `json:"provider"`   // provider signing the transactions
+	Methods    []hexutil.Bytes `json:"methods"`    // method selectors allowed, any if empty
+	MaxGas     uint64          `json:"maxGas"`     // maximum gas limit of a transaction, no cap if 0
+	RateLimit  int             `json:"rateLimit"`  // maximum transactions of a sender within the rate window, no limit if 0
+	RateWindow uint64          `json:"rateWindow"` // rate window in seconds
+}
+
+// loadConfig reads the rules of the gas station from the file.
+func loadConfig(file string) (*Config, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	return &config, config.validate()
+}
+
+// validate returns an error if a rule is invalid.
+func (c *Config) validate() error {
+	if len(c.Contracts) == 0 {
+		return errors.New("no contract rule")
+	}
+	seen := make(map[common.Address]bool)
+	for _, rule := range c.Contracts {
+		if seen[rule.Contract] {
+			return fmt.Errorf("duplicate rule for contract %s", rule.Contract.Hex())
+		}
+		seen[rule.Contract] = true
+		for _, method := range rule.Methods {
+			if len(method) != 4 {
+				return fmt.Errorf("invalid method selector %s for contract %s", method, rule.Contract.Hex())
+			}
+		}
+		if rule.RateLimit < 0 || (rule.RateLimit > 0 && rule.RateWindow == 0) {
+			return fmt.Errorf("invalid rate limit for contract %s", rule.Contract.Hex())
+		}
+	}
+	return nil
+}
+
+// rateSweepInterval is the interval at which the rate limiter drops the windows without recent transactions
+const rateSweepInterval = time.Minute
+
+// rateLimiter counts the transactions signed for each sender to a contract within the rate window of the contract.
+type rateLimiter struct {
+	mu        sync.Mutex
+	sent      map[rateKey]*rateWindow
+	lastSweep time.Time
+	clock     func() time.Time
+}
+
+type rateKey struct {
+	contract common.Address
+	sender   common.Address
+}
+
+// rateWindow holds the times of the transactions signed within the window of a sender to a contract.
+type rateWindow struct {
+	sent   []time.Time
+	length time.Duration
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{
+		sent:  make(map[rateKey]*rateWindow),
+		clock: time.Now,
+	}
+}
+
+// allow returns true if the sender can send one more transaction to the contract of the rule,
+// the transaction is counted if it is allowed.
+func (l *rateLimiter) allow(rule *ContractRule, sender common.Address) bool {
+	if rule.RateLimit == 0 {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var (
+		key    = rateKey{contract: rule.Contract, sender: sender}
+		now    = l.clock()
+		length = time.Duration(rule.RateWindow) * time.Second
+		start  = now.Add(-length)
+		recent []time.Time
+	)
+	if now.Sub(l.lastSweep) >= rateSweepInterval {
+		l.sweep(now)
+	}
+	if window := l.sent[key]; window != nil {
+		for _, sent := range window.sent {
+			if sent.After(start) {
+				recent = append(recent, sent)
+			}
+		}
+	}
+	if len(recent) >= rule.RateLimit {
+		l.sent[key] = &rateWindow{sent: recent, length: length}
+		return false
+	}
+	l.sent[key] = &rateWindow{sent: append(recent, now), length: length}
+	return true
+}
+
+// sweep drops the windows whose transactions are all older than the window length.
+func (l *rateLimiter) sweep(now time.Time) {
+	for key, window := range l.sent {
+		if last := window.sent[len(window.sent)-1]; !last.After(now.Add(-window.length)) {
+			delete(l.sent, key)
+		}
+	}
+	l.lastSweep = now
+}
@@ -0,0 +1,154 @@
+// Copyright 2020 The evrynet-node Authors
+// This file is part of evrynet-node.
+//
+// evrynet-node is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// evrynet-node is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with evrynet-node. If not, see <http://www.gnu.org/licenses/>.
+
+// gasstation holds the keys of the providers of enterprise contracts and co-signs the transactions
+// of their users which follow the rules of each contract, optionally submitting them to a node.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/Evrynetlabs/evrynet-node/accounts"
+	"github.com/Evrynetlabs/evrynet-node/accounts/external"
+	"github.com/Evrynetlabs/evrynet-node/accounts/keystore"
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/evrclient"
+	"github.com/Evrynetlabs/evrynet-node/log"
+	"github.com/Evrynetlabs/evrynet-node/rpc"
+)
+
+func main() {
+	var (
+		configFile   = flag.String("config", "gasstation.json", "JSON file of the rules of the sponsored contracts")
+		nodeURL      = flag.String("rpc", "http://127.0.0.1:22001", "RPC endpoint of the node to check and submit the transactions with")
+		keystoreDir  = flag.String("keystore", "", "keystore directory of the provider keys")
+		passwordFile = flag.String("password", "", "file of the password unlocking the provider keys of the keystore")
+		signerURL    = flag.String("signer", "", "external signer holding the provider keys (url or path to ipc file)")
+		submit       = flag.Bool("submit", false, "allow submitting the co-signed transactions to the node")
+		ipcPath      = flag.String("ipcpath", "", "IPC path to serve the gas station API, empty to disable")
+		httpAddr     = flag.String("http", "127.0.0.1:8570", "HTTP listen address to serve the gas station API, empty to disable")
+		httpCors     = flag.String("http.corsdomain", "", "comma separated list of domains from which to accept cross origin requests")
+		verbosity    = flag.Int("verbosity", int(log.LvlInfo), "log verbosity (0-9)")
+	)
+	flag.Parse()
+
+	glogger := log.NewGlogHandler(log.StreamHandler(os.Stderr, log.TerminalFormat(false)))
+	glogger.Verbosity(log.Lvl(*verbosity))
+	log.Root().SetHandler(glogger)
+
+	config, err := loadConfig(*configFile)
+	if err != nil {
+		log.Crit("Failed to load the rules", "file", *configFile, "err", err)
+	}
+	if *ipcPath == "" && *httpAddr == "" {
+		log.Crit("Use -ipcpath or -http to serve the gas station API")
+	}
+	signers, err := providerSigners(config, *keystoreDir, *passwordFile, *signerURL)
+	if err != nil {
+		log.Crit("Failed to load the provider keys", "err", err)
+	}
+	client, err := evrclient.Dial(*nodeURL)
+	if err != nil {
+		log.Crit("Failed to connect to the node", "url", *nodeURL, "err", err)
+	}
+	chainID, err := client.ChainID(context.Background())
+	if err != nil {
+		log.Crit("Failed to retrieve the chain ID", "err", err)
+	}
+	station, err := NewGasStation(config, signers, client, chainID, *submit)
+	if err != nil {
+		log.Crit("Failed to create the gas station", "err", err)
+	}
+
+	apis := station.APIs()
+	if *ipcPath != "" {
+		listener, _, err := rpc.StartIPCEndpoint(*ipcPath, apis)
+		if err != nil {
+			log.Crit("Could not start IPC endpoint", "err", err)
+		}
+		defer listener.Close()
+		log.Info("IPC endpoint opened", "url", *ipcPath)
+	}
+	if *httpAddr != "" {
+		var cors []string
+		if *httpCors != "" {
+			cors = strings.Split(*httpCors, ",")
+		}
+		listener, _, err := rpc.StartHTTPEndpoint(*httpAddr, apis, []string{namespace}, cors, []string{"*"}, rpc.DefaultHTTPTimeouts)
+		if err != nil {
+			log.Crit("Could not start HTTP endpoint", "err", err)
+		}
+		defer listener.Close()
+		log.Info("HTTP endpoint opened", "url", "http://"+*httpAddr)
+	}
+	log.Info("Sponsoring enterprise contracts", "contracts", len(config.Contracts), "chainid", chainID, "submit", *submit)
+
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+	<-sigc
+	log.Info("Got interrupt, shutting down...")
+}
+
+// providerSigners returns the signers of the providers of the rules, either from the keystore
+// unlocked with the password or from the external signer.
+func providerSigners(config *Config, keystoreDir, passwordFile, signerURL string) (map[common.Address]ProviderSigner, error) {
+	signers := make(map[common.Address]ProviderSigner)
+	switch {
+	case signerURL != "" && keystoreDir != "":
+		return nil, errors.New("options -keystore and -signer are mutually exclusive")
+	case signerURL != "":
+		signer, err := external.NewExternalSigner(signerURL)
+		if err != nil {
+			return nil, err
+		}
+		for _, rule := range config.Contracts {
+			if !signer.Contains(accounts.Account{Address: rule.Provider}) {
+				return nil, fmt.Errorf("provider %s is not an account of the external signer", rule.Provider.Hex())
+			}
+			signers[rule.Provider] = signer
+		}
+	case keystoreDir != "":
+		password, err := ioutil.ReadFile(passwordFile)
+		if err != nil {
+			return nil, err
+		}
+		ks := keystore.NewKeyStore(keystoreDir, keystore.StandardScryptN, keystore.StandardScryptP)
+		for _, rule := range config.Contracts {
+			if _, ok := signers[rule.Provider]; ok {
+				continue
+			}
+			account, err := ks.Find(accounts.Account{Address: rule.Provider})
+			if err != nil {
+				return nil, fmt.Errorf("provider %s: %v", rule.Provider.Hex(), err)
+			}
+			if err := ks.Unlock(account, strings.TrimRight(string(password), "\r\n")); err != nil {
+				return nil, fmt.Errorf("provider %s: %v", rule.Provider.Hex(), err)
+			}
+			signers[rule.Provider] = ks
+		}
+	default:
+		return nil, errors.New("use -keystore or -signer to specify the provider keys")
+	}
+	return signers, nil
+}
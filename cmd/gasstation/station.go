@@ -0,0 +1,215 @@
+// Copyright 2020 The evrynet-node Authors
+// This file is part of evrynet-node.
+//
+// evrynet-node is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// evrynet-node is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with evrynet-node. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	evrynetNode "github.com/Evrynetlabs/evrynet-node"
+	"github.com/Evrynetlabs/evrynet-node/accounts"
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/common/hexutil"
+	"github.com/Evrynetlabs/evrynet-node/core/types"
+	"github.com/Evrynetlabs/evrynet-node/log"
+	"github.com/Evrynetlabs/evrynet-node/rlp"
+	"github.com/Evrynetlabs/evrynet-node/rpc"
+)
+
+// namespace is the namespace of the gas station API
+const namespace = "gasstation"
+
+var (
+	errUnknownContract    = errors.New("contract is not sponsored by the gas station")
+	errContractCreation   = errors.New("contract creation is not sponsored")
+	errOwnerTransaction   = errors.New("owner transaction is not sponsored")
+	errProviderSigned     = errors.New("transaction is already signed by a provider")
+	errMethodNotAllowed   = errors.New("method is not sponsored")
+	errGasCapExceeded     = errors.New("gas limit exceeds the gas cap of the contract")
+	errRateLimited        = errors.New("too many transactions from the sender")
+	errProviderNotAllowed = errors.New("provider is not a provider of the contract")
+	errSubmitDisabled     = errors.New("submitting transactions is disabled")
+)
+
+// Backend is the node the gas station checks and submits the transactions with.
+type Backend interface {
+	// PendingProvidersAt returns the providers of an enterprise contract in the pending state.
+	PendingProvidersAt(ctx context.Context, account common.Address) ([]common.Address, error)
+	// EstimateGas executes a call against the pending state, it fails if the call fails.
+	EstimateGas(ctx context.Context, call evrynetNode.CallMsg) (uint64, error)
+	// SendTransaction injects a transaction into the pending pool of the node.
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+}
+
+// ProviderSigner signs the transactions as a provider, it is implemented by accounts.Wallet.
+type ProviderSigner interface {
+	ProviderSignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// GasStation co-signs the transactions to enterprise contracts which follow its rules.
+type GasStation struct {
+	rules   map[common.Address]*ContractRule
+	signers map[common.Address]ProviderSigner
+	backend Backend
+	chainID *big.Int
+	signer  types.Signer
+	submit  bool
+	limiter *rateLimiter
+}
+
+// NewGasStation creates a gas station signing with the signer of the provider of each rule.
+func NewGasStation(config *Config, signers map[common.Address]ProviderSigner, backend Backend, chainID *big.Int, submit bool) (*GasStation, error) {
+	rules := make(map[common.Address]*ContractRule, len(config.Contracts))
+	for _, rule := range config.Contracts {
+		if _, ok := signers[rule.Provider]; !ok {
+			return nil, fmt.Errorf("no key for provider %s of contract %s", rule.Provider.Hex(), rule.Contract.Hex())
+		}
+		rules[rule.Contract] = rule
+	}
+	return &GasStation{
+		rules:   rules,
+		signers: signers,
+		backend: backend,
+		chainID: chainID,
+		signer:  types.NewOmahaSigner(chainID),
+		submit:  submit,
+		limiter: newRateLimiter(),
+	}, nil
+}
+
+// APIs returns the RPC APIs of the gas station.
+func (s *GasStation) APIs() []rpc.API {
+	return []rpc.API{{
+		Namespace: namespace,
+		Version:   "1.0",
+		Service:   &API{station: s},
+		Public:    true,
+	}}
+}
+
+// sign checks the transaction against the rules and the state of the node, and signs it as provider.
+func (s *GasStation) sign(ctx context.Context, tx *types.Transaction) (*types.Transaction, error) {
+	if tx.To() == nil {
+		return nil, errContractCreation
+	}
+	rule, ok := s.rules[*tx.To()]
+	if !ok {
+		return nil, errUnknownContract
+	}
+	if provider, err := types.Provider(s.signer, tx); err != nil || provider != nil {
+		return nil, errProviderSigned
+	}
+	msg, err := tx.AsMessage(s.signer)
+	if err != nil {
+		return nil, err
+	}
+	if msg.TxType() != types.NormalTxType {
+		return nil, errOwnerTransaction
+	}
+	if !rule.allowMethod(tx.Data()) {
+		return nil, errMethodNotAllowed
+	}
+	if rule.MaxGas != 0 && tx.Gas() > rule.MaxGas {
+		return nil, errGasCapExceeded
+	}
+	providers, err := s.backend.PendingProvidersAt(ctx, rule.Contract)
+	if err != nil {
+		return nil, err
+	}
+	if !rule.Provider.InList(providers) {
+		return nil, errProviderNotAllowed
+	}
+	// Simulate the execution with the gas limit of the transaction, the call fails if the transaction would fail.
+	// The gas is free as the sender does not pay for it.
+	call := evrynetNode.CallMsg{
+		From:     msg.From(),
+		To:       msg.To(),
+		Gas:      tx.Gas(),
+		GasPrice: new(big.Int),
+		Value:    tx.Value(),
+		Data:     tx.Data(),
+	}
+	if _, err := s.backend.EstimateGas(ctx, call); err != nil {
+		return nil, fmt.Errorf("simulation failed: %v", err)
+	}
+	if !s.limiter.allow(rule, msg.From()) {
+		return nil, errRateLimited
+	}
+	signed, err := s.signers[rule.Provider].ProviderSignTx(accounts.Account{Address: rule.Provider}, tx, s.chainID)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Signed sponsored transaction", "hash", signed.Hash(), "contract", rule.Contract, "sender", msg.From(), "gas", tx.Gas())
+	return signed, nil
+}
+
+// allowMethod returns true if the method called by the data is allowed by the rule.
+func (r *ContractRule) allowMethod(data []byte) bool {
+	if len(r.Methods) == 0 {
+		return true
+	}
+	if len(data) < 4 {
+		return false
+	}
+	for _, method := range r.Methods {
+		if bytes.Equal(method, data[:4]) {
+			return true
+		}
+	}
+	return false
+}
+
+// API is the RPC API of the gas station.
+type API struct {
+	station *GasStation
+}
+
+// SignTransaction checks the RLP encoded transaction signed by its sender and returns it signed by the provider of its contract.
+func (api *API) SignTransaction(ctx context.Context, encodedTx hexutil.Bytes) (hexutil.Bytes, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(encodedTx, tx); err != nil {
+		return nil, err
+	}
+	signed, err := api.station.sign(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	return rlp.EncodeToBytes(signed)
+}
+
+// SendTransaction checks the RLP encoded transaction signed by its sender, signs it by the provider of its contract
+// and submits it to the node.
+func (api *API) SendTransaction(ctx context.Context, encodedTx hexutil.Bytes) (common.Hash, error) {
+	if !api.station.submit {
+		return common.Hash{}, errSubmitDisabled
+	}
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(encodedTx, tx); err != nil {
+		return common.Hash{}, err
+	}
+	signed, err := api.station.sign(ctx, tx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if err := api.station.backend.SendTransaction(ctx, signed); err != nil {
+		return common.Hash{}, err
+	}
+	return signed.Hash(), nil
+}
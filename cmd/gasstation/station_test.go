@@ -0,0 +1,246 @@
+// Copyright 2020 The evrynet-node Authors
+// This file is part of evrynet-node.
+//
+// evrynet-node is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// evrynet-node is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with evrynet-node. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	evrynetNode "github.com/Evrynetlabs/evrynet-node"
+	"github.com/Evrynetlabs/evrynet-node/accounts/keystore"
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/common/hexutil"
+	"github.com/Evrynetlabs/evrynet-node/core/types"
+	"github.com/Evrynetlabs/evrynet-node/crypto"
+	"github.com/Evrynetlabs/evrynet-node/rlp"
+)
+
+var (
+	senderKey, _   = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	senderAddr     = crypto.PubkeyToAddress(senderKey.PublicKey)
+	providerKey, _ = crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+	providerAddr   = crypto.PubkeyToAddress(providerKey.PublicKey)
+	contractAddr   = common.HexToAddress("0x1000000000000000000000000000000000000001")
+	method         = hexutil.Bytes{0xa9, 0x05, 0x9c, 0xbb}
+	chainID        = big.NewInt(15)
+)
+
+type testBackend struct {
+	providers []common.Address
+	callErr   error
+	sent      []*types.Transaction
+}
+
+func (b *testBackend) PendingProvidersAt(ctx context.Context, account common.Address) ([]common.Address, error) {
+	return b.providers, nil
+}
+
+func (b *testBackend) EstimateGas(ctx context.Context, call evrynetNode.CallMsg) (uint64, error) {
+	return call.Gas, b.callErr
+}
+
+func (b *testBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	b.sent = append(b.sent, tx)
+	return nil
+}
+
+func newTestStation(t *testing.T, rule *ContractRule, backend Backend, submit bool) (*GasStation, func()) {
+	dir, err := ioutil.TempDir("", "gasstation-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	account, err := ks.ImportECDSA(providerKey, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.Unlock(account, ""); err != nil {
+		t.Fatal(err)
+	}
+	config := &Config{Contracts: []*ContractRule{rule}}
+	if err := config.validate(); err != nil {
+		t.Fatal(err)
+	}
+	station, err := NewGasStation(config, map[common.Address]ProviderSigner{providerAddr: ks}, backend, chainID, submit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return station, func() { os.RemoveAll(dir) }
+}
+
+func signedTx(t *testing.T, nonce uint64, to common.Address, gas uint64, data []byte) hexutil.Bytes {
+	tx := types.NewTransaction(nonce, to, new(big.Int), gas, big.NewInt(1), data)
+	tx, err := types.SignTx(tx, types.NewOmahaSigner(chainID), senderKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return encoded
+}
+
+func TestGasStationSignTransaction(t *testing.T) {
+	rule := &ContractRule{
+		Contract:   contractAddr,
+		Provider:   providerAddr,
+		Methods:    []hexutil.Bytes{method},
+		MaxGas:     100000,
+		RateLimit:  2,
+		RateWindow: 60,
+	}
+	backend := &testBackend{providers: []common.Address{providerAddr}}
+	station, cleanup := newTestStation(t, rule, backend, false)
+	defer cleanup()
+	api := &API{station: station}
+
+	now := time.Unix(1000, 0)
+	station.limiter.clock = func() time.Time { return now }
+
+	data := append(common.CopyBytes(method), make([]byte, 64)...)
+	tests := []struct {
+		tx  hexutil.Bytes
+		err error
+	}{
+		{signedTx(t, 0, common.HexToAddress("0x2"), 50000, data), errUnknownContract},
+		{signedTx(t, 0, contractAddr, 50000, []byte{0x01, 0x02, 0x03, 0x04}), errMethodNotAllowed},
+		{signedTx(t, 0, contractAddr, 50000, nil), errMethodNotAllowed},
+		{signedTx(t, 0, contractAddr, 100001, data), errGasCapExceeded},
+		{signedTx(t, 0, contractAddr, 50000, data), nil},
+		{signedTx(t, 1, contractAddr, 50000, data), nil},
+		{signedTx(t, 2, contractAddr, 50000, data), errRateLimited},
+	}
+	for i, test := range tests {
+		encoded, err := api.SignTransaction(context.Background(), test.tx)
+		if err != test.err {
+			t.Fatalf("test %d: error mismatch: have %v, want %v", i, err, test.err)
+		}
+		if err != nil {
+			continue
+		}
+		tx := new(types.Transaction)
+		if err := rlp.DecodeBytes(encoded, tx); err != nil {
+			t.Fatalf("test %d: failed to decode signed transaction: %v", i, err)
+		}
+		provider, err := types.Provider(station.signer, tx)
+		if err != nil || provider == nil || *provider != providerAddr {
+			t.Fatalf("test %d: provider mismatch: have %v (%v), want %s", i, provider, err, providerAddr.Hex())
+		}
+		if sender, err := types.Sender(station.signer, tx); err != nil || sender != senderAddr {
+			t.Fatalf("test %d: sender mismatch: have %s (%v), want %s", i, sender.Hex(), err, senderAddr.Hex())
+		}
+		// A co-signed transaction can not be signed again.
+		if _, err := api.SignTransaction(context.Background(), encoded); err != errProviderSigned {
+			t.Fatalf("test %d: error mismatch for co-signed transaction: have %v, want %v", i, err, errProviderSigned)
+		}
+	}
+
+	// The rate limit is lifted once the window has passed.
+	now = now.Add(61 * time.Second)
+	if _, err := api.SignTransaction(context.Background(), signedTx(t, 2, contractAddr, 50000, data)); err != nil {
+		t.Fatalf("failed to sign after the rate window: %v", err)
+	}
+
+	// The transaction is refused if it fails in the simulation.
+	backend.callErr = errors.New("execution reverted")
+	if _, err := api.SignTransaction(context.Background(), signedTx(t, 3, contractAddr, 50000, data)); err == nil {
+		t.Fatal("signed a transaction failing in the simulation")
+	}
+
+	// The transaction is refused if the provider was removed from the contract.
+	backend.callErr, backend.providers = nil, nil
+	if _, err := api.SignTransaction(context.Background(), signedTx(t, 3, contractAddr, 50000, data)); err != errProviderNotAllowed {
+		t.Fatalf("error mismatch: have %v, want %v", err, errProviderNotAllowed)
+	}
+}
+
+func TestGasStationSendTransaction(t *testing.T) {
+	rule := &ContractRule{Contract: contractAddr, Provider: providerAddr}
+	backend := &testBackend{providers: []common.Address{providerAddr}}
+
+	station, cleanup := newTestStation(t, rule, backend, false)
+	defer cleanup()
+	if _, err := (&API{station: station}).SendTransaction(context.Background(), signedTx(t, 0, contractAddr, 50000, nil)); err != errSubmitDisabled {
+		t.Fatalf("error mismatch: have %v, want %v", err, errSubmitDisabled)
+	}
+
+	station, cleanup = newTestStation(t, rule, backend, true)
+	defer cleanup()
+	hash, err := (&API{station: station}).SendTransaction(context.Background(), signedTx(t, 0, contractAddr, 50000, nil))
+	if err != nil {
+		t.Fatalf("failed to send transaction: %v", err)
+	}
+	if len(backend.sent) != 1 || backend.sent[0].Hash() != hash {
+		t.Fatalf("transaction not submitted: have %d, want 1", len(backend.sent))
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		config *Config
+		valid  bool
+	}{
+		{&Config{}, false},
+		{&Config{Contracts: []*ContractRule{{Contract: contractAddr, Provider: providerAddr}}}, true},
+		{&Config{Contracts: []*ContractRule{{Contract: contractAddr}, {Contract: contractAddr}}}, false},
+		{&Config{Contracts: []*ContractRule{{Contract: contractAddr, Methods: []hexutil.Bytes{{0x01, 0x02}}}}}, false},
+		{&Config{Contracts: []*ContractRule{{Contract: contractAddr, RateLimit: 1}}}, false},
+		{&Config{Contracts: []*ContractRule{{Contract: contractAddr, RateLimit: -1, RateWindow: 60}}}, false},
+		{&Config{Contracts: []*ContractRule{{Contract: contractAddr, RateLimit: 1, RateWindow: 60}}}, true},
+	}
+	for i, test := range tests {
+		if err := test.config.validate(); (err == nil) != test.valid {
+			t.Errorf("test %d: validity mismatch: have %v, want valid %v", i, err, test.valid)
+		}
+	}
+}
+
+func TestRateLimiterEviction(t *testing.T) {
+	var (
+		limiter = newRateLimiter()
+		now     = time.Unix(1000, 0)
+		short   = &ContractRule{Contract: contractAddr, RateLimit: 1, RateWindow: 10}
+		long    = &ContractRule{Contract: common.HexToAddress("0x2"), RateLimit: 1, RateWindow: 3600}
+	)
+	limiter.clock = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if !limiter.allow(short, common.BigToAddress(big.NewInt(int64(i)))) {
+			t.Fatalf("sender %d: transaction refused", i)
+		}
+	}
+	if !limiter.allow(long, senderAddr) {
+		t.Fatal("transaction refused")
+	}
+	// The windows without recent transactions are dropped at the next sweep.
+	now = now.Add(rateSweepInterval)
+	if !limiter.allow(short, senderAddr) {
+		t.Fatal("transaction refused after the sweep interval")
+	}
+	if len(limiter.sent) != 2 {
+		t.Fatalf("rate windows mismatch: have %d, want %d", len(limiter.sent), 2)
+	}
+	if limiter.allow(long, senderAddr) {
+		t.Fatal("rate limit of the long window lifted by the sweep")
+	}
+}
@@ -36,6 +36,7 @@ import (
 	"github.com/Evrynetlabs/evrynet-node/event"
 	"github.com/Evrynetlabs/evrynet-node/evr/downloader"
 	"github.com/Evrynetlabs/evrynet-node/log"
+	"github.com/Evrynetlabs/evrynet-node/params"
 	"github.com/Evrynetlabs/evrynet-node/trie"
 	"github.com/urfave/cli"
 )
@@ -191,9 +192,23 @@ Use "evrynetNode dump 0" to dump the genesis block.`,
 			utils.TendermintTimeoutCommitFlag,
 			utils.TendermintFaultyModeFlag,
 			utils.TendermintSCUseEVMCallerFlag,
+			utils.TendermintStakingLayoutFlag,
 		},
 		Category: "BLOCKCHAIN COMMANDS",
 	}
+	verifyFraudProofCommand = cli.Command{
+		Action:    utils.MigrateFlags(verifyFraudProof),
+		Name:      "verify-fraud-proof",
+		Usage:     "Verify the fraud proof of an evil block",
+		ArgsUsage: "<proofPath> [<genesisPath>]",
+		Category:  "BLOCKCHAIN COMMANDS",
+		Description: `
+The verify-fraud-proof command re-executes the block of a JSON fraud proof, as
+returned by finality_getFraudProof, against only the state held by the proof.
+The chain config is read from the optional genesis file, the main net config is
+used otherwise. The state is finalized by the consensus engine of the chain config.
+It fails if the proof does not show an invalid state transition.`,
+	}
 )
 
 // initGenesis will initialise the given JSON format genesis file and writes it as
@@ -548,6 +563,46 @@ func inspect(ctx *cli.Context) error {
 	return rawdb.InspectDatabase(chainDb)
 }
 
+// verifyFraudProof re-executes the given fraud proof and fails if the block of the proof is valid.
+func verifyFraudProof(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	proof := new(types.FraudProof)
+	if err := readJSONFile(ctx.Args().Get(0), proof); err != nil {
+		utils.Fatalf("Invalid fraud proof: %v", err)
+	}
+	config := params.MainnetChainConfig
+	if len(ctx.Args()) > 1 {
+		genesis := new(core.Genesis)
+		if err := readJSONFile(ctx.Args().Get(1), genesis); err != nil {
+			utils.Fatalf("Invalid genesis file: %v", err)
+		}
+		if genesis.Config != nil {
+			config = genesis.Config
+		}
+	}
+	node, _ := makeConfigNode(ctx)
+	defer node.Close()
+
+	engine := utils.MakeEngine(ctx, node, config, rawdb.NewMemoryDatabase())
+	if err := core.VerifyFraudProof(config, engine, proof); err != nil {
+		utils.Fatalf("Fraud proof rejected: %v", err)
+	}
+	fmt.Printf("Fraud proof verified: block %d (%x) has an invalid state transition\n", proof.Header.Number, proof.Header.Hash())
+	return nil
+}
+
+// readJSONFile decodes the JSON file into v.
+func readJSONFile(path string, v interface{}) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return json.NewDecoder(file).Decode(v)
+}
+
 // hashish returns true for strings that look like hashes.
 func hashish(x string) bool {
 	_, err := strconv.Atoi(x)
@@ -126,6 +126,7 @@ func makeConfigNode(ctx *cli.Context) (*node.Node, gevConfig) {
 	// Apply flags.
 	utils.SetULC(ctx, &cfg.Evr)
 	utils.SetNodeConfig(ctx, &cfg.Node)
+	utils.SetTendermintSentryConfig(ctx, &cfg.Evr.Tendermint, &cfg.Node.P2P)
 	stack, err := node.New(&cfg.Node)
 	if err != nil {
 		utils.Fatalf("Failed to create the protocol stack: %v", err)
@@ -159,6 +159,10 @@ var (
 		utils.TendermintTimeoutPrecommitDeltaFlag,
 		utils.TendermintTimeoutCommitFlag,
 		utils.TendermintSCUseEVMCallerFlag,
+		utils.TendermintStakingLayoutFlag,
+		utils.TendermintSignerFlag,
+		utils.TendermintSentriesFlag,
+		utils.TendermintPrivateValidatorsFlag,
 	}
 
 	rpcFlags = []cli.Flag{
@@ -219,6 +223,7 @@ func init() {
 		removedbCommand,
 		dumpCommand,
 		inspectCommand,
+		verifyFraudProofCommand,
 		// See accountcmd.go:
 		accountCommand,
 		walletCommand,
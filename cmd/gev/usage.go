@@ -265,6 +265,10 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.TendermintTimeoutCommitFlag,
 			utils.TendermintFaultyModeFlag,
 			utils.TendermintSCUseEVMCallerFlag,
+			utils.TendermintStakingLayoutFlag,
+			utils.TendermintSignerFlag,
+			utils.TendermintSentriesFlag,
+			utils.TendermintPrivateValidatorsFlag,
 		},
 	},
 	{
@@ -0,0 +1,106 @@
+// Copyright 2015 The evrynet-node Authors
+// This file is part of evrynet-node.
+//
+// evrynet-node is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// evrynet-node is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with evrynet-node. If not, see <http://www.gnu.org/licenses/>.
+
+// tmsigner holds the keys of a Tendermint validator and signs the consensus messages and seals of its node,
+// it refuses to sign two different proposals or votes at the same block number, round and step.
+package main
+
+import (
+	"crypto/ecdsa"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/Evrynetlabs/evrynet-node/cmd/utils"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/signer"
+	"github.com/Evrynetlabs/evrynet-node/crypto"
+	"github.com/Evrynetlabs/evrynet-node/crypto/bls"
+	"github.com/Evrynetlabs/evrynet-node/log"
+	"github.com/Evrynetlabs/evrynet-node/rpc"
+)
+
+func main() {
+	var (
+		keyFile    = flag.String("nodekey", "", "validator private key filename")
+		keyHex     = flag.String("nodekeyhex", "", "validator private key as hex (for testing)")
+		blsKeyFile = flag.String("blskey", "", "validator BLS key filename, a new key is generated if the file does not exist")
+		stateFile  = flag.String("state", "tmsigner-state.json", "file persisting the last proposal or vote signed")
+		ipcPath    = flag.String("ipcpath", "tmsigner.ipc", "IPC path to serve the signer API, empty to disable")
+		httpAddr   = flag.String("http", "", "HTTP listen address to serve the signer API, i.e. 127.0.0.1:8560, empty to disable")
+		verbosity  = flag.Int("verbosity", int(log.LvlInfo), "log verbosity (0-9)")
+
+		key *ecdsa.PrivateKey
+		err error
+	)
+	flag.Parse()
+
+	glogger := log.NewGlogHandler(log.StreamHandler(os.Stderr, log.TerminalFormat(false)))
+	glogger.Verbosity(log.Lvl(*verbosity))
+	log.Root().SetHandler(glogger)
+
+	switch {
+	case *keyFile == "" && *keyHex == "":
+		utils.Fatalf("Use -nodekey or -nodekeyhex to specify a private key")
+	case *keyFile != "" && *keyHex != "":
+		utils.Fatalf("Options -nodekey and -nodekeyhex are mutually exclusive")
+	case *keyFile != "":
+		if key, err = crypto.LoadECDSA(*keyFile); err != nil {
+			utils.Fatalf("-nodekey: %v", err)
+		}
+	case *keyHex != "":
+		if key, err = crypto.HexToECDSA(*keyHex); err != nil {
+			utils.Fatalf("-nodekeyhex: %v", err)
+		}
+	}
+	if *ipcPath == "" && *httpAddr == "" {
+		utils.Fatalf("Use -ipcpath or -http to serve the signer API")
+	}
+
+	var blsKey *bls.SecretKey
+	if *blsKeyFile != "" {
+		if blsKey, err = signer.LoadBLSKey(*blsKeyFile); err != nil {
+			utils.Fatalf("-blskey: %v", err)
+		}
+	}
+	guarded, err := signer.NewGuardedSigner(signer.NewLocalSigner(key, blsKey), *stateFile)
+	if err != nil {
+		utils.Fatalf("-state: %v", err)
+	}
+	apis := signer.APIs(guarded)
+	if *ipcPath != "" {
+		listener, _, err := rpc.StartIPCEndpoint(*ipcPath, apis)
+		if err != nil {
+			utils.Fatalf("Could not start IPC endpoint: %v", err)
+		}
+		defer listener.Close()
+		log.Info("IPC endpoint opened", "url", *ipcPath)
+	}
+	if *httpAddr != "" {
+		listener, _, err := rpc.StartHTTPEndpoint(*httpAddr, apis, []string{signer.Namespace}, nil, []string{"localhost"}, rpc.DefaultHTTPTimeouts)
+		if err != nil {
+			utils.Fatalf("Could not start HTTP endpoint: %v", err)
+		}
+		defer listener.Close()
+		log.Info("HTTP endpoint opened", "url", "http://"+*httpAddr)
+	}
+	log.Info("Signing for the validator", "address", guarded.Address())
+
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+	<-sigc
+	log.Info("Got interrupt, shutting down...")
+}
@@ -710,6 +710,22 @@ var (
 		Name:  "tendermint.use-evm-caller",
 		Usage: "The flag allowance reading data from stateDB or EVM",
 	}
+	TendermintStakingLayoutFlag = cli.StringFlag{
+		Name:  "tendermint.staking-layout",
+		Usage: "Path to the solc storage layout JSON of the staking contract, overrides the layout of the genesis",
+	}
+	TendermintSignerFlag = cli.StringFlag{
+		Name:  "tendermint.signer",
+		Usage: "IPC path or URL of the signer process holding the validator keys (default = the node key signs)",
+	}
+	TendermintSentriesFlag = cli.StringFlag{
+		Name:  "tendermint.sentries",
+		Usage: "Comma separated enode URLs of the sentries of the validator, it then only connects to them without discovery",
+	}
+	TendermintPrivateValidatorsFlag = cli.StringFlag{
+		Name:  "tendermint.private-validators",
+		Usage: "Comma separated enode URLs of the validators fronted by the sentry, it relays their consensus messages",
+	}
 
 	// Metrics flags
 	MetricsEnabledFlag = cli.BoolFlag{
@@ -1361,6 +1377,46 @@ func setWhitelist(ctx *cli.Context, cfg *evr.Config) {
 	}
 }
 
+// SetTendermintSentryConfig applies the sentry flags to the Tendermint config and the P2P config:
+// a validator behind sentries only keeps connections to its sentries and does not take part in the discovery,
+// a sentry keeps connections to the validators it fronts
+func SetTendermintSentryConfig(ctx *cli.Context, cfg *tendermint.Config, p2pCfg *p2p.Config) {
+	if ctx.GlobalIsSet(TendermintSentriesFlag.Name) {
+		cfg.Sentries = parseEnodes(TendermintSentriesFlag.Name, ctx.GlobalString(TendermintSentriesFlag.Name))
+	}
+	if ctx.GlobalIsSet(TendermintPrivateValidatorsFlag.Name) {
+		cfg.PrivateValidators = parseEnodes(TendermintPrivateValidatorsFlag.Name, ctx.GlobalString(TendermintPrivateValidatorsFlag.Name))
+	}
+	if len(cfg.Sentries) > 0 {
+		// the sentries are trusted, so they are the only peers allowed with no peer slot
+		p2pCfg.MaxPeers = 0
+		p2pCfg.NoDiscovery = true
+		p2pCfg.DiscoveryV5 = false
+		p2pCfg.StaticNodes = append(p2pCfg.StaticNodes, cfg.Sentries...)
+		p2pCfg.TrustedNodes = append(p2pCfg.TrustedNodes, cfg.Sentries...)
+	}
+	if len(cfg.PrivateValidators) > 0 {
+		p2pCfg.StaticNodes = append(p2pCfg.StaticNodes, cfg.PrivateValidators...)
+		p2pCfg.TrustedNodes = append(p2pCfg.TrustedNodes, cfg.PrivateValidators...)
+	}
+}
+
+// parseEnodes parses the comma separated enode URLs of the flag
+func parseEnodes(flag string, urls string) []*enode.Node {
+	var nodes []*enode.Node
+	for _, url := range strings.Split(urls, ",") {
+		if url = strings.TrimSpace(url); url == "" {
+			continue
+		}
+		node, err := enode.Parse(enode.ValidSchemes, url)
+		if err != nil {
+			Fatalf("Option %q: invalid enode %s: %v", flag, url, err)
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
 // setTendermint will use params from CLI for tendermint config
 // NOTE: ProposerPolicy, Epoch are used for chain, so they not allowed to inject. They will be got from genesis
 func setTendermint(ctx *cli.Context, cfg *tendermint.Config) {
@@ -1368,6 +1424,12 @@ func setTendermint(ctx *cli.Context, cfg *tendermint.Config) {
 	if ctx.GlobalIsSet(TendermintSCUseEVMCallerFlag.Name) {
 		cfg.UseEVMCaller = true
 	}
+	if ctx.GlobalIsSet(TendermintStakingLayoutFlag.Name) {
+		cfg.StakingLayoutFile = ctx.GlobalString(TendermintStakingLayoutFlag.Name)
+	}
+	if ctx.GlobalIsSet(TendermintSignerFlag.Name) {
+		cfg.RemoteSigner = ctx.GlobalString(TendermintSignerFlag.Name)
+	}
 
 	if ctx.GlobalIsSet(TendermintBlockPeriodFlag.Name) {
 		cfg.BlockPeriod = ctx.GlobalUint64(TendermintBlockPeriodFlag.Name)
@@ -1400,6 +1462,12 @@ func setTendermint(ctx *cli.Context, cfg *tendermint.Config) {
 	if ctx.IsSet(TendermintSCUseEVMCallerFlag.Name) {
 		cfg.UseEVMCaller = true
 	}
+	if ctx.IsSet(TendermintStakingLayoutFlag.Name) {
+		cfg.StakingLayoutFile = ctx.String(TendermintStakingLayoutFlag.Name)
+	}
+	if ctx.IsSet(TendermintSignerFlag.Name) {
+		cfg.RemoteSigner = ctx.String(TendermintSignerFlag.Name)
+	}
 
 	if ctx.IsSet(TendermintBlockPeriodFlag.Name) {
 		cfg.BlockPeriod = ctx.Uint64(TendermintBlockPeriodFlag.Name)
@@ -1749,31 +1817,7 @@ func MakeChain(ctx *cli.Context, stack *node.Node) (chain *core.BlockChain, chai
 	if err != nil {
 		Fatalf("%v", err)
 	}
-	var engine consensus.Engine
-	if config.Clique != nil {
-		engine = clique.New(config.Clique, chainDb)
-	} else if config.Tendermint != nil { // In case Clique config was not defined
-		tdmintConfig := tendermint.DefaultConfig
-		setTendermint(ctx, tdmintConfig)
-		tdmintConfig.ProposerPolicy = tendermint.ProposerPolicy(config.Tendermint.ProposerPolicy)
-		tdmintConfig.Epoch = config.Tendermint.Epoch
-		tdmintConfig.StakingSCAddress = config.Tendermint.StakingSCAddress
-		tdmintConfig.FixedValidators = config.Tendermint.FixedValidators
-		tdmintConfig.BlockReward = config.Tendermint.BlockReward
-		engine = tdmintBackend.New(tdmintConfig, stack.Config().NodeKey())
-	} else {
-		engine = ethash.NewFaker()
-		if !ctx.GlobalBool(FakePoWFlag.Name) {
-			engine = ethash.New(ethash.Config{
-				CacheDir:       stack.ResolvePath(evr.DefaultConfig.Ethash.CacheDir),
-				CachesInMem:    evr.DefaultConfig.Ethash.CachesInMem,
-				CachesOnDisk:   evr.DefaultConfig.Ethash.CachesOnDisk,
-				DatasetDir:     stack.ResolvePath(evr.DefaultConfig.Ethash.DatasetDir),
-				DatasetsInMem:  evr.DefaultConfig.Ethash.DatasetsInMem,
-				DatasetsOnDisk: evr.DefaultConfig.Ethash.DatasetsOnDisk,
-			}, nil, false)
-		}
-	}
+	engine := MakeEngine(ctx, stack, config, chainDb)
 	if gcmode := ctx.GlobalString(GCModeFlag.Name); gcmode != "full" && gcmode != "archive" {
 		Fatalf("--%s must be either 'full' or 'archive'", GCModeFlag.Name)
 	}
@@ -1798,6 +1842,34 @@ func MakeChain(ctx *cli.Context, stack *node.Node) (chain *core.BlockChain, chai
 	return chain, chainDb
 }
 
+// MakeEngine creates the consensus engine of the chain config from set command line flags.
+func MakeEngine(ctx *cli.Context, stack *node.Node, config *params.ChainConfig, chainDb evrdb.Database) consensus.Engine {
+	var engine consensus.Engine
+	if config.Clique != nil {
+		engine = clique.New(config.Clique, chainDb)
+	} else if config.Tendermint != nil { // In case Clique config was not defined
+		tdmintConfig := *tendermint.DefaultConfig
+		setTendermint(ctx, &tdmintConfig)
+		if err := evr.SetTendermintConfig(&tdmintConfig, config); err != nil {
+			Fatalf("%v", err)
+		}
+		engine = tdmintBackend.New(&tdmintConfig, stack.Config().NodeKey())
+	} else {
+		engine = ethash.NewFaker()
+		if !ctx.GlobalBool(FakePoWFlag.Name) {
+			engine = ethash.New(ethash.Config{
+				CacheDir:       stack.ResolvePath(evr.DefaultConfig.Ethash.CacheDir),
+				CachesInMem:    evr.DefaultConfig.Ethash.CachesInMem,
+				CachesOnDisk:   evr.DefaultConfig.Ethash.CachesOnDisk,
+				DatasetDir:     stack.ResolvePath(evr.DefaultConfig.Ethash.DatasetDir),
+				DatasetsInMem:  evr.DefaultConfig.Ethash.DatasetsInMem,
+				DatasetsOnDisk: evr.DefaultConfig.Ethash.DatasetsOnDisk,
+			}, nil, false)
+		}
+	}
+	return engine
+}
+
 // MakeConsolePreloads retrieves the absolute paths for the console JavaScript
 // scripts to preload before starting.
 func MakeConsolePreloads(ctx *cli.Context) []string {
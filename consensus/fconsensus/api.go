@@ -0,0 +1,97 @@
+package fconsensus
+
+import (
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/common/hexutil"
+	"github.com/Evrynetlabs/evrynet-node/consensus"
+	"github.com/Evrynetlabs/evrynet-node/core/types"
+	"github.com/Evrynetlabs/evrynet-node/rpc"
+)
+
+// API is a user facing RPC API to allow controlling the signers of the final chain
+// and collecting the signatures of its blocks.
+type API struct {
+	chain      consensus.ChainReader
+	fconsensus *FConsensus
+}
+
+// GetSigners retrieves the list of authorized signers at the specified block.
+func (api *API) GetSigners(number *rpc.BlockNumber) ([]common.Address, error) {
+	var header *types.Header
+	if number == nil || *number == rpc.LatestBlockNumber {
+		header = api.chain.CurrentHeader()
+	} else {
+		header = api.chain.GetHeaderByNumber(uint64(number.Int64()))
+	}
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	fsnap, err := api.fconsensus.fsnapshot(api.chain, header.Number.Uint64(), header.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return fsnap.signers(), nil
+}
+
+// GetThreshold retrieves the number of signatures required to seal the block after the specified one.
+func (api *API) GetThreshold(number *rpc.BlockNumber) (int, error) {
+	var header *types.Header
+	if number == nil || *number == rpc.LatestBlockNumber {
+		header = api.chain.CurrentHeader()
+	} else {
+		header = api.chain.GetHeaderByNumber(uint64(number.Int64()))
+	}
+	if header == nil {
+		return 0, errUnknownBlock
+	}
+	fsnap, err := api.fconsensus.fsnapshot(api.chain, header.Number.Uint64(), header.Hash(), nil)
+	if err != nil {
+		return 0, err
+	}
+	return fsnap.threshold(header.Number.Uint64() + 1), nil
+}
+
+// Proposals returns the current proposals the node tries to uphold and vote on.
+func (api *API) Proposals() map[common.Address]bool {
+	api.fconsensus.lock.RLock()
+	defer api.fconsensus.lock.RUnlock()
+
+	proposals := make(map[common.Address]bool)
+	for address, auth := range api.fconsensus.proposals {
+		proposals[address] = auth
+	}
+	return proposals
+}
+
+// Propose injects a new authorization proposal that the signer will attempt to
+// push through.
+func (api *API) Propose(address common.Address, auth bool) {
+	api.fconsensus.lock.Lock()
+	defer api.fconsensus.lock.Unlock()
+
+	api.fconsensus.proposals[address] = auth
+}
+
+// Discard drops a currently running proposal, stopping the signer from casting
+// further votes (either for or against).
+func (api *API) Discard(address common.Address) {
+	api.fconsensus.lock.Lock()
+	defer api.fconsensus.lock.Unlock()
+
+	delete(api.fconsensus.proposals, address)
+}
+
+// PendingHeader returns the header of the locally proposed block waiting for the signatures of other signers.
+func (api *API) PendingHeader() *types.Header {
+	return api.fconsensus.PendingHeader()
+}
+
+// SignHeader returns the signature of the local signer for a block proposed by another signer.
+func (api *API) SignHeader(header *types.Header) (hexutil.Bytes, error) {
+	return api.fconsensus.SignHeader(api.chain, header)
+}
+
+// AddSignature adds the signature of another signer to the locally proposed block.
+func (api *API) AddSignature(sealHash common.Hash, signature hexutil.Bytes) error {
+	return api.fconsensus.AddSignature(sealHash, signature)
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/Evrynetlabs/evrynet-node/core/state"
 	"github.com/Evrynetlabs/evrynet-node/core/types"
 	"github.com/Evrynetlabs/evrynet-node/crypto"
+	"github.com/Evrynetlabs/evrynet-node/event"
 	"github.com/Evrynetlabs/evrynet-node/evrdb"
 	"github.com/Evrynetlabs/evrynet-node/log"
 	"github.com/Evrynetlabs/evrynet-node/params"
@@ -32,7 +33,7 @@ const (
 	inmemorySignatures = 4096
 	inmemorySnapshots  = 128 // Number of recent vote snapshots to keep in memory
 	ExtraVanity        = 32
-	SignerAddress      = "EJp8jwQvRs7L74t5XYAH6SfG44koVWUVqv"
+	ExtraSeal          = 65 // Fixed number of bytes of a signature in the seal
 )
 
 var (
@@ -77,6 +78,18 @@ var (
 	errRecentlySigned               = errors.New("recently signed")
 	errSignersNumberWrong           = errors.New("wrong number of signers")
 	errMismatchingCheckpointSigners = errors.New("mismatching signer list on checkpoint block")
+
+	// errInvalidSeal is returned if the seal is not a list of 65 bytes signatures.
+	errInvalidSeal = errors.New("invalid seal")
+
+	// errDuplicatedSigner is returned if a signer signed a block more than once.
+	errDuplicatedSigner = errors.New("duplicated signer")
+
+	// errInsufficientSignatures is returned if a block is sealed with less signatures than the threshold.
+	errInsufficientSignatures = errors.New("insufficient signatures")
+
+	// errNoPendingBlock is returned if a signature is added while no block waits for signatures.
+	errNoPendingBlock = errors.New("no pending block")
 )
 
 type SignerFn func(accounts.Account, string, []byte) ([]byte, error)
@@ -91,7 +104,29 @@ type FConsensus struct {
 	proposals map[common.Address]bool
 	signer    common.Address
 	signFn    SignerFn
+	pending   *pendingSeal // the locally sealed block waiting for the signatures of other signers
 	lock      sync.RWMutex
+
+	pendingFeed event.Feed // pendingFeed notifies the blocks waiting for the signatures of other signers
+}
+
+// PendingSealEvent is posted when the local signer proposes a block which needs the signatures of other signers,
+// the header carries the signature of the proposer.
+type PendingSealEvent struct {
+	Header *types.Header
+}
+
+// pendingSeal is a block signed by the local signer, it is sent to the sealing results
+// once it has been signed by the threshold of signers.
+type pendingSeal struct {
+	block      *types.Block
+	sealHash   common.Hash
+	signers    map[common.Address]struct{}
+	threshold  int
+	signatures [][]byte
+	signed     map[common.Address]bool
+	results    chan<- *types.Block
+	stop       <-chan struct{}
 }
 
 func New(config *params.FConConfig, db evrdb.Database) *FConsensus {
@@ -119,6 +154,7 @@ func (fc *FConsensus) Authorize(signer common.Address, signFn SignerFn) {
 	fc.signFn = signFn
 }
 
+// Author returns the proposer of the block, who signed the first signature of the seal.
 func (fc *FConsensus) Author(header *types.Header) (common.Address, error) {
 	return ecrecover(header, fc.signature)
 }
@@ -249,8 +285,9 @@ func (fc *FConsensus) fsnapshot(chain consensus.ChainReader, number uint64, hash
 			var signers []common.Address
 			if checkpoint != nil {
 				hash := checkpoint.Hash()
-				if number == 0 {
-					// TODO change genesisfile
+				if number == 0 && len(fc.config.Signers) > 0 {
+					signers = fc.config.Signers
+				} else if number == 0 {
 					signers = make([]common.Address, (len(checkpoint.Extra)-97)/common.AddressLength)
 					for i := 0; i < len(signers); i++ {
 						copy(signers[i][:], checkpoint.Extra[32+i*common.AddressLength:])
@@ -313,10 +350,6 @@ func (fc *FConsensus) VerifyUncles(chain consensus.ChainReader, block *types.Blo
 	return nil
 }
 
-func (fc *FConsensus) GetAuthorizedSinger() (common.Address, error) {
-	return common.EvryAddressStringToAddressCheck(SignerAddress)
-}
-
 func (fc *FConsensus) VerifySeal(chain consensus.ChainReader, header *types.Header) error {
 	return fc.verifyHeader(chain, header, nil)
 }
@@ -330,17 +363,20 @@ func (fc *FConsensus) verifySeal(chain consensus.ChainReader, header *types.Head
 	if err != nil {
 		return err
 	}
-	//authorizedSigner, err := fc.GetAuthorizedSinger()
+	signers, err := ecrecoverSigners(header, fc.signature)
 	if err != nil {
 		return err
 	}
-	signer, err := ecrecover(header, fc.signature)
-	if err != nil {
-		return err
+	for _, signer := range signers {
+		if _, ok := fsnap.Signers[signer]; !ok {
+			return errUnauthorizedSigner
+		}
 	}
-	if _, ok := fsnap.Signers[signer]; !ok {
-		return errUnauthorizedSigner
+	if len(signers) < fsnap.threshold(number) {
+		return errInsufficientSignatures
 	}
+	// only the proposer is subject to the recently signed rule, the other signers just approve its block
+	signer := signers[0]
 	for seen, recent := range fsnap.Recents {
 		if recent == signer {
 			if limit := uint64(len(fsnap.Signers)/2 + 1); seen > number-limit {
@@ -421,18 +457,9 @@ func (fc *FConsensus) SealForTest(block *types.Block) (*types.Block, error) {
 	if err != nil {
 		return nil, err
 	}
-	fce, err := fconTypes.ExtractFConExtra(header)
-	if err != nil {
+	if err := writeSeal(header, [][]byte{signHash}); err != nil {
 		return nil, err
 	}
-
-	fce.Seal = append(fce.Seal[:0], signHash[:]...)
-	byteBuffer := new(bytes.Buffer)
-	err = rlp.Encode(byteBuffer, &fce)
-	if err != nil {
-		return nil, err
-	}
-	header.Extra = append(header.Extra[:ExtraVanity], byteBuffer.Bytes()...)
 	return block.WithSeal(header), nil
 
 }
@@ -472,29 +499,183 @@ func (fc *FConsensus) Seal(chain consensus.ChainReader, block *types.Block, resu
 		return err
 	}
 
-	fce, err := fconTypes.ExtractFConExtra(header)
+	fc.lock.Lock()
+	pending := &pendingSeal{
+		block:     block,
+		sealHash:  SealHash(header),
+		signers:   fsnap.Signers,
+		threshold: fsnap.threshold(number),
+		signed:    make(map[common.Address]bool),
+		results:   results,
+		stop:      stop,
+	}
+	fc.pending = pending
+	if err := fc.addSignature(pending.sealHash, signHash); err != nil {
+		fc.lock.Unlock()
+		return err
+	}
+	fc.lock.Unlock()
+
+	if pending.threshold > 1 {
+		log.Info("Waiting for the signatures of other signers", "number", number, "sealhash", pending.sealHash,
+			"threshold", pending.threshold)
+		if err := writeSeal(header, [][]byte{signHash}); err != nil {
+			return err
+		}
+		// the subscribers gossip the block to the other signers, which send back their signatures
+		fc.pendingFeed.Send(PendingSealEvent{Header: header})
+	}
+	return nil
+}
+
+// SubscribePendingSeal registers a subscription of PendingSealEvent
+func (fc *FConsensus) SubscribePendingSeal(ch chan<- PendingSealEvent) event.Subscription {
+	return fc.pendingFeed.Subscribe(ch)
+}
+
+// VerifyPendingHeader checks that the header of a block waiting for signatures is on top of a known block
+// and signed by an authorized proposer, so that it can be relayed to the other signers.
+func (fc *FConsensus) VerifyPendingHeader(chain consensus.ChainReader, header *types.Header) error {
+	number := header.Number.Uint64()
+	if number == 0 {
+		return errUnknownBlock
+	}
+	fsnap, err := fc.fsnapshot(chain, number-1, header.ParentHash, nil)
 	if err != nil {
 		return err
 	}
+	proposer, err := ecrecover(header, fc.signature)
+	if err != nil {
+		return err
+	}
+	if _, authorized := fsnap.Signers[proposer]; !authorized {
+		return errUnauthorizedSigner
+	}
+	return nil
+}
 
-	fce.Seal = append(fce.Seal[:0], signHash[:]...)
-	byteBuffer := new(bytes.Buffer)
-	err = rlp.Encode(byteBuffer, &fce)
+// VerifySignature checks that the signature of a seal hash is signed by a signer authorized at the head of chain,
+// so that it can be relayed to the proposer of the block.
+func (fc *FConsensus) VerifySignature(chain consensus.ChainReader, sealHash common.Hash, signature []byte) error {
+	if len(signature) != ExtraSeal {
+		return errInvalidSeal
+	}
+	pubkey, err := crypto.Ecrecover(sealHash.Bytes(), signature)
 	if err != nil {
 		return err
 	}
-	header.Extra = append(header.Extra[:ExtraVanity], byteBuffer.Bytes()...)
+	var signer common.Address
+	copy(signer[:], crypto.Keccak256(pubkey[1:])[12:])
+
+	head := chain.CurrentHeader()
+	fsnap, err := fc.fsnapshot(chain, head.Number.Uint64(), head.Hash(), nil)
+	if err != nil {
+		return err
+	}
+	if _, authorized := fsnap.Signers[signer]; !authorized {
+		return errUnauthorizedSigner
+	}
+	return nil
+}
+
+// SignHeader returns the signature of the local signer for a block proposed by another signer,
+// the header must be valid and signed by its proposer.
+func (fc *FConsensus) SignHeader(chain consensus.ChainReader, header *types.Header) ([]byte, error) {
+	number := header.Number.Uint64()
+	if number == 0 {
+		return nil, errUnknownBlock
+	}
+	fc.lock.RLock()
+	signer, signFn := fc.signer, fc.signFn
+	fc.lock.RUnlock()
+	if signFn == nil {
+		return nil, errUnauthorizedSigner
+	}
+
+	if err := fc.VerifyPendingHeader(chain, header); err != nil {
+		return nil, err
+	}
+	fsnap, err := fc.fsnapshot(chain, number-1, header.ParentHash, nil)
+	if err != nil {
+		return nil, err
+	}
+	if _, authorized := fsnap.Signers[signer]; !authorized {
+		return nil, errUnauthorizedSigner
+	}
+	return signFn(accounts.Account{Address: signer}, accounts.MimetypeClique, FConRLP(header))
+}
+
+// AddSignature adds the signature of another signer to the pending block.
+// The block is sent to the sealing results once it has been signed by the threshold of signers.
+func (fc *FConsensus) AddSignature(sealHash common.Hash, signature []byte) error {
+	fc.lock.Lock()
+	defer fc.lock.Unlock()
+	return fc.addSignature(sealHash, signature)
+}
+
+// PendingHeader returns the header of the block waiting for signatures, nil if there is none.
+func (fc *FConsensus) PendingHeader() *types.Header {
+	fc.lock.RLock()
+	defer fc.lock.RUnlock()
+	if fc.pending == nil {
+		return nil
+	}
+	header := fc.pending.block.Header()
+	if err := writeSeal(header, fc.pending.signatures); err != nil {
+		return nil
+	}
+	return header
+}
+
+// addSignature must be called with the lock held
+func (fc *FConsensus) addSignature(sealHash common.Hash, signature []byte) error {
+	p := fc.pending
+	if p == nil || p.sealHash != sealHash {
+		return errNoPendingBlock
+	}
+	select {
+	case <-p.stop:
+		fc.pending = nil
+		return errNoPendingBlock
+	default:
+	}
+	if len(signature) != ExtraSeal {
+		return errInvalidSeal
+	}
+	pubkey, err := crypto.Ecrecover(sealHash.Bytes(), signature)
+	if err != nil {
+		return err
+	}
+	var signer common.Address
+	copy(signer[:], crypto.Keccak256(pubkey[1:])[12:])
+	if _, ok := p.signers[signer]; !ok {
+		return errUnauthorizedSigner
+	}
+	if p.signed[signer] {
+		return errDuplicatedSigner
+	}
+	p.signed[signer] = true
+	p.signatures = append(p.signatures, signature)
+	if len(p.signatures) < p.threshold {
+		return nil
+	}
+
+	header := p.block.Header()
+	if err := writeSeal(header, p.signatures); err != nil {
+		return err
+	}
+	fc.pending = nil
 	go func() {
 		select {
-		case <-stop:
+		case <-p.stop:
 			return
 		default:
 
 		}
 		select {
-		case results <- block.WithSeal(header):
+		case p.results <- p.block.WithSeal(header):
 		default:
-			log.Warn("Sealing result is not read by miner", "sealhash", SealHash(header))
+			log.Warn("Sealing result is not read by miner", "sealhash", sealHash)
 		}
 	}()
 	return nil
@@ -520,34 +701,79 @@ func CalcDifficulty(snap *FSnapshot, signer common.Address) *big.Int {
 }
 
 func (fc *FConsensus) APIs(chain consensus.ChainReader) []rpc.API {
-	return nil
+	return []rpc.API{{
+		Namespace: "fconsensus",
+		Version:   "1.0",
+		Service:   &API{chain: chain, fconsensus: fc},
+		Public:    false,
+	}}
 }
 
 func (fc *FConsensus) Close() error {
 	return nil
 }
 
+// ecrecover returns the proposer of the block, who signed the first signature of the seal
 func ecrecover(header *types.Header, sigcache *lru.ARCCache) (common.Address, error) {
+	signers, err := ecrecoverSigners(header, sigcache)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return signers[0], nil
+}
+
+// ecrecoverSigners returns the signers of all the signatures of the seal, starting with the proposer
+func ecrecoverSigners(header *types.Header, sigcache *lru.ARCCache) ([]common.Address, error) {
 	hash := header.Hash()
-	if address, known := sigcache.Get(hash); known {
-		return address.(common.Address), nil
+	if signers, known := sigcache.Get(hash); known {
+		return signers.([]common.Address), nil
 	}
 
 	if len(header.Extra) < ExtraVanity {
-		return common.Address{}, errInvalidHeaderExtra
+		return nil, errInvalidHeaderExtra
 	}
 	fce, err := fconTypes.ExtractFConExtra(header)
 	if err != nil {
-		return common.Address{}, err
+		return nil, err
+	}
+	if len(fce.Seal) == 0 || len(fce.Seal)%ExtraSeal != 0 {
+		return nil, errInvalidSeal
+	}
+	var (
+		sealHash = SealHash(header).Bytes()
+		signers  = make([]common.Address, 0, len(fce.Seal)/ExtraSeal)
+		seen     = make(map[common.Address]bool)
+	)
+	for i := 0; i < len(fce.Seal); i += ExtraSeal {
+		pubkey, err := crypto.Ecrecover(sealHash, fce.Seal[i:i+ExtraSeal])
+		if err != nil {
+			return nil, err
+		}
+		var signer common.Address
+		copy(signer[:], crypto.Keccak256(pubkey[1:])[12:])
+		if seen[signer] {
+			return nil, errDuplicatedSigner
+		}
+		seen[signer] = true
+		signers = append(signers, signer)
 	}
-	pubkey, err := crypto.Ecrecover(SealHash(header).Bytes(), fce.Seal)
+	sigcache.Add(hash, signers)
+	return signers, nil
+}
+
+// writeSeal writes the concatenated signatures to the seal of the header
+func writeSeal(header *types.Header, signatures [][]byte) error {
+	fce, err := fconTypes.ExtractFConExtra(header)
 	if err != nil {
-		return common.Address{}, err
+		return err
 	}
-	var signer common.Address
-	copy(signer[:], crypto.Keccak256(pubkey[1:])[12:])
-	sigcache.Add(hash, signer)
-	return signer, nil
+	fce.Seal = bytes.Join(signatures, nil)
+	byteBuffer := new(bytes.Buffer)
+	if err := rlp.Encode(byteBuffer, &fce); err != nil {
+		return err
+	}
+	header.Extra = append(header.Extra[:ExtraVanity], byteBuffer.Bytes()...)
+	return nil
 }
 
 func FConRLP(header *types.Header) []byte {
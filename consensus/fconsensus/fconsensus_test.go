@@ -2,12 +2,15 @@ package fconsensus
 
 import (
 	"bytes"
+	"crypto/ecdsa"
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
 	"math/big"
 	"testing"
+	"time"
 
+	"github.com/Evrynetlabs/evrynet-node/accounts"
 	"github.com/Evrynetlabs/evrynet-node/common"
 	"github.com/Evrynetlabs/evrynet-node/common/hexutil"
 	"github.com/Evrynetlabs/evrynet-node/consensus/clique"
@@ -106,3 +109,169 @@ func TestExtractFConExtra(t *testing.T) {
 		t.Errorf("FConExtra.Conrrent not match, expect:%s, but get:%s", expect.String(), fce.CurrentBlock.String())
 	}
 }
+
+// testChainReader is a chain reader serving the headers of a single chain
+type testChainReader struct {
+	headers []*types.Header
+}
+
+func (c *testChainReader) Config() *params.ChainConfig { return params.FConsensusChainConfig }
+
+func (c *testChainReader) CurrentHeader() *types.Header { return c.headers[len(c.headers)-1] }
+
+func (c *testChainReader) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if header := c.GetHeaderByNumber(number); header != nil && header.Hash() == hash {
+		return header
+	}
+	return nil
+}
+
+func (c *testChainReader) GetHeaderByNumber(number uint64) *types.Header {
+	if number >= uint64(len(c.headers)) {
+		return nil
+	}
+	return c.headers[number]
+}
+
+func (c *testChainReader) GetHeaderByHash(hash common.Hash) *types.Header {
+	for _, header := range c.headers {
+		if header.Hash() == hash {
+			return header
+		}
+	}
+	return nil
+}
+
+func (c *testChainReader) GetBlock(hash common.Hash, number uint64) *types.Block {
+	if header := c.GetHeader(hash, number); header != nil {
+		return types.NewBlockWithHeader(header)
+	}
+	return nil
+}
+
+func newSignerFn(key *ecdsa.PrivateKey) SignerFn {
+	return func(account accounts.Account, mimeType string, data []byte) ([]byte, error) {
+		return crypto.Sign(crypto.Keccak256(data), key)
+	}
+}
+
+func newTestHeader(parent *types.Header) *types.Header {
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		UncleHash:  types.CalcUncleHash(nil),
+		Number:     new(big.Int).Add(parent.Number, common.Big1),
+		Difficulty: new(big.Int).Set(diffInTurn),
+		Time:       parent.Time + 1,
+		Extra:      make([]byte, ExtraVanity),
+	}
+	extra, _ := rlp.EncodeToBytes(&fconTypes.FConExtra{})
+	header.Extra = append(header.Extra, extra...)
+	return header
+}
+
+func TestThresholdSeal(t *testing.T) {
+	var (
+		keys    = make([]*ecdsa.PrivateKey, 4)
+		signers = make([]common.Address, len(keys))
+	)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		signers[i] = crypto.PubkeyToAddress(keys[i].PublicKey)
+	}
+	var (
+		genesis = &types.Header{Number: common.Big0, Difficulty: common.Big1, Extra: make([]byte, ExtraVanity)}
+		chain   = &testChainReader{headers: []*types.Header{genesis}}
+		config  = &params.FConConfig{Epoch: 30000, Signers: signers[:3], Threshold: 2, ThresholdBlock: common.Big0}
+		engine  = New(config, rawdb.NewMemoryDatabase())
+		sign    = func(header *types.Header, keys ...*ecdsa.PrivateKey) *types.Header {
+			var signatures [][]byte
+			for _, key := range keys {
+				signature, _ := crypto.Sign(SealHash(header).Bytes(), key)
+				signatures = append(signatures, signature)
+			}
+			if err := writeSeal(header, signatures); err != nil {
+				t.Fatal(err)
+			}
+			return header
+		}
+	)
+	tests := []struct {
+		keys []*ecdsa.PrivateKey
+		err  error
+	}{
+		{keys: keys[:2], err: nil},
+		{keys: keys[:3], err: nil},
+		{keys: keys[:1], err: errInsufficientSignatures},
+		{keys: []*ecdsa.PrivateKey{keys[0], keys[3]}, err: errUnauthorizedSigner},
+		{keys: []*ecdsa.PrivateKey{keys[1], keys[1]}, err: errDuplicatedSigner},
+	}
+	for i, test := range tests {
+		header := sign(newTestHeader(genesis), test.keys...)
+		if err := engine.VerifyHeader(chain, header, true); err != test.err {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, test.err)
+		}
+	}
+	// before the threshold fork the blocks are sealed by their proposer only
+	legacy := New(&params.FConConfig{Epoch: 30000, Signers: signers[:3], Threshold: 2, ThresholdBlock: common.Big2},
+		rawdb.NewMemoryDatabase())
+	if err := legacy.VerifyHeader(chain, sign(newTestHeader(genesis), keys[0]), true); err != nil {
+		t.Errorf("failed to verify the block sealed before the threshold fork: %v", err)
+	}
+
+	// the proposer seals the block once another signer added its signature
+	engine.Authorize(signers[0], newSignerFn(keys[0]))
+	cosigner := New(config, rawdb.NewMemoryDatabase())
+	cosigner.Authorize(signers[1], newSignerFn(keys[1]))
+
+	var (
+		results = make(chan *types.Block, 1)
+		stop    = make(chan struct{})
+		header  = newTestHeader(genesis)
+		events  = make(chan PendingSealEvent, 1)
+	)
+	defer close(stop)
+	sub := engine.SubscribePendingSeal(events)
+	defer sub.Unsubscribe()
+	if err := engine.Seal(chain, types.NewBlockWithHeader(header), results, stop); err != nil {
+		t.Fatal(err)
+	}
+	pending := engine.PendingHeader()
+	if pending == nil {
+		t.Fatal("no pending block waiting for signatures")
+	}
+	select {
+	case ev := <-events:
+		if ev.Header.Hash() != pending.Hash() {
+			t.Errorf("pending header mismatch: have %x, want %x", ev.Header.Hash(), pending.Hash())
+		}
+	default:
+		t.Fatal("the pending block is not posted")
+	}
+	if err := cosigner.VerifyPendingHeader(chain, pending); err != nil {
+		t.Fatalf("failed to verify the pending block: %v", err)
+	}
+	signature, err := cosigner.SignHeader(chain, pending)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.VerifySignature(chain, SealHash(pending), signature); err != nil {
+		t.Fatalf("failed to verify the signature: %v", err)
+	}
+	if err := engine.AddSignature(SealHash(pending), signature); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case block := <-results:
+		if err := engine.VerifyHeader(chain, block.Header(), true); err != nil {
+			t.Errorf("failed to verify the sealed block: %v", err)
+		}
+		if author, _ := engine.Author(block.Header()); author != signers[0] {
+			t.Errorf("author mismatch: have %x, want %x", author, signers[0])
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the block is not sealed")
+	}
+	if err := engine.AddSignature(SealHash(pending), signature); err != errNoPendingBlock {
+		t.Errorf("error mismatch: have %v, want %v", err, errNoPendingBlock)
+	}
+}
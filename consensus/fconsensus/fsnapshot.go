@@ -9,6 +9,7 @@ import (
 	"github.com/Evrynetlabs/evrynet-node/log"
 	"github.com/Evrynetlabs/evrynet-node/params"
 	lru "github.com/hashicorp/golang-lru"
+	"math/big"
 	"sort"
 	"time"
 )
@@ -117,12 +118,10 @@ func (fs *FSnapshot) cast(address common.Address, authorize bool) bool {
 		return false
 	}
 	if old, ok := fs.FTallys[address]; ok {
-		if ok {
-			old.Votes++
-			fs.FTallys[address] = old
-		} else {
-			fs.FTallys[address] = FTally{Authorize: authorize, Votes: 1}
-		}
+		old.Votes++
+		fs.FTallys[address] = old
+	} else {
+		fs.FTallys[address] = FTally{Authorize: authorize, Votes: 1}
 	}
 	return true
 }
@@ -204,7 +203,7 @@ func (fs *FSnapshot) apply(headers []*types.Header) (*FSnapshot, error) {
 				}
 				for i := 0; i < len(fsnap.FVotes); i++ {
 					if fsnap.FVotes[i].Signer == header.Coinbase {
-						fsnap.uncast(fsnap.FVotes[i].Signer, fsnap.FVotes[i].Authorize)
+						fsnap.uncast(fsnap.FVotes[i].Address, fsnap.FVotes[i].Authorize)
 						fsnap.FVotes = append(fsnap.FVotes[:i], fsnap.FVotes[i+1:]...)
 						i--
 					}
@@ -257,6 +256,20 @@ func (fs *FSnapshot) signers() []common.Address {
 	return sigs
 }
 
+// threshold returns the number of signatures required to seal the block number.
+// It is the configured threshold once the threshold fork is active, never more than the number of signers,
+// and 1 before, i.e. the block is sealed by its proposer only.
+func (fs *FSnapshot) threshold(number uint64) int {
+	if !fs.config.IsThreshold(new(big.Int).SetUint64(number)) {
+		return 1
+	}
+	threshold := int(fs.config.Threshold)
+	if threshold > len(fs.Signers) {
+		threshold = len(fs.Signers)
+	}
+	return threshold
+}
+
 func (fs *FSnapshot) inturn(number uint64, signer common.Address) bool {
 	signers, offset := fs.signers(), 0
 	for offset < len(signers) && signers[offset] != signer {
@@ -0,0 +1,132 @@
+package fconsensus
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/consensus"
+	fconTypes "github.com/Evrynetlabs/evrynet-node/consensus/fconsensus/types"
+	"github.com/Evrynetlabs/evrynet-node/core/types"
+	"github.com/Evrynetlabs/evrynet-node/log"
+	"github.com/Evrynetlabs/evrynet-node/params"
+)
+
+var (
+	// errUnknownPackedBlock is returned if the main chain block packed by a final chain block is not found.
+	errUnknownPackedBlock = errors.New("unknown packed main chain block")
+
+	// errUnknownSectionBlock is returned if a main chain block of a section is not found.
+	errUnknownSectionBlock = errors.New("unknown main chain block in section")
+)
+
+// SectionPlanner plans the sections of main chain blocks packed into the final chain blocks.
+//
+// A section is planned once the main chain head is at least Size+Distance blocks after the latest
+// packed block, it packs at least Size blocks and all the blocks up to Distance blocks behind the head,
+// so that a final chain that fell behind catches up in a few blocks. The blocks packed at once are
+// limited by MaxBlocks, by the gas of the final block and by the MaxGas and MaxSize budgets.
+type SectionPlanner struct {
+	config         *params.ChainConfig       // Chain config of the final chain
+	mainChain      consensus.ChainReader     // Main chain to pack the blocks from
+	defaultPacking *params.FConPackingConfig // Packing window used until a configured window is activated
+}
+
+// NewSectionPlanner creates a section planner of the final chain configured by config,
+// which packs the blocks with defaultPacking until a configured packing window is activated.
+func NewSectionPlanner(config *params.ChainConfig, mainChain consensus.ChainReader, defaultPacking *params.FConPackingConfig) *SectionPlanner {
+	return &SectionPlanner{
+		config:         config,
+		mainChain:      mainChain,
+		defaultPacking: defaultPacking,
+	}
+}
+
+// PackedHeight returns the number of the latest main chain block packed by the final chain up to header.
+func (p *SectionPlanner) PackedHeight(header *types.Header) (uint64, error) {
+	if header.Number.Uint64() == 0 {
+		return 0, nil
+	}
+	fce, err := fconTypes.ExtractFConExtra(header)
+	if err != nil {
+		return 0, err
+	}
+	// the final blocks packed before the height was recorded only have the hash of the packed block
+	if fce.CurrentHeight == 0 && fce.CurrentBlock != (common.Hash{}) {
+		packed := p.mainChain.GetHeaderByHash(fce.CurrentBlock)
+		if packed == nil {
+			return 0, errUnknownPackedBlock
+		}
+		return packed.Number.Uint64(), nil
+	}
+	return fce.CurrentHeight, nil
+}
+
+// Plan returns the section [begin, end] of main chain blocks to pack into the final block after parent,
+// whose gas limit is gasLimit. It returns false if no section is ready to be packed yet.
+func (p *SectionPlanner) Plan(parent *types.Header, gasLimit uint64) (uint64, uint64, bool, error) {
+	packed, err := p.PackedHeight(parent)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	var (
+		window = p.config.FConPackingOrDefault(new(big.Int).Add(parent.Number, common.Big1), p.defaultPacking)
+		head   = p.mainChain.CurrentHeader().Number.Uint64()
+	)
+	if packed+window.Size+window.Distance > head {
+		return 0, 0, false, nil
+	}
+	end := packed + window.Size
+	if end < head-window.Distance {
+		end = head - window.Distance
+	}
+	if window.MaxBlocks > 0 && end > packed+window.MaxBlocks {
+		end = packed + window.MaxBlocks
+	}
+	gasBudget := gasLimit
+	if window.MaxGas > 0 && window.MaxGas < gasBudget {
+		gasBudget = window.MaxGas
+	}
+	var gas, size uint64
+	for number := packed + 1; number <= end; number++ {
+		block := p.mainChain.GetBlock(p.blockHash(number), number)
+		if block == nil {
+			return 0, 0, false, errUnknownSectionBlock
+		}
+		gas += block.GasUsed()
+		size += uint64(block.Size())
+		// the transactions of the block need their gas limit to be available when they are applied
+		if gas-block.GasUsed()+peakGas(block) > gasBudget || (window.MaxSize > 0 && size > window.MaxSize) {
+			end = number - 1
+			if number == packed+1 {
+				// the first block is packed anyway, the final chain would stall otherwise
+				log.Warn("Main chain block exceeds the final block budget", "number", number, "gas", block.GasUsed(), "size", block.Size())
+				end = number
+			}
+			break
+		}
+	}
+	return packed + 1, end, true, nil
+}
+
+// blockHash returns the hash of the canonical main chain block number
+func (p *SectionPlanner) blockHash(number uint64) common.Hash {
+	if header := p.mainChain.GetHeaderByNumber(number); header != nil {
+		return header.Hash()
+	}
+	return common.Hash{}
+}
+
+// peakGas returns an upper bound of the gas pool used while applying the transactions of the block
+func peakGas(block *types.Block) uint64 {
+	var maxGas uint64
+	for _, tx := range block.Transactions() {
+		if tx.Gas() > maxGas {
+			maxGas = tx.Gas()
+		}
+	}
+	if peak := block.GasUsed() + maxGas; peak < block.GasLimit() {
+		return peak
+	}
+	return block.GasLimit()
+}
@@ -0,0 +1,92 @@
+package fconsensus
+
+import (
+	"math/big"
+	"testing"
+
+	fconTypes "github.com/Evrynetlabs/evrynet-node/consensus/fconsensus/types"
+	"github.com/Evrynetlabs/evrynet-node/core/types"
+	"github.com/Evrynetlabs/evrynet-node/params"
+	"github.com/Evrynetlabs/evrynet-node/rlp"
+)
+
+// finalHeader returns a final chain header which packed the main chain blocks up to height
+func finalHeader(t *testing.T, number, height uint64) *types.Header {
+	extra, err := rlp.EncodeToBytes(&fconTypes.FConExtra{CurrentHeight: height})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &types.Header{
+		Number: new(big.Int).SetUint64(number),
+		Extra:  append(make([]byte, fconTypes.ExtraVanity), extra...),
+	}
+}
+
+func TestSectionPlanner(t *testing.T) {
+	// a main chain of 100 blocks using 1000 gas each
+	mainChain := &testChainReader{}
+	for i := 0; i <= 100; i++ {
+		mainChain.headers = append(mainChain.headers, &types.Header{Number: big.NewInt(int64(i)), GasLimit: 10000, GasUsed: 1000})
+	}
+	config := &params.ChainConfig{IsFinalChain: true, FConsensus: &params.FConConfig{
+		Packing: []*params.FConPackingConfig{
+			{Block: big.NewInt(10), Size: 2, Distance: 3, MaxBlocks: 20},
+			{Block: big.NewInt(20), Size: 2, Distance: 3, MaxSize: 3000},
+		},
+	}}
+	planner := NewSectionPlanner(config, mainChain, params.DefaultFConPacking)
+
+	tests := []struct {
+		number, height uint64 // final chain parent and the main chain block it packed
+		gasLimit       uint64
+		begin, end     uint64
+		trigger        bool
+	}{
+		// default window of 2 blocks, 2 blocks behind the head
+		{number: 0, height: 0, gasLimit: 1000000, begin: 1, end: 98, trigger: true},
+		{number: 1, height: 97, gasLimit: 1000000, trigger: false},
+		{number: 1, height: 96, gasLimit: 1000000, begin: 97, end: 98, trigger: true},
+		// catching up is limited by the gas of the final block
+		{number: 1, height: 0, gasLimit: 10500, begin: 1, end: 10, trigger: true},
+		{number: 1, height: 0, gasLimit: 30000, begin: 1, end: 30, trigger: true},
+		// a block is packed even if it exceeds the gas of the final block
+		{number: 1, height: 0, gasLimit: 500, begin: 1, end: 1, trigger: true},
+		// the first configured window limits the number of blocks
+		{number: 9, height: 0, gasLimit: 1000000, begin: 1, end: 20, trigger: true},
+		{number: 9, height: 96, gasLimit: 1000000, trigger: false},
+		{number: 9, height: 95, gasLimit: 1000000, begin: 96, end: 97, trigger: true},
+		// the second configured window limits the size of the blocks
+		{number: 19, height: 0, gasLimit: 1000000, begin: 1, trigger: true},
+	}
+	size := uint64(types.NewBlockWithHeader(mainChain.headers[1]).Size())
+	tests[len(tests)-1].end = 3000 / size
+
+	for i, tt := range tests {
+		begin, end, trigger, err := planner.Plan(finalHeader(t, tt.number, tt.height), tt.gasLimit)
+		if err != nil {
+			t.Fatalf("test %d: failed to plan section: %v", i, err)
+		}
+		if trigger != tt.trigger || begin != tt.begin || end != tt.end {
+			t.Errorf("test %d: section mismatch: have [%d, %d] %v, want [%d, %d] %v", i, begin, end, trigger, tt.begin, tt.end, tt.trigger)
+		}
+	}
+}
+
+func TestFConPacking(t *testing.T) {
+	first := &params.FConPackingConfig{Block: big.NewInt(10), Size: 1, Distance: 1}
+	second := &params.FConPackingConfig{Block: big.NewInt(20), Size: 2, Distance: 2}
+	config := &params.ChainConfig{FConsensus: &params.FConConfig{Packing: []*params.FConPackingConfig{second, first}}}
+
+	for number, want := range map[int64]*params.FConPackingConfig{
+		0:  params.DefaultFConPacking,
+		9:  params.DefaultFConPacking,
+		10: first,
+		19: first,
+		20: second,
+		50: second,
+	} {
+		if have := config.FConPacking(big.NewInt(number)); have != want {
+			t.Errorf("block %d: packing window mismatch: have %+v, want %+v", number, have, want)
+		}
+	}
+}
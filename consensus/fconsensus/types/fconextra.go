@@ -20,6 +20,8 @@ type FConExtra struct {
 	CurrentHeight uint64
 	EvilHeader    *types.Header
 	Signers       []common.Address
+	// FraudProof is the hash of the fraud proof of the evil header, it is only encoded if there is an evil header
+	FraudProof common.Hash
 }
 
 func (fce *FConExtra) EncodeRLP(w io.Writer) error {
@@ -27,13 +29,17 @@ func (fce *FConExtra) EncodeRLP(w io.Writer) error {
 	if err != nil {
 		return err
 	}
-	return rlp.Encode(w, []interface{}{
+	fields := []interface{}{
 		fce.Seal,
 		fce.CurrentBlock,
 		fce.CurrentHeight,
 		headerRLP,
 		fce.Signers,
-	})
+	}
+	if fce.FraudProof != (common.Hash{}) {
+		fields = append(fields, fce.FraudProof)
+	}
+	return rlp.Encode(w, fields)
 }
 
 func (fce *FConExtra) DecodeRLP(s *rlp.Stream) error {
@@ -43,11 +49,15 @@ func (fce *FConExtra) DecodeRLP(s *rlp.Stream) error {
 		CurrentHeight uint64
 		EvilBytes     []byte
 		Signers       []common.Address
+		Rest          []common.Hash `rlp:"tail"`
 	}
 	if err := s.Decode(&extra); err != nil {
 		return err
 	}
 	fce.Seal, fce.CurrentBlock, fce.CurrentHeight, fce.Signers = extra.Seal, extra.CurrentBlock, extra.CurrentHeight, extra.Signers
+	if len(extra.Rest) > 0 {
+		fce.FraudProof = extra.Rest[0]
+	}
 
 	if len(extra.EvilBytes) > 1 {
 		var header types.Header
@@ -21,6 +21,9 @@ type Backend interface {
 	// Sign signs input data with the backend's private key
 	Sign([]byte) ([]byte, error)
 
+	// SignBLS signs input data with the backend's BLS key, the signature can be aggregated with the other validators' ones
+	SignBLS([]byte) ([]byte, error)
+
 	// Gossip sends a message to all validators (exclude self)
 	// these message are send via p2p network interface.
 	Gossip(valSet ValidatorSet, blockNumber *big.Int, round int64, msgType uint64, payload []byte) error
@@ -57,4 +60,8 @@ type Backend interface {
 	// VerifyProposalBlock verify post-processor state of proposal block (txs, Root, receipt).
 	// If success, the result will be send to the pending tasks of miner
 	VerifyProposalBlock(block *types.Block) error
+
+	// ReportEvidence adds an evidence of double signing to the pending pool, so that it will be included in a proposal block.
+	// It returns false if the evidence was already known.
+	ReportEvidence(evidence *types.TendermintEvidence) bool
 }
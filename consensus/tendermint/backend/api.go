@@ -1,10 +1,30 @@
 package backend
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"math/big"
 
 	"github.com/Evrynetlabs/evrynet-node/common"
 	"github.com/Evrynetlabs/evrynet-node/consensus"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint"
+	"github.com/Evrynetlabs/evrynet-node/core/rawdb"
+	"github.com/Evrynetlabs/evrynet-node/core/types"
+	"github.com/Evrynetlabs/evrynet-node/rpc"
+)
+
+// roundStepChanSize is the size of channel listening to the step transitions of core
+const roundStepChanSize = 64
+
+// maxRewardsEpochRange is the maximum number of epochs whose rewards are returned by a single request
+const maxRewardsEpochRange = 1000
+
+var (
+	errRewardsWithFixedValidators = errors.New("rewards are credited to the proposers with fixed validators")
+	errNoChainState               = errors.New("chain state is not available")
+	errInvalidEpochRange          = errors.New("invalid epoch range")
+	errTooManyRewardsEpochs       = errors.New("too many epochs requested")
 )
 
 // TendermintAPI is a user facing RPC API to dump tendermint state
@@ -30,3 +50,102 @@ func (api *TendermintAPI) GetValidators(number *uint64) []common.Address {
 	}
 	return validators
 }
+
+// GetRewards returns the reward ledger records of the epochs from fromEpoch to toEpoch which involve the address,
+// as a validator, an owner or a voter. The epoch e ends at block e*Epoch of the canonical chain.
+// The ledger of an epoch is read from the database if it was stored when its last block was committed or inserted,
+// otherwise it is computed again from the state of the epoch, which fails if the state is not available.
+func (api *TendermintAPI) GetRewards(address common.Address, fromEpoch, toEpoch uint64) ([]*types.ValidatorReward, error) {
+	config := api.chain.Config().Tendermint
+	if len(config.FixedValidators) > 0 {
+		return nil, errRewardsWithFixedValidators
+	}
+	if fromEpoch == 0 {
+		fromEpoch = 1
+	}
+	if toEpoch < fromEpoch {
+		return nil, errInvalidEpochRange
+	}
+	if toEpoch-fromEpoch >= maxRewardsEpochRange {
+		return nil, errTooManyRewardsEpochs
+	}
+	rewards := make([]*types.ValidatorReward, 0)
+	for epoch := fromEpoch; epoch <= toEpoch; epoch++ {
+		header := api.chain.GetHeaderByNumber(epoch * config.Epoch)
+		if header == nil {
+			break
+		}
+		ledger, err := api.epochRewards(header)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the rewards of epoch %d: %v", epoch, err)
+		}
+		for _, reward := range ledger {
+			if reward.Involves(address) {
+				rewards = append(rewards, reward)
+			}
+		}
+	}
+	return rewards, nil
+}
+
+// epochRewards returns the reward ledger of the epoch ended by the header, from the database or the state of the epoch
+func (api *TendermintAPI) epochRewards(header *types.Header) ([]*types.ValidatorReward, error) {
+	if api.be.db != nil {
+		if ledger := rawdb.ReadEpochRewards(api.be.db, header.Hash()); ledger != nil {
+			return ledger, nil
+		}
+	}
+	chain, ok := api.chain.(consensus.FullChainReader)
+	if !ok {
+		return nil, errNoChainState
+	}
+	return api.be.epochRewards(chain, header)
+}
+
+// DumpConsensusState returns the consensus state of the running core: the height, round and step, the proposal received,
+// the locked and valid blocks, the timeout pending and which validators prevoted and precommitted for which hash at each round.
+func (api *TendermintAPI) DumpConsensusState() (*types.ConsensusState, error) {
+	if !api.be.isCoreStarted() {
+		return nil, tendermint.ErrStoppedEngine
+	}
+	return api.be.core.ConsensusState()
+}
+
+// GetRoundVotes returns which validators prevoted and precommitted for which hash at the round of the block number.
+// The votes are only kept for the block number the running core is at.
+func (api *TendermintAPI) GetRoundVotes(number uint64, round int64) (*types.RoundVotes, error) {
+	if !api.be.isCoreStarted() {
+		return nil, tendermint.ErrStoppedEngine
+	}
+	return api.be.core.RoundVotes(number, round)
+}
+
+// RoundSteps creates a subscription that fires each time the consensus state machine moves to another step.
+func (api *TendermintAPI) RoundSteps(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		steps := make(chan types.RoundStep, roundStepChanSize)
+		stepsSub := api.be.core.SubscribeRoundStep(steps)
+
+		for {
+			select {
+			case step := <-steps:
+				notifier.Notify(rpcSub.ID, step)
+			case <-rpcSub.Err():
+				stepsSub.Unsubscribe()
+				return
+			case <-notifier.Closed():
+				stepsSub.Unsubscribe()
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
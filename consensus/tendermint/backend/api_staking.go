@@ -0,0 +1,180 @@
+package backend
+
+import (
+	"errors"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/common/hexutil"
+	"github.com/Evrynetlabs/evrynet-node/consensus"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint"
+	"github.com/Evrynetlabs/evrynet-node/core/state"
+	"github.com/Evrynetlabs/evrynet-node/core/state/staking"
+	"github.com/Evrynetlabs/evrynet-node/core/types"
+	"github.com/Evrynetlabs/evrynet-node/rpc"
+)
+
+var errStakingWithFixedValidators = errors.New("staking contract is not used with fixed validators")
+
+// StakingAPI is a user facing RPC API to read the state of the staking contract.
+// The state is read directly from the state DB at the given block, the latest one if it is omitted.
+type StakingAPI struct {
+	chain consensus.ChainReader
+	be    *Backend
+}
+
+// GetInfo returns the configuration of the staking contract
+func (api *StakingAPI) GetInfo(blockNr *rpc.BlockNumber) (*types.StakingInfo, error) {
+	reader, _, _, err := api.stakingReader(blockNr)
+	if err != nil {
+		return nil, err
+	}
+	scAddress := api.be.stakingContractAddr
+	return &types.StakingInfo{
+		Contract:          scAddress,
+		Admin:             reader.GetAdmin(scAddress),
+		StartBlock:        (*hexutil.Big)(reader.GetStartBlock(scAddress)),
+		EpochPeriod:       (*hexutil.Big)(reader.GetEpochPeriod(scAddress)),
+		MaxValidatorSize:  (*hexutil.Big)(reader.GetMaxValidatorSize(scAddress)),
+		MinValidatorStake: (*hexutil.Big)(reader.GetMinValidatorStake(scAddress)),
+		MinVoterCap:       (*hexutil.Big)(reader.GetMinVoterCap(scAddress)),
+	}, nil
+}
+
+// GetCandidates returns the candidates with their owner and total stake
+func (api *StakingAPI) GetCandidates(blockNr *rpc.BlockNumber) ([]*types.StakingCandidate, error) {
+	reader, stateDB, header, err := api.stakingReader(blockNr)
+	if err != nil {
+		return nil, err
+	}
+	scAddress := api.be.stakingContractAddr
+	candidates, err := api.candidates(reader)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*types.StakingCandidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		data := reader.GetCandidateData(scAddress, candidate)
+		result = append(result, &types.StakingCandidate{
+			Address:        candidate,
+			Owner:          data.Owner,
+			TotalStake:     (*hexutil.Big)(data.TotalStake),
+			Voters:         hexutil.Uint64(len(data.VoterStakes)),
+			CommissionRate: (*hexutil.Uint64)(data.CommissionRate),
+			Jailed:         staking.IsJailed(stateDB, scAddress, candidate, header.Number.Uint64()),
+		})
+	}
+	return result, nil
+}
+
+// GetVoters returns the voters of a candidate with their stakes
+func (api *StakingAPI) GetVoters(candidate common.Address, blockNr *rpc.BlockNumber) ([]*types.StakingVote, error) {
+	reader, _, _, err := api.stakingReader(blockNr)
+	if err != nil {
+		return nil, err
+	}
+	scAddress := api.be.stakingContractAddr
+	voters := reader.GetVoters(scAddress, candidate)
+	result := make([]*types.StakingVote, 0, len(voters))
+	for _, voter := range voters {
+		result = append(result, &types.StakingVote{
+			Candidate: candidate,
+			Voter:     voter,
+			Stake:     (*hexutil.Big)(reader.GetVoterStake(scAddress, candidate, voter)),
+		})
+	}
+	return result, nil
+}
+
+// GetVoterStakes returns the stakes of a voter across the candidates
+func (api *StakingAPI) GetVoterStakes(voter common.Address, blockNr *rpc.BlockNumber) ([]*types.StakingVote, error) {
+	reader, _, _, err := api.stakingReader(blockNr)
+	if err != nil {
+		return nil, err
+	}
+	scAddress := api.be.stakingContractAddr
+	candidates, err := api.candidates(reader)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*types.StakingVote, 0)
+	for _, candidate := range candidates {
+		stake := reader.GetVoterStake(scAddress, candidate, voter)
+		if stake.Sign() == 0 {
+			continue
+		}
+		result = append(result, &types.StakingVote{
+			Candidate: candidate,
+			Voter:     voter,
+			Stake:     (*hexutil.Big)(stake),
+		})
+	}
+	return result, nil
+}
+
+// GetWithdrawals returns the unvoted stakes of an address which are not withdrawn yet, by epoch
+func (api *StakingAPI) GetWithdrawals(address common.Address, blockNr *rpc.BlockNumber) ([]*types.StakingWithdrawal, error) {
+	reader, _, _, err := api.stakingReader(blockNr)
+	if err != nil {
+		return nil, err
+	}
+	scAddress := api.be.stakingContractAddr
+	epochs := reader.GetWithdrawEpochs(scAddress, address)
+	result := make([]*types.StakingWithdrawal, 0, len(epochs))
+	for _, epoch := range epochs {
+		amount := reader.GetWithdrawCap(scAddress, address, epoch)
+		if amount.Sign() == 0 {
+			continue
+		}
+		result = append(result, &types.StakingWithdrawal{
+			Epoch:  (*hexutil.Big)(epoch),
+			Amount: (*hexutil.Big)(amount),
+		})
+	}
+	return result, nil
+}
+
+// GetNextValidators returns the validator set elected by the state of the block,
+// which becomes the validator set of the next epoch if the block is an epoch transition
+func (api *StakingAPI) GetNextValidators(blockNr *rpc.BlockNumber) ([]common.Address, error) {
+	reader, stateDB, header, err := api.stakingReader(blockNr)
+	if err != nil {
+		return nil, err
+	}
+	validators, err := reader.GetValidators(api.be.stakingContractAddr)
+	if err != nil {
+		return nil, err
+	}
+	return api.be.filterJailedValidators(stateDB, validators, header.Number.Uint64()), nil
+}
+
+// stakingReader returns a reader of the staking contract at the state of the block, the latest one if it is nil
+func (api *StakingAPI) stakingReader(blockNr *rpc.BlockNumber) (staking.StakingReader, *state.StateDB, *types.Header, error) {
+	if len(api.be.config.FixedValidators) > 0 {
+		return nil, nil, nil, errStakingWithFixedValidators
+	}
+	chain, ok := api.chain.(consensus.FullChainReader)
+	if !ok {
+		return nil, nil, nil, errNoChainState
+	}
+	header := chain.CurrentHeader()
+	if blockNr != nil && *blockNr >= 0 {
+		header = chain.GetHeaderByNumber(uint64(*blockNr))
+	}
+	if header == nil {
+		return nil, nil, nil, tendermint.ErrUnknownBlock
+	}
+	stateDB, err := chain.StateAt(header.Root)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return staking.NewStateDbStakingCaller(stateDB, api.be.config.IndexStateVariables), stateDB, header, nil
+}
+
+// candidates returns the registered candidates, an empty list if there is none
+func (api *StakingAPI) candidates(reader staking.StakingReader) ([]common.Address, error) {
+	candidates, err := reader.GetCandidates(api.be.stakingContractAddr)
+	if err == staking.ErrEmptyValidatorSet {
+		return []common.Address{}, nil
+	}
+	return candidates, err
+}
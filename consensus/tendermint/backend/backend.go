@@ -17,8 +17,9 @@ import (
 	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/backend/fixed_valset_info"
 	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/backend/staking"
 	tendermintCore "github.com/Evrynetlabs/evrynet-node/consensus/tendermint/core"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/signer"
 	"github.com/Evrynetlabs/evrynet-node/core/types"
-	"github.com/Evrynetlabs/evrynet-node/crypto"
+	"github.com/Evrynetlabs/evrynet-node/crypto/bls"
 	"github.com/Evrynetlabs/evrynet-node/event"
 	"github.com/Evrynetlabs/evrynet-node/evrdb"
 	"github.com/Evrynetlabs/evrynet-node/log"
@@ -34,6 +35,10 @@ const (
 	broadcastSleepTimeIncreament = time.Millisecond * 100
 	inMemoryValset               = 10
 	inMemoryProposer             = 100
+	inMemoryRelayedMsgs          = 4096
+	inMemoryProposedRewards      = 16
+	inMemoryFinalizedRewards     = 16
+	relayChanSize                = 256 // relayChanSize is the number of messages waiting to be relayed by the sentry, the others are dropped
 )
 
 var (
@@ -44,16 +49,42 @@ var (
 //Option return an optional function for backend's initial behaviour
 type Option func(b *Backend) error
 
+//WithSigner returns an option to sign the consensus messages and seals with the signer instead of the private key,
+//the private key is then only used as the node key
+func WithSigner(signer tendermint.Signer) Option {
+	return func(b *Backend) error {
+		b.signer = signer
+		return nil
+	}
+}
+
+//WithBLSKey returns an option to sign the BLS committed seals with the key, it is ignored if the signer is set by WithSigner
+func WithBLSKey(key *bls.SecretKey) Option {
+	return func(b *Backend) error {
+		b.blsKey = key
+		return nil
+	}
+}
+
+//WithDB returns an option to set the database, which core uses to persist its state and sent messages
+func WithDB(db evrdb.Database) Option {
+	return func(b *Backend) error {
+		b.db = db
+		return nil
+	}
+}
+
 // New creates an backend for Istanbul core engine.
 // The p2p communication, i.e, broadcaster is set separately by calling backend.SetBroadcaster
 func New(config *tendermint.Config, privateKey *ecdsa.PrivateKey, opts ...Option) consensus.Tendermint {
 	valSetCache, _ := lru.NewARC(inMemoryValset)
 	proposerCache, _ := lru.NewARC(inMemoryProposer)
+	relayedMsgs, _ := lru.New(inMemoryRelayedMsgs)
+	proposedRewards, _ := lru.NewARC(inMemoryProposedRewards)
+	finalizedRewards, _ := lru.NewARC(inMemoryFinalizedRewards)
 	be := &Backend{
 		config:                     config,
 		tendermintEventMux:         new(event.TypeMux),
-		privateKey:                 privateKey,
-		address:                    crypto.PubkeyToAddress(privateKey.PublicKey),
 		commitChs:                  newCommitChannels(),
 		mutex:                      &sync.RWMutex{},
 		storingMsgs:                queue.NewFIFO(),
@@ -62,6 +93,14 @@ func New(config *tendermint.Config, privateKey *ecdsa.PrivateKey, opts ...Option
 		controlChan:                make(chan struct{}),
 		computedValSetCache:        valSetCache,
 		blockProposerCache:         proposerCache,
+		proposedRewards:            proposedRewards,
+		finalizedRewards:           finalizedRewards,
+		evidences:                  newEvidencePool(),
+		validatorNodes:             newValidatorNodes(config.ValidatorNodes),
+		sentries:                   nodeAddresses(config.Sentries),
+		privateValidators:          nodeAddresses(config.PrivateValidators),
+		relayedMsgs:                relayedMsgs,
+		relayCh:                    make(chan relayedMsg, relayChanSize),
 	}
 
 	if config.FixedValidators != nil && len(config.FixedValidators) > 0 {
@@ -73,15 +112,26 @@ func New(config *tendermint.Config, privateKey *ecdsa.PrivateKey, opts ...Option
 		}
 		be.stakingContractAddr = *config.StakingSCAddress
 	}
-	be.core = tendermintCore.New(be, config)
-
 	for _, opt := range opts {
 		if err := opt(be); err != nil {
 			log.Error("error at initialization of backend", err)
 		}
 	}
+	if be.signer == nil {
+		be.signer = signer.NewLocalSigner(privateKey, be.blsKey)
+	}
+	be.address = be.signer.Address()
+
+	var coreOpts []tendermintCore.Option
+	if be.db != nil {
+		coreOpts = append(coreOpts, tendermintCore.WithDB(be.db))
+	}
+	be.core = tendermintCore.New(be, config, coreOpts...)
 
 	go be.dequeueMsgLoop()
+	if len(be.privateValidators) > 0 {
+		go be.relayLoop()
+	}
 	return be
 }
 
@@ -94,7 +144,8 @@ func (sb *Backend) SetBroadcaster(broadcaster consensus.Broadcaster) {
 type Backend struct {
 	config             *tendermint.Config
 	tendermintEventMux *event.TypeMux
-	privateKey         *ecdsa.PrivateKey
+	signer             tendermint.Signer // signer holds the keys of the validator, they may be held by another process
+	blsKey             *bls.SecretKey    // blsKey is the BLS key of the local signer
 	core               tendermintCore.Engine
 	db                 evrdb.Database
 	broadcaster        consensus.Broadcaster
@@ -124,6 +175,18 @@ type Backend struct {
 	computedValSetCache *lru.ARCCache  // computedValSetCache stores the valset is computed from stateDB
 
 	blockProposerCache *lru.ARCCache // blockProposerCache stores the address of proposal block
+	proposedRewards    *lru.ARCCache // proposedRewards stores the reward ledgers of the epoch blocks proposed by this node until they are committed
+	finalizedRewards   *lru.ARCCache // finalizedRewards stores the reward ledgers of the epoch blocks finalized by this node until they are committed or inserted
+
+	evidences *evidencePool // evidences stores the evidences of double signing waiting to be included in a block
+
+	commitTimer commitTimer // commitTimer measures the time to insert the blocks committed by core
+
+	validatorNodes    map[common.Address][]common.Address // validatorNodes are the nodes of the validators which do not sign with their node key or are behind sentries
+	sentries          map[common.Address]bool             // sentries are the node addresses of the sentries of the validator
+	privateValidators map[common.Address]bool             // privateValidators are the node addresses of the validators fronted by the sentry
+	relayedMsgs       *lru.Cache                          // relayedMsgs stores the hashes of the messages relayed by the sentry
+	relayCh           chan relayedMsg                     // relayCh queues the messages to be relayed by the sentry
 }
 
 // EventMux implements tendermint.Backend.EventMux
@@ -133,8 +196,12 @@ func (sb *Backend) EventMux() *event.TypeMux {
 
 // Sign implements tendermint.Backend.Sign
 func (sb *Backend) Sign(data []byte) ([]byte, error) {
-	hashData := crypto.Keccak256(data)
-	return crypto.Sign(hashData, sb.privateKey)
+	return sb.signer.Sign(data)
+}
+
+// SignBLS implements tendermint.Backend.SignBLS
+func (sb *Backend) SignBLS(data []byte) ([]byte, error) {
+	return sb.signer.SignBLS(data)
 }
 
 // Address implements tendermint.Backend.Address
@@ -184,9 +251,14 @@ func (sb *Backend) Gossip(valSet tendermint.ValidatorSet, blockNumber *big.Int,
 		return ErrNoBroadcaster
 	}
 	if len(targets) > 0 {
+		minPeers := valSet.MinPeers()
+		if sb.isBehindSentries() {
+			// the sentries relay the message to the validators
+			targets, minPeers = sb.messageTargets(targets), 1
+		}
 		task := broadcastTask{
 			Payload:     payload,
-			MinPeers:    valSet.MinPeers(),
+			MinPeers:    minPeers,
 			Targets:     targets,
 			TotalPeers:  len(targets),
 			BlockNumber: blockNumber,
@@ -234,7 +306,7 @@ func (sb *Backend) gossip(task broadcastTask) {
 		}
 	}()
 	for {
-		ps := sb.broadcaster.FindPeers(task.Targets)
+		ps := sb.findPeers(task.Targets)
 		log.Info("find peers", "found_peers", len(ps),
 			"block", task.BlockNumber, "round", task.Round, "msg_type", task.MsgType)
 		done := make(chan struct{})
@@ -292,9 +364,10 @@ func (sb *Backend) Multicast(targets map[common.Address]bool, payload []byte) er
 	if len(targets) == 0 {
 		return nil
 	}
+	targets = sb.messageTargets(targets)
 	var (
 		failed   int64 = 0
-		ps             = sb.broadcaster.FindPeers(targets)
+		ps             = sb.findPeers(targets)
 		notFound       = len(targets) - len(ps)
 	)
 	log.Trace("multicast", "targets", len(targets), "found", len(ps))
@@ -333,11 +406,13 @@ func (sb *Backend) FindExistingPeers(valSet tendermint.ValidatorSet) map[common.
 			targets[val.Address()] = true
 		}
 	}
-	return sb.broadcaster.FindPeers(targets)
+	return sb.findPeers(targets)
 }
 
 //Commit implement tendermint.Backend.Commit()
 func (sb *Backend) Commit(block *types.Block) {
+	sb.commitTimer.committed(block.Number())
+	sb.commitEpochRewards(block.Header())
 	isSent := sb.commitChs.sendBlock(block)
 	// if don't have committed channel to sent, then enqueue for downloading
 	if !isSent {
@@ -14,6 +14,7 @@ import (
 	"github.com/Evrynetlabs/evrynet-node/common"
 	"github.com/Evrynetlabs/evrynet-node/consensus"
 	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/signer"
 	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/tests_utils"
 	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/validator"
 	evrynetCore "github.com/Evrynetlabs/evrynet-node/core"
@@ -28,7 +29,7 @@ func TestSign(t *testing.T) {
 	privateKey, err := tests_utils.GeneratePrivateKey()
 	require.NoError(t, err)
 	b := &Backend{
-		privateKey: privateKey,
+		signer: signer.NewLocalSigner(privateKey, nil),
 	}
 	data := []byte("Here is a string....")
 	sig, err := b.Sign(data)
@@ -0,0 +1,132 @@
+package backend
+
+import (
+	"bytes"
+
+	"github.com/pkg/errors"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/consensus"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/utils"
+	"github.com/Evrynetlabs/evrynet-node/core/state"
+	"github.com/Evrynetlabs/evrynet-node/core/state/staking"
+	"github.com/Evrynetlabs/evrynet-node/core/types"
+	"github.com/Evrynetlabs/evrynet-node/crypto/bls"
+	"github.com/Evrynetlabs/evrynet-node/log"
+)
+
+var (
+	// ErrInvalidBLSKeyRegistration is returned if the BLS key registration in a header has an invalid proof of possession,
+	// or if BLS committed seals are not enabled.
+	ErrInvalidBLSKeyRegistration = errors.New("invalid BLS key registration")
+)
+
+// blsEnabled returns true if the validators can register BLS keys and commit blocks with aggregated BLS seals.
+// The keys are stored with the staking contract, so it is not available with fixed validators.
+func (sb *Backend) blsEnabled() bool {
+	return sb.config.BLSCommittedSeals && len(sb.config.FixedValidators) == 0
+}
+
+// addBLSKeyRegistration writes the BLS public key of this node to the header if it is not the one registered at parent's state,
+// i.e. the node has not registered a key yet or its key has been rotated. A new key replaces the registered one,
+// it is used to verify the seals of the node from the next epoch.
+func (sb *Backend) addBLSKeyRegistration(chainReader consensus.FullChainReader, header *types.Header, parent *types.Header) error {
+	if !sb.blsEnabled() {
+		return nil
+	}
+	stateDB, err := chainReader.StateAt(parent.Root)
+	if err != nil {
+		return err
+	}
+	publicKey, proof, err := sb.signer.BLSKeyRegistration()
+	if err != nil {
+		return err
+	}
+	registered := staking.GetBLSPublicKey(stateDB, sb.stakingContractAddr, sb.address)
+	if bytes.Equal(registered, publicKey) {
+		return nil
+	}
+	log.Info("registers the BLS public key", "number", header.Number.Uint64(), "rotated", registered != nil)
+	return utils.WriteBLSKeyRegistration(header, publicKey, proof)
+}
+
+// verifyBLSKeyRegistration checks the proof of possession of the BLS public key registered by the header's proposer
+func (sb *Backend) verifyBLSKeyRegistration(header *types.Header) error {
+	extra, err := types.ExtractTendermintExtra(header)
+	if err != nil {
+		return err
+	}
+	if len(extra.BLSPublicKey) == 0 && len(extra.BLSProof) == 0 {
+		return nil
+	}
+	if !sb.blsEnabled() {
+		return ErrInvalidBLSKeyRegistration
+	}
+	publicKey, err := bls.UnmarshalPublicKey(extra.BLSPublicKey)
+	if err != nil {
+		return errors.Wrap(ErrInvalidBLSKeyRegistration, err.Error())
+	}
+	proof, err := bls.UnmarshalSignature(extra.BLSProof)
+	if err != nil {
+		return errors.Wrap(ErrInvalidBLSKeyRegistration, err.Error())
+	}
+	if !bls.VerifyPossession(publicKey, proof) {
+		return ErrInvalidBLSKeyRegistration
+	}
+	return nil
+}
+
+// applyBLSKeyRegistration stores the BLS public key registered by the header's proposer
+func (sb *Backend) applyBLSKeyRegistration(state *state.StateDB, header *types.Header) error {
+	extra, err := types.ExtractTendermintExtra(header)
+	if err != nil {
+		return err
+	}
+	if len(extra.BLSPublicKey) == 0 {
+		return nil
+	}
+	staking.SetBLSPublicKey(state, sb.stakingContractAddr, header.Coinbase, extra.BLSPublicKey)
+	return nil
+}
+
+// getNextValidatorBLSKeys returns the registered BLS public keys of validators at the state of header,
+// in the same order as validators. It returns nil if any validator has not registered a key.
+func (sb *Backend) getNextValidatorBLSKeys(chainReader consensus.FullChainReader, header *types.Header, validators []common.Address) ([][]byte, error) {
+	if !sb.blsEnabled() {
+		return nil, nil
+	}
+	stateDB, err := chainReader.StateAt(header.Root)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([][]byte, len(validators))
+	for i, val := range validators {
+		keys[i] = staking.GetBLSPublicKey(stateDB, sb.stakingContractAddr, val)
+		if keys[i] == nil {
+			return nil, nil
+		}
+	}
+	return keys, nil
+}
+
+// verifyValSetBLSKeys checks that the BLS public keys in the header match the ones registered at the parent's state
+func (sb *Backend) verifyValSetBLSKeys(chainReader consensus.FullChainReader, header *types.Header, parent *types.Header, validators []common.Address) error {
+	extra, err := types.ExtractTendermintExtra(header)
+	if err != nil {
+		return err
+	}
+	keys, err := sb.getNextValidatorBLSKeys(chainReader, parent, validators)
+	if err != nil {
+		return err
+	}
+	if len(keys) != len(extra.ValidatorBLSKeys) {
+		return tendermint.ErrMismatchValSet
+	}
+	for i := range keys {
+		if string(keys[i]) != string(extra.ValidatorBLSKeys[i]) {
+			return tendermint.ErrMismatchValSet
+		}
+	}
+	return nil
+}
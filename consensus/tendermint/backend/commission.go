@@ -0,0 +1,51 @@
+package backend
+
+import (
+	"math/big"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/consensus"
+	"github.com/Evrynetlabs/evrynet-node/core/state"
+	"github.com/Evrynetlabs/evrynet-node/core/state/staking"
+	"github.com/Evrynetlabs/evrynet-node/core/types"
+	"github.com/Evrynetlabs/evrynet-node/log"
+)
+
+// commissionTxDataLength is the length of the data of a commission transaction, the candidate address followed by the rate
+const commissionTxDataLength = common.AddressLength + common.HashLength
+
+// applyCommissionRates sets the commission rates of the commission transactions of the block.
+// A commission transaction is sent to staking.CommissionAddress by the owner of a candidate, with the candidate address
+// followed by the 32 bytes rate as data. A transaction which can not set the rate is kept in the block without effect.
+func (sb *Backend) applyCommissionRates(chainReader consensus.FullChainReader, state *state.StateDB, header *types.Header, txs []*types.Transaction) {
+	if len(sb.config.FixedValidators) > 0 {
+		return
+	}
+	var (
+		config = chainReader.Config().Tendermint
+		signer = types.MakeSigner(chainReader.Config(), header.Number)
+	)
+	for _, tx := range txs {
+		if tx.To() == nil || *tx.To() != staking.CommissionAddress {
+			continue
+		}
+		sender, err := types.Sender(signer, tx)
+		if err != nil || len(tx.Data()) != commissionTxDataLength {
+			continue
+		}
+		var (
+			candidate = common.BytesToAddress(tx.Data()[:common.AddressLength])
+			rate      = new(big.Int).SetBytes(tx.Data()[common.AddressLength:])
+		)
+		if owner := sb.candidateOwner(state, candidate); owner == (common.Address{}) || owner != sender {
+			log.Info("ignore commission transaction from non owner", "candidate", candidate, "sender", sender, "tx", tx.Hash())
+			continue
+		}
+		if !rate.IsUint64() || rate.Uint64() < config.MinCommissionRate || rate.Uint64() > maxCommissionRate(config) {
+			log.Info("ignore commission transaction out of bounds", "candidate", candidate, "rate", rate, "tx", tx.Hash())
+			continue
+		}
+		staking.SetCommissionRate(state, sb.stakingContractAddr, candidate, rate.Uint64())
+		log.Info("set commission rate", "candidate", candidate, "rate", rate, "number", header.Number)
+	}
+}
@@ -12,7 +12,6 @@ import (
 	fcontypes "github.com/Evrynetlabs/evrynet-node/consensus/fconsensus/types"
 	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint"
 	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/utils"
-	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/validator"
 	"github.com/Evrynetlabs/evrynet-node/core/state"
 	"github.com/Evrynetlabs/evrynet-node/core/state/staking"
 	"github.com/Evrynetlabs/evrynet-node/core/types"
@@ -23,9 +22,6 @@ import (
 )
 
 var (
-	validatorRewardPercentage int64 = 50
-	voterRewardPercentage     int64 = 50
-
 	defaultDifficulty = big.NewInt(1)
 	now               = time.Now
 )
@@ -152,6 +148,13 @@ func (sb *Backend) tryStartCore() bool {
 	return true
 }
 
+// isCoreStarted returns true if core is running
+func (sb *Backend) isCoreStarted() bool {
+	sb.mutex.RLock()
+	defer sb.mutex.RUnlock()
+	return sb.coreStarted
+}
+
 // Start implements consensus.Tendermint.Start
 func (sb *Backend) Start(chain consensus.FullChainReader, assistChain consensus.FullChainReader, currentBlock func() *types.Block, verifyAndSubmitBlock func(*types.Block) error) error {
 	sb.mutex.Lock()
@@ -249,6 +252,12 @@ func (sb *Backend) VerifyProposalHeader(header *types.Header) error {
 		if !reflect.DeepEqual(validators, valSetInHeader) {
 			return tendermint.ErrMismatchValSet
 		}
+		if err := sb.verifyValSetPowers(sb.chain, header, parent, validators); err != nil {
+			return err
+		}
+		if err := sb.verifyValSetBLSKeys(sb.chain, header, parent, validators); err != nil {
+			return err
+		}
 	}
 	return sb.verifyHeader(sb.chain, header, nil)
 }
@@ -271,6 +280,9 @@ func (sb *Backend) verifyHeader(chain consensus.ChainReader, header *types.Heade
 	if _, err := types.ExtractTendermintExtra(header); err != nil {
 		return tendermint.ErrInvalidExtraDataFormat
 	}
+	if err := sb.verifyBLSKeyRegistration(header); err != nil {
+		return err
+	}
 
 	// Ensure that the mix digest is zero as we don't have fork protection currently
 	if header.MixDigest != types.TendermintDigest {
@@ -317,6 +329,9 @@ func (sb *Backend) verifyCascadingFields(chain consensus.ChainReader, header *ty
 	if err := sb.verifyProposalSeal(header, valSet); err != nil {
 		return err
 	}
+	if err := sb.verifyEvidences(chain, header); err != nil {
+		return err
+	}
 
 	return sb.verifyCommittedSeals(header, valSet)
 }
@@ -360,11 +375,7 @@ func (sb *Backend) getValSetFromChain(chain consensus.ChainReader, header *types
 			}
 		}
 		if number%sb.config.Epoch == 0 {
-			validators, err := utils.GetValSetAddresses(currentHeader)
-			if err != nil {
-				return nil, err
-			}
-			return validator.NewSet(validators, sb.config.ProposerPolicy, int64(blockNumber)), nil
+			return utils.GetValSet(currentHeader, sb.config.ProposerPolicy, int64(blockNumber))
 		}
 		number, hash = number-1, currentHeader.ParentHash
 	}
@@ -441,7 +452,7 @@ func (sb *Backend) Prepare(chain consensus.FullChainReader, header *types.Header
 	}
 
 	// prepare extra data without validators
-	header.Extra = sb.prepareExtra(header)
+	header.Extra = sb.prepareExtra(header, sb.pendingEvidences(chain, parent))
 
 	// set header's timestamp from parent's timestamp and blockperiod
 	var (
@@ -459,6 +470,9 @@ func (sb *Backend) Prepare(chain consensus.FullChainReader, header *types.Header
 	if err := sb.addValSetToHeader(chain, header, parent); err != nil {
 		log.Error("failed to add val set to header", "err", err)
 	}
+	if err := sb.addBLSKeyRegistration(chain, header, parent); err != nil {
+		log.Error("failed to add BLS key registration to header", "err", err)
+	}
 
 	return nil
 }
@@ -470,13 +484,33 @@ func (sb *Backend) Prepare(chain consensus.FullChainReader, header *types.Header
 func (sb *Backend) Finalize(chain consensus.FullChainReader, header *types.Header, state *state.StateDB, txs []*types.Transaction,
 	uncles []*types.Header) error {
 	// Accumulate any block rewards and commit the final state root
-	if err := sb.accumulateRewards(chain, state, header); err != nil {
+	rewards, err := sb.accumulateRewards(chain, state, header)
+	if err != nil {
 		log.Error("failed to accumulateRewards", "err", err)
 		return err
 	}
+	if err := sb.applyEvidences(chain, state, header); err != nil {
+		log.Error("failed to applyEvidences", "err", err)
+		return err
+	}
+	if err := sb.applyBLSKeyRegistration(state, header); err != nil {
+		log.Error("failed to applyBLSKeyRegistration", "err", err)
+		return err
+	}
+	if err := sb.applyLiveness(chain, state, header); err != nil {
+		log.Error("failed to applyLiveness", "err", err)
+		return err
+	}
+	sb.applyUnjails(chain, state, header, txs)
+	sb.applyCommissionRates(chain, state, header, txs)
 
 	// Since there is a change in stateDB, its trie must be update
 	header.Root = state.IntermediateRoot(true)
+	// Finalize also runs for the blocks which are not inserted, i.e. the replay of a fraud proof or a failed import,
+	// so the reward ledger is only stored once the block is committed or inserted
+	if rewards != nil {
+		sb.finalizedRewards.Add(header.Hash(), rewards)
+	}
 	return nil
 }
 
@@ -488,14 +522,33 @@ func (sb *Backend) Finalize(chain consensus.FullChainReader, header *types.Heade
 func (sb *Backend) FinalizeAndAssemble(chain consensus.FullChainReader, header *types.Header, state *state.StateDB, txs []*types.Transaction,
 	uncles []*types.Header, receipts []*types.Receipt) (*types.Block, error) {
 	// Accumulate any block rewards and commit the final state root
-	if err := sb.accumulateRewards(chain, state, header); err != nil {
+	rewards, err := sb.accumulateRewards(chain, state, header)
+	if err != nil {
 		log.Error("failed to accumulateRewards", "err", err)
 		return nil, err
 	}
+	if err := sb.applyEvidences(chain, state, header); err != nil {
+		log.Error("failed to applyEvidences", "err", err)
+		return nil, err
+	}
+	if err := sb.applyBLSKeyRegistration(state, header); err != nil {
+		log.Error("failed to applyBLSKeyRegistration", "err", err)
+		return nil, err
+	}
+	if err := sb.applyLiveness(chain, state, header); err != nil {
+		log.Error("failed to applyLiveness", "err", err)
+		return nil, err
+	}
+	sb.applyUnjails(chain, state, header, txs)
+	sb.applyCommissionRates(chain, state, header, txs)
 
 	// No block rewards, so the state remains as is and uncles are dropped
 	header.Root = state.IntermediateRoot(true)
 	header.UncleHash = types.CalcUncleHash(nil)
+	// the block is not sealed yet, its reward ledger is stored once it is committed
+	if rewards != nil {
+		sb.proposedRewards.Add(sb.SealHash(header), rewards)
+	}
 
 	// Assemble and return the final block for sealing
 	return types.NewBlock(header, txs, nil, receipts), nil
@@ -518,6 +571,11 @@ func (sb *Backend) APIs(chain consensus.ChainReader) []rpc.API {
 		Version:   "1.0",
 		Service:   &TendermintAPI{chain: chain, be: sb},
 		Public:    true,
+	}, {
+		Namespace: "staking",
+		Version:   "1.0",
+		Service:   &StakingAPI{chain: chain, be: sb},
+		Public:    true,
 	}}
 }
 
@@ -551,42 +609,7 @@ func (sb *Backend) verifyProposalSeal(header *types.Header, valSet tendermint.Va
 
 // verifyCommittedSeals checks whether every committed seal is signed by one of the parent's validators
 func (sb *Backend) verifyCommittedSeals(header *types.Header, valSet tendermint.ValidatorSet) error {
-	extra, err := types.ExtractTendermintExtra(header)
-	if err != nil {
-		return err
-	}
-	// The length of Committed seals should be larger than 0
-	if len(extra.CommittedSeal) == 0 {
-		return tendermint.ErrEmptyCommittedSeals
-	}
-
-	vals := valSet.Copy()
-	// Check whether the committed seals are generated by parent's validators
-	validSeal := 0
-	proposalSeal := utils.PrepareCommittedSeal(header.Hash())
-	// 1. Get committed seals from current header
-	for _, seal := range extra.CommittedSeal {
-		// 2. Get the original address by seal and parent block hash
-		addr, err := utils.GetSignatureAddress(proposalSeal, seal)
-		if err != nil {
-			log.Error("not a valid address", "err", err)
-			return tendermint.ErrInvalidSignature
-		}
-		// Every validator can have only one seal. If more than one seals are signed by a
-		// validator, the validator cannot be found and errInvalidCommittedSeals is returned.
-		if vals.RemoveValidator(addr) {
-			validSeal++
-		} else {
-			return tendermint.ErrInvalidCommittedSeals
-		}
-	}
-
-	// The length of validSeal should be larger or equal than min majority (num validator - maximum faulty)
-	if validSeal < valSet.MinMajority() {
-		return tendermint.ErrInvalidCommittedSeals
-	}
-
-	return nil
+	return utils.VerifyCommittedSeals(header, valSet)
 }
 
 // blockProposer extracts the Evrynet account address from a signed header.
@@ -629,7 +652,23 @@ func (sb *Backend) addValSetToHeader(chainReader consensus.FullChainReader, head
 	}
 
 	log.Info("sets the val-set back to extra-data", "number", blockNumber)
-	return utils.WriteValSet(header, validators)
+	if err := utils.WriteValSet(header, validators); err != nil {
+		return err
+	}
+	if sb.config.StakeWeightedVoting {
+		powers, err := sb.getNextValidatorPowers(chainReader, parent, validators)
+		if err != nil {
+			return err
+		}
+		if err := utils.WriteValSetPowers(header, powers); err != nil {
+			return err
+		}
+	}
+	blsKeys, err := sb.getNextValidatorBLSKeys(chainReader, parent, validators)
+	if err != nil || len(blsKeys) == 0 {
+		return err
+	}
+	return utils.WriteValSetBLSKeys(header, blsKeys)
 }
 
 func (sb *Backend) getNextValidatorSet(chainReader consensus.FullChainReader, header *types.Header) ([]common.Address, error) {
@@ -649,6 +688,10 @@ func (sb *Backend) getNextValidatorSet(chainReader consensus.FullChainReader, he
 	if err != nil {
 		return nil, err
 	}
+	validators = sb.filterJailedValidators(stateDB, validators, header.Number.Uint64())
+	if len(validators) == 0 {
+		return nil, tendermint.ErrEmptyValSet
+	}
 	sb.computedValSetCache.Add(header.Number.Uint64(), validators)
 	log.Info("found new val set", "number", header.Number.Uint64(), "elapsed", common.PrettyDuration(time.Since(start)),
 		"valset", common.PrettyAddresses(validators))
@@ -690,7 +733,7 @@ func (sb *Backend) getEvilProof(parentNumber uint64) common.Hash {
 
 }
 
-func (sb *Backend) prepareExtra(header *types.Header) []byte {
+func (sb *Backend) prepareExtra(header *types.Header, evidences []*types.TendermintEvidence) []byte {
 	var (
 		tdm     *types.TendermintExtra
 		payload []byte
@@ -704,7 +747,7 @@ func (sb *Backend) prepareExtra(header *types.Header) []byte {
 	buf.Write(header.Extra[:types.TendermintExtraVanity])
 
 	evilProof := sb.getEvilProof(header.Number.Uint64() - 1)
-	tdm = &types.TendermintExtra{EvilProof: evilProof}
+	tdm = &types.TendermintExtra{EvilProof: evilProof, Evidences: evidences}
 	payload, err = rlp.EncodeToBytes(&tdm)
 
 	if err != nil {
@@ -158,13 +158,13 @@ func TestPrepareExtra(t *testing.T) {
 		Number: big.NewInt(0),
 	}
 
-	header.Extra = engine.prepareExtra(header)
+	header.Extra = engine.prepareExtra(header, nil)
 	assert.Equal(t, expectedResult, header.Extra)
 
 	// append useless information to extra-data
 	header.Extra = append(vanity, make([]byte, 15)...)
 
-	header.Extra = engine.prepareExtra(header)
+	header.Extra = engine.prepareExtra(header, nil)
 	assert.Equal(t, expectedResult, header.Extra)
 }
 
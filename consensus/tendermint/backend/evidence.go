@@ -0,0 +1,235 @@
+package backend
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/consensus"
+	tendermintCore "github.com/Evrynetlabs/evrynet-node/consensus/tendermint/core"
+	"github.com/Evrynetlabs/evrynet-node/core/state"
+	"github.com/Evrynetlabs/evrynet-node/core/state/staking"
+	"github.com/Evrynetlabs/evrynet-node/core/types"
+	"github.com/Evrynetlabs/evrynet-node/log"
+)
+
+const (
+	// maxEvidencesPerBlock is the maximum number of evidences a proposer includes in a block
+	maxEvidencesPerBlock = 10
+	// defaultDoubleSignSlashPercentage is used if the chain config does not specify the slash percentage
+	defaultDoubleSignSlashPercentage uint64 = 5
+)
+
+var (
+	// ErrDuplicatedEvidence is returned if a header contains the same evidence twice
+	ErrDuplicatedEvidence = errors.New("duplicated evidence")
+	// ErrExpiredEvidence is returned if the evidence is older than an epoch
+	ErrExpiredEvidence = errors.New("expired evidence")
+	// ErrEvidenceOffenderNotValidator is returned if the offender is not a validator at the height of the evidence
+	ErrEvidenceOffenderNotValidator = errors.New("offender of evidence is not a validator")
+	// ErrEvidenceInFixedValidatorMode is returned if a header contains evidences while using fixed validators
+	ErrEvidenceInFixedValidatorMode = errors.New("evidences are not supported with fixed validators")
+)
+
+// evidencePool keeps the evidences of double signing which are not included in a block yet
+type evidencePool struct {
+	mu        sync.Mutex
+	evidences map[common.Hash]*types.TendermintEvidence
+}
+
+func newEvidencePool() *evidencePool {
+	return &evidencePool{
+		evidences: make(map[common.Hash]*types.TendermintEvidence),
+	}
+}
+
+// add adds the evidence to pool, it returns false if the evidence is already in the pool
+func (p *evidencePool) add(evidence *types.TendermintEvidence) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	hash := evidence.Hash()
+	if _, ok := p.evidences[hash]; ok {
+		return false
+	}
+	p.evidences[hash] = evidence
+	return true
+}
+
+// remove removes the evidence with given hash from pool
+func (p *evidencePool) remove(hash common.Hash) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.evidences, hash)
+}
+
+// list returns all evidences in pool, sorted by hash
+func (p *evidencePool) list() []*types.TendermintEvidence {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	evidences := make([]*types.TendermintEvidence, 0, len(p.evidences))
+	for _, evidence := range p.evidences {
+		evidences = append(evidences, evidence)
+	}
+	sort.Slice(evidences, func(i, j int) bool {
+		hashI, hashJ := evidences[i].Hash(), evidences[j].Hash()
+		return bytes.Compare(hashI[:], hashJ[:]) < 0
+	})
+	return evidences
+}
+
+// ReportEvidence implements tendermint.Backend.ReportEvidence
+func (sb *Backend) ReportEvidence(evidence *types.TendermintEvidence) bool {
+	if len(sb.config.FixedValidators) > 0 {
+		return false
+	}
+	return sb.evidences.add(evidence)
+}
+
+// pendingEvidences returns the evidences to be included in the block on top of parent.
+// Evidences which are invalid, expired or already applied are removed from the pool.
+func (sb *Backend) pendingEvidences(chain consensus.FullChainReader, parent *types.Header) []*types.TendermintEvidence {
+	if len(sb.config.FixedValidators) > 0 {
+		return nil
+	}
+	stateDB, err := chain.StateAt(parent.Root)
+	if err != nil {
+		log.Error("failed to get state to select evidences", "err", err, "number", parent.Number)
+		return nil
+	}
+	var (
+		evidences   []*types.TendermintEvidence
+		blockNumber = new(big.Int).Add(parent.Number, big.NewInt(1))
+	)
+	seen := make(map[common.Hash]bool)
+	for _, evidence := range sb.evidences.list() {
+		hash := evidence.Hash()
+		offence, err := sb.verifyEvidence(chain, blockNumber, evidence)
+		if err != nil {
+			log.Warn("drop invalid evidence", "err", err, "hash", hash)
+			sb.evidences.remove(hash)
+			continue
+		}
+		if sb.isOffenceSlashed(stateDB, offence) {
+			sb.evidences.remove(hash)
+			continue
+		}
+		// another evidence of the same offence may be in the pool
+		if seen[offence.Key()] {
+			continue
+		}
+		seen[offence.Key()] = true
+		evidences = append(evidences, evidence)
+		if len(evidences) >= maxEvidencesPerBlock {
+			break
+		}
+	}
+	return evidences
+}
+
+// verifyEvidence checks that the evidence proves a validator at its height signed 2 conflicting votes
+// and it is not older than an epoch at blockNumber. It returns the offence proven by the evidence.
+func (sb *Backend) verifyEvidence(chain consensus.ChainReader, blockNumber *big.Int, evidence *types.TendermintEvidence) (*tendermintCore.Offence, error) {
+	offence, err := tendermintCore.VerifyEvidence(evidence)
+	if err != nil {
+		return nil, err
+	}
+	if offence.BlockNumber.Cmp(blockNumber) >= 0 ||
+		new(big.Int).Sub(blockNumber, offence.BlockNumber).Uint64() > sb.config.Epoch {
+		return nil, ErrExpiredEvidence
+	}
+	valSet, err := sb.valSetInfo.GetValSet(chain, offence.BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if _, v := valSet.GetByAddress(offence.Offender); v == nil {
+		return nil, ErrEvidenceOffenderNotValidator
+	}
+	return offence, nil
+}
+
+// isOffenceSlashed returns true if the offence has been slashed already, by any evidence, or its offender is tombstoned
+func (sb *Backend) isOffenceSlashed(stateDB *state.StateDB, offence *tendermintCore.Offence) bool {
+	return staking.IsOffenceSlashed(stateDB, sb.stakingContractAddr, offence.Key()) ||
+		staking.IsTombstoned(stateDB, sb.stakingContractAddr, offence.Offender)
+}
+
+// verifyEvidences checks all evidences included in the header
+func (sb *Backend) verifyEvidences(chain consensus.ChainReader, header *types.Header) error {
+	extra, err := types.ExtractTendermintExtra(header)
+	if err != nil {
+		return err
+	}
+	if len(extra.Evidences) == 0 {
+		return nil
+	}
+	if len(sb.config.FixedValidators) > 0 {
+		return ErrEvidenceInFixedValidatorMode
+	}
+	if len(extra.Evidences) > maxEvidencesPerBlock {
+		return errors.Errorf("too many evidences: have %d, max %d", len(extra.Evidences), maxEvidencesPerBlock)
+	}
+	seen := make(map[common.Hash]bool)
+	for _, evidence := range extra.Evidences {
+		hash := evidence.Hash()
+		offence, err := sb.verifyEvidence(chain, header.Number, evidence)
+		if err != nil {
+			return errors.Wrapf(err, "invalid evidence %s", hash.Hex())
+		}
+		// evidences are duplicated if they prove the same offence, even with different votes
+		if seen[offence.Key()] {
+			return ErrDuplicatedEvidence
+		}
+		seen[offence.Key()] = true
+	}
+	return nil
+}
+
+// applyEvidences slashes and tombstones the offenders of evidences included in header.
+// An offence is slashed only once, later evidences of the same offence and evidences of tombstoned offenders are ignored.
+func (sb *Backend) applyEvidences(chainReader consensus.FullChainReader, state *state.StateDB, header *types.Header) error {
+	extra, err := types.ExtractTendermintExtra(header)
+	if err != nil {
+		return err
+	}
+	if len(extra.Evidences) == 0 {
+		return nil
+	}
+	percentage := chainReader.Config().Tendermint.DoubleSignSlashPercentage
+	if percentage == 0 {
+		percentage = defaultDoubleSignSlashPercentage
+	}
+	for _, evidence := range extra.Evidences {
+		hash := evidence.Hash()
+		offence, err := tendermintCore.VerifyEvidence(evidence)
+		if err != nil {
+			return err
+		}
+		sb.evidences.remove(hash)
+		if sb.isOffenceSlashed(state, offence) {
+			continue
+		}
+		slashed := staking.Slash(state, sb.config.IndexStateVariables, sb.stakingContractAddr, offence.Offender, percentage)
+		staking.Jail(state, sb.stakingContractAddr, offence.Offender, staking.Tombstoned)
+		staking.MarkOffenceSlashed(state, sb.stakingContractAddr, offence.Key())
+		log.Warn("slashed validator for double signing", "offender", offence.Offender, "slashed", slashed,
+			"number", header.Number, "evidence", hash)
+	}
+	return nil
+}
+
+// filterJailedValidators removes the validators which are jailed at the given block number
+func (sb *Backend) filterJailedValidators(stateDB *state.StateDB, validators []common.Address, number uint64) []common.Address {
+	filtered := make([]common.Address, 0, len(validators))
+	for _, val := range validators {
+		if staking.IsJailed(stateDB, sb.stakingContractAddr, val, number) {
+			log.Info("exclude jailed validator from val set", "validator", val, "number", number)
+			continue
+		}
+		filtered = append(filtered, val)
+	}
+	return filtered
+}
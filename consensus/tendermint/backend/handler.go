@@ -98,11 +98,14 @@ func (sb *Backend) dequeueMsgLoop() {
 func (sb *Backend) HandleMsg(addr common.Address, msg p2p.Msg) (bool, error) {
 	switch msg.Code {
 	case consensus.TendermintMsg:
-		decodedMsg, _, err := sb.decode(msg)
+		decodedMsg, hash, err := sb.decode(msg)
 		if err != nil {
 			log.Error("failed to decode message from p2p.Msg", "err", err)
 			return true, err
 		}
+		if len(sb.privateValidators) > 0 {
+			sb.relay(addr, hash, decodedMsg)
+		}
 
 		//Dequeue if storingMsg reached max
 		if sb.storingMsgs.GetLen() >= maxNumberMessages {
@@ -149,6 +152,11 @@ func (sb *Backend) HandleNewChainHead(blockNumber *big.Int) error {
 	if !sb.coreStarted {
 		return tendermint.ErrStoppedEngine
 	}
+	sb.commitTimer.inserted(blockNumber)
+	// the ledger of an epoch block inserted without being committed by core, i.e. synced, is stored once inserted
+	if header := sb.chain.GetHeaderByNumber(blockNumber.Uint64()); header != nil {
+		sb.commitEpochRewards(header)
+	}
 	sb.commitChs.closeAndRemoveCommitChannel(blockNumber.String())
 	go func() {
 		if err := sb.tendermintEventMux.Post(tendermint.FinalCommittedEvent{
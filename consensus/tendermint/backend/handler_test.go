@@ -107,6 +107,18 @@ func (m *mockCore) SetBlockForProposal(block *types.Block) {
 	panic("implement me")
 }
 
+func (m *mockCore) ConsensusState() (*types.ConsensusState, error) {
+	panic("implement me")
+}
+
+func (m *mockCore) RoundVotes(blockNumber uint64, round int64) (*types.RoundVotes, error) {
+	panic("implement me")
+}
+
+func (m *mockCore) SubscribeRoundStep(ch chan<- types.RoundStep) event.Subscription {
+	panic("implement me")
+}
+
 // This test case is when user start miner then stop it before core handles all msg in storingMsgs
 func TestBackend_HandleMsg(t *testing.T) {
 	log.Root().SetHandler(log.LvlFilterHandler(log.LvlTrace, log.StreamHandler(os.Stderr, log.TerminalFormat(false))))
@@ -0,0 +1,127 @@
+package backend
+
+import (
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/consensus"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/utils"
+	"github.com/Evrynetlabs/evrynet-node/core/state"
+	"github.com/Evrynetlabs/evrynet-node/core/state/staking"
+	"github.com/Evrynetlabs/evrynet-node/core/types"
+	"github.com/Evrynetlabs/evrynet-node/log"
+)
+
+// defaultMinSignedPerWindow is used if the chain config does not specify the minimum percentage of signed blocks
+const defaultMinSignedPerWindow uint64 = 50
+
+// livenessEnabled returns true if the validators which miss too many blocks are jailed for downtime.
+// The signed blocks are recorded with the staking contract, so it is not available with fixed validators.
+func (sb *Backend) livenessEnabled() bool {
+	return sb.config.SignedBlocksWindow > 0 && len(sb.config.FixedValidators) == 0
+}
+
+// maxMissedBlocks returns the number of blocks a validator can miss in the window without being jailed
+func (sb *Backend) maxMissedBlocks() uint64 {
+	minSigned := sb.config.MinSignedPerWindow
+	if minSigned == 0 || minSigned > 100 {
+		minSigned = defaultMinSignedPerWindow
+	}
+	return sb.config.SignedBlocksWindow - sb.config.SignedBlocksWindow*minSigned/100
+}
+
+// downtimeJailDuration returns the number of blocks before a validator jailed for downtime can be unjailed
+func (sb *Backend) downtimeJailDuration() uint64 {
+	if sb.config.DowntimeJailDuration == 0 {
+		return sb.config.SignedBlocksWindow
+	}
+	return sb.config.DowntimeJailDuration
+}
+
+// applyLiveness records which validators committed the parent of header, from its committed seals,
+// and jails the validators which missed more blocks of the window than allowed.
+// The jailed validators are excluded from the validator set of the next epoch.
+func (sb *Backend) applyLiveness(chainReader consensus.FullChainReader, state *state.StateDB, header *types.Header) error {
+	// the genesis block is not committed by any validator
+	if !sb.livenessEnabled() || header.Number.Uint64() < 2 {
+		return nil
+	}
+	parent := chainReader.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	if parent == nil {
+		return consensus.ErrUnknownAncestor
+	}
+	valSet, err := sb.valSetInfo.GetValSet(chainReader, parent.Number)
+	if err != nil {
+		return err
+	}
+	signers, err := utils.CommittedSigners(parent, valSet)
+	if err != nil {
+		return err
+	}
+	signed := make(map[common.Address]bool, len(signers))
+	for _, signer := range signers {
+		signed[signer] = true
+	}
+	var (
+		number    = parent.Number.Uint64()
+		window    = sb.config.SignedBlocksWindow
+		maxMissed = sb.maxMissedBlocks()
+	)
+	for _, val := range valSet.List() {
+		addr := val.Address()
+		// a jailed validator stays in the validator set until the end of the epoch
+		if staking.IsJailed(state, sb.stakingContractAddr, addr, number) {
+			continue
+		}
+		missed := staking.RecordSigning(state, sb.stakingContractAddr, addr, number, window, signed[addr])
+		if missed <= maxMissed {
+			continue
+		}
+		until := header.Number.Uint64() + sb.downtimeJailDuration()
+		staking.JailForDowntime(state, sb.stakingContractAddr, addr, until)
+		staking.ResetSigning(state, sb.stakingContractAddr, addr, window)
+		log.Warn("jailed validator for downtime", "validator", addr, "missed", missed, "window", window,
+			"number", header.Number, "until", until)
+	}
+	return nil
+}
+
+// applyUnjails releases the candidates jailed for downtime by the unjail transactions of the block.
+// An unjail transaction is sent to staking.UnjailAddress by the candidate, or by its owner with the candidate address as data.
+// A transaction which can not unjail its candidate is kept in the block without effect.
+func (sb *Backend) applyUnjails(chainReader consensus.FullChainReader, state *state.StateDB, header *types.Header, txs []*types.Transaction) {
+	if !sb.livenessEnabled() {
+		return
+	}
+	signer := types.MakeSigner(chainReader.Config(), header.Number)
+	for _, tx := range txs {
+		if tx.To() == nil || *tx.To() != staking.UnjailAddress {
+			continue
+		}
+		sender, err := types.Sender(signer, tx)
+		if err != nil {
+			continue
+		}
+		candidate := sender
+		if len(tx.Data()) == common.AddressLength {
+			candidate = common.BytesToAddress(tx.Data())
+		}
+		if candidate != sender && sb.candidateOwner(state, candidate) != sender {
+			log.Info("ignore unjail transaction from non owner", "candidate", candidate, "sender", sender, "tx", tx.Hash())
+			continue
+		}
+		if err := staking.Unjail(state, sb.stakingContractAddr, candidate, header.Number.Uint64()); err != nil {
+			log.Info("ignore unjail transaction", "candidate", candidate, "err", err, "tx", tx.Hash())
+			continue
+		}
+		staking.ResetSigning(state, sb.stakingContractAddr, candidate, sb.config.SignedBlocksWindow)
+		log.Info("unjailed validator", "candidate", candidate, "number", header.Number)
+	}
+}
+
+// candidateOwner returns the owner of the candidate registered with the staking contract
+func (sb *Backend) candidateOwner(state *state.StateDB, candidate common.Address) common.Address {
+	data, err := staking.NewStateDbStakingCaller(state, sb.config.IndexStateVariables).GetValidatorsData(sb.stakingContractAddr, []common.Address{candidate})
+	if err != nil {
+		return common.Address{}
+	}
+	return data[candidate].Owner
+}
@@ -0,0 +1,38 @@
+package backend
+
+import (
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/Evrynetlabs/evrynet-node/metrics"
+)
+
+var (
+	tendermintCommitInsertTimer = metrics.NewRegisteredTimer("evr/consensus/tendermint/commit/insert", nil)
+)
+
+// commitTimer measures the time between the commit of a block by core and its insertion into the chain
+type commitTimer struct {
+	mu          sync.Mutex
+	number      *big.Int
+	committedAt time.Time
+}
+
+// committed marks the block number as committed by core
+func (t *commitTimer) committed(number *big.Int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.number, t.committedAt = new(big.Int).Set(number), time.Now()
+}
+
+// inserted updates the commit to insert latency if the block number inserted is the last one committed by core
+func (t *commitTimer) inserted(number *big.Int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.number == nil || t.number.Cmp(number) != 0 {
+		return
+	}
+	tendermintCommitInsertTimer.UpdateSince(t.committedAt)
+	t.number = nil
+}
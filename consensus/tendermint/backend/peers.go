@@ -0,0 +1,204 @@
+package backend
+
+import (
+	"math/big"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/consensus"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint"
+	tendermintCore "github.com/Evrynetlabs/evrynet-node/consensus/tendermint/core"
+	"github.com/Evrynetlabs/evrynet-node/crypto"
+	"github.com/Evrynetlabs/evrynet-node/log"
+	"github.com/Evrynetlabs/evrynet-node/p2p/enode"
+)
+
+// newValidatorNodes indexes the configured nodes of the validators by validator address
+func newValidatorNodes(configured []tendermint.ValidatorNodes) map[common.Address][]common.Address {
+	nodes := make(map[common.Address][]common.Address, len(configured))
+	for _, vn := range configured {
+		nodes[vn.Validator] = append(nodes[vn.Validator], vn.Nodes...)
+	}
+	return nodes
+}
+
+// nodeAddresses returns the addresses of the node keys of the nodes, as the peers are found by
+func nodeAddresses(nodes []*enode.Node) map[common.Address]bool {
+	addrs := make(map[common.Address]bool, len(nodes))
+	for _, n := range nodes {
+		if pubKey := n.Pubkey(); pubKey != nil {
+			addrs[crypto.PubkeyToAddress(*pubKey)] = true
+		}
+	}
+	return addrs
+}
+
+// nodesOf returns the addresses of the node keys to reach the validator at, which is the validator address by default
+func (sb *Backend) nodesOf(validator common.Address) []common.Address {
+	if nodes, ok := sb.validatorNodes[validator]; ok {
+		return nodes
+	}
+	return []common.Address{validator}
+}
+
+// isBehindSentries returns true if the validator only connects to its sentries
+func (sb *Backend) isBehindSentries() bool {
+	return len(sb.sentries) > 0
+}
+
+// messageTargets returns the targets to send a message to the validators at,
+// they are the sentries if the validator is behind sentries
+func (sb *Backend) messageTargets(validators map[common.Address]bool) map[common.Address]bool {
+	if !sb.isBehindSentries() {
+		return validators
+	}
+	targets := make(map[common.Address]bool, len(sb.sentries))
+	for sentry := range sb.sentries {
+		targets[sentry] = true
+	}
+	return targets
+}
+
+// findPeers returns the connected peers of the validators, indexed by validator address.
+// A validator behind sentries reaches every validator through any of its sentries, the sentries are found by their address.
+func (sb *Backend) findPeers(validators map[common.Address]bool) map[common.Address]consensus.Peer {
+	if sb.isBehindSentries() {
+		return sb.findSentryPeers(validators)
+	}
+	if len(sb.validatorNodes) == 0 {
+		return sb.broadcaster.FindPeers(validators)
+	}
+	targets := make(map[common.Address]bool)
+	for validator := range validators {
+		for _, node := range sb.nodesOf(validator) {
+			targets[node] = true
+		}
+	}
+	found := sb.broadcaster.FindPeers(targets)
+	peers := make(map[common.Address]consensus.Peer)
+	for validator := range validators {
+		for _, node := range sb.nodesOf(validator) {
+			if p, ok := found[node]; ok {
+				peers[validator] = p
+				break
+			}
+		}
+	}
+	return peers
+}
+
+func (sb *Backend) findSentryPeers(targets map[common.Address]bool) map[common.Address]consensus.Peer {
+	var (
+		found = sb.broadcaster.FindPeers(sb.sentries)
+		first consensus.Peer
+		peers = make(map[common.Address]consensus.Peer)
+	)
+	for _, p := range found {
+		first = p
+		break
+	}
+	for target := range targets {
+		switch p, ok := found[target]; {
+		case ok:
+			peers[target] = p
+		case first != nil && !sb.sentries[target]:
+			peers[target] = first
+		}
+	}
+	return peers
+}
+
+// relayedMsg is a consensus message verified to be relayed by the sentry
+type relayedMsg struct {
+	from    common.Address // from is the address of the peer the message is received from
+	signer  common.Address // signer is the validator signing the message
+	payload []byte
+}
+
+// relay queues the consensus message received by a sentry to be forwarded. The message is only relayed if it is signed by
+// a validator of the next block, and only once. The message is dropped if the queue is full.
+func (sb *Backend) relay(from common.Address, hash common.Hash, payload []byte) {
+	if sb.broadcaster == nil {
+		return
+	}
+	if sb.relayedMsgs.Contains(hash) {
+		return
+	}
+	signer, err := tendermintCore.PayloadSigner(payload)
+	if err != nil {
+		log.Debug("failed to verify message to relay", "err", err, "from", from)
+		return
+	}
+	if !sb.isNextValidator(signer) {
+		log.Debug("message to relay is not signed by a validator", "from", from, "signer", signer)
+		return
+	}
+	if ok, _ := sb.relayedMsgs.ContainsOrAdd(hash, true); ok {
+		return
+	}
+	select {
+	case sb.relayCh <- relayedMsg{from: from, signer: signer, payload: payload}:
+	default:
+		log.Debug("relay queue is full, dropping message", "from", from, "signer", signer)
+	}
+}
+
+// nextValidators returns the validator set of the next block, or nil if the chain is not started
+func (sb *Backend) nextValidators() tendermint.ValidatorSet {
+	if sb.currentBlock == nil {
+		return nil
+	}
+	return sb.Validators(new(big.Int).Add(sb.currentBlock().Number(), common.Big1))
+}
+
+// isNextValidator returns true if the address is in the validator set of the next block
+func (sb *Backend) isNextValidator(addr common.Address) bool {
+	valSet := sb.nextValidators()
+	if valSet == nil {
+		return false
+	}
+	_, val := valSet.GetByAddress(addr)
+	return val != nil
+}
+
+// relayLoop forwards the queued messages one at a time: the messages of the private validators are sent to
+// the other validators, and the other messages are sent to the private validators.
+func (sb *Backend) relayLoop() {
+	for {
+		select {
+		case msg := <-sb.relayCh:
+			sent := make(map[consensus.Peer]bool)
+			for _, p := range sb.broadcaster.FindPeers(map[common.Address]bool{msg.from: true}) {
+				sent[p] = true
+			}
+			for addr, p := range sb.relayTargets(msg) {
+				if sent[p] {
+					continue
+				}
+				sent[p] = true
+				if err := p.Send(consensus.TendermintMsg, msg.payload); err != nil {
+					log.Debug("failed to relay message", "err", err, "from", msg.from, "to", addr)
+				}
+			}
+		case <-sb.closingBackgroundThreadsCh:
+			return
+		}
+	}
+}
+
+// relayTargets returns the peers to relay the message to
+func (sb *Backend) relayTargets(msg relayedMsg) map[common.Address]consensus.Peer {
+	if !sb.privateValidators[msg.from] {
+		return sb.broadcaster.FindPeers(sb.privateValidators)
+	}
+	valSet := sb.nextValidators()
+	if valSet == nil {
+		return nil
+	}
+	validators := make(map[common.Address]bool)
+	for _, val := range valSet.List() {
+		if val.Address() != msg.signer {
+			validators[val.Address()] = true
+		}
+	}
+	return sb.findPeers(validators)
+}
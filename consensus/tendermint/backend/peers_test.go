@@ -0,0 +1,159 @@
+package backend
+
+import (
+	"crypto/ecdsa"
+	"testing"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/consensus"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/backend/fixed_valset_info"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/tests_utils"
+	"github.com/Evrynetlabs/evrynet-node/core/types"
+	"github.com/Evrynetlabs/evrynet-node/crypto"
+	"github.com/Evrynetlabs/evrynet-node/rlp"
+)
+
+// connectedBroadcaster finds the connected peers only
+type connectedBroadcaster map[common.Address]consensus.Peer
+
+func (m connectedBroadcaster) FindPeers(targets map[common.Address]bool) map[common.Address]consensus.Peer {
+	out := make(map[common.Address]consensus.Peer)
+	for addr := range targets {
+		if p, ok := m[addr]; ok {
+			out[addr] = p
+		}
+	}
+	return out
+}
+
+func (m connectedBroadcaster) Enqueue(id string, block *types.Block) {}
+
+func TestBackend_FindPeers(t *testing.T) {
+	var (
+		validatorA = common.HexToAddress("0x0a")
+		validatorB = common.HexToAddress("0x0b")
+		validatorC = common.HexToAddress("0x0c")
+		nodeB      = common.HexToAddress("0xb0")
+		peerA      = &tests_utils.MockPeer{}
+		peerB      = &tests_utils.MockPeer{}
+	)
+	be := &Backend{
+		broadcaster: connectedBroadcaster{validatorA: peerA, nodeB: peerB},
+		validatorNodes: newValidatorNodes([]tendermint.ValidatorNodes{
+			{Validator: validatorB, Nodes: []common.Address{common.HexToAddress("0xb1"), nodeB}},
+		}),
+	}
+	peers := be.findPeers(map[common.Address]bool{validatorA: true, validatorB: true, validatorC: true})
+	assert.Equal(t, map[common.Address]consensus.Peer{validatorA: peerA, validatorB: peerB}, peers)
+}
+
+func TestBackend_FindPeersBehindSentries(t *testing.T) {
+	var (
+		sentryA    = common.HexToAddress("0xa0")
+		sentryB    = common.HexToAddress("0xb0")
+		validatorC = common.HexToAddress("0x0c")
+		validatorD = common.HexToAddress("0x0d")
+		peerB      = &tests_utils.MockPeer{}
+	)
+	be := &Backend{
+		broadcaster: connectedBroadcaster{sentryB: peerB},
+		sentries:    map[common.Address]bool{sentryA: true, sentryB: true},
+	}
+	// the validators are reached through any sentry, a sentry only through itself
+	peers := be.findPeers(map[common.Address]bool{validatorC: true, validatorD: true})
+	assert.Equal(t, map[common.Address]consensus.Peer{validatorC: peerB, validatorD: peerB}, peers)
+	peers = be.findPeers(be.messageTargets(map[common.Address]bool{validatorC: true}))
+	assert.Equal(t, map[common.Address]consensus.Peer{sentryB: peerB}, peers)
+
+	be.broadcaster = connectedBroadcaster{}
+	assert.Empty(t, be.findPeers(map[common.Address]bool{validatorC: true}))
+}
+
+// signedPayload returns the payload of a consensus message signed by the key
+func signedPayload(t *testing.T, key *ecdsa.PrivateKey, data []byte) []byte {
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	unsigned, err := rlp.EncodeToBytes([]interface{}{uint64(1), data, addr, []byte{}})
+	require.NoError(t, err)
+	sig, err := crypto.Sign(crypto.Keccak256(unsigned), key)
+	require.NoError(t, err)
+	payload, err := rlp.EncodeToBytes([]interface{}{uint64(1), data, addr, sig})
+	require.NoError(t, err)
+	return payload
+}
+
+func TestBackend_Relay(t *testing.T) {
+	var (
+		privateKey, _  = crypto.GenerateKey()
+		otherKey, _    = crypto.GenerateKey()
+		outsiderKey, _ = crypto.GenerateKey()
+		validator      = crypto.PubkeyToAddress(privateKey.PublicKey)
+		otherValidator = crypto.PubkeyToAddress(otherKey.PublicKey)
+		private        = common.HexToAddress("0x01")
+		stranger       = common.HexToAddress("0x03")
+		sent           = make(chan common.Address, 4)
+		peer           = func(addr common.Address) *tests_utils.MockPeer {
+			return &tests_utils.MockPeer{SendFn: func(interface{}) error {
+				sent <- addr
+				return nil
+			}}
+		}
+		relayedMsgs, _ = lru.New(inMemoryRelayedMsgs)
+		head           = types.NewBlockWithHeader(&types.Header{Number: common.Big0})
+	)
+	be := &Backend{
+		broadcaster: connectedBroadcaster{
+			private:        peer(private),
+			otherValidator: peer(otherValidator),
+			stranger:       peer(stranger),
+		},
+		valSetInfo:                 fixed_valset_info.NewFixedValidatorSetInfo([]common.Address{validator, otherValidator}),
+		currentBlock:               func() *types.Block { return head },
+		privateValidators:          map[common.Address]bool{private: true},
+		relayedMsgs:                relayedMsgs,
+		relayCh:                    make(chan relayedMsg, relayChanSize),
+		closingBackgroundThreadsCh: make(chan struct{}),
+	}
+	go be.relayLoop()
+	defer close(be.closingBackgroundThreadsCh)
+
+	received := func() common.Address {
+		select {
+		case addr := <-sent:
+			return addr
+		case <-time.After(time.Second):
+			t.Fatal("message is not relayed")
+		}
+		return common.Address{}
+	}
+	notReceived := func() {
+		select {
+		case addr := <-sent:
+			t.Fatalf("message is relayed to %s", addr.Hex())
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+
+	// the messages of the private validator are relayed to the other validators, the other messages to the private validator
+	be.relay(private, common.HexToHash("0x01"), signedPayload(t, privateKey, []byte("a")))
+	assert.Equal(t, otherValidator, received())
+	notReceived()
+	be.relay(otherValidator, common.HexToHash("0x02"), signedPayload(t, otherKey, []byte("b")))
+	assert.Equal(t, private, received())
+
+	// a message is relayed once
+	be.relay(otherValidator, common.HexToHash("0x02"), signedPayload(t, otherKey, []byte("b")))
+	notReceived()
+
+	// the messages which are not signed by a validator are not relayed
+	be.relay(stranger, common.HexToHash("0x03"), signedPayload(t, outsiderKey, []byte("c")))
+	notReceived()
+	be.relay(stranger, common.HexToHash("0x04"), []byte("d"))
+	notReceived()
+}
@@ -1,27 +1,34 @@
 package backend
 
 import (
+	"bytes"
 	"math/big"
+	"sort"
 	"time"
 
 	"github.com/Evrynetlabs/evrynet-node/common"
 	"github.com/Evrynetlabs/evrynet-node/consensus"
 	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint"
 	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/utils"
+	"github.com/Evrynetlabs/evrynet-node/core/rawdb"
 	"github.com/Evrynetlabs/evrynet-node/core/state"
 	"github.com/Evrynetlabs/evrynet-node/core/state/staking"
 	"github.com/Evrynetlabs/evrynet-node/core/types"
 	"github.com/Evrynetlabs/evrynet-node/log"
+	"github.com/Evrynetlabs/evrynet-node/params"
 )
 
+// defaultCommissionRate is used if the chain config does not specify the default commission rate
+const defaultCommissionRate uint64 = 50
+
 // AccumulateRewards credits the coinbase of the given block with the proposing
-// reward.
-func (sb *Backend) accumulateRewards(chainReader consensus.FullChainReader, state *state.StateDB, header *types.Header) error {
+// reward. It returns the reward ledger of the epoch if the block ends an epoch.
+func (sb *Backend) accumulateRewards(chainReader consensus.FullChainReader, state *state.StateDB, header *types.Header) ([]*types.ValidatorReward, error) {
 	// If fixed validators (test) then return
 	if chainReader.Config().Tendermint.FixedValidators != nil {
 		reward := new(big.Int).Set(chainReader.Config().Tendermint.BlockReward)
 		state.AddBalance(header.Coinbase, reward)
-		return nil
+		return nil, nil
 	}
 	var (
 		currentBlock = header.Number.Uint64()
@@ -30,42 +37,95 @@ func (sb *Backend) accumulateRewards(chainReader consensus.FullChainReader, stat
 	)
 
 	if currentBlock == 0 {
-		return tendermint.ErrFinalizeZeroBlock
+		return nil, tendermint.ErrFinalizeZeroBlock
 	}
 
 	if currentBlock%epoch != 0 {
-		return nil
+		return nil, nil
 	}
 
-	validatorsRewards := calculateTotalValidatorsRewards(chainReader, epoch, header)
+	rewards, err := sb.epochRewards(chainReader, header)
+	if err != nil {
+		return nil, err
+	}
+	for _, reward := range rewards {
+		state.AddBalance(reward.Owner, reward.OwnerReward)
+		for i, voter := range reward.Voters {
+			state.AddBalance(voter, reward.VoterRewards[i])
+		}
+	}
+	log.Debug("accumulateRewards", "number", currentBlock, "elapsed", common.PrettyDuration(time.Since(start)))
+	return rewards, nil
+}
+
+// epochRewards returns the reward ledger of the epoch ended by the given header.
+func (sb *Backend) epochRewards(chainReader consensus.FullChainReader, header *types.Header) ([]*types.ValidatorReward, error) {
+	var (
+		currentBlock = header.Number.Uint64()
+		epoch        = chainReader.Config().Tendermint.Epoch
+	)
+	blockRewards, txFees := calculateTotalValidatorsRewards(chainReader, epoch, header)
 	transitionHeader := chainReader.GetHeaderByNumber(currentBlock - epoch)
+	if transitionHeader == nil {
+		return nil, consensus.ErrUnknownAncestor
+	}
 	validatorAdds, err := utils.GetValSetAddresses(transitionHeader)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	stateDB, err := chainReader.StateAt(transitionHeader.Root)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	stakingCaller := sb.getStakingCaller(chainReader, stateDB, header)
 	validatorsData, err := stakingCaller.GetValidatorsData(*sb.config.StakingSCAddress, validatorAdds)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	finalReward := calculateReward(validatorsData, validatorsRewards)
-	for addr, value := range finalReward {
-		state.AddBalance(addr, value)
+	// the validators data is read at the state of the epoch transition, so a commission rate set during an epoch
+	// only applies to the rewards of the next epoch
+	return distributeRewards(chainReader.Config().Tendermint, currentBlock/epoch, validatorsData, blockRewards, txFees), nil
+}
+
+// storeEpochRewards stores the reward ledger of the epoch ended by the block with the given hash if the database is set.
+func (sb *Backend) storeEpochRewards(hash common.Hash, rewards []*types.ValidatorReward) {
+	if sb.db != nil && rewards != nil {
+		rawdb.WriteEpochRewards(sb.db, hash, rewards)
+	}
+}
+
+// commitEpochRewards stores the reward ledger of a committed or inserted block, computed when this node proposed
+// or finalized it.
+func (sb *Backend) commitEpochRewards(header *types.Header) {
+	sealHash := sb.SealHash(header)
+	if rewards, ok := sb.proposedRewards.Get(sealHash); ok {
+		sb.storeEpochRewards(header.Hash(), rewards.([]*types.ValidatorReward))
+		sb.proposedRewards.Remove(sealHash)
+		return
+	}
+	hash := header.Hash()
+	if rewards, ok := sb.finalizedRewards.Get(hash); ok {
+		sb.storeEpochRewards(hash, rewards.([]*types.ValidatorReward))
+		sb.finalizedRewards.Remove(hash)
 	}
-	log.Debug("accumulateRewards", "number", currentBlock, "elapsed", common.PrettyDuration(time.Since(start)))
-	return nil
 }
 
 // calculateTotalValidatorsRewards gets reward from chainReader and current header (from finalize)
-// reward includes block rewards and tx fee from block number currentBlock - epoch +1
-func calculateTotalValidatorsRewards(chainReader consensus.ChainReader, epoch uint64, header *types.Header) map[common.Address]*big.Int {
-	var currentBlock = header.Number.Uint64()
-	validatorsRewards := make(map[common.Address]*big.Int)
+// reward includes block rewards and tx fee from block number currentBlock - epoch +1, they are returned separately
+func calculateTotalValidatorsRewards(chainReader consensus.ChainReader, epoch uint64, header *types.Header) (map[common.Address]*big.Int, map[common.Address]*big.Int) {
+	var (
+		currentBlock = header.Number.Uint64()
+		blockRewards = make(map[common.Address]*big.Int)
+		txFees       = make(map[common.Address]*big.Int)
+	)
+	addTo := func(rewards map[common.Address]*big.Int, addr common.Address, value *big.Int) {
+		if current, ok := rewards[addr]; ok {
+			rewards[addr] = new(big.Int).Add(current, value)
+		} else {
+			rewards[addr] = new(big.Int).Set(value)
+		}
+	}
 	for i := currentBlock - epoch + 1; i <= currentBlock; i++ {
 		var currentHeader *types.Header
 		if i != currentBlock {
@@ -74,45 +134,98 @@ func calculateTotalValidatorsRewards(chainReader consensus.ChainReader, epoch ui
 			currentHeader = header
 		}
 		txFee := new(big.Int).Mul(big.NewInt(int64(currentHeader.GasUsed)), chainReader.Config().GasPrice)
-		reward := new(big.Int).Add(chainReader.Config().Tendermint.BlockReward, txFee)
-		if current, ok := validatorsRewards[currentHeader.Coinbase]; ok {
-			validatorsRewards[currentHeader.Coinbase] = new(big.Int).Add(current, reward)
-		} else {
-			validatorsRewards[currentHeader.Coinbase] = reward
-		}
+		addTo(blockRewards, currentHeader.Coinbase, chainReader.Config().Tendermint.BlockReward)
+		addTo(txFees, currentHeader.Coinbase, txFee)
 	}
-	return validatorsRewards
+	return blockRewards, txFees
 }
 
-// calculateReward divides rewards into 50% to owner and 50% among voters
-// rewards for voters is proportional to voters'stake
-func calculateReward(validatorsData map[common.Address]staking.CandidateData, validatorsReward map[common.Address]*big.Int) map[common.Address]*big.Int {
-	finalReward := make(map[common.Address]*big.Int)
-	addReward := func(addr common.Address, value *big.Int) {
-		if current, ok := finalReward[addr]; ok {
-			finalReward[addr] = new(big.Int).Add(current, value)
-		} else {
-			finalReward[addr] = new(big.Int).Set(value)
+// distributeRewards divides rewards between the owner and the voters of each validator by its commission rate,
+// the owner keeps the commission and the rewards for voters is proportional to voters'stake.
+// The ledger is sorted by validator and the voters of each validator by address.
+func distributeRewards(config *params.TendermintConfig, epoch uint64, validatorsData map[common.Address]staking.CandidateData,
+	blockRewards, txFees map[common.Address]*big.Int) []*types.ValidatorReward {
+	validators := make([]common.Address, 0, len(validatorsData))
+	for addr := range validatorsData {
+		if _, ok := blockRewards[addr]; ok {
+			validators = append(validators, addr)
 		}
 	}
-	for addr, validatorData := range validatorsData {
-		totalReward, ok := validatorsReward[addr]
-		if !ok {
-			continue
+	sort.Slice(validators, func(i, j int) bool {
+		return bytes.Compare(validators[i][:], validators[j][:]) < 0
+	})
+
+	rewards := make([]*types.ValidatorReward, 0, len(validators))
+	for _, addr := range validators {
+		var (
+			validatorData = validatorsData[addr]
+			txFee         = new(big.Int)
+		)
+		if fee, ok := txFees[addr]; ok {
+			txFee.Set(fee)
 		}
+		reward := &types.ValidatorReward{
+			Epoch:          epoch,
+			Validator:      addr,
+			Owner:          validatorData.Owner,
+			BlockReward:    new(big.Int).Set(blockRewards[addr]),
+			TxFee:          txFee,
+			CommissionRate: commissionRate(config, validatorData),
+			Voters:         []common.Address{},
+			VoterRewards:   []*big.Int{},
+		}
+		totalReward := new(big.Int).Add(reward.BlockReward, reward.TxFee)
 		// remainingReward to ensure the total reward for the voters and owner is equals to the wei validator earns
 		remainingReward := new(big.Int).Set(totalReward)
-		totalVoterReward := new(big.Int).Mul(totalReward, big.NewInt(voterRewardPercentage))
+		voterRewardPercentage := 100 - reward.CommissionRate
+		totalVoterReward := new(big.Int).Mul(totalReward, new(big.Int).SetUint64(voterRewardPercentage))
 		totalVoterReward = new(big.Int).Div(totalVoterReward, big.NewInt(100))
-		for voter, voterStake := range validatorData.VoterStakes {
-			voterReward := new(big.Int).Mul(totalVoterReward, voterStake)
-			voterReward = new(big.Int).Div(voterReward, validatorData.TotalStake)
-			addReward(voter, voterReward)
-			remainingReward.Sub(remainingReward, voterReward)
+		if validatorData.TotalStake != nil && validatorData.TotalStake.Sign() > 0 {
+			for voter := range validatorData.VoterStakes {
+				reward.Voters = append(reward.Voters, voter)
+			}
+			sort.Slice(reward.Voters, func(i, j int) bool {
+				return bytes.Compare(reward.Voters[i][:], reward.Voters[j][:]) < 0
+			})
+			for _, voter := range reward.Voters {
+				voterReward := new(big.Int).Mul(totalVoterReward, validatorData.VoterStakes[voter])
+				voterReward = new(big.Int).Div(voterReward, validatorData.TotalStake)
+				reward.VoterRewards = append(reward.VoterRewards, voterReward)
+				remainingReward.Sub(remainingReward, voterReward)
+			}
 		}
-		validatorReward := new(big.Int).Mul(totalReward, big.NewInt(validatorRewardPercentage))
-		validatorReward = new(big.Int).Div(validatorReward, big.NewInt(100))
-		addReward(validatorData.Owner, remainingReward)
+		reward.OwnerReward = remainingReward
+		rewards = append(rewards, reward)
+	}
+	return rewards
+}
+
+// commissionRate returns the percentage of the rewards kept by the owner of the validator,
+// the rate set by the candidate or the default one, within the bounds of the chain config
+func commissionRate(config *params.TendermintConfig, validatorData staking.CandidateData) uint64 {
+	var (
+		rate    = defaultCommissionRate
+		maxRate = maxCommissionRate(config)
+	)
+	if config.DefaultCommissionRate != 0 {
+		rate = config.DefaultCommissionRate
+	}
+	if validatorData.CommissionRate != nil {
+		rate = *validatorData.CommissionRate
+	}
+	if rate < config.MinCommissionRate {
+		rate = config.MinCommissionRate
+	}
+	if rate > maxRate {
+		rate = maxRate
+	}
+	return rate
+}
+
+// maxCommissionRate returns the maximum commission rate a candidate can set
+func maxCommissionRate(config *params.TendermintConfig) uint64 {
+	if config.MaxCommissionRate == 0 || config.MaxCommissionRate > 100 {
+		return 100
 	}
-	return finalReward
+	return config.MaxCommissionRate
 }
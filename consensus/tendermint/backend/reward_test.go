@@ -2,20 +2,26 @@ package backend
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"os"
 	"strings"
 	"testing"
 
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/stretchr/testify/require"
 
 	"github.com/Evrynetlabs/evrynet-node/accounts/abi"
 	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/consensus"
 	"github.com/Evrynetlabs/evrynet-node/consensus/staking_contracts"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/signer"
 	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/tests_utils"
 	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/utils"
 	"github.com/Evrynetlabs/evrynet-node/core"
+	"github.com/Evrynetlabs/evrynet-node/core/rawdb"
+	"github.com/Evrynetlabs/evrynet-node/core/state/staking"
 	"github.com/Evrynetlabs/evrynet-node/core/types"
 	"github.com/Evrynetlabs/evrynet-node/log"
 	"github.com/Evrynetlabs/evrynet-node/params"
@@ -163,3 +169,207 @@ func testFinalize(t *testing.T, generate func(int, *core.BlockGen), n int, asser
 		assertFn(chain)
 	}
 }
+
+func TestCalculateReward(t *testing.T) {
+	var (
+		validator = common.HexToAddress("0x1000")
+		owner     = common.HexToAddress("0x2000")
+		voter     = common.HexToAddress("0x3000")
+		rate      = uint64(20)
+		data      = staking.CandidateData{
+			Owner:       owner,
+			TotalStake:  big.NewInt(400),
+			VoterStakes: map[common.Address]*big.Int{owner: big.NewInt(100), voter: big.NewInt(300)},
+		}
+		rewards = map[common.Address]*big.Int{validator: big.NewInt(1000)}
+	)
+	tests := []struct {
+		config         *params.TendermintConfig
+		commissionRate *uint64
+		owner, voter   int64
+	}{
+		// the default commission rate is 50%
+		{config: &params.TendermintConfig{}, owner: 625, voter: 375},
+		{config: &params.TendermintConfig{DefaultCommissionRate: 10}, owner: 325, voter: 675},
+		{config: &params.TendermintConfig{}, commissionRate: &rate, owner: 400, voter: 600},
+		// the commission rate is kept within the bounds of the chain config
+		{config: &params.TendermintConfig{MinCommissionRate: 30}, commissionRate: &rate, owner: 475, voter: 525},
+		{config: &params.TendermintConfig{MaxCommissionRate: 10}, owner: 325, voter: 675},
+	}
+	for i, test := range tests {
+		data.CommissionRate = test.commissionRate
+		finalReward := rewardsByAddress(distributeRewards(test.config, 1, map[common.Address]staking.CandidateData{validator: data}, rewards, nil))
+		require.Equal(t, big.NewInt(test.owner), finalReward[owner], "test %d: owner reward", i)
+		require.Equal(t, big.NewInt(test.voter), finalReward[voter], "test %d: voter reward", i)
+	}
+}
+
+func TestDistributeRewards(t *testing.T) {
+	var (
+		validatorA = common.HexToAddress("0x1000")
+		validatorB = common.HexToAddress("0x1100")
+		validatorC = common.HexToAddress("0x1200")
+		owner      = common.HexToAddress("0x2000")
+		voterA     = common.HexToAddress("0x3000")
+		voterB     = common.HexToAddress("0x3100")
+		rate       = uint64(10)
+		config     = &params.TendermintConfig{}
+	)
+	validatorsData := map[common.Address]staking.CandidateData{
+		validatorB: {
+			Owner:          owner,
+			TotalStake:     big.NewInt(300),
+			VoterStakes:    map[common.Address]*big.Int{voterB: big.NewInt(200), voterA: big.NewInt(100)},
+			CommissionRate: &rate,
+		},
+		validatorA: {
+			Owner:       owner,
+			TotalStake:  big.NewInt(0),
+			VoterStakes: map[common.Address]*big.Int{},
+		},
+		// validatorC did not propose any block of the epoch
+		validatorC: {Owner: owner, TotalStake: big.NewInt(100), VoterStakes: map[common.Address]*big.Int{voterA: big.NewInt(100)}},
+	}
+	blockRewards := map[common.Address]*big.Int{validatorA: big.NewInt(400), validatorB: big.NewInt(800)}
+	txFees := map[common.Address]*big.Int{validatorB: big.NewInt(200)}
+
+	rewards := distributeRewards(config, 3, validatorsData, blockRewards, txFees)
+	require.Len(t, rewards, 2)
+
+	// the validator without stake pays everything to its owner
+	require.Equal(t, validatorA, rewards[0].Validator)
+	require.Equal(t, uint64(3), rewards[0].Epoch)
+	require.Equal(t, big.NewInt(0), rewards[0].TxFee)
+	require.Equal(t, big.NewInt(400), rewards[0].OwnerReward)
+	require.Empty(t, rewards[0].Voters)
+
+	// the voters are sorted by address, the owner keeps the commission and the rounding remainder
+	require.Equal(t, validatorB, rewards[1].Validator)
+	require.Equal(t, big.NewInt(800), rewards[1].BlockReward)
+	require.Equal(t, big.NewInt(200), rewards[1].TxFee)
+	require.Equal(t, rate, rewards[1].CommissionRate)
+	require.Equal(t, []common.Address{voterA, voterB}, rewards[1].Voters)
+	require.Equal(t, []*big.Int{big.NewInt(300), big.NewInt(600)}, rewards[1].VoterRewards)
+	require.Equal(t, big.NewInt(100), rewards[1].OwnerReward)
+
+	// the ledger survives a JSON round trip
+	data, err := json.Marshal(rewards)
+	require.NoError(t, err)
+	var decoded []*types.ValidatorReward
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	encoded, err := json.Marshal(decoded)
+	require.NoError(t, err)
+	require.JSONEq(t, string(data), string(encoded))
+}
+
+func TestCommitEpochRewards(t *testing.T) {
+	privateKey, err := tests_utils.GeneratePrivateKey()
+	require.NoError(t, err)
+	proposedRewards, _ := lru.NewARC(inMemoryProposedRewards)
+	finalizedRewards, _ := lru.NewARC(inMemoryFinalizedRewards)
+	be := &Backend{
+		signer:           signer.NewLocalSigner(privateKey, nil),
+		db:               rawdb.NewMemoryDatabase(),
+		proposedRewards:  proposedRewards,
+		finalizedRewards: finalizedRewards,
+	}
+	header := &types.Header{Number: big.NewInt(10), MixDigest: types.TendermintDigest}
+	header.Extra, err = tests_utils.PrepareExtra(header)
+	require.NoError(t, err)
+	rewards := []*types.ValidatorReward{{
+		Epoch:        1,
+		Validator:    common.HexToAddress("0x1000"),
+		Owner:        common.HexToAddress("0x2000"),
+		BlockReward:  big.NewInt(100),
+		TxFee:        big.NewInt(0),
+		OwnerReward:  big.NewInt(100),
+		Voters:       []common.Address{},
+		VoterRewards: []*big.Int{},
+	}}
+	be.proposedRewards.Add(be.SealHash(header), rewards)
+
+	// the ledger of the proposed block is stored with the hash of the sealed block once it is committed
+	require.NoError(t, be.addProposalSeal(header))
+	block := types.NewBlockWithHeader(header)
+	be.commitEpochRewards(block.Header())
+	stored := rawdb.ReadEpochRewards(be.db, block.Hash())
+	require.Len(t, stored, 1)
+	require.Equal(t, rewards[0].Validator, stored[0].Validator)
+	require.Equal(t, rewards[0].OwnerReward, stored[0].OwnerReward)
+	require.Equal(t, 0, be.proposedRewards.Len())
+
+	// the ledger of a finalized block is only stored once the block is committed or inserted
+	other := &types.Header{Number: big.NewInt(20), MixDigest: types.TendermintDigest}
+	other.Extra, err = tests_utils.PrepareExtra(other)
+	require.NoError(t, err)
+	be.finalizedRewards.Add(other.Hash(), rewards)
+	require.Nil(t, rawdb.ReadEpochRewards(be.db, other.Hash()))
+	be.commitEpochRewards(other)
+	require.Len(t, rawdb.ReadEpochRewards(be.db, other.Hash()), 1)
+	require.Equal(t, 0, be.finalizedRewards.Len())
+}
+
+// rewardsChainReader serves the headers of a chain without its state
+type rewardsChainReader struct {
+	consensus.ChainReader
+	config *params.ChainConfig
+}
+
+func (c *rewardsChainReader) Config() *params.ChainConfig {
+	return c.config
+}
+
+func TestGetRewards(t *testing.T) {
+	var (
+		validator = common.HexToAddress("0x1000")
+		headers   []*types.Header
+	)
+	for i := int64(0); i <= 20; i++ {
+		headers = append(headers, &types.Header{Number: big.NewInt(i)})
+	}
+	api := &TendermintAPI{
+		chain: &rewardsChainReader{
+			ChainReader: tests_utils.NewHeadersMockChainReader(headers),
+			config:      &params.ChainConfig{Tendermint: &params.TendermintConfig{Epoch: 10}},
+		},
+		be: &Backend{db: rawdb.NewMemoryDatabase()},
+	}
+	rawdb.WriteEpochRewards(api.be.db, headers[10].Hash(), []*types.ValidatorReward{{
+		Epoch:        1,
+		Validator:    validator,
+		Owner:        validator,
+		BlockReward:  big.NewInt(100),
+		TxFee:        big.NewInt(0),
+		OwnerReward:  big.NewInt(100),
+		Voters:       []common.Address{},
+		VoterRewards: []*big.Int{},
+	}})
+
+	rewards, err := api.GetRewards(validator, 1, 1)
+	require.NoError(t, err)
+	require.Len(t, rewards, 1)
+	require.Equal(t, big.NewInt(100), rewards[0].OwnerReward)
+
+	// the ledger of epoch 2 is not stored and can not be computed again without the chain state
+	_, err = api.GetRewards(validator, 1, 2)
+	require.Error(t, err)
+}
+
+// rewardsByAddress sums up the rewards credited to each address by the reward ledger
+func rewardsByAddress(rewards []*types.ValidatorReward) map[common.Address]*big.Int {
+	finalReward := make(map[common.Address]*big.Int)
+	addReward := func(addr common.Address, value *big.Int) {
+		if current, ok := finalReward[addr]; ok {
+			finalReward[addr] = new(big.Int).Add(current, value)
+		} else {
+			finalReward[addr] = new(big.Int).Set(value)
+		}
+	}
+	for _, reward := range rewards {
+		for i, voter := range reward.Voters {
+			addReward(voter, reward.VoterRewards[i])
+		}
+		addReward(reward.Owner, reward.OwnerReward)
+	}
+	return finalReward
+}
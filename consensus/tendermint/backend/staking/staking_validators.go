@@ -39,11 +39,10 @@ func (v *StakingValidator) GetValSet(chainReader consensus.ChainReader, number *
 		return valSet, tendermint.ErrUnknownBlock
 	}
 
-	validatorAdds, err := utils.GetValSetAddresses(header)
+	headerValSet, err := utils.GetValSet(header, v.ProposerPolicy, blockNumber)
 	if err != nil {
-		log.Error("can't get the validators's address from extra-data", "number", blockNumber)
+		log.Error("can't get the validators from extra-data", "number", blockNumber, "err", err)
 		return valSet, err
 	}
-
-	return validator.NewSet(validatorAdds, v.ProposerPolicy, blockNumber), nil
+	return headerValSet, nil
 }
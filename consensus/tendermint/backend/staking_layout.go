@@ -0,0 +1,27 @@
+package backend
+
+import (
+	"github.com/Evrynetlabs/evrynet-node/consensus"
+	"github.com/Evrynetlabs/evrynet-node/core/state/staking"
+	"github.com/Evrynetlabs/evrynet-node/log"
+)
+
+// VerifyStakingLayout cross-checks the layout used to read the staking contract from the state DB against the contract
+// itself, called through the EVM at the head of the chain, so the node does not elect validators with a wrong layout.
+func (sb *Backend) VerifyStakingLayout(chain consensus.FullChainReader) error {
+	if len(sb.config.FixedValidators) > 0 {
+		return nil
+	}
+	header := chain.CurrentHeader()
+	stateDB, err := chain.StateAt(header.Root)
+	if err != nil {
+		// the state of the head is not available while fast syncing, the layout is checked at the next start
+		log.Warn("Skip the staking layout verification", "number", header.Number, "err", err)
+		return nil
+	}
+	if len(stateDB.GetCode(sb.stakingContractAddr)) == 0 {
+		return nil
+	}
+	chainContext := staking.NewChainContextWrapper(sb, chain.GetHeader)
+	return staking.VerifyIndexConfigs(stateDB, chainContext, header, chain.Config(), sb.stakingContractAddr, sb.config.IndexStateVariables)
+}
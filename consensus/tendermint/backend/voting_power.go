@@ -0,0 +1,80 @@
+package backend
+
+import (
+	"math/big"
+	"reflect"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/consensus"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/utils"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/validator"
+	"github.com/Evrynetlabs/evrynet-node/core/types"
+	"github.com/Evrynetlabs/evrynet-node/params"
+)
+
+// votingPowerUnit is the amount of stake for a voting power of 1
+var votingPowerUnit = big.NewInt(params.Ether)
+
+// stakeToVotingPower converts the total stake of a validator to its voting power.
+// A validator always has a voting power of at least 1.
+func stakeToVotingPower(stake *big.Int) uint64 {
+	if stake == nil {
+		return 1
+	}
+	power := new(big.Int).Div(stake, votingPowerUnit)
+	if power.Sign() <= 0 {
+		return 1
+	}
+	if !power.IsUint64() || power.Uint64() > validator.MaxTotalVotingPower {
+		return validator.MaxTotalVotingPower
+	}
+	return power.Uint64()
+}
+
+// getNextValidatorPowers returns the voting powers of the validators for the next epoch, computed from the total stakes
+// at the state of header. The powers are in the same order as validators.
+func (sb *Backend) getNextValidatorPowers(chainReader consensus.FullChainReader, header *types.Header, validators []common.Address) ([]uint64, error) {
+	stateDB, err := chainReader.StateAt(header.Root)
+	if err != nil {
+		return nil, err
+	}
+	validatorsData, err := sb.getStakingCaller(chainReader, stateDB, header).GetValidatorsData(sb.stakingContractAddr, validators)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		powers = make([]uint64, len(validators))
+		total  uint64
+	)
+	for i, val := range validators {
+		powers[i] = stakeToVotingPower(validatorsData[val].TotalStake)
+		total += powers[i]
+		if total > validator.MaxTotalVotingPower {
+			return nil, utils.ErrInvalidVotingPower
+		}
+	}
+	return powers, nil
+}
+
+// verifyValSetPowers checks that the voting powers in the header match the ones computed from the parent's state
+func (sb *Backend) verifyValSetPowers(chainReader consensus.FullChainReader, header *types.Header, parent *types.Header, validators []common.Address) error {
+	powersInHeader, err := utils.GetValSetPowers(header)
+	if err != nil {
+		return err
+	}
+	if !sb.config.StakeWeightedVoting {
+		if len(powersInHeader) != 0 {
+			return tendermint.ErrMismatchValSet
+		}
+		return nil
+	}
+	powers, err := sb.getNextValidatorPowers(chainReader, parent, validators)
+	if err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(powers, powersInHeader) {
+		return tendermint.ErrMismatchValSet
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/Evrynetlabs/evrynet-node/common"
 	"github.com/Evrynetlabs/evrynet-node/core/state/staking"
+	"github.com/Evrynetlabs/evrynet-node/p2p/enode"
 )
 
 type ProposerPolicy uint64
@@ -13,6 +14,9 @@ type ProposerPolicy uint64
 const (
 	RoundRobin ProposerPolicy = iota
 	Sticky
+	// WeightedPriority selects the proposer by the proposer priority of validators, which grows with their voting power.
+	// It is always used by a stake-weighted validator set.
+	WeightedPriority
 )
 
 //FaultyMode is the config mode to enable fauty node
@@ -31,6 +35,13 @@ func (f FaultyMode) Uint64() uint64 {
 	return uint64(f)
 }
 
+// ValidatorNodes are the addresses of the node keys to reach a validator at,
+// when the validator signs with a remote signer rather than with its node key or is behind sentries
+type ValidatorNodes struct {
+	Validator common.Address
+	Nodes     []common.Address
+}
+
 //Config store all the configuration required for a Tendermint consensus
 type Config struct {
 	ProposerPolicy        ProposerPolicy   `toml:",omitempty"` // The policy for proposer selection
@@ -51,6 +62,18 @@ type Config struct {
 
 	UseEVMCaller        bool
 	IndexStateVariables *staking.IndexConfigs //The index of state variables has stored in stateDB
+	StakingLayoutFile   string                `toml:",omitempty"` // The solc storage layout file of the staking contract, overrides the layout of the genesis
+	RemoteSigner        string                `toml:",omitempty"` // The IPC path or URL of the signer process holding the validator keys, the node key signs if empty
+	ValidatorNodes      []ValidatorNodes      `toml:",omitempty"` // The nodes of the validators which do not sign with their node key or are behind sentries
+	Sentries            []*enode.Node         `toml:",omitempty"` // The sentries of the validator, it only connects to them and sends its messages through them
+	PrivateValidators   []*enode.Node         `toml:",omitempty"` // The validators fronted by the sentry, it relays their messages to its other peers and the other messages to them
+
+	StakeWeightedVoting bool `toml:",omitempty"` // If true, the voting power of a validator is proportional to its total stake
+	BLSCommittedSeals   bool `toml:",omitempty"` // If true, blocks are committed with an aggregated BLS seal once every validator registers a BLS key
+
+	SignedBlocksWindow   uint64 `toml:",omitempty"` // The number of recent blocks to track the liveness of the validators, 0 to disable the downtime jailing
+	MinSignedPerWindow   uint64 `toml:",omitempty"` // The minimum percentage of the window a validator must sign not to be jailed, 0 for 50%
+	DowntimeJailDuration uint64 `toml:",omitempty"` // The number of blocks before a validator jailed for downtime can be unjailed, 0 for the window size
 }
 
 var DefaultConfig = &Config{
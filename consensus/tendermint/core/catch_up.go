@@ -113,5 +113,7 @@ func (c *core) sendCatchUpRequest(logger *zap.SugaredLogger, tiBlock *big.Int, t
 
 	if err := c.backend.Multicast(missing, payload); err != nil {
 		logger.Debugw("Failed to multicast msg", "err", err.Error())
+		return
 	}
+	tendermintCatchUpSentMeter.Mark(1)
 }
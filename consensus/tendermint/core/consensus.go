@@ -11,6 +11,7 @@ import (
 	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint"
 	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/utils"
 	"github.com/Evrynetlabs/evrynet-node/core/types"
+	"github.com/Evrynetlabs/evrynet-node/crypto/bls"
 	"github.com/Evrynetlabs/evrynet-node/log"
 	"github.com/Evrynetlabs/evrynet-node/metrics"
 	"github.com/Evrynetlabs/evrynet-node/rlp"
@@ -59,7 +60,7 @@ func (c *core) enterNewRound(blockNumber *big.Int, round int64) {
 		state.SetProposalReceived(nil)
 	}
 	//Update to RoundStepNewRound
-	state.UpdateRoundStep(round, RoundStepNewRound)
+	c.updateRoundStep(round, RoundStepNewRound)
 	state.setPrecommitWaited(false)
 
 	c.enterPropose(blockNumber, round)
@@ -134,7 +135,7 @@ func (c *core) enterPropose(blockNumber *big.Int, round int64) {
 	c.proposeStart = time.Now()
 	defer func() {
 		// Done enterPropose:
-		state.UpdateRoundStep(round, RoundStepPropose)
+		c.updateRoundStep(round, RoundStepPropose)
 
 		// If we have the whole proposal + POL, then goto PrevoteTimeout now.
 		// else, we'll enterPrevote when the rest of the proposal is received (in AddProposalBlockPart),
@@ -245,7 +246,7 @@ func (c *core) enterPrevote(blockNumber *big.Int, round int64) {
 	})
 	//eventually we'll enterPrevote
 	defer func() {
-		state.UpdateRoundStep(round, RoundStepPrevote)
+		c.updateRoundStep(round, RoundStepPrevote)
 	}()
 	c.defaultDoPrevote(round)
 }
@@ -276,7 +277,7 @@ func (c *core) enterPrevoteWait(blockNumber *big.Int, round int64) {
 
 	defer func() {
 		// Done enterPrevoteWait:
-		state.UpdateRoundStep(round, RoundStepPrevoteWait)
+		c.updateRoundStep(round, RoundStepPrevoteWait)
 	}()
 
 	//We have to copy blockNumber out since it's pointer, and the use of ScheduleTimeout
@@ -316,7 +317,7 @@ func (c *core) enterPrecommitWait(blockNumber *big.Int, round int64) {
 
 	//after this we setPrecommitWaited to true to make sure that the wait happens only once each round
 	defer func() {
-		state.UpdateRoundStep(round, RoundStepPrecommitWait)
+		c.updateRoundStep(round, RoundStepPrecommitWait)
 		state.setPrecommitWaited(true)
 	}()
 	//We have to copy blockNumber out since it's pointer, and the use of ScheduleTimeout
@@ -362,7 +363,7 @@ func (c *core) enterPrecommit(blockNumber *big.Int, round int64) {
 
 	defer func() {
 		// Done enterPrecommit:
-		state.UpdateRoundStep(round, RoundStepPrecommit)
+		c.updateRoundStep(round, RoundStepPrecommit)
 	}()
 
 	var blockHash = common.Hash{}
@@ -439,7 +440,7 @@ func (c *core) enterCommit(blockNumber *big.Int, commitRound int64) {
 	defer func() {
 		// Done enterCommit:
 		// keep state.Round the same, commitRound points to the right Precommits set.
-		state.UpdateRoundStep(state.Round(), RoundStepCommit)
+		c.updateRoundStep(state.Round(), RoundStepCommit)
 		state.commitRound = commitRound
 		state.commitTime = time.Now()
 
@@ -524,6 +525,7 @@ func (c *core) finalizeCommit(blockNumber *big.Int) {
 	if err != nil {
 		logger.Panicw("block committing failed", "error", err)
 	}
+	tendermintHeightRoundsHistogram.Update(state.commitRound + 1)
 
 	c.backend.Commit(block)
 }
@@ -531,12 +533,12 @@ func (c *core) finalizeCommit(blockNumber *big.Int) {
 //FinalizeBlock will fill extradata with signature and return the ready to store block
 func (c *core) FinalizeBlock(proposal *Proposal) (*types.Block, error) {
 	var (
-		state           = c.currentState
-		round           = state.commitRound
-		totalPrecommits = 0
-		commitSeals     = [][]byte{}
-		header          = proposal.Block.Header()
-		minMajority     = c.valSet.MinMajority()
+		state            = c.currentState
+		round            = state.commitRound
+		totalPower       int64
+		commitSeals      = [][]byte{}
+		header           = proposal.Block.Header()
+		minMajorityPower = c.valSet.MinMajorityPower()
 	)
 	precommits, ok := state.GetPrecommitsByRound(round)
 	if !ok {
@@ -547,24 +549,33 @@ func (c *core) FinalizeBlock(proposal *Proposal) (*types.Block, error) {
 	if !ok || votes == nil {
 		c.getLogger().Panicw("no votes for the committing block", "block_hash", header.Hash())
 	}
-	if votes.totalReceived < minMajority {
-		return nil, fmt.Errorf("not enough precommits received expect at least %d received %d", minMajority, totalPrecommits)
+	if votes.totalPower < minMajorityPower {
+		return nil, fmt.Errorf("not enough precommits received expect at least %d voting power received %d", minMajorityPower, votes.totalPower)
 	}
 
-	for _, vote := range votes.votes {
+	if utils.UseBLSSeals(c.valSet) {
+		return c.finalizeBlockWithBLSSeal(proposal, votes)
+	}
+
+	commitHash := utils.PrepareCommittedSeal(header.Hash())
+	for index, vote := range votes.votes {
 		if vote == nil {
 			continue
 		}
-		commitSeals = append(commitSeals, vote.Seal)
-		totalPrecommits++
-		//TODO: is it fair to always take the first 2F+1 seals?
-		if totalPrecommits >= minMajority {
-			break
+		// a precommit is only checked against the vote signature, so its seal is verified before being written
+		val := c.valSet.GetByIndex(int64(index))
+		signer, err := utils.GetSignatureAddress(commitHash, vote.Seal)
+		if err != nil || signer != val.Address() {
+			c.getLogger().Warnw("invalid committed seal", "validator", val.Address())
+			continue
 		}
+		// every precommit is kept, not only the first 2F+1, as the liveness of the validators is recorded from the seals
+		commitSeals = append(commitSeals, vote.Seal)
+		totalPower += val.VotingPower()
 	}
 
-	if totalPrecommits < minMajority {
-		return nil, fmt.Errorf("not enough precommits received expect at least %d received %d", minMajority, totalPrecommits)
+	if totalPower < minMajorityPower {
+		return nil, fmt.Errorf("not enough precommits received expect at least %d voting power received %d", minMajorityPower, totalPower)
 	}
 	//writeCommitSeals
 	if err := utils.WriteCommittedSeals(header, commitSeals); err != nil {
@@ -573,6 +584,49 @@ func (c *core) FinalizeBlock(proposal *Proposal) (*types.Block, error) {
 	return proposal.Block.WithSeal(header), nil
 }
 
+// finalizeBlockWithBLSSeal aggregates the BLS committed seals of all the precommits into a single seal
+// and writes it with the bitmap of the signers to the block header.
+// As BLS seals are aggregated, an invalid seal would invalidate the whole block so every seal is verified first.
+func (c *core) finalizeBlockWithBLSSeal(proposal *Proposal, votes *blockVotes) (*types.Block, error) {
+	var (
+		header           = proposal.Block.Header()
+		commitHash       = utils.PrepareCommittedSeal(header.Hash())
+		minMajorityPower = c.valSet.MinMajorityPower()
+		totalPower       int64
+		seals            []*bls.Signature
+		signers          []int
+	)
+	for index, vote := range votes.votes {
+		if vote == nil {
+			continue
+		}
+		val := c.valSet.GetByIndex(int64(index))
+		publicKey, err := bls.UnmarshalPublicKey(val.BLSPublicKey())
+		if err != nil {
+			return nil, err
+		}
+		seal, err := bls.UnmarshalSignature(vote.Seal)
+		if err != nil || !bls.Verify(publicKey, commitHash, seal) {
+			c.getLogger().Warnw("invalid BLS committed seal", "validator", val.Address())
+			continue
+		}
+		seals = append(seals, seal)
+		signers = append(signers, index)
+		totalPower += val.VotingPower()
+	}
+	if totalPower < minMajorityPower {
+		return nil, fmt.Errorf("not enough valid BLS seals received expect at least %d voting power received %d", minMajorityPower, totalPower)
+	}
+	aggregatedSeal, err := bls.AggregateSignatures(seals)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.WriteAggregatedSeal(header, aggregatedSeal.Marshal(), utils.NewSignersBitmap(c.valSet.Size(), signers)); err != nil {
+		return nil, err
+	}
+	return proposal.Block.WithSeal(header), nil
+}
+
 func (c *core) startNewRound() {
 	var (
 		state                 = c.CurrentState()
@@ -595,6 +649,7 @@ func (c *core) startNewRound() {
 		state.clearPreviousRoundData()
 		c.sentMsgStorage.truncateMsgStored(c.getLogger())
 		c.valSet = c.backend.Validators(state.BlockNumber())
+		c.onRoundStep()
 	}
 
 	//TODO: the timeout must account for the stopped time that core wasn't
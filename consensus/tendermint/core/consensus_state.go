@@ -0,0 +1,166 @@
+package core
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/common/hexutil"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint"
+	"github.com/Evrynetlabs/evrynet-node/core/types"
+	"github.com/Evrynetlabs/evrynet-node/event"
+)
+
+// ErrUnknownRoundVotes is returned if the votes are requested for another block number than the current one,
+// core drops the votes of a block number once it is committed
+var ErrUnknownRoundVotes = errors.New("votes are only kept for the current block number")
+
+// roundStepChanSize is the number of step transitions waiting to be sent to the subscribers, the others are dropped
+const roundStepChanSize = 64
+
+// ConsensusState implements core.Engine.ConsensusState
+func (c *core) ConsensusState() (*types.ConsensusState, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	state := c.currentState
+	if state == nil {
+		return nil, tendermint.ErrStoppedEngine
+	}
+	dump := &types.ConsensusState{
+		BlockNumber:     hexutil.Uint64(state.BlockNumber().Uint64()),
+		Round:           state.Round(),
+		Step:            state.Step().String(),
+		StartTime:       state.startTime,
+		LockedRound:     state.LockedRound(),
+		ValidRound:      state.ValidRound(),
+		CommitRound:     state.commitRound,
+		PrecommitWaited: state.PrecommitWaited,
+		Votes:           make([]*types.RoundVotes, 0),
+		Validators:      make([]common.Address, 0, c.valSet.Size()),
+	}
+	for _, val := range c.valSet.List() {
+		dump.Validators = append(dump.Validators, val.Address())
+	}
+	if proposer := c.valSet.GetProposer(); proposer != nil {
+		dump.Proposer = proposer.Address()
+	}
+	if block := state.LockedBlock(); block != nil {
+		hash := block.Hash()
+		dump.LockedBlock = &hash
+	}
+	if block := state.ValidBlock(); block != nil {
+		hash := block.Hash()
+		dump.ValidBlock = &hash
+	}
+	if proposal := state.ProposalReceived(); proposal != nil {
+		dump.Proposal = &types.ProposalState{
+			BlockHash: proposal.Block.Hash(),
+			Round:     proposal.Round,
+			POLRound:  proposal.POLRound,
+			Complete:  state.IsProposalComplete(),
+		}
+	}
+	if ti, deadline, ok := c.timeout.Pending(); ok {
+		dump.PendingTimeout = &types.TimeoutState{
+			BlockNumber: hexutil.Uint64(ti.BlockNumber.Uint64()),
+			Round:       ti.Round,
+			Step:        ti.Step.String(),
+			Retry:       ti.Retry,
+			Deadline:    deadline,
+		}
+	}
+	for _, round := range state.votedRounds() {
+		dump.Votes = append(dump.Votes, c.roundVotes(round))
+	}
+	return dump, nil
+}
+
+// RoundVotes implements core.Engine.RoundVotes
+func (c *core) RoundVotes(blockNumber uint64, round int64) (*types.RoundVotes, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.currentState == nil {
+		return nil, tendermint.ErrStoppedEngine
+	}
+	if c.currentState.BlockNumber().Uint64() != blockNumber {
+		return nil, ErrUnknownRoundVotes
+	}
+	return c.roundVotes(round), nil
+}
+
+// SubscribeRoundStep implements core.Engine.SubscribeRoundStep
+func (c *core) SubscribeRoundStep(ch chan<- types.RoundStep) event.Subscription {
+	return c.roundStepFeed.Subscribe(ch)
+}
+
+// roundVotes returns the votes received at the round of the current block number (caller should lock the mutex)
+func (c *core) roundVotes(round int64) *types.RoundVotes {
+	var (
+		state         = c.currentState
+		prevotes, _   = state.GetPrevotesByRound(round)
+		precommits, _ = state.GetPrecommitsByRound(round)
+	)
+	return &types.RoundVotes{
+		BlockNumber: hexutil.Uint64(state.BlockNumber().Uint64()),
+		Round:       round,
+		Prevotes:    prevotes.voteSet(c.valSet),
+		Precommits:  precommits.voteSet(c.valSet),
+	}
+}
+
+// updateRoundStep moves the current state to the step of the round and notifies the subscribers of the transition
+func (c *core) updateRoundStep(round int64, step RoundStepType) {
+	c.currentState.UpdateRoundStep(round, step)
+	c.onRoundStep()
+}
+
+// onRoundStep records the time spent at the previous step and notifies the subscribers of the step of the current state
+func (c *core) onRoundStep() {
+	var (
+		state = c.currentState
+		now   = time.Now()
+	)
+	if timer, ok := tendermintStepTimers[c.lastStep]; ok && !c.stepStart.IsZero() {
+		timer.Update(now.Sub(c.stepStart))
+	}
+	c.lastStep, c.stepStart = state.Step(), now
+	// the caller holds the mutex, so the subscribers are notified by roundStepLoop not to block the state machine
+	select {
+	case c.roundSteps <- types.RoundStep{
+		BlockNumber: hexutil.Uint64(state.BlockNumber().Uint64()),
+		Round:       state.Round(),
+		Step:        state.Step().String(),
+		Time:        now,
+	}:
+	default:
+		c.getLogger().Warnw("round step queue is full, dropping step transition", "step", state.Step().String())
+	}
+}
+
+// roundStepLoop sends the step transitions queued by onRoundStep to the subscribers until stop is closed
+func (c *core) roundStepLoop(stop <-chan struct{}) {
+	for {
+		select {
+		case step := <-c.roundSteps:
+			c.roundStepFeed.Send(step)
+		case <-stop:
+			return
+		}
+	}
+}
+
+// votedRounds returns the rounds at which votes are received, in ascending order
+func (s *roundState) votedRounds() []int64 {
+	rounds := make([]int64, 0, len(s.PrevotesReceived))
+	for round := range s.PrevotesReceived {
+		rounds = append(rounds, round)
+	}
+	for round := range s.PrecommitsReceived {
+		if _, ok := s.PrevotesReceived[round]; !ok {
+			rounds = append(rounds, round)
+		}
+	}
+	sort.Slice(rounds, func(i, j int) bool { return rounds[i] < rounds[j] })
+	return rounds
+}
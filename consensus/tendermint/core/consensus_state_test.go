@@ -0,0 +1,156 @@
+package core
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/tests_utils"
+	"github.com/Evrynetlabs/evrynet-node/core/types"
+	"github.com/Evrynetlabs/evrynet-node/crypto"
+	"github.com/Evrynetlabs/evrynet-node/metrics"
+)
+
+func TestCore_ConsensusState(t *testing.T) {
+	var (
+		nodePrivateKey = tests_utils.MakeNodeKey()
+		nodeAddr       = crypto.PubkeyToAddress(nodePrivateKey.PublicKey)
+		nodeAddr2, _   = common.EvryAddressStringToAddressCheck("EH9uVaqWRxHuzJbroqzX18yxmeW8XVJyV9")
+		validators     = []common.Address{nodeAddr, nodeAddr2}
+		genesisHeader  = tests_utils.MakeGenesisHeader(validators)
+		blockHash      = common.HexToHash("0x01")
+	)
+	be, _ := tests_utils.MustCreateAndStartNewBackend(t, nodePrivateKey, genesisHeader, validators)
+	core := newTestCore(be, tendermint.DefaultConfig)
+	_, err := core.ConsensusState()
+	assert.Equal(t, tendermint.ErrStoppedEngine, err)
+
+	core.currentState = core.getInitializedState()
+	core.valSet = be.Validators(core.CurrentState().BlockNumber())
+	blockNumber := core.CurrentState().BlockNumber()
+	require.NoError(t, core.handlePrevote(*newSignedVote(t, core, msgPrevote, blockNumber, 0, blockHash)))
+
+	state, err := core.ConsensusState()
+	require.NoError(t, err)
+	assert.Equal(t, blockNumber.Uint64(), uint64(state.BlockNumber))
+	assert.Equal(t, RoundStepNewHeight.String(), state.Step)
+	assert.Equal(t, int64(-1), state.LockedRound)
+	assert.Nil(t, state.LockedBlock)
+	assert.Nil(t, state.Proposal)
+	assert.Len(t, state.Validators, 2)
+	require.Len(t, state.Votes, 1)
+
+	votes, err := core.RoundVotes(blockNumber.Uint64(), 0)
+	require.NoError(t, err)
+	assert.Equal(t, state.Votes[0], votes)
+	assert.Equal(t, map[common.Address]common.Hash{nodeAddr: blockHash}, votes.Prevotes.Votes)
+	assert.Equal(t, []common.Address{nodeAddr2}, votes.Prevotes.Missing)
+	assert.Nil(t, votes.Prevotes.TwoThirdMajority)
+	assert.Empty(t, votes.Precommits.Votes)
+	assert.Len(t, votes.Precommits.Missing, 2)
+
+	_, err = core.RoundVotes(blockNumber.Uint64()+1, 0)
+	assert.Equal(t, ErrUnknownRoundVotes, err)
+
+	// the step transitions do not wait for the subscribers while the mutex is held
+	steps := make(chan types.RoundStep)
+	sub := core.SubscribeRoundStep(steps)
+	defer sub.Unsubscribe()
+	updated := make(chan struct{})
+	go func() {
+		core.mu.Lock()
+		defer core.mu.Unlock()
+		core.updateRoundStep(1, RoundStepPropose)
+		close(updated)
+	}()
+	select {
+	case <-updated:
+	case <-time.After(time.Second):
+		t.Fatal("round step update is blocked by the subscriber")
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go core.roundStepLoop(stop)
+	select {
+	case step := <-steps:
+		assert.Equal(t, int64(1), step.Round)
+		assert.Equal(t, RoundStepPropose.String(), step.Step)
+	case <-time.After(time.Second):
+		t.Fatal("no round step event")
+	}
+}
+
+func TestTimeoutTicker_Pending(t *testing.T) {
+	ticker := NewTimeoutTicker()
+	require.NoError(t, ticker.Start())
+	defer func() {
+		require.NoError(t, ticker.Stop())
+	}()
+	_, _, ok := ticker.Pending()
+	assert.False(t, ok)
+
+	ticker.ScheduleTimeout(timeoutInfo{
+		Duration:    time.Hour,
+		BlockNumber: big.NewInt(1),
+		Round:       0,
+		Step:        RoundStepPropose,
+	})
+	require.Eventually(t, func() bool {
+		_, _, ok := ticker.Pending()
+		return ok
+	}, time.Second, 10*time.Millisecond)
+	ti, deadline, _ := ticker.Pending()
+	assert.Equal(t, RoundStepPropose, ti.Step)
+	assert.True(t, deadline.After(time.Now().Add(time.Minute)))
+}
+
+func TestCore_MissedVotes(t *testing.T) {
+	metricsEnabled := metrics.Enabled
+	metrics.Enabled = true
+	defer func() { metrics.Enabled = metricsEnabled }()
+
+	nodePrivateKey := tests_utils.MakeNodeKey()
+	validators := []common.Address{crypto.PubkeyToAddress(nodePrivateKey.PublicKey)}
+	for i := 0; i < 6; i++ {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		validators = append(validators, crypto.PubkeyToAddress(key.PublicKey))
+	}
+	be, _ := tests_utils.MustCreateAndStartNewBackend(t, nodePrivateKey, tests_utils.MakeGenesisHeader(validators), validators)
+	core := newTestCore(be, tendermint.DefaultConfig)
+	core.currentState = core.getInitializedState()
+	core.valSet = be.Validators(core.CurrentState().BlockNumber())
+
+	var (
+		blockNumber = core.CurrentState().BlockNumber()
+		blockHash   = common.HexToHash("0x01")
+		late        = validators[5]
+		missing     = validators[6]
+	)
+	precommit := func(addr common.Address) {
+		msg := newSignedVote(t, core, msgPrecommit, blockNumber, 0, blockHash)
+		msg.Address = addr
+		_, _, err := core.currentState.addPrecommit(*msg, &Vote{BlockHash: &blockHash, BlockNumber: blockNumber, Round: 0}, core.valSet)
+		require.NoError(t, err)
+	}
+	for _, addr := range validators[:5] {
+		precommit(addr)
+	}
+	core.currentState.commitRound = 0
+	// a precommit received after the commit is not missed
+	precommit(late)
+	core.updateStateForNewblock()
+
+	missed := func(addr common.Address) int64 {
+		return metrics.GetOrRegisterCounter(missedVotesMetricPrefix+strings.ToLower(addr.Hex()), nil).Count()
+	}
+	assert.Equal(t, int64(0), missed(late))
+	assert.Equal(t, int64(1), missed(missing))
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"bytes"
+	"crypto/ecdsa"
 	"fmt"
 	"math/big"
 	"testing"
@@ -13,8 +14,10 @@ import (
 	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint"
 	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/tests_utils"
 	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/utils"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/validator"
 	"github.com/Evrynetlabs/evrynet-node/core/types"
 	"github.com/Evrynetlabs/evrynet-node/crypto"
+	"github.com/Evrynetlabs/evrynet-node/crypto/bls"
 	"github.com/Evrynetlabs/evrynet-node/rlp"
 )
 
@@ -25,19 +28,17 @@ const (
 
 func TestFinalizeBlock(t *testing.T) {
 	var (
-		nodePrivateKey, _ = crypto.HexToECDSA("ce900e4057ef7253ce737dccf3979ec4e74a19d595e8cc30c6c5ea92dfdd37f1")
-		nodeAddr          = crypto.PubkeyToAddress(nodePrivateKey.PublicKey)
-		a, _              = common.EvryAddressStringToAddressCheck("EPXt4N4aPgV4BEKLtYJgz2NdiAHfa7c2ao")
-		b, _              = common.EvryAddressStringToAddressCheck("ERXpWm2MrviVU2KjGMgq1xUQYLie2zUfkK")
-		c, _              = common.EvryAddressStringToAddressCheck("EWmMyKETQCsTYEC3W51dZ3bpUWvn3XtrwG")
-		validators        = []common.Address{
-			a,
-			nodeAddr,
-			b,
-			c,
-		}
-		genesisHeader = tests_utils.MakeGenesisHeader(validators)
+		nodePrivateKey = tests_utils.MakeNodeKey()
+		privateKeys    = []*ecdsa.PrivateKey{nodePrivateKey, tests_utils.MakeNodeKey(), tests_utils.MakeNodeKey(), tests_utils.MakeNodeKey()}
+		validators     []common.Address
+		keys           = make(map[common.Address]*ecdsa.PrivateKey)
 	)
+	for _, key := range privateKeys {
+		addr := crypto.PubkeyToAddress(key.PublicKey)
+		validators = append(validators, addr)
+		keys[addr] = key
+	}
+	genesisHeader := tests_utils.MakeGenesisHeader(validators)
 	//create New test backend and newMockChain
 	be, _ := tests_utils.MustCreateAndStartNewBackend(t, nodePrivateKey, genesisHeader, validators)
 
@@ -62,6 +63,7 @@ func TestFinalizeBlock(t *testing.T) {
 	testCases := []struct {
 		name           string
 		validatorVotes map[int]int
+		forged         map[int]bool // the validators whose seal is signed by another validator
 		totalReceived  int
 		assertFn       func(block *types.Block, err error)
 	}{
@@ -107,6 +109,36 @@ func TestFinalizeBlock(t *testing.T) {
 				assert.Error(t, err) // Get error "not enough precommits received expect at least 3 received 2"
 			},
 		},
+		{
+			name: "Case 4: All validators vote for block 2 but the seal of validator 3 is forged, it is dropped from the committed seals",
+			validatorVotes: map[int]int{
+				0: 2,
+				1: 2,
+				2: 2,
+				3: 2,
+			},
+			forged:        map[int]bool{3: true},
+			totalReceived: 4,
+			assertFn: func(block *types.Block, err error) {
+				assert.NotNil(t, block)
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "Case 5: Validator 0,1,2 vote for block 2 but the seal of validator 2 is forged, the valid seals are not enough",
+			validatorVotes: map[int]int{
+				0: 2,
+				1: 2,
+				2: 2,
+				3: 1,
+			},
+			forged:        map[int]bool{2: true},
+			totalReceived: 3,
+			assertFn: func(block *types.Block, err error) {
+				assert.Nil(t, block)
+				assert.Error(t, err)
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -117,28 +149,29 @@ func TestFinalizeBlock(t *testing.T) {
 			genesisHeader.Number = big.NewInt(1)
 			bl1 := tests_utils.MakeBlockWithoutSeal(genesisHeader)
 			blHash1 := bl1.Hash()
-			committedSeal1, err := core.backend.Sign(utils.PrepareCommittedSeal(blHash1))
-			require.NoError(t, err)
 
 			//Create block 2
 			genesisHeader.Number = big.NewInt(2)
 			bl2 := tests_utils.MakeBlockWithoutSeal(genesisHeader)
 			blHash2 := bl2.Hash()
-			committedSeal2, err := core.backend.Sign(utils.PrepareCommittedSeal(blHash2))
-			require.NoError(t, err)
 			require.NotEqual(t, bl1.Hash().Hex(), bl2.Hash().Hex(), "Block hash of 2 blocks must be different")
 
 			var block2ExpectCommittedSeals [][]byte //It stores what commit seals were appended to block 2
 			//Add vote from node 1,2,3,4
-			for i := 0; i < len(validators); i++ {
+			for i, val := range core.valSet.List() {
 				msg := message{
 					Code:    msgPrecommit,
-					Address: validators[i],
+					Address: val.Address(),
+				}
+				key := keys[val.Address()]
+				if tc.forged[i] {
+					key = keys[core.valSet.GetByIndex(int64((i+1)%len(validators))).Address()]
 				}
 
-				assert.Equal(t, validators[i].String(), core.valSet.GetByIndex(int64(i)).String(), "The order voting must be the same")
 				switch tc.validatorVotes[i] {
 				case Block1:
+					committedSeal1, err := crypto.Sign(crypto.Keccak256(utils.PrepareCommittedSeal(blHash1)), key)
+					require.NoError(t, err)
 					ok, err := newMsgSet.AddVote(msg,
 						&Vote{
 							BlockHash:   &blHash1,
@@ -149,6 +182,8 @@ func TestFinalizeBlock(t *testing.T) {
 					require.NoError(t, err)
 					assert.True(t, ok)
 				case Block2:
+					committedSeal2, err := crypto.Sign(crypto.Keccak256(utils.PrepareCommittedSeal(blHash2)), key)
+					require.NoError(t, err)
 					vote := &Vote{
 						BlockHash:   &blHash2,
 						BlockNumber: core.CurrentState().BlockNumber(),
@@ -159,8 +194,8 @@ func TestFinalizeBlock(t *testing.T) {
 					require.NoError(t, err)
 					assert.True(t, ok)
 
-					//Add committed seals will be added to block 2 to compare after finalizing
-					if len(block2ExpectCommittedSeals) < core.valSet.MinMajority() {
+					//Add committed seals will be added to block 2 to compare after finalizing, every valid precommit is kept
+					if !tc.forged[i] {
 						block2ExpectCommittedSeals = append(block2ExpectCommittedSeals, vote.Seal)
 					}
 				default:
@@ -195,3 +230,95 @@ func TestFinalizeBlock(t *testing.T) {
 		t.Run(tc.name, validateVote)
 	}
 }
+
+func TestFinalizeBlockWithBLSSeal(t *testing.T) {
+	var (
+		nodePrivateKey = tests_utils.MakeNodeKey()
+		privateKeys    = []*ecdsa.PrivateKey{nodePrivateKey, tests_utils.MakeNodeKey(), tests_utils.MakeNodeKey(), tests_utils.MakeNodeKey()}
+		validators     []common.Address
+		blsKeys        = make(map[common.Address]*bls.SecretKey)
+		publicKeys     = make(map[common.Address][]byte)
+	)
+	for _, key := range privateKeys {
+		addr := crypto.PubkeyToAddress(key.PublicKey)
+		validators = append(validators, addr)
+		blsKeys[addr] = bls.DeriveSecretKey(crypto.FromECDSA(key))
+		publicKeys[addr] = blsKeys[addr].PublicKey().Marshal()
+	}
+	genesisHeader := tests_utils.MakeGenesisHeader(validators)
+	be, _ := tests_utils.MustCreateAndStartNewBackend(t, nodePrivateKey, genesisHeader, validators)
+	core := newTestCore(be, tendermint.DefaultConfig)
+	core.currentState = core.getInitializedState()
+	core.currentState.commitRound = 0
+	core.valSet = validator.WithBLSPublicKeys(be.Validators(core.CurrentState().BlockNumber()), publicKeys)
+	require.True(t, utils.UseBLSSeals(core.valSet))
+
+	genesisHeader.Number = big.NewInt(1)
+	block := tests_utils.MakeBlockWithoutSeal(genesisHeader)
+	blockHash := block.Hash()
+	commitHash := utils.PrepareCommittedSeal(blockHash)
+
+	// the node signs its own seal with the backend's BLS key
+	ownSeal, err := be.SignBLS(commitHash)
+	require.NoError(t, err)
+	assert.Equal(t, blsKeys[validators[0]].Sign(commitHash).Marshal(), ownSeal)
+
+	msgSet := newMessageSet(core.valSet, msgPrecommit, &tendermint.View{BlockNumber: core.CurrentState().BlockNumber(), Round: 0})
+	for i, val := range core.valSet.List() {
+		seal := blsKeys[val.Address()].Sign(commitHash).Marshal()
+		if i == 0 {
+			// an invalid seal is skipped and does not invalidate the aggregated seal
+			seal = blsKeys[val.Address()].Sign([]byte("other block")).Marshal()
+		}
+		_, err := msgSet.AddVote(message{Code: msgPrecommit, Address: val.Address()}, &Vote{
+			BlockHash:   &blockHash,
+			BlockNumber: core.CurrentState().BlockNumber(),
+			Round:       0,
+			Seal:        seal,
+		})
+		require.NoError(t, err)
+	}
+	core.currentState.PrecommitsReceived[0] = msgSet
+
+	finalizedBlock, err := core.FinalizeBlock(&Proposal{Block: block})
+	require.NoError(t, err)
+	extra, err := types.ExtractTendermintExtra(finalizedBlock.Header())
+	require.NoError(t, err)
+	assert.Empty(t, extra.CommittedSeal)
+	signers, err := utils.SignersFromBitmap(extra.SignersBitmap, core.valSet.Size())
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, signers)
+
+	var signerKeys []*bls.PublicKey
+	for _, index := range signers {
+		signerKeys = append(signerKeys, blsKeys[core.valSet.GetByIndex(int64(index)).Address()].PublicKey())
+	}
+	aggregatedKey, err := bls.AggregatePublicKeys(signerKeys)
+	require.NoError(t, err)
+	aggregatedSeal, err := bls.UnmarshalSignature(extra.AggregatedSeal)
+	require.NoError(t, err)
+	assert.True(t, bls.Verify(aggregatedKey, utils.PrepareCommittedSeal(finalizedBlock.Hash()), aggregatedSeal))
+
+	// every validator signs: all the precommits are kept in the bitmap, not only the first 2F+1,
+	// so that none of them is counted as missing the block
+	msgSet = newMessageSet(core.valSet, msgPrecommit, &tendermint.View{BlockNumber: core.CurrentState().BlockNumber(), Round: 0})
+	for _, val := range core.valSet.List() {
+		_, err := msgSet.AddVote(message{Code: msgPrecommit, Address: val.Address()}, &Vote{
+			BlockHash:   &blockHash,
+			BlockNumber: core.CurrentState().BlockNumber(),
+			Round:       0,
+			Seal:        blsKeys[val.Address()].Sign(commitHash).Marshal(),
+		})
+		require.NoError(t, err)
+	}
+	core.currentState.PrecommitsReceived[0] = msgSet
+
+	finalizedBlock, err = core.FinalizeBlock(&Proposal{Block: block})
+	require.NoError(t, err)
+	committers, err := utils.CommittedSigners(finalizedBlock.Header(), core.valSet)
+	require.NoError(t, err)
+	require.Len(t, committers, core.valSet.Size())
+	for i, val := range core.valSet.List() {
+		assert.Equal(t, val.Address(), committers[i])
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/utils"
 	"github.com/Evrynetlabs/evrynet-node/core/types"
 	"github.com/Evrynetlabs/evrynet-node/event"
+	"github.com/Evrynetlabs/evrynet-node/evrdb"
 	"github.com/Evrynetlabs/evrynet-node/rlp"
 )
 
@@ -26,6 +27,15 @@ func WithoutRebroadcast() Option {
 	}
 }
 
+//WithDB return an option to persist the core's state and sent messages into db
+//so that the core can resume where it stopped after restarting
+func WithDB(db evrdb.KeyValueStore) Option {
+	return func(c *core) error {
+		c.wal = newWAL(db)
+		return nil
+	}
+}
+
 // New creates an Tendermint consensus core
 func New(backend tendermint.Backend, config *tendermint.Config, opts ...Option) Engine {
 	c := &core{
@@ -39,6 +49,7 @@ func New(backend tendermint.Backend, config *tendermint.Config, opts ...Option)
 		futureProposals: make(map[int64]message),
 		sentMsgStorage:  NewMsgStorage(),
 		rebroadcast:     true,
+		roundSteps:      make(chan types.RoundStep, roundStepChanSize),
 	}
 	for _, opt := range opts {
 		if err := opt(c); err != nil {
@@ -82,9 +93,14 @@ type core struct {
 
 	// a Helper supports to store message before send proposal/ vote for every block
 	sentMsgStorage *msgStorage
+	// wal persists currentState and sentMsgStorage, it is nil if core is not created with a db
+	wal *wal
 
 	//proposeStart mark the time core enter propose. This is purely use for metrics
 	proposeStart time.Time
+	//lastStep and stepStart mark the step core is at and the time it entered it. This is purely use for metrics
+	lastStep  RoundStepType
+	stepStart time.Time
 
 	// futureMessages stores future messages (prevote and precommit) fromo other peers
 	// and handle them later when we jump to that block number
@@ -96,6 +112,12 @@ type core struct {
 	futureProposals map[int64]message
 
 	rebroadcast bool
+
+	// roundStepFeed notifies the subscribers of each step transition of the consensus state machine
+	roundStepFeed event.Feed
+	// roundSteps queues the step transitions to be sent to roundStepFeed, stopRoundSteps stops sending them
+	roundSteps     chan types.RoundStep
+	stopRoundSteps chan struct{}
 }
 
 // Start implements core.Engine.Start
@@ -104,9 +126,11 @@ func (c *core) Start() error {
 	// Tests will handle events itself, so we have to make subscribeEvents()
 	// be able to call in test.
 	c.getLogger().Infow("starting Tendermint's core...")
+	var restored bool
 	if c.currentState == nil {
 		c.currentState = c.getInitializedState()
 		c.valSet = c.backend.Validators(c.CurrentState().BlockNumber())
+		restored = c.restoreFromWAL()
 	}
 	c.subscribeEvents()
 
@@ -115,8 +139,13 @@ func (c *core) Start() error {
 	if err := c.timeout.Start(); err != nil {
 		return err
 	}
+	c.stopRoundSteps = make(chan struct{})
+	go c.roundStepLoop(c.stopRoundSteps)
 	c.startNewRound()
 	go c.handleEvents()
+	if restored {
+		go c.replaySentMsgs(c.getLogger())
+	}
 
 	return nil
 }
@@ -128,6 +157,10 @@ func (c *core) Stop() error {
 	err := c.timeout.Stop()
 	c.unsubscribeEvents()
 	c.handlerWg.Wait()
+	if c.stopRoundSteps != nil {
+		close(c.stopRoundSteps)
+		c.stopRoundSteps = nil
+	}
 	c.getLogger().Infow("Tendermint's timeout core stopped")
 	return err
 }
@@ -153,6 +186,15 @@ func (c *core) SendPropose(propose *Proposal) {
 	logger := c.getLogger().With("propose_round", propose.Round,
 		"propose_block_number", propose.Block.Number(), "propose_block_hash", propose.Block.Hash())
 
+	// never sign a second proposal for the same round, i.e, after restarting
+	if payload, ok := c.sentMsgStorage.find(RoundStepPropose, propose.Round); ok {
+		logger.Warnw("already sent a proposal at this round, re-broadcasting it")
+		if err := c.backend.Broadcast(c.valSet, c.currentState.CopyBlockNumber(), propose.Round, msgPropose, payload); err != nil {
+			logger.Errorw("Failed to Broadcast proposal", "error", err)
+		}
+		return
+	}
+
 	msgData, err := rlp.EncodeToBytes(propose)
 	if err != nil {
 		logger.Errorw("Failed to encode Proposal to bytes", "error", err)
@@ -169,6 +211,10 @@ func (c *core) SendPropose(propose *Proposal) {
 
 	// store before send propose msg
 	c.sentMsgStorage.storeSentMsg(c.getLogger(), RoundStepPropose, propose.Round, payload)
+	if err := c.writeWAL(propose.Round, RoundStepPropose); err != nil {
+		logger.Errorw("Failed to write proposal to WAL", "error", err)
+		return
+	}
 
 	if err := c.backend.Broadcast(c.valSet, c.currentState.CopyBlockNumber(), propose.Round, msgPropose, payload); err != nil {
 		c.getLogger().Errorw("Failed to Broadcast proposal", "error", err)
@@ -191,6 +237,18 @@ func (c *core) SendVote(voteType uint64, block *types.Block, round int64) {
 		logger.Errorw("vote type is invalid")
 		return
 	}
+	step := RoundStepPrevote
+	if voteType == msgPrecommit {
+		step = RoundStepPrecommit
+	}
+	// never sign a second vote for the same round and step, i.e, after restarting
+	if payload, ok := c.sentMsgStorage.find(step, round); ok {
+		logger.Warnw("already sent a vote at this round and step, re-broadcasting it")
+		if err := c.backend.Broadcast(c.valSet, c.currentState.CopyBlockNumber(), round, voteType, payload); err != nil {
+			logger.Errorw("Failed to Broadcast vote", "error", err)
+		}
+		return
+	}
 	var (
 		blockHash = emptyBlockHash
 		seal      []byte
@@ -198,7 +256,11 @@ func (c *core) SendVote(voteType uint64, block *types.Block, round int64) {
 	if block != nil {
 		var err error
 		commitHash := utils.PrepareCommittedSeal(block.Header().Hash())
-		seal, err = c.backend.Sign(commitHash)
+		if utils.UseBLSSeals(c.valSet) {
+			seal, err = c.backend.SignBLS(commitHash)
+		} else {
+			seal, err = c.backend.Sign(commitHash)
+		}
 		if err != nil {
 			logger.Errorw("failed to sign seal", err, "err")
 			return
@@ -225,13 +287,11 @@ func (c *core) SendVote(voteType uint64, block *types.Block, round int64) {
 		return
 	}
 
-	// store before send propose msg
-	switch voteType {
-	case msgPrevote:
-		c.sentMsgStorage.storeSentMsg(c.getLogger(), RoundStepPrevote, round, payload)
-	case msgPrecommit:
-		c.sentMsgStorage.storeSentMsg(c.getLogger(), RoundStepPrecommit, round, payload)
-	default:
+	// store before send vote msg
+	c.sentMsgStorage.storeSentMsg(c.getLogger(), step, round, payload)
+	if err := c.writeWAL(round, step); err != nil {
+		logger.Errorw("Failed to write vote to WAL", "error", err)
+		return
 	}
 
 	if err := c.backend.Broadcast(c.valSet, c.currentState.CopyBlockNumber(), round, voteType, payload); err != nil {
@@ -265,6 +325,7 @@ func (c *core) SendCatchupReply(target common.Address, payloads [][]byte) {
 		logger.Errorw("Failed to send catchUpReply msgs", "err", err)
 		return
 	}
+	tendermintCatchUpAnsweredMeter.Mark(1)
 	logger.Infow("Reply catch up msgs")
 }
 
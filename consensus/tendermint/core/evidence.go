@@ -0,0 +1,166 @@
+package core
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/pkg/errors"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/core/types"
+	"github.com/Evrynetlabs/evrynet-node/crypto"
+	"github.com/Evrynetlabs/evrynet-node/rlp"
+)
+
+var (
+	// ErrInvalidEvidence is returned if the evidence is not a proof of 2 conflicting votes
+	ErrInvalidEvidence = errors.New("invalid evidence")
+)
+
+// Offence is a double signing of a validator proven by an evidence.
+// Different evidences of the same validator at the same block number and round prove the same offence.
+type Offence struct {
+	Offender    common.Address
+	BlockNumber *big.Int
+	Round       int64
+}
+
+// Key returns the key identifying the offence, it does not depend on the votes of the evidence
+func (o *Offence) Key() common.Hash {
+	data, _ := rlp.EncodeToBytes([]interface{}{o.Offender, o.BlockNumber, uint64(o.Round)})
+	return crypto.Keccak256Hash(data)
+}
+
+// newEvidence creates an evidence from 2 conflicting vote messages,
+// the messages are sorted by hash so that the same pair of votes always produce the same evidence.
+func newEvidence(msgA, msgB *message) (*types.TendermintEvidence, error) {
+	voteA, err := rlp.EncodeToBytes(msgA)
+	if err != nil {
+		return nil, err
+	}
+	voteB, err := rlp.EncodeToBytes(msgB)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Compare(voteA, voteB) > 0 {
+		voteA, voteB = voteB, voteA
+	}
+	return &types.TendermintEvidence{
+		VoteA: voteA,
+		VoteB: voteB,
+	}, nil
+}
+
+// VerifyEvidence checks that the evidence contains 2 votes of the same type, block number and round for different blocks,
+// which are both signed by the same validator.
+// It returns the offence proven by the evidence.
+func VerifyEvidence(evidence *types.TendermintEvidence) (*Offence, error) {
+	if bytes.Compare(evidence.VoteA, evidence.VoteB) >= 0 {
+		return nil, errors.Wrap(ErrInvalidEvidence, "votes are not sorted")
+	}
+	msgA, voteA, err := decodeSignedVote(evidence.VoteA)
+	if err != nil {
+		return nil, err
+	}
+	msgB, voteB, err := decodeSignedVote(evidence.VoteB)
+	if err != nil {
+		return nil, err
+	}
+	if msgA.Address != msgB.Address {
+		return nil, errors.Wrap(ErrInvalidEvidence, "votes are signed by different validators")
+	}
+	if msgA.Code != msgB.Code {
+		return nil, errors.Wrap(ErrInvalidEvidence, "votes are of different types")
+	}
+	if voteA.BlockNumber.Cmp(voteB.BlockNumber) != 0 || voteA.Round != voteB.Round {
+		return nil, errors.Wrap(ErrInvalidEvidence, "votes are at different block number or round")
+	}
+	if voteA.BlockHash.Hex() == voteB.BlockHash.Hex() {
+		return nil, errors.Wrap(ErrInvalidEvidence, "votes are for the same block")
+	}
+	return &Offence{
+		Offender:    msgA.Address,
+		BlockNumber: new(big.Int).Set(voteA.BlockNumber),
+		Round:       voteA.Round,
+	}, nil
+}
+
+// decodeSignedVote decodes a prevote/ precommit message and checks its signature
+func decodeSignedVote(payload []byte) (*message, *Vote, error) {
+	var (
+		msg  message
+		vote Vote
+	)
+	if err := rlp.DecodeBytes(payload, &msg); err != nil {
+		return nil, nil, err
+	}
+	if msg.Code != msgPrevote && msg.Code != msgPrecommit {
+		return nil, nil, errors.Wrap(ErrInvalidEvidence, "message is not a vote")
+	}
+	signer, err := msg.GetAddressFromSignature()
+	if err != nil {
+		return nil, nil, err
+	}
+	if signer != msg.Address {
+		return nil, nil, ErrSignerMessageMissMatch
+	}
+	if err := rlp.DecodeBytes(msg.Msg, &vote); err != nil {
+		return nil, nil, err
+	}
+	if vote.BlockHash == nil || vote.BlockNumber == nil {
+		return nil, nil, errors.Wrap(ErrInvalidEvidence, "vote has no block hash or block number")
+	}
+	return &msg, &vote, nil
+}
+
+// handleConflictingVote packages the vote conflicting with the one received before from the same validator as an evidence
+// and reports it to backend.
+func (c *core) handleConflictingVote(msgSet *messageSet, msg message) {
+	logger := c.getLogger().With("from", msg.Address)
+	current, ok := msgSet.MessageByAddress(msg.Address)
+	if !ok {
+		return
+	}
+	evidence, err := newEvidence(current, &msg)
+	if err != nil {
+		logger.Errorw("failed to create evidence of conflicting votes", "err", err)
+		return
+	}
+	logger.Warnw("received conflicting votes, reporting evidence", "evidence_hash", evidence.Hash())
+	c.reportEvidence(evidence)
+}
+
+// reportEvidence adds the evidence to backend and gossips it if it is new
+func (c *core) reportEvidence(evidence *types.TendermintEvidence) {
+	if !c.backend.ReportEvidence(evidence) {
+		return
+	}
+	msgData, err := rlp.EncodeToBytes(evidence)
+	if err != nil {
+		c.getLogger().Errorw("Failed to encode evidence to bytes", "err", err)
+		return
+	}
+	payload, err := c.FinalizeMsg(&message{
+		Code: msgEvidence,
+		Msg:  msgData,
+	})
+	if err != nil {
+		c.getLogger().Errorw("Failed to finalize evidence", "err", err)
+		return
+	}
+	if err := c.backend.Gossip(c.valSet, c.currentState.CopyBlockNumber(), c.currentState.Round(), msgEvidence, payload); err != nil {
+		c.getLogger().Errorw("Failed to gossip evidence", "err", err)
+	}
+}
+
+func (c *core) handleEvidence(msg message) error {
+	var evidence types.TendermintEvidence
+	if err := rlp.DecodeBytes(msg.Msg, &evidence); err != nil {
+		return err
+	}
+	if _, err := VerifyEvidence(&evidence); err != nil {
+		return err
+	}
+	c.reportEvidence(&evidence)
+	return nil
+}
@@ -0,0 +1,129 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/tests_utils"
+	"github.com/Evrynetlabs/evrynet-node/core/types"
+	"github.com/Evrynetlabs/evrynet-node/crypto"
+	"github.com/Evrynetlabs/evrynet-node/rlp"
+)
+
+func newSignedVote(t *testing.T, c *core, code uint64, blockNumber *big.Int, round int64, blockHash common.Hash) *message {
+	voteData, err := rlp.EncodeToBytes(&Vote{
+		BlockHash:   &blockHash,
+		BlockNumber: blockNumber,
+		Round:       round,
+	})
+	require.NoError(t, err)
+	msg := &message{
+		Code: code,
+		Msg:  voteData,
+	}
+	_, err = c.FinalizeMsg(msg)
+	require.NoError(t, err)
+	return msg
+}
+
+func TestVerifyEvidence(t *testing.T) {
+	var (
+		nodePrivateKey = tests_utils.MakeNodeKey()
+		nodeAddr       = crypto.PubkeyToAddress(nodePrivateKey.PublicKey)
+		validators     = []common.Address{nodeAddr}
+		genesisHeader  = tests_utils.MakeGenesisHeader(validators)
+	)
+	be, _ := tests_utils.MustCreateAndStartNewBackend(t, nodePrivateKey, genesisHeader, validators)
+	core := newTestCore(be, tendermint.DefaultConfig)
+
+	voteA := newSignedVote(t, core, msgPrevote, big.NewInt(1), 0, common.HexToHash("0x01"))
+	voteB := newSignedVote(t, core, msgPrevote, big.NewInt(1), 0, common.HexToHash("0x02"))
+
+	evidence, err := newEvidence(voteA, voteB)
+	require.NoError(t, err)
+	reversed, err := newEvidence(voteB, voteA)
+	require.NoError(t, err)
+	assert.Equal(t, evidence.Hash(), reversed.Hash())
+
+	offence, err := VerifyEvidence(evidence)
+	require.NoError(t, err)
+	assert.Equal(t, nodeAddr, offence.Offender)
+	assert.Equal(t, int64(1), offence.BlockNumber.Int64())
+	assert.Equal(t, int64(0), offence.Round)
+
+	// another evidence of the same validator at the same block number and round proves the same offence
+	voteC := newSignedVote(t, core, msgPrevote, big.NewInt(1), 0, common.HexToHash("0x03"))
+	other, err := newEvidence(voteA, voteC)
+	require.NoError(t, err)
+	otherOffence, err := VerifyEvidence(other)
+	require.NoError(t, err)
+	assert.NotEqual(t, evidence.Hash(), other.Hash())
+	assert.Equal(t, offence.Key(), otherOffence.Key())
+
+	// the evidence must survive a round trip through the header extra
+	extraData, err := rlp.EncodeToBytes(&types.TendermintExtra{Evidences: []*types.TendermintEvidence{evidence}})
+	require.NoError(t, err)
+	var extra types.TendermintExtra
+	require.NoError(t, rlp.DecodeBytes(extraData, &extra))
+	require.Len(t, extra.Evidences, 1)
+	assert.Equal(t, evidence.Hash(), extra.Evidences[0].Hash())
+
+	testCases := []struct {
+		name  string
+		voteB *message
+	}{
+		{"same block", newSignedVote(t, core, msgPrevote, big.NewInt(1), 0, common.HexToHash("0x01"))},
+		{"different round", newSignedVote(t, core, msgPrevote, big.NewInt(1), 1, common.HexToHash("0x02"))},
+		{"different block number", newSignedVote(t, core, msgPrevote, big.NewInt(2), 0, common.HexToHash("0x02"))},
+		{"different vote type", newSignedVote(t, core, msgPrecommit, big.NewInt(1), 0, common.HexToHash("0x02"))},
+	}
+	for _, tc := range testCases {
+		ev, err := newEvidence(voteA, tc.voteB)
+		require.NoError(t, err)
+		_, err = VerifyEvidence(ev)
+		assert.Error(t, err, tc.name)
+	}
+
+	// tampered vote has a signature mismatch
+	tampered := *voteB
+	tampered.Address = common.HexToAddress("0x1234")
+	ev, err := newEvidence(voteA, &tampered)
+	require.NoError(t, err)
+	_, err = VerifyEvidence(ev)
+	assert.Error(t, err)
+}
+
+// TestHandleConflictingPrevotes assures that receiving 2 prevotes of a validator for different blocks in the same round
+// reports an evidence to backend.
+func TestHandleConflictingPrevotes(t *testing.T) {
+	var (
+		nodePrivateKey = tests_utils.MakeNodeKey()
+		nodeAddr       = crypto.PubkeyToAddress(nodePrivateKey.PublicKey)
+		nodeAddr2, _   = common.EvryAddressStringToAddressCheck("EH9uVaqWRxHuzJbroqzX18yxmeW8XVJyV9")
+		validators     = []common.Address{nodeAddr, nodeAddr2}
+		genesisHeader  = tests_utils.MakeGenesisHeader(validators)
+	)
+	be, _ := tests_utils.MustCreateAndStartNewBackend(t, nodePrivateKey, genesisHeader, validators)
+	core := newTestCore(be, tendermint.DefaultConfig)
+	core.currentState = core.getInitializedState()
+	core.valSet = be.Validators(core.CurrentState().BlockNumber())
+
+	blockNumber := core.CurrentState().BlockNumber()
+	voteA := newSignedVote(t, core, msgPrevote, blockNumber, 0, common.HexToHash("0x01"))
+	voteB := newSignedVote(t, core, msgPrevote, blockNumber, 0, common.HexToHash("0x02"))
+
+	require.NoError(t, core.handlePrevote(*voteA))
+	assert.Equal(t, ErrConflictingVotes, core.handlePrevote(*voteB))
+
+	mockBackend, ok := be.(*tests_utils.MockBackend)
+	require.True(t, ok)
+	require.Len(t, mockBackend.Evidences, 1)
+	expected, err := newEvidence(voteA, voteB)
+	require.NoError(t, err)
+	assert.NotNil(t, mockBackend.Evidences[expected.Hash()])
+}
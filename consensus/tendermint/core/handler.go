@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"math/big"
+	"time"
 
 	"github.com/Evrynetlabs/evrynet-node/common"
 	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint"
@@ -241,7 +242,11 @@ func (c *core) handlePropose(msg message) error {
 		return nil
 	}
 
-	if err := c.VerifyProposal(proposal, msg); err != nil {
+	tendermintProposalSizeHistogram.Update(int64(proposal.Block.Size()))
+	verifyStart := time.Now()
+	err := c.VerifyProposal(proposal, msg)
+	tendermintProposalVerifyTimer.UpdateSince(verifyStart)
+	if err != nil {
 		if err == evrynetCore.ErrKnownBlock { // block is already inserted into chain
 			return nil
 		}
@@ -292,8 +297,11 @@ func (c *core) handlePrevote(msg message) error {
 		return nil
 	}
 	//log.Info("received prevote", "from", msg.Address, "round", vote.Round, "block_hash", vote.BlockHash.Hex())
-	added, err := state.addPrevote(msg, &vote, c.valSet)
+	added, msgSet, err := state.addPrevote(msg, &vote, c.valSet)
 	if err != nil {
+		if err == ErrConflictingVotes {
+			c.handleConflictingVote(msgSet, msg)
+		}
 		return err
 	}
 	if !added {
@@ -380,8 +388,11 @@ func (c *core) handlePrecommit(msg message) error {
 		return nil
 	}
 	//log.Info("received precommit", "from", msg.Address, "round", vote.Round, "block_hash", vote.BlockHash.Hex())
-	added, err := state.addPrecommit(msg, &vote, c.valSet)
+	added, msgSet, err := state.addPrecommit(msg, &vote, c.valSet)
 	if err != nil {
+		if err == ErrConflictingVotes {
+			c.handleConflictingVote(msgSet, msg)
+		}
 		return err
 	}
 	if !added {
@@ -526,6 +537,8 @@ func (c *core) handleMsgLocked(msg message) error {
 		return c.handleCatchupRequest(msg)
 	case msgCatchUpReply:
 		return c.handleCatchUpReply(msg)
+	case msgEvidence:
+		return c.handleEvidence(msg)
 	default:
 		return fmt.Errorf("unknown msg code %d", msg.Code)
 	}
@@ -554,6 +567,9 @@ func (c *core) handleTimeout(ti timeoutInfo) {
 	// the timeout will now cause a state transition
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	if meter, ok := tendermintTimeoutMeters[ti.Step]; ok {
+		meter.Mark(1)
+	}
 
 	switch ti.Step {
 	case RoundStepNewHeight:
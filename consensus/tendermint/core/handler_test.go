@@ -31,6 +31,7 @@ func newTestCore(backend tendermint.Backend, config *tendermint.Config) *core {
 		futureMessages: queue.NewPriorityQueue(0, true),
 		sentMsgStorage: NewMsgStorage(),
 		rebroadcast:    false,
+		roundSteps:     make(chan types.RoundStep, roundStepChanSize),
 	}
 }
 
@@ -1,5 +1,10 @@
 package core
 
+import (
+	"github.com/Evrynetlabs/evrynet-node/core/types"
+	"github.com/Evrynetlabs/evrynet-node/event"
+)
+
 //Engine abstract the core's functions
 //Note that backend and other packages doesn't care about core's internal logic.
 //It only requires core to start receiving/handling messages
@@ -7,4 +12,10 @@ package core
 type Engine interface {
 	Start() error
 	Stop() error
+	// ConsensusState returns a snapshot of the consensus state at the current block number
+	ConsensusState() (*types.ConsensusState, error)
+	// RoundVotes returns the votes received at the round of the block number, which must be the current one
+	RoundVotes(blockNumber uint64, round int64) (*types.RoundVotes, error)
+	// SubscribeRoundStep subscribes the channel to the step transitions of the consensus state machine
+	SubscribeRoundStep(ch chan<- types.RoundStep) event.Subscription
 }
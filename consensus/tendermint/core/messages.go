@@ -2,6 +2,7 @@ package core
 
 import (
 	"io"
+	"math/big"
 	"sync"
 
 	"github.com/Workiva/go-datastructures/queue"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/Evrynetlabs/evrynet-node/common"
 	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint"
+	"github.com/Evrynetlabs/evrynet-node/core/types"
 	"github.com/Evrynetlabs/evrynet-node/crypto"
 	"github.com/Evrynetlabs/evrynet-node/log"
 	"github.com/Evrynetlabs/evrynet-node/rlp"
@@ -26,6 +28,7 @@ const (
 	msgPrecommit
 	msgCatchUpRequest
 	msgCatchUpReply
+	msgEvidence
 )
 
 //message is used to store consensus information between steps
@@ -102,6 +105,7 @@ func (item *msgItem) Compare(other queue.Item) int {
 type blockVotes struct {
 	votes         []*Vote // validatorIndex -> *Vote
 	totalReceived int
+	totalPower    int64 // the sum of voting power of validators voting for this block
 }
 
 type messageSet struct {
@@ -114,6 +118,7 @@ type messageSet struct {
 	voteByBlock   map[common.Hash]*blockVotes
 	maj23         *common.Hash
 	totalReceived int
+	totalPower    int64 // the sum of voting power of validators sending votes
 	//TODO: Do we have to keep track of which peer has 2/3Majority?
 }
 
@@ -142,6 +147,14 @@ func (ms *messageSet) VotesByAddress() map[common.Address]*Vote {
 	return ret
 }
 
+// MessageByAddress returns the message received from the given address
+func (ms *messageSet) MessageByAddress(addr common.Address) (*message, bool) {
+	ms.messagesMu.Lock()
+	defer ms.messagesMu.Unlock()
+	msg, ok := ms.messages[addr]
+	return msg, ok
+}
+
 func (ms *messageSet) AddVote(msg message, vote *Vote) (bool, error) {
 	ms.messagesMu.Lock()
 	defer ms.messagesMu.Unlock()
@@ -149,7 +162,7 @@ func (ms *messageSet) AddVote(msg message, vote *Vote) (bool, error) {
 	if ms.msgCode != msg.Code {
 		return false, ErrDifferentMsgType
 	}
-	index, _ := ms.valSet.GetByAddress(msg.Address)
+	index, val := ms.valSet.GetByAddress(msg.Address)
 	if index == -1 {
 		return false, errors.Wrapf(ErrVoteInvalidValidatorAddress, "address in vote message:%s ", msg.Address.String())
 	}
@@ -179,11 +192,12 @@ func (ms *messageSet) AddVote(msg message, vote *Vote) (bool, error) {
 	ms.messages[msg.Address] = &msg
 	ms.voteByAddress[msg.Address] = vote
 	ms.totalReceived++
-	if err := ms.addVoteToBlockVote(vote, index); err != nil {
+	ms.totalPower += val.VotingPower()
+	if err := ms.addVoteToBlockVote(vote, index, val.VotingPower()); err != nil {
 		return false, err
 	}
 
-	if ms.voteByBlock[copyHash].totalReceived >= ms.valSet.MinMajority() {
+	if ms.voteByBlock[copyHash].totalPower >= ms.valSet.MinMajorityPower() {
 		if ms.maj23 == nil {
 			ms.maj23 = &copyHash
 		}
@@ -192,7 +206,7 @@ func (ms *messageSet) AddVote(msg message, vote *Vote) (bool, error) {
 	return true, nil
 }
 
-func (ms *messageSet) addVoteToBlockVote(vote *Vote, index int) error {
+func (ms *messageSet) addVoteToBlockVote(vote *Vote, index int, power int64) error {
 	bvotes, exist := ms.voteByBlock[*(vote.BlockHash)]
 	if !exist {
 		bvotes = &blockVotes{
@@ -206,6 +220,7 @@ func (ms *messageSet) addVoteToBlockVote(vote *Vote, index int) error {
 	}
 	bvotes.votes[index] = vote
 	bvotes.totalReceived++
+	bvotes.totalPower += power
 	ms.voteByBlock[*(vote.BlockHash)] = bvotes
 	return nil
 }
@@ -225,7 +240,7 @@ func (ms *messageSet) HasTwoThirdAny() bool {
 	}
 	ms.messagesMu.Lock()
 	defer ms.messagesMu.Unlock()
-	return ms.totalReceived >= ms.valSet.MinMajority()
+	return ms.totalPower >= ms.valSet.MinMajorityPower()
 }
 
 //TwoThirdMajority return a blockHash and a bool inidicate if this messageSet hash got a
@@ -252,3 +267,94 @@ func (ms *messageSet) MissingVotes() map[common.Address]bool {
 	}
 	return missing
 }
+
+// voteSet returns a snapshot of the votes of the message set, the votes of a nil message set are all missing
+func (ms *messageSet) voteSet(valSet tendermint.ValidatorSet) *types.VoteSet {
+	votes := &types.VoteSet{
+		Votes:         make(map[common.Address]common.Hash),
+		Missing:       make([]common.Address, 0),
+		MajorityPower: valSet.MinMajorityPower(),
+	}
+	if ms == nil {
+		for _, val := range valSet.List() {
+			votes.Missing = append(votes.Missing, val.Address())
+		}
+		return votes
+	}
+	ms.messagesMu.Lock()
+	defer ms.messagesMu.Unlock()
+	for addr, vote := range ms.voteByAddress {
+		votes.Votes[addr] = *vote.BlockHash
+	}
+	for _, val := range ms.valSet.List() {
+		if _, ok := ms.voteByAddress[val.Address()]; !ok {
+			votes.Missing = append(votes.Missing, val.Address())
+		}
+	}
+	if ms.maj23 != nil {
+		maj23 := *ms.maj23
+		votes.TwoThirdMajority = &maj23
+	}
+	votes.VotingPower = ms.totalPower
+	votes.MajorityPower = ms.valSet.MinMajorityPower()
+	return votes
+}
+
+// PayloadStep returns the block number, round and step of a proposal or vote from the payload signed by its sender,
+// i.e. the message without signature. It returns false if the payload is not a proposal or a vote.
+func PayloadStep(payload []byte) (blockNumber *big.Int, round int64, step RoundStepType, ok bool) {
+	var msg message
+	if err := rlp.DecodeBytes(payload, &msg); err != nil || len(msg.Signature) != 0 {
+		return nil, 0, 0, false
+	}
+	switch msg.Code {
+	case msgPropose:
+		var proposal Proposal
+		if err := rlp.DecodeBytes(msg.Msg, &proposal); err != nil || proposal.Block == nil {
+			return nil, 0, 0, false
+		}
+		return proposal.Block.Number(), proposal.Round, RoundStepPropose, true
+	case msgPrevote, msgPrecommit:
+		var vote Vote
+		if err := rlp.DecodeBytes(msg.Msg, &vote); err != nil || vote.BlockNumber == nil {
+			return nil, 0, 0, false
+		}
+		step = RoundStepPrevote
+		if msg.Code == msgPrecommit {
+			step = RoundStepPrecommit
+		}
+		return vote.BlockNumber, vote.Round, step, true
+	}
+	return nil, 0, 0, false
+}
+
+// IsInfoPayload returns true if the payload signed by its sender is a catch up or evidence message.
+// These messages only relay information and do not commit their sender to a block.
+func IsInfoPayload(payload []byte) bool {
+	var msg message
+	if err := rlp.DecodeBytes(payload, &msg); err != nil || len(msg.Signature) != 0 {
+		return false
+	}
+	switch msg.Code {
+	case msgCatchUpRequest, msgCatchUpReply, msgEvidence:
+		return true
+	}
+	return false
+}
+
+// PayloadSigner decodes the payload of a consensus message and returns the address of its signer,
+// the signature must match the address field of the message.
+func PayloadSigner(payload []byte) (common.Address, error) {
+	var msg message
+	if err := rlp.DecodeBytes(payload, &msg); err != nil {
+		return common.Address{}, err
+	}
+	signer, err := msg.GetAddressFromSignature()
+	if err != nil {
+		return common.Address{}, err
+	}
+	if signer != msg.Address {
+		return common.Address{}, ErrSignerMessageMissMatch
+	}
+	return signer, nil
+}
@@ -0,0 +1,50 @@
+package core
+
+import (
+	"strings"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/metrics"
+)
+
+const (
+	// missedVotesMetricPrefix prefixes the counters of precommits missed by each validator
+	missedVotesMetricPrefix = "evr/consensus/tendermint/votes/missed/"
+)
+
+var (
+	tendermintHeightRoundsHistogram = metrics.NewRegisteredHistogram("evr/consensus/tendermint/height/rounds", nil, metrics.NewExpDecaySample(1028, 0.015))
+	tendermintCatchUpSentMeter      = metrics.NewRegisteredMeter("evr/consensus/tendermint/catchup/sent", nil)
+	tendermintCatchUpAnsweredMeter  = metrics.NewRegisteredMeter("evr/consensus/tendermint/catchup/answered", nil)
+	tendermintRebroadcastMeter      = metrics.NewRegisteredMeter("evr/consensus/tendermint/rebroadcasts", nil)
+	tendermintProposalSizeHistogram = metrics.NewRegisteredHistogram("evr/consensus/tendermint/proposal/size", nil, metrics.NewExpDecaySample(1028, 0.015))
+	tendermintProposalVerifyTimer   = metrics.NewRegisteredTimer("evr/consensus/tendermint/proposal/verify", nil)
+
+	// tendermintStepTimers measure the time spent at each step
+	tendermintStepTimers = make(map[RoundStepType]metrics.Timer)
+	// tendermintTimeoutMeters count the timeouts fired at each step
+	tendermintTimeoutMeters = make(map[RoundStepType]metrics.Meter)
+)
+
+func init() {
+	for step := RoundStepNewHeight; step.IsValid(); step++ {
+		name := stepMetricName(step)
+		tendermintStepTimers[step] = metrics.NewRegisteredTimer("evr/consensus/tendermint/step/"+name, nil)
+		tendermintTimeoutMeters[step] = metrics.NewRegisteredMeter("evr/consensus/tendermint/timeouts/"+name, nil)
+	}
+}
+
+// stepMetricName returns the name of the step in metrics, i.e. "prevotewait" for RoundStepPrevoteWait
+func stepMetricName(step RoundStepType) string {
+	return strings.ToLower(strings.TrimPrefix(step.String(), "RoundStep"))
+}
+
+// markMissedVotes counts a missed vote for each validator
+func markMissedVotes(missing map[common.Address]bool) {
+	if !metrics.Enabled {
+		return
+	}
+	for addr := range missing {
+		metrics.GetOrRegisterCounter(missedVotesMetricPrefix+strings.ToLower(addr.Hex()), nil).Inc(1)
+	}
+}
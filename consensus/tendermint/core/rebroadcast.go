@@ -21,5 +21,7 @@ func (c *core) reBroadcastMsg(msg message, logger *zap.SugaredLogger) {
 	}
 	if err := c.backend.Multicast(c.valSet.GetNeighbors(c.getAddress()), payload); err != nil {
 		logger.Error("failed to re-gossip the vote received", "error", err)
+		return
 	}
+	tendermintRebroadcastMeter.Mark(1)
 }
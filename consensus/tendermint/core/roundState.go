@@ -234,7 +234,7 @@ func (s *roundState) EncodeRLP(w io.Writer) error {
 	})
 }
 
-func (s *roundState) addPrevote(msg message, vote *Vote, valset tendermint.ValidatorSet) (bool, error) {
+func (s *roundState) addPrevote(msg message, vote *Vote, valset tendermint.ValidatorSet) (bool, *messageSet, error) {
 	view := tendermint.View{
 		BlockNumber: big.NewInt(0).Set(vote.BlockNumber),
 		Round:       vote.Round,
@@ -244,7 +244,8 @@ func (s *roundState) addPrevote(msg message, vote *Vote, valset tendermint.Valid
 		msgSet = newMessageSet(valset, msgPrevote, &view)
 		s.PrevotesReceived[vote.Round] = msgSet
 	}
-	return msgSet.AddVote(msg, vote)
+	added, err := msgSet.AddVote(msg, vote)
+	return added, msgSet, err
 }
 
 //GetPrevotesByRound return prevote messageSet for that round, if there is no prevotes message on the said round, return nil and false
@@ -253,7 +254,7 @@ func (s *roundState) GetPrevotesByRound(round int64) (*messageSet, bool) {
 	return msgSet, ok
 }
 
-func (s *roundState) addPrecommit(msg message, vote *Vote, valset tendermint.ValidatorSet) (bool, error) {
+func (s *roundState) addPrecommit(msg message, vote *Vote, valset tendermint.ValidatorSet) (bool, *messageSet, error) {
 	view := tendermint.View{
 		BlockNumber: big.NewInt(0).Set(vote.BlockNumber),
 		Round:       vote.Round,
@@ -263,7 +264,8 @@ func (s *roundState) addPrecommit(msg message, vote *Vote, valset tendermint.Val
 		msgSet = newMessageSet(valset, msgPrecommit, &view)
 		s.PrecommitsReceived[vote.Round] = msgSet
 	}
-	return msgSet.AddVote(msg, vote)
+	added, err := msgSet.AddVote(msg, vote)
+	return added, msgSet, err
 }
 
 //GetPrecommitsByRound return precommit messageSet for that round, if there is no precommit message on the said round, return nil and false
@@ -27,7 +27,7 @@ func NewMsgStorage() *msgStorage {
 	}
 }
 
-// storeSentMsg stores vote/ propose to database
+// storeSentMsg stores vote/ propose, it is persisted to database by the WAL before the message is sent
 func (c *msgStorage) storeSentMsg(logger *zap.SugaredLogger, step RoundStepType, round int64, msgData []byte) {
 	logger = logger.With("msg_step", step.String(), "msg_round", round)
 	if len(msgData) == 0 {
@@ -68,6 +68,34 @@ func (c *msgStorage) lookup(step RoundStepType, round int64) int {
 	return -1
 }
 
+// find returns the payload of the message sent exactly at (step, round)
+func (c *msgStorage) find(step RoundStepType, round int64) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, element := range c.savedMsg {
+		if element.Round == round && element.Step == step {
+			return element.Data, true
+		}
+	}
+	return nil, false
+}
+
+// all returns a copy of all messages stored
+func (c *msgStorage) all() []*MsgStorageData {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	msgs := make([]*MsgStorageData, len(c.savedMsg))
+	copy(msgs, c.savedMsg)
+	return msgs
+}
+
+// restore replaces the messages stored, it is used when replaying the WAL
+func (c *msgStorage) restore(msgs []*MsgStorageData) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.savedMsg = msgs
+}
+
 func (c *msgStorage) get(index int) ([]byte, error) {
 	if index >= len(c.savedMsg) || index < 0 {
 		return nil, io.EOF
@@ -66,6 +66,8 @@ func (c *core) updateStateForNewblock() {
 			logger.Errorw("updateStateForNewblock(): Having commitRound with no +2/3 precommits")
 			return
 		}
+		// the precommits received after the commit until the block is inserted are not missed
+		markMissedVotes(precommits.MissingVotes())
 	}
 
 	// Update all roundState's fields
@@ -89,5 +91,6 @@ func (c *core) updateStateForNewblock() {
 	c.currentState = state
 	c.valSet = c.backend.Validators(c.CurrentState().BlockNumber())
 	c.futureProposals = make(map[int64]message)
+	c.onRoundStep()
 	logger.Infow("updated to new block", "new_block_number", state.BlockNumber())
 }
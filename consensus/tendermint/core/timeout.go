@@ -21,8 +21,9 @@ const (
 type TimeoutTicker interface {
 	Start() error
 	Stop() error
-	Chan() <-chan timeoutInfo       // on which to receive a timeout
-	ScheduleTimeout(ti timeoutInfo) // reset the timer
+	Chan() <-chan timeoutInfo                // on which to receive a timeout
+	ScheduleTimeout(ti timeoutInfo)          // reset the timer
+	Pending() (timeoutInfo, time.Time, bool) // the timeout running and its deadline
 }
 
 // timeoutInfo keep track about a timeout job
@@ -75,6 +76,10 @@ type timeoutTicker struct {
 
 	running bool
 	lock    sync.Mutex
+
+	pendingMu       sync.Mutex
+	pending         *timeoutInfo // pending is the timeout running, it is nil once fired
+	pendingDeadline time.Time
 }
 
 // NewTimeoutTicker returns a new TimeoutTicker that's ready to use
@@ -128,6 +133,25 @@ func (tt *timeoutTicker) Chan() <-chan timeoutInfo {
 	return tt.tockChan
 }
 
+// Pending returns the timeout running and the time it fires, it returns false if no timeout is running
+func (tt *timeoutTicker) Pending() (timeoutInfo, time.Time, bool) {
+	tt.pendingMu.Lock()
+	defer tt.pendingMu.Unlock()
+	if tt.pending == nil {
+		return timeoutInfo{}, time.Time{}, false
+	}
+	return *tt.pending, tt.pendingDeadline, true
+}
+
+func (tt *timeoutTicker) setPending(ti *timeoutInfo) {
+	tt.pendingMu.Lock()
+	defer tt.pendingMu.Unlock()
+	tt.pending = ti
+	if ti != nil {
+		tt.pendingDeadline = time.Now().Add(ti.Duration)
+	}
+}
+
 // stop the timer and drain if necessary
 func (tt *timeoutTicker) stopTimer() {
 	// Stop() returns false if it was already fired or was stopped
@@ -171,9 +195,11 @@ func (tt *timeoutTicker) timeoutRoutine() {
 			// NOTE time.Timer allows duration to be non-positive
 			ti = newti
 			tt.timer.Reset(ti.Duration)
+			tt.setPending(&newti)
 			log.Info("Scheduled timeout", "dur", ti.Duration, "block_number", ti.BlockNumber, "round", ti.Round, "step", ti.Step)
 		case <-tt.timer.C:
 			log.Info("Timed out", "dur", ti.Duration, "block_number", ti.BlockNumber, "round", ti.Round, "step", ti.Step)
+			tt.setPending(nil)
 			// go routine here guarantees timeoutRoutine doesn't block.
 			// Determinism comes from playback in the handleEvents.
 			// We can eliminate it by merging the timeoutRoutine into receiveRoutine
@@ -189,6 +215,7 @@ func (tt *timeoutTicker) timeoutRoutine() {
 			}(ti)
 		case <-tt.Quit:
 			// abort to send to tt.tockChan
+			tt.setPending(nil)
 			close(abort)
 			return
 		}
@@ -0,0 +1,254 @@
+package core
+
+import (
+	"io"
+	"math/big"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint"
+	"github.com/Evrynetlabs/evrynet-node/core/types"
+	"github.com/Evrynetlabs/evrynet-node/evrdb"
+	"github.com/Evrynetlabs/evrynet-node/rlp"
+)
+
+var (
+	// walStateKey tracks the latest roundState written before a proposal/ vote is signed and sent
+	walStateKey = []byte("tendermint-wal-state")
+	// walSentMsgsKey tracks all messages this node has signed for the current block number
+	walSentMsgsKey = []byte("tendermint-wal-sent-msgs")
+)
+
+// walRoundState is the part of roundState which must survive a restart to keep the node from double-signing.
+// Votes received from other validators are not stored since they can be requested again via catch up.
+type walRoundState struct {
+	BlockNumber *big.Int
+	Round       int64
+	Step        RoundStepType
+	LockedRound int64
+	LockedBlock *types.Block
+	ValidRound  int64
+	ValidBlock  *types.Block
+}
+
+func (rs *walRoundState) EncodeRLP(w io.Writer) error {
+	return rlp.Encode(w, []interface{}{
+		rs.BlockNumber,
+		strconv.FormatInt(rs.Round, 10),
+		rs.Step,
+		strconv.FormatInt(rs.LockedRound, 10),
+		optionalBlock(rs.LockedBlock),
+		strconv.FormatInt(rs.ValidRound, 10),
+		optionalBlock(rs.ValidBlock),
+	})
+}
+
+func (rs *walRoundState) DecodeRLP(s *rlp.Stream) error {
+	var ws struct {
+		BlockNumber *big.Int
+		RStr        string
+		Step        RoundStepType
+		LockedRStr  string
+		LockedBlock []*types.Block
+		ValidRStr   string
+		ValidBlock  []*types.Block
+	}
+	if err := s.Decode(&ws); err != nil {
+		return err
+	}
+	var err error
+	if rs.Round, err = strconv.ParseInt(ws.RStr, 10, 64); err != nil {
+		return err
+	}
+	if rs.LockedRound, err = strconv.ParseInt(ws.LockedRStr, 10, 64); err != nil {
+		return err
+	}
+	if rs.ValidRound, err = strconv.ParseInt(ws.ValidRStr, 10, 64); err != nil {
+		return err
+	}
+	rs.BlockNumber, rs.Step = ws.BlockNumber, ws.Step
+	if len(ws.LockedBlock) > 0 {
+		rs.LockedBlock = ws.LockedBlock[0]
+	}
+	if len(ws.ValidBlock) > 0 {
+		rs.ValidBlock = ws.ValidBlock[0]
+	}
+	return nil
+}
+
+// optionalBlock wraps a block which might be nil into a list so it can be RLP-encoded
+func optionalBlock(block *types.Block) []*types.Block {
+	if block == nil {
+		return []*types.Block{}
+	}
+	return []*types.Block{block}
+}
+
+// walSentMsgs is the list of messages signed and sent at BlockNumber
+type walSentMsgs struct {
+	BlockNumber *big.Int
+	Msgs        []*MsgStorageData
+}
+
+// EncodeRLP implements rlp.Encoder, MsgStorageData is encoded with its round as string since RLP does not support signed integers.
+func (m *MsgStorageData) EncodeRLP(w io.Writer) error {
+	return rlp.Encode(w, []interface{}{
+		m.Step,
+		strconv.FormatInt(m.Round, 10),
+		m.Data,
+	})
+}
+
+// DecodeRLP implements rlp.Decoder, and load the MsgStorageData fields from a RLP stream.
+func (m *MsgStorageData) DecodeRLP(s *rlp.Stream) error {
+	var ms struct {
+		Step RoundStepType
+		RStr string
+		Data []byte
+	}
+	if err := s.Decode(&ms); err != nil {
+		return err
+	}
+	round, err := strconv.ParseInt(ms.RStr, 10, 64)
+	if err != nil {
+		return err
+	}
+	m.Step, m.Round, m.Data = ms.Step, round, ms.Data
+	return nil
+}
+
+// wal is a write-ahead log persisting roundState and the sent messages into a database,
+// it is written before a message is sent to the network and replayed on core.Start()
+type wal struct {
+	db evrdb.KeyValueStore
+}
+
+func newWAL(db evrdb.KeyValueStore) *wal {
+	return &wal{db: db}
+}
+
+// writeState stores the locking info of the state at (round, step)
+func (w *wal) writeState(state *roundState, round int64, step RoundStepType) error {
+	rs := &walRoundState{
+		BlockNumber: state.CopyBlockNumber(),
+		Round:       round,
+		Step:        step,
+		LockedRound: state.LockedRound(),
+		LockedBlock: state.LockedBlock(),
+		ValidRound:  state.ValidRound(),
+		ValidBlock:  state.ValidBlock(),
+	}
+	data, err := rlp.EncodeToBytes(rs)
+	if err != nil {
+		return err
+	}
+	return w.db.Put(walStateKey, data)
+}
+
+// readState returns the last roundState written, or nil if there is none
+func (w *wal) readState() (*walRoundState, error) {
+	if has, err := w.db.Has(walStateKey); err != nil || !has {
+		return nil, err
+	}
+	data, err := w.db.Get(walStateKey)
+	if err != nil {
+		return nil, err
+	}
+	var rs walRoundState
+	if err := rlp.DecodeBytes(data, &rs); err != nil {
+		return nil, err
+	}
+	return &rs, nil
+}
+
+// writeSentMsgs stores all messages sent at blockNumber
+func (w *wal) writeSentMsgs(blockNumber *big.Int, msgs []*MsgStorageData) error {
+	data, err := rlp.EncodeToBytes(&walSentMsgs{
+		BlockNumber: blockNumber,
+		Msgs:        msgs,
+	})
+	if err != nil {
+		return err
+	}
+	return w.db.Put(walSentMsgsKey, data)
+}
+
+// readSentMsgs returns the messages sent at the last block number written, or nil if there is none
+func (w *wal) readSentMsgs() (*walSentMsgs, error) {
+	if has, err := w.db.Has(walSentMsgsKey); err != nil || !has {
+		return nil, err
+	}
+	data, err := w.db.Get(walSentMsgsKey)
+	if err != nil {
+		return nil, err
+	}
+	var msgs walSentMsgs
+	if err := rlp.DecodeBytes(data, &msgs); err != nil {
+		return nil, err
+	}
+	return &msgs, nil
+}
+
+// writeWAL persists the current state and the sent messages before the message at (round, step) is sent.
+// A message must not be sent if it fails, otherwise the node might sign a conflicting message after restarting.
+func (c *core) writeWAL(round int64, step RoundStepType) error {
+	if c.wal == nil {
+		return nil
+	}
+	state := c.CurrentState()
+	if err := c.wal.writeState(state, round, step); err != nil {
+		return err
+	}
+	return c.wal.writeSentMsgs(state.CopyBlockNumber(), c.sentMsgStorage.all())
+}
+
+// restoreFromWAL restores the locking info and sent messages of the current block number from the WAL
+// it returns true if the state is restored
+func (c *core) restoreFromWAL() bool {
+	if c.wal == nil {
+		return false
+	}
+	var (
+		state  = c.CurrentState()
+		logger = c.getLogger()
+	)
+	rs, err := c.wal.readState()
+	if err != nil {
+		logger.Errorw("failed to read roundState from WAL", "err", err)
+		return false
+	}
+	if rs == nil || rs.BlockNumber.Cmp(state.BlockNumber()) != 0 {
+		logger.Infow("no roundState in WAL for current block number")
+		return false
+	}
+	msgs, err := c.wal.readSentMsgs()
+	if err != nil {
+		logger.Errorw("failed to read sent msgs from WAL", "err", err)
+		return false
+	}
+
+	state.UpdateRoundStep(rs.Round, rs.Step)
+	state.SetLockedRoundAndBlock(rs.LockedRound, rs.LockedBlock)
+	state.SetValidRoundAndBlock(rs.ValidRound, rs.ValidBlock)
+	if rs.Round > 0 {
+		c.valSet.CalcProposer(c.valSet.GetProposer().Address(), rs.Round)
+	}
+	if msgs != nil && msgs.BlockNumber.Cmp(state.BlockNumber()) == 0 {
+		c.sentMsgStorage.restore(msgs.Msgs)
+	}
+	c.getLogger().Infow("restored roundState from WAL", "locked_round", rs.LockedRound, "valid_round", rs.ValidRound)
+	return true
+}
+
+// replaySentMsgs posts the restored sent messages back to core so its own votes are counted again
+func (c *core) replaySentMsgs(logger *zap.SugaredLogger) {
+	for _, msg := range c.sentMsgStorage.all() {
+		if err := c.backend.EventMux().Post(tendermint.MessageEvent{
+			Payload: msg.Data,
+		}); err != nil {
+			logger.Errorw("Failed to replay msg from WAL to eventMux", "err", err)
+			return
+		}
+	}
+}
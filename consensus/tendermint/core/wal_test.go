@@ -0,0 +1,99 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/tests_utils"
+	"github.com/Evrynetlabs/evrynet-node/core/types"
+	"github.com/Evrynetlabs/evrynet-node/crypto"
+	"github.com/Evrynetlabs/evrynet-node/evrdb/memorydb"
+)
+
+func TestWAL_WriteAndReadState(t *testing.T) {
+	var (
+		w     = newWAL(memorydb.New())
+		block = types.NewBlockWithHeader(&types.Header{Number: big.NewInt(5)})
+		state = newRoundState(&tendermint.View{BlockNumber: big.NewInt(5), Round: 2},
+			make(map[int64]*messageSet), make(map[int64]*messageSet), nil,
+			1, block, -1, nil, nil, RoundStepPrevote, -1)
+	)
+	rs, err := w.readState()
+	require.NoError(t, err)
+	assert.Nil(t, rs)
+
+	require.NoError(t, w.writeState(state, 2, RoundStepPrecommit))
+	rs, err = w.readState()
+	require.NoError(t, err)
+	require.NotNil(t, rs)
+	assert.Equal(t, int64(5), rs.BlockNumber.Int64())
+	assert.Equal(t, int64(2), rs.Round)
+	assert.Equal(t, RoundStepPrecommit, rs.Step)
+	assert.Equal(t, int64(1), rs.LockedRound)
+	assert.Equal(t, block.Hash(), rs.LockedBlock.Hash())
+	assert.Equal(t, int64(-1), rs.ValidRound)
+	assert.Nil(t, rs.ValidBlock)
+
+	msgs := []*MsgStorageData{
+		{Step: RoundStepPrevote, Round: 2, Data: []byte("abc")},
+		{Step: RoundStepPrecommit, Round: 2, Data: []byte("def")},
+	}
+	require.NoError(t, w.writeSentMsgs(big.NewInt(5), msgs))
+	sent, err := w.readSentMsgs()
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), sent.BlockNumber.Int64())
+	assert.Equal(t, msgs, sent.Msgs)
+}
+
+// TestCore_RestoreFromWAL assures that a restarted core resumes its round and does not sign another vote
+func TestCore_RestoreFromWAL(t *testing.T) {
+	zap.ReplaceGlobals(zap.NewExample())
+	var (
+		nodePrivateKey = tests_utils.MakeNodeKey()
+		nodeAddr       = crypto.PubkeyToAddress(nodePrivateKey.PublicKey)
+		nodeAddr2, _   = common.EvryAddressStringToAddressCheck("EH9uVaqWRxHuzJbroqzX18yxmeW8XVJyV9")
+		validators     = []common.Address{
+			nodeAddr,
+			nodeAddr2,
+		}
+		genesisHeader = tests_utils.MakeGenesisHeader(validators)
+		db            = memorydb.New()
+	)
+	be, _ := tests_utils.MustCreateAndStartNewBackend(t, nodePrivateKey, genesisHeader, validators)
+
+	core := newTestCore(be, tendermint.DefaultConfig)
+	core.wal = newWAL(db)
+	core.currentState = core.getInitializedState()
+	core.valSet = be.Validators(core.CurrentState().BlockNumber())
+
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1)})
+	core.CurrentState().SetLockedRoundAndBlock(1, block)
+	core.SendVote(msgPrecommit, block, 1)
+	sentPayload, ok := core.sentMsgStorage.find(RoundStepPrecommit, 1)
+	require.True(t, ok)
+
+	restarted := newTestCore(be, tendermint.DefaultConfig)
+	restarted.wal = newWAL(db)
+	restarted.currentState = restarted.getInitializedState()
+	restarted.valSet = be.Validators(restarted.CurrentState().BlockNumber())
+	require.True(t, restarted.restoreFromWAL())
+
+	state := restarted.CurrentState()
+	assert.Equal(t, int64(1), state.Round())
+	assert.Equal(t, RoundStepPrecommit, state.Step())
+	assert.Equal(t, int64(1), state.LockedRound())
+	assert.Equal(t, block.Hash(), state.LockedBlock().Hash())
+
+	// a precommit nil at the same round must not be signed
+	restarted.SendVote(msgPrecommit, nil, 1)
+	payload, ok := restarted.sentMsgStorage.find(RoundStepPrecommit, 1)
+	require.True(t, ok)
+	assert.Equal(t, sentPayload, payload)
+	assert.Len(t, restarted.sentMsgStorage.all(), 1)
+}
@@ -0,0 +1,337 @@
+// Package light implements a header-only verifier of the Tendermint consensus for light clients.
+// It tracks the validator sets from the checkpoint headers and verifies the committed seals of every header,
+// without any access to the state.
+package light
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/consensus"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/utils"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/validator"
+	"github.com/Evrynetlabs/evrynet-node/core/state"
+	"github.com/Evrynetlabs/evrynet-node/core/types"
+	"github.com/Evrynetlabs/evrynet-node/rpc"
+)
+
+const inMemoryValSets = 16
+
+var (
+	// ErrLightClient is returned when a light client is asked to produce a block
+	ErrLightClient = errors.New("light client can not produce blocks")
+	// ErrNotCheckpoint is returned if a skipping verification target is not a checkpoint header
+	ErrNotCheckpoint = errors.New("header is not a checkpoint")
+	// ErrNoTrustedCheckpoint is returned if a header can not be verified because neither its checkpoint
+	// nor a trusted checkpoint is known
+	ErrNoTrustedCheckpoint = errors.New("no trusted checkpoint")
+	// ErrInsufficientTrustedPower is returned if the validators of the trusted val-set signed less than 1/3
+	// of the trusted voting power of a header verified by skipping
+	ErrInsufficientTrustedPower = errors.New("insufficient voting power of trusted validators")
+	// ErrSkippingNotSupported is returned when skipping across epochs with aggregated BLS seals,
+	// whose signers can only be identified with the val-set of the previous checkpoint
+	ErrSkippingNotSupported = errors.New("skipping verification is not supported with aggregated seals")
+
+	defaultDifficulty = big.NewInt(1)
+)
+
+// Engine is a consensus.Engine verifying Tendermint headers for light clients.
+// A header is verified against the val-set stored in the checkpoint header of its epoch. If that checkpoint is unknown,
+// a checkpoint header can be verified by skipping from the latest trusted checkpoint: it is accepted if the trusted
+// validators signing it have more than 1/3 of the trusted voting power, so at least one of them is honest.
+type Engine struct {
+	config *tendermint.Config
+
+	valSets *lru.ARCCache // valSets caches the val-sets by the hash of their checkpoint header
+
+	trustedMu sync.RWMutex
+	trusted   *types.Header // trusted is the latest verified checkpoint header
+}
+
+// New creates a light Tendermint engine
+func New(config *tendermint.Config) *Engine {
+	valSets, _ := lru.NewARC(inMemoryValSets)
+	return &Engine{
+		config:  config,
+		valSets: valSets,
+	}
+}
+
+// SetTrustedCheckpoint sets a checkpoint header which is trusted without verification, i.e from a hardcoded checkpoint.
+func (e *Engine) SetTrustedCheckpoint(header *types.Header) error {
+	if !e.isCheckpoint(header.Number.Uint64()) {
+		return ErrNotCheckpoint
+	}
+	if _, err := utils.GetValSetAddresses(header); err != nil {
+		return err
+	}
+	e.trust(header)
+	return nil
+}
+
+// TrustedCheckpoint returns the latest verified checkpoint header
+func (e *Engine) TrustedCheckpoint() *types.Header {
+	e.trustedMu.RLock()
+	defer e.trustedMu.RUnlock()
+	return e.trusted
+}
+
+// Author retrieves the address of the proposer who signed the header
+func (e *Engine) Author(header *types.Header) (common.Address, error) {
+	return utils.GetProposer(header)
+}
+
+// VerifyHeader checks whether a header is proposed and committed by the validators of its epoch
+func (e *Engine) VerifyHeader(chain consensus.ChainReader, header *types.Header, seal bool) error {
+	return e.verifyHeader(chain, header, nil)
+}
+
+// VerifyHeaders is similar to VerifyHeader, but verifies a batch of headers in order.
+func (e *Engine) VerifyHeaders(chain consensus.ChainReader, headers []*types.Header, seals []bool) (chan<- struct{}, <-chan error) {
+	abort := make(chan struct{})
+	results := make(chan error, len(headers))
+	go func() {
+		for i, header := range headers {
+			err := e.verifyHeader(chain, header, headers[:i])
+			select {
+			case <-abort:
+				return
+			case results <- err:
+			}
+		}
+	}()
+	return abort, results
+}
+
+// VerifyUncles verifies that the block has no uncles
+func (e *Engine) VerifyUncles(chain consensus.ChainReader, block *types.Block) error {
+	if len(block.Uncles()) > 0 {
+		return tendermint.ErrInvalidUncleHash
+	}
+	return nil
+}
+
+// VerifySeal checks whether the header is proposed and committed by the validators of its epoch
+func (e *Engine) VerifySeal(chain consensus.ChainReader, header *types.Header) error {
+	if header.Number.Uint64() == 0 {
+		return tendermint.ErrUnknownBlock
+	}
+	valSet, err := e.getValSet(chain, header, nil)
+	if err != nil {
+		return err
+	}
+	return e.verifySeals(header, valSet)
+}
+
+// VerifySkipping verifies a checkpoint header from a trusted checkpoint header of an earlier epoch
+// without the checkpoint headers in between. On success, the header becomes the trusted checkpoint.
+func (e *Engine) VerifySkipping(trusted *types.Header, header *types.Header) error {
+	if !e.isCheckpoint(header.Number.Uint64()) || !e.isCheckpoint(trusted.Number.Uint64()) {
+		return ErrNotCheckpoint
+	}
+	if header.Number.Cmp(trusted.Number) <= 0 {
+		return ErrNotCheckpoint
+	}
+	trustedValSet, err := e.valSetOf(trusted, header.Number.Int64())
+	if err != nil {
+		return err
+	}
+	if err := e.verifyFields(header); err != nil {
+		return err
+	}
+	extra, err := types.ExtractTendermintExtra(header)
+	if err != nil {
+		return err
+	}
+	if len(extra.AggregatedSeal) != 0 {
+		return ErrSkippingNotSupported
+	}
+	signers, err := utils.CommittedSealSigners(header)
+	if err != nil {
+		return err
+	}
+	var trustedPower int64
+	for _, addr := range signers {
+		if _, val := trustedValSet.GetByAddress(addr); val != nil {
+			trustedPower += val.VotingPower()
+		}
+	}
+	if trustedPower*3 <= trustedValSet.TotalVotingPower() {
+		return ErrInsufficientTrustedPower
+	}
+	if _, err := utils.GetValSetAddresses(header); err != nil {
+		return err
+	}
+	e.trust(header)
+	return nil
+}
+
+// Prepare is not supported by light clients
+func (e *Engine) Prepare(chain consensus.FullChainReader, header *types.Header) error {
+	return ErrLightClient
+}
+
+// Finalize is not supported by light clients
+func (e *Engine) Finalize(chain consensus.FullChainReader, header *types.Header, state *state.StateDB, txs []*types.Transaction,
+	uncles []*types.Header) error {
+	return ErrLightClient
+}
+
+// FinalizeAndAssemble is not supported by light clients
+func (e *Engine) FinalizeAndAssemble(chain consensus.FullChainReader, header *types.Header, state *state.StateDB, txs []*types.Transaction,
+	uncles []*types.Header, receipts []*types.Receipt) (*types.Block, error) {
+	return nil, ErrLightClient
+}
+
+// Seal is not supported by light clients
+func (e *Engine) Seal(chain consensus.ChainReader, block *types.Block, results chan<- *types.Block, stop <-chan struct{}) error {
+	return ErrLightClient
+}
+
+// SealHash returns the hash of a block prior to it being sealed.
+func (e *Engine) SealHash(header *types.Header) common.Hash {
+	return utils.SigHash(header)
+}
+
+// CalcDifficulty returns the difficulty of all Tendermint blocks
+func (e *Engine) CalcDifficulty(chain consensus.ChainReader, time uint64, parent *types.Header) *big.Int {
+	return defaultDifficulty
+}
+
+// APIs returns no RPC API
+func (e *Engine) APIs(chain consensus.ChainReader) []rpc.API {
+	return nil
+}
+
+// Close does nothing as the light engine has no background threads
+func (e *Engine) Close() error {
+	return nil
+}
+
+func (e *Engine) verifyHeader(chain consensus.ChainReader, header *types.Header, parents []*types.Header) error {
+	if header.Number == nil {
+		return tendermint.ErrUnknownBlock
+	}
+	if err := e.verifyFields(header); err != nil {
+		return err
+	}
+	number := header.Number.Uint64()
+	if number == 0 {
+		return nil
+	}
+	var parent *types.Header
+	if len(parents) > 0 {
+		parent = parents[len(parents)-1]
+	} else {
+		parent = chain.GetHeader(header.ParentHash, number-1)
+	}
+	if parent == nil || parent.Number.Uint64() != number-1 || parent.Hash() != header.ParentHash {
+		return consensus.ErrUnknownAncestor
+	}
+
+	valSet, err := e.getValSet(chain, header, parents)
+	if err == ErrNoTrustedCheckpoint && e.isCheckpoint(number) {
+		// the previous checkpoint is unknown, i.e the chain starts from a CHT checkpoint
+		if trusted := e.TrustedCheckpoint(); trusted != nil && trusted.Number.Uint64() < number {
+			return e.VerifySkipping(trusted, header)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if err := e.verifySeals(header, valSet); err != nil {
+		return err
+	}
+	if e.isCheckpoint(number) {
+		e.trust(header)
+	}
+	return nil
+}
+
+// verifyFields checks the standalone fields of the header
+func (e *Engine) verifyFields(header *types.Header) error {
+	if _, err := types.ExtractTendermintExtra(header); err != nil {
+		return tendermint.ErrInvalidExtraDataFormat
+	}
+	if header.MixDigest != types.TendermintDigest {
+		return tendermint.ErrInvalidMixDigest
+	}
+	if header.Difficulty == nil || header.Difficulty.Cmp(defaultDifficulty) != 0 {
+		return tendermint.ErrInvalidDifficulty
+	}
+	return nil
+}
+
+// verifySeals checks that the header is proposed by a validator and committed by +2/3 voting power of valSet
+func (e *Engine) verifySeals(header *types.Header, valSet tendermint.ValidatorSet) error {
+	proposer, err := utils.GetProposer(header)
+	if err != nil {
+		return err
+	}
+	if proposer != header.Coinbase {
+		return tendermint.ErrCoinBaseInvalid
+	}
+	if _, val := valSet.GetByAddress(proposer); val == nil {
+		return tendermint.ErrUnauthorized
+	}
+	return utils.VerifyCommittedSeals(header, valSet)
+}
+
+// getValSet returns the val-set of the header's epoch, which is stored in its checkpoint header.
+// The checkpoint is looked up in parents first, then in the chain.
+func (e *Engine) getValSet(chain consensus.ChainReader, header *types.Header, parents []*types.Header) (tendermint.ValidatorSet, error) {
+	number := header.Number.Uint64()
+	if len(e.config.FixedValidators) > 0 {
+		return validator.NewSet(e.config.FixedValidators, tendermint.RoundRobin, int64(number)), nil
+	}
+	checkpointNumber := utils.GetCheckpointNumber(e.config.Epoch, number)
+
+	var checkpoint *types.Header
+	for i := len(parents) - 1; i >= 0; i-- {
+		if parents[i].Number.Uint64() == checkpointNumber {
+			checkpoint = parents[i]
+			break
+		}
+	}
+	if checkpoint == nil {
+		checkpoint = chain.GetHeaderByNumber(checkpointNumber)
+	}
+	if checkpoint == nil {
+		return nil, ErrNoTrustedCheckpoint
+	}
+	return e.valSetOf(checkpoint, int64(number))
+}
+
+// valSetOf returns the val-set stored in the checkpoint header for the given block number
+func (e *Engine) valSetOf(checkpoint *types.Header, number int64) (tendermint.ValidatorSet, error) {
+	// the members and voting powers of a val-set do not depend on the block number, only its proposer does
+	hash := checkpoint.Hash()
+	if cached, ok := e.valSets.Get(hash); ok {
+		if valSet, ok := cached.(tendermint.ValidatorSet); ok {
+			return valSet.Copy(), nil
+		}
+	}
+	valSet, err := utils.GetValSet(checkpoint, e.config.ProposerPolicy, number)
+	if err != nil {
+		return nil, err
+	}
+	e.valSets.Add(hash, valSet.Copy())
+	return valSet, nil
+}
+
+func (e *Engine) isCheckpoint(number uint64) bool {
+	return e.config.Epoch != 0 && number%e.config.Epoch == 0
+}
+
+func (e *Engine) trust(header *types.Header) {
+	e.trustedMu.Lock()
+	defer e.trustedMu.Unlock()
+	if e.trusted == nil || e.trusted.Number.Cmp(header.Number) < 0 {
+		e.trusted = header
+	}
+}
@@ -0,0 +1,141 @@
+package light
+
+import (
+	"crypto/ecdsa"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/tests_utils"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/utils"
+	"github.com/Evrynetlabs/evrynet-node/core/types"
+	"github.com/Evrynetlabs/evrynet-node/crypto"
+)
+
+const testEpoch = 3
+
+func makeKeys(n int) ([]*ecdsa.PrivateKey, []common.Address) {
+	var (
+		keys  = make([]*ecdsa.PrivateKey, n)
+		addrs = make([]common.Address, n)
+	)
+	for i := range keys {
+		keys[i] = tests_utils.MakeNodeKey()
+		addrs[i] = crypto.PubkeyToAddress(keys[i].PublicKey)
+	}
+	return keys, addrs
+}
+
+// makeHeader returns a child of parent proposed by proposer and committed by signers.
+// nextValidators are written to the header if it is a checkpoint.
+func makeHeader(t *testing.T, parent *types.Header, proposer *ecdsa.PrivateKey, signers []*ecdsa.PrivateKey, nextValidators []common.Address) *types.Header {
+	header := tests_utils.MakeBlockWithoutSeal(parent).Header()
+	header.Coinbase = crypto.PubkeyToAddress(proposer.PublicKey)
+	if header.Number.Uint64()%testEpoch == 0 {
+		require.NoError(t, utils.WriteValSet(header, nextValidators))
+	}
+	tests_utils.AppendSealByPkKey(header, proposer)
+	tests_utils.AppendCommitedSealByPkKeys(header, signers)
+	return header
+}
+
+func newTestEngine() *Engine {
+	return New(&tendermint.Config{Epoch: testEpoch, ProposerPolicy: tendermint.RoundRobin})
+}
+
+func TestVerifyHeaders(t *testing.T) {
+	var (
+		keys, addrs = makeKeys(4)
+		genesis     = tests_utils.MakeGenesisHeader(addrs)
+		headers     []*types.Header
+		parent      = genesis
+	)
+	for i := 0; i < 2*testEpoch; i++ {
+		header := makeHeader(t, parent, keys[i%4], keys[:3], addrs)
+		headers = append(headers, header)
+		parent = header
+	}
+	engine := newTestEngine()
+	chain := tests_utils.NewHeadersMockChainReader([]*types.Header{genesis})
+	_, results := engine.VerifyHeaders(chain, headers, nil)
+	for i := range headers {
+		assert.NoError(t, <-results, "header %d", i+1)
+	}
+	assert.Equal(t, headers[len(headers)-1].Hash(), engine.TrustedCheckpoint().Hash())
+
+	proposer, err := engine.Author(headers[0])
+	require.NoError(t, err)
+	assert.Equal(t, addrs[0], proposer)
+
+	// less than 2/3 of the validators committed the header
+	header := makeHeader(t, genesis, keys[0], keys[:2], addrs)
+	assert.Equal(t, tendermint.ErrInvalidCommittedSeals, engine.VerifyHeader(chain, header, true))
+
+	// the proposer is not a validator
+	outsiders, _ := makeKeys(1)
+	header = makeHeader(t, genesis, outsiders[0], keys[:3], addrs)
+	assert.Equal(t, tendermint.ErrUnauthorized, engine.VerifyHeader(chain, header, true))
+
+	// the signer is not a validator
+	header = makeHeader(t, genesis, keys[0], append(keys[:2:2], outsiders[0]), addrs)
+	assert.Equal(t, tendermint.ErrInvalidCommittedSeals, engine.VerifyHeader(chain, header, true))
+}
+
+func TestVerifySkipping(t *testing.T) {
+	var (
+		keys, addrs = makeKeys(7)
+		genesis     = tests_utils.MakeGenesisHeader(addrs[:4])
+		// the 4th validator is replaced at the first checkpoint, then all the trusted validators but one are replaced
+		validatorsA = keys[:4]
+		validatorsB = append(keys[:3:3], keys[4])
+		addrsB      = append(addrs[:3:3], addrs[4])
+		addrsC      = []common.Address{addrs[0], addrs[4], addrs[5], addrs[6]}
+		validatorsC = []*ecdsa.PrivateKey{keys[0], keys[4], keys[5], keys[6]}
+		parent      = genesis
+		headers     []*types.Header
+	)
+	for i := 1; i <= 3*testEpoch; i++ {
+		var (
+			signers = validatorsA
+			next    = addrsB
+		)
+		switch {
+		case i > 2*testEpoch:
+			signers, next = validatorsC, addrsC
+		case i > testEpoch:
+			signers, next = validatorsB, addrsC
+		}
+		header := makeHeader(t, parent, signers[0], signers, next)
+		headers = append(headers, header)
+		parent = header
+	}
+	var (
+		engine      = newTestEngine()
+		checkpoint1 = headers[testEpoch-1]
+		checkpoint2 = headers[2*testEpoch-1]
+		checkpoint3 = headers[3*testEpoch-1]
+	)
+	// checkpoint 2 is signed by validators B, 3 of them are trusted validators of genesis
+	require.NoError(t, engine.VerifySkipping(genesis, checkpoint2))
+	assert.Equal(t, checkpoint2.Hash(), engine.TrustedCheckpoint().Hash())
+
+	// checkpoint 3 is signed by validators C, only 1 of them is a trusted validator of genesis
+	assert.Equal(t, ErrInsufficientTrustedPower, engine.VerifySkipping(genesis, checkpoint3))
+	assert.NoError(t, engine.VerifySkipping(checkpoint1, checkpoint3))
+
+	assert.Equal(t, ErrNotCheckpoint, engine.VerifySkipping(genesis, headers[0]))
+	assert.Equal(t, ErrNotCheckpoint, engine.VerifySkipping(checkpoint2, checkpoint1))
+
+	// a chain starting from a trusted checkpoint verifies the next checkpoint by skipping
+	engine = newTestEngine()
+	require.NoError(t, engine.SetTrustedCheckpoint(checkpoint1))
+	// the light chain only has the headers after the CHT, the parent of checkpoint 2 is its last header
+	lightHeaders := make([]*types.Header, 2*testEpoch)
+	lightHeaders[2*testEpoch-1] = headers[2*testEpoch-2]
+	chain := tests_utils.NewHeadersMockChainReader(lightHeaders)
+	assert.NoError(t, engine.VerifyHeader(chain, checkpoint2, true))
+	assert.Equal(t, checkpoint2.Hash(), engine.TrustedCheckpoint().Hash())
+}
@@ -0,0 +1,21 @@
+package tendermint
+
+import (
+	"github.com/Evrynetlabs/evrynet-node/common"
+)
+
+// Signer holds the keys of a validator and signs its consensus messages and seals,
+// so that the keys do not have to be held by the process running the node
+type Signer interface {
+	// Address returns the address of the validator
+	Address() common.Address
+
+	// Sign signs the keccak256 hash of the data with the validator's private key
+	Sign(data []byte) ([]byte, error)
+
+	// SignBLS signs the data with the validator's BLS key, the signature can be aggregated with the other validators' ones
+	SignBLS(data []byte) ([]byte, error)
+
+	// BLSKeyRegistration returns the BLS public key of the validator and the proof of possession of its BLS key
+	BLSKeyRegistration() (publicKey []byte, proof []byte, err error)
+}
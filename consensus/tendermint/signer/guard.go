@@ -0,0 +1,232 @@
+package signer
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"sync"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/common/hexutil"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/core"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/utils"
+	"github.com/Evrynetlabs/evrynet-node/crypto"
+)
+
+var (
+	// ErrDoubleSign is returned if the signer is asked to sign another proposal or vote at the step it already signed,
+	// or another seal before signing the proposal or vote carrying the previous one
+	ErrDoubleSign = errors.New("refused to sign a conflicting proposal, vote or seal")
+	// ErrStepRegression is returned if the signer is asked to sign a proposal or vote before the step it last signed
+	ErrStepRegression = errors.New("refused to sign a proposal or vote before the last signed step")
+	// ErrUnknownPayload is returned if the signer is asked to sign data which is neither a consensus message nor a seal
+	ErrUnknownPayload = errors.New("refused to sign an unknown payload")
+)
+
+// sealKind is the kind of a seal signed by the validator
+type sealKind uint8
+
+const (
+	headerSeal       sealKind = iota // headerSeal is the proposer seal of a block header
+	committedSeal                    // committedSeal is the ECDSA committed seal of a precommit
+	committedBLSSeal                 // committedBLSSeal is the BLS committed seal of a precommit
+)
+
+// slot returns the kind of the seals which conflict with a seal of kind k
+func (k sealKind) slot() sealKind {
+	if k == committedBLSSeal {
+		return committedSeal
+	}
+	return k
+}
+
+// sealState is a seal signed after the last proposal or vote
+type sealState struct {
+	Kind        sealKind      `json:"kind"`
+	PayloadHash common.Hash   `json:"payloadHash"`
+	Signature   hexutil.Bytes `json:"signature"`
+}
+
+// signState is the last proposal or vote signed, with the seals signed since then
+type signState struct {
+	BlockNumber *big.Int           `json:"blockNumber"`
+	Round       int64              `json:"round"`
+	Step        core.RoundStepType `json:"step"`
+	PayloadHash common.Hash        `json:"payloadHash"`
+	Signature   hexutil.Bytes      `json:"signature"`
+	Seals       []*sealState       `json:"seals,omitempty"`
+}
+
+// compare returns -1, 0 or 1 whether the block number, round and step are before, at or after the state
+func (s *signState) compare(blockNumber *big.Int, round int64, step core.RoundStepType) int {
+	if c := blockNumber.Cmp(s.BlockNumber); c != 0 {
+		return c
+	}
+	switch {
+	case round < s.Round:
+		return -1
+	case round > s.Round:
+		return 1
+	case step < s.Step:
+		return -1
+	case step > s.Step:
+		return 1
+	}
+	return 0
+}
+
+// GuardedSigner wraps a signer so that it never signs two different proposals or votes at the same block number,
+// round and step, nor a proposal or vote before the last one it signed.
+// The seals do not have a block number nor a round, but a seal is always signed right before the proposal or vote
+// carrying it: after a proposal or vote, the signer signs at most one header seal and one committed seal, ECDSA or BLS.
+// The last proposal or vote signed and the seals are persisted before their signature is returned,
+// so the guard survives restarts. The catch up and evidence messages are signed without check, any other data is refused.
+type GuardedSigner struct {
+	signer tendermint.Signer
+	path   string // path is the file persisting the last proposal or vote signed, it is not persisted if empty
+
+	mu   sync.Mutex
+	last *signState
+}
+
+// NewGuardedSigner returns a guarded signer which persists its state into the file at path
+func NewGuardedSigner(signer tendermint.Signer, path string) (*GuardedSigner, error) {
+	s := &GuardedSigner{
+		signer: signer,
+		path:   path,
+	}
+	if path == "" {
+		return s, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		return s, nil
+	case err != nil:
+		return nil, err
+	}
+	s.last = new(signState)
+	if err := json.Unmarshal(data, s.last); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Address implements tendermint.Signer.Address
+func (s *GuardedSigner) Address() common.Address {
+	return s.signer.Address()
+}
+
+// Sign implements tendermint.Signer.Sign
+func (s *GuardedSigner) Sign(data []byte) ([]byte, error) {
+	switch {
+	case utils.IsCommittedSeal(data):
+		return s.signSeal(committedSeal, data, s.signer.Sign)
+	case len(data) == common.HashLength:
+		return s.signSeal(headerSeal, data, s.signer.Sign)
+	case core.IsInfoPayload(data):
+		return s.signer.Sign(data)
+	}
+	blockNumber, round, step, ok := core.PayloadStep(data)
+	if !ok {
+		return nil, ErrUnknownPayload
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	hash := crypto.Keccak256Hash(data)
+	if s.last != nil && s.last.BlockNumber != nil {
+		switch s.last.compare(blockNumber, round, step) {
+		case -1:
+			return nil, ErrStepRegression
+		case 0:
+			// the same proposal or vote can be signed again, i.e. after the node restarts
+			if s.last.PayloadHash != hash {
+				return nil, ErrDoubleSign
+			}
+			return common.CopyBytes(s.last.Signature), nil
+		}
+	}
+	signature, err := s.signer.Sign(data)
+	if err != nil {
+		return nil, err
+	}
+	state := &signState{
+		BlockNumber: new(big.Int).Set(blockNumber),
+		Round:       round,
+		Step:        step,
+		PayloadHash: hash,
+		Signature:   signature,
+	}
+	if err := s.save(state); err != nil {
+		return nil, err
+	}
+	s.last = state
+	return signature, nil
+}
+
+// SignBLS implements tendermint.Signer.SignBLS, only the committed seals are signed with the BLS key
+func (s *GuardedSigner) SignBLS(data []byte) ([]byte, error) {
+	if !utils.IsCommittedSeal(data) {
+		return nil, ErrUnknownPayload
+	}
+	return s.signSeal(committedBLSSeal, data, s.signer.SignBLS)
+}
+
+// signSeal signs a seal of the given kind unless another seal of the same slot was signed after the last proposal or vote.
+// The same seal can be signed again, i.e. after the node restarts.
+func (s *GuardedSigner) signSeal(kind sealKind, data []byte, sign func([]byte) ([]byte, error)) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	hash := crypto.Keccak256Hash(data)
+	state := new(signState)
+	if s.last != nil {
+		*state = *s.last
+	}
+	for _, seal := range state.Seals {
+		if seal.Kind.slot() != kind.slot() {
+			continue
+		}
+		if seal.Kind != kind || seal.PayloadHash != hash {
+			return nil, ErrDoubleSign
+		}
+		return common.CopyBytes(seal.Signature), nil
+	}
+	signature, err := sign(data)
+	if err != nil {
+		return nil, err
+	}
+	state.Seals = append(append([]*sealState{}, state.Seals...), &sealState{
+		Kind:        kind,
+		PayloadHash: hash,
+		Signature:   signature,
+	})
+	if err := s.save(state); err != nil {
+		return nil, err
+	}
+	s.last = state
+	return signature, nil
+}
+
+// BLSKeyRegistration implements tendermint.Signer.BLSKeyRegistration
+func (s *GuardedSigner) BLSKeyRegistration() ([]byte, []byte, error) {
+	return s.signer.BLSKeyRegistration()
+}
+
+// save persists the state, the file is replaced atomically so that a crash never leaves a partial state
+func (s *GuardedSigner) save(state *signState) error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
@@ -0,0 +1,80 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"os"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/crypto"
+	"github.com/Evrynetlabs/evrynet-node/crypto/bls"
+	"github.com/Evrynetlabs/evrynet-node/log"
+)
+
+// ErrNoBLSKey is returned if a BLS signature is requested from a signer without BLS key
+var ErrNoBLSKey = errors.New("signer has no BLS key")
+
+// LocalSigner signs with the validator's private key held in memory
+type LocalSigner struct {
+	privateKey *ecdsa.PrivateKey
+	blsKey     *bls.SecretKey // blsKey signs the aggregatable committed seals, it is stored separately from privateKey
+	address    common.Address
+}
+
+// NewLocalSigner returns a signer holding the private key and the BLS key of the validator.
+// The BLS key may be nil if the blocks are not committed with BLS seals.
+func NewLocalSigner(privateKey *ecdsa.PrivateKey, blsKey *bls.SecretKey) *LocalSigner {
+	return &LocalSigner{
+		privateKey: privateKey,
+		blsKey:     blsKey,
+		address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+	}
+}
+
+// LoadBLSKey loads the BLS key of the validator from file, a new key is generated and saved to file if it does not exist.
+// The key is rotated by replacing the file, the validator registers the new key in the next block it proposes
+// and the new key is used from the next epoch.
+// If file is empty, an ephemeral key is generated.
+func LoadBLSKey(file string) (*bls.SecretKey, error) {
+	if file == "" {
+		return bls.GenerateKey(nil)
+	}
+	key, err := bls.LoadSecretKey(file)
+	if err == nil {
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+	if key, err = bls.GenerateKey(nil); err != nil {
+		return nil, err
+	}
+	log.Info("Generated new BLS key", "file", file)
+	return key, bls.SaveSecretKey(file, key)
+}
+
+// Address implements tendermint.Signer.Address
+func (s *LocalSigner) Address() common.Address {
+	return s.address
+}
+
+// Sign implements tendermint.Signer.Sign
+func (s *LocalSigner) Sign(data []byte) ([]byte, error) {
+	return crypto.Sign(crypto.Keccak256(data), s.privateKey)
+}
+
+// SignBLS implements tendermint.Signer.SignBLS
+func (s *LocalSigner) SignBLS(data []byte) ([]byte, error) {
+	if s.blsKey == nil {
+		return nil, ErrNoBLSKey
+	}
+	return s.blsKey.Sign(data).Marshal(), nil
+}
+
+// BLSKeyRegistration implements tendermint.Signer.BLSKeyRegistration
+func (s *LocalSigner) BLSKeyRegistration() ([]byte, []byte, error) {
+	if s.blsKey == nil {
+		return nil, nil, ErrNoBLSKey
+	}
+	return s.blsKey.PublicKey().Marshal(), s.blsKey.ProvePossession().Marshal(), nil
+}
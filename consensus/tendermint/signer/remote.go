@@ -0,0 +1,83 @@
+package signer
+
+import (
+	"context"
+	"time"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/common/hexutil"
+	"github.com/Evrynetlabs/evrynet-node/rpc"
+)
+
+// remoteSignTimeout is the time allowed to the signer process to answer a request,
+// it is lower than the shortest consensus timeout so that a stuck signer does not stall the round
+const remoteSignTimeout = 500 * time.Millisecond
+
+// RemoteSigner signs with the keys held by another process serving the signer API, i.e. the tmsigner command.
+// The signer process is expected to guard against double signing.
+type RemoteSigner struct {
+	client  *rpc.Client
+	address common.Address
+}
+
+// NewRemoteSigner connects to the signer process at the endpoint, which is an IPC path or an HTTP or WebSocket URL
+func NewRemoteSigner(endpoint string) (*RemoteSigner, error) {
+	client, err := rpc.Dial(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return NewRemoteSignerWithClient(client)
+}
+
+// NewRemoteSignerWithClient returns a signer using the RPC client connected to the signer process
+func NewRemoteSignerWithClient(client *rpc.Client) (*RemoteSigner, error) {
+	s := &RemoteSigner{client: client}
+	if err := s.call(&s.address, "address"); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Address implements tendermint.Signer.Address
+func (s *RemoteSigner) Address() common.Address {
+	return s.address
+}
+
+// Sign implements tendermint.Signer.Sign
+func (s *RemoteSigner) Sign(data []byte) ([]byte, error) {
+	var signature hexutil.Bytes
+	if err := s.call(&signature, "sign", hexutil.Bytes(data)); err != nil {
+		return nil, err
+	}
+	return signature, nil
+}
+
+// SignBLS implements tendermint.Signer.SignBLS
+func (s *RemoteSigner) SignBLS(data []byte) ([]byte, error) {
+	var signature hexutil.Bytes
+	if err := s.call(&signature, "signBLS", hexutil.Bytes(data)); err != nil {
+		return nil, err
+	}
+	return signature, nil
+}
+
+// BLSKeyRegistration implements tendermint.Signer.BLSKeyRegistration
+func (s *RemoteSigner) BLSKeyRegistration() ([]byte, []byte, error) {
+	var registration BLSKeyRegistration
+	if err := s.call(&registration, "getBLSKeyRegistration"); err != nil {
+		return nil, nil, err
+	}
+	return registration.PublicKey, registration.Proof, nil
+}
+
+// Close closes the connection to the signer process
+func (s *RemoteSigner) Close() {
+	s.client.Close()
+}
+
+func (s *RemoteSigner) call(result interface{}, method string, args ...interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteSignTimeout)
+	defer cancel()
+	return s.client.CallContext(ctx, result, Namespace+"_"+method, args...)
+}
@@ -0,0 +1,64 @@
+package signer
+
+import (
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/common/hexutil"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint"
+	"github.com/Evrynetlabs/evrynet-node/rpc"
+)
+
+// Namespace is the RPC namespace of the signer API
+const Namespace = "validator"
+
+// BLSKeyRegistration is the BLS public key of a validator with the proof of possession of its BLS key
+type BLSKeyRegistration struct {
+	PublicKey hexutil.Bytes `json:"publicKey"`
+	Proof     hexutil.Bytes `json:"proof"`
+}
+
+// API exposes a signer over RPC to the nodes using a RemoteSigner
+type API struct {
+	signer tendermint.Signer
+}
+
+// NewAPI returns the RPC API of the signer, the signer should be guarded if it is exposed to several nodes
+func NewAPI(signer tendermint.Signer) *API {
+	return &API{signer: signer}
+}
+
+// APIs returns the RPC descriptors of the signer API
+func APIs(signer tendermint.Signer) []rpc.API {
+	return []rpc.API{{
+		Namespace: Namespace,
+		Version:   "1.0",
+		Service:   NewAPI(signer),
+		Public:    true,
+	}}
+}
+
+// Address returns the address of the validator
+func (api *API) Address() common.Address {
+	return api.signer.Address()
+}
+
+// Sign signs the keccak256 hash of the data with the validator's private key
+func (api *API) Sign(data hexutil.Bytes) (hexutil.Bytes, error) {
+	return api.signer.Sign(data)
+}
+
+// SignBLS signs the data with the validator's BLS key
+func (api *API) SignBLS(data hexutil.Bytes) (hexutil.Bytes, error) {
+	return api.signer.SignBLS(data)
+}
+
+// GetBLSKeyRegistration returns the BLS public key of the validator and the proof of possession of its BLS key
+func (api *API) GetBLSKeyRegistration() (*BLSKeyRegistration, error) {
+	publicKey, proof, err := api.signer.BLSKeyRegistration()
+	if err != nil {
+		return nil, err
+	}
+	return &BLSKeyRegistration{
+		PublicKey: publicKey,
+		Proof:     proof,
+	}, nil
+}
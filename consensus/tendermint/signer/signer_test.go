@@ -0,0 +1,179 @@
+package signer
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/core"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/utils"
+	"github.com/Evrynetlabs/evrynet-node/crypto"
+	"github.com/Evrynetlabs/evrynet-node/crypto/bls"
+	"github.com/Evrynetlabs/evrynet-node/rlp"
+	"github.com/Evrynetlabs/evrynet-node/rpc"
+)
+
+const (
+	msgPrevote   uint64 = 1
+	msgPrecommit uint64 = 2
+)
+
+// votePayload returns the payload without signature of a vote, as core signs it
+func votePayload(t *testing.T, code uint64, blockNumber int64, round int64, blockHash common.Hash) []byte {
+	vote, err := rlp.EncodeToBytes(&core.Vote{
+		BlockHash:   &blockHash,
+		BlockNumber: big.NewInt(blockNumber),
+		Round:       round,
+	})
+	require.NoError(t, err)
+	payload, err := rlp.EncodeToBytes([]interface{}{code, vote, common.Address{}, []byte{}})
+	require.NoError(t, err)
+	return payload
+}
+
+func TestGuardedSigner(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tmsigner")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	var (
+		path      = filepath.Join(dir, "state.json")
+		key, _    = crypto.GenerateKey()
+		local     = NewLocalSigner(key, nil)
+		blockA    = common.HexToHash("0x0a")
+		blockB    = common.HexToHash("0x0b")
+		prevoteA  = votePayload(t, msgPrevote, 10, 1, blockA)
+		prevoteB  = votePayload(t, msgPrevote, 10, 1, blockB)
+		precommit = votePayload(t, msgPrecommit, 10, 1, blockA)
+	)
+	guarded, err := NewGuardedSigner(local, path)
+	require.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), guarded.Address())
+
+	signature, err := guarded.Sign(prevoteA)
+	require.NoError(t, err)
+	pub, err := crypto.SigToPub(crypto.Keccak256(prevoteA), signature)
+	require.NoError(t, err)
+	assert.Equal(t, guarded.Address(), crypto.PubkeyToAddress(*pub))
+
+	// the same vote is signed again with the same signature, a conflicting one is refused
+	again, err := guarded.Sign(prevoteA)
+	require.NoError(t, err)
+	assert.Equal(t, signature, again)
+	_, err = guarded.Sign(prevoteB)
+	assert.Equal(t, ErrDoubleSign, err)
+
+	_, err = guarded.Sign(precommit)
+	require.NoError(t, err)
+	_, err = guarded.Sign(votePayload(t, msgPrevote, 10, 2, blockB))
+	require.NoError(t, err)
+	_, err = guarded.Sign(votePayload(t, msgPrecommit, 10, 1, blockB))
+	assert.Equal(t, ErrStepRegression, err)
+	_, err = guarded.Sign(votePayload(t, msgPrecommit, 9, 5, blockB))
+	assert.Equal(t, ErrStepRegression, err)
+
+	// a single committed seal and header seal are signed before the next proposal or vote
+	sealA, err := guarded.Sign(utils.PrepareCommittedSeal(blockA))
+	require.NoError(t, err)
+	again, err = guarded.Sign(utils.PrepareCommittedSeal(blockA))
+	require.NoError(t, err)
+	assert.Equal(t, sealA, again)
+	_, err = guarded.Sign(utils.PrepareCommittedSeal(blockB))
+	assert.Equal(t, ErrDoubleSign, err)
+	_, err = guarded.Sign(blockB.Bytes())
+	require.NoError(t, err)
+	_, err = guarded.Sign(blockA.Bytes())
+	assert.Equal(t, ErrDoubleSign, err)
+
+	// the other data are refused
+	_, err = guarded.Sign([]byte("seal"))
+	assert.Equal(t, ErrUnknownPayload, err)
+	_, err = guarded.SignBLS(blockA.Bytes())
+	assert.Equal(t, ErrUnknownPayload, err)
+
+	// the guard survives restarts
+	restarted, err := NewGuardedSigner(local, path)
+	require.NoError(t, err)
+	_, err = restarted.Sign(precommit)
+	assert.Equal(t, ErrStepRegression, err)
+	_, err = restarted.Sign(votePayload(t, msgPrevote, 10, 2, blockA))
+	assert.Equal(t, ErrDoubleSign, err)
+	_, err = restarted.Sign(utils.PrepareCommittedSeal(blockB))
+	assert.Equal(t, ErrDoubleSign, err)
+	_, err = restarted.Sign(votePayload(t, msgPrecommit, 10, 2, blockA))
+	require.NoError(t, err)
+
+	// another seal is signed once the precommit carrying the previous one is signed
+	_, err = restarted.Sign(utils.PrepareCommittedSeal(blockB))
+	require.NoError(t, err)
+}
+
+func TestRemoteSigner(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	blsKey, _ := bls.GenerateKey(nil)
+	local := NewLocalSigner(key, blsKey)
+	guarded, err := NewGuardedSigner(local, "")
+	require.NoError(t, err)
+	server := rpc.NewServer()
+	defer server.Stop()
+	for _, api := range APIs(guarded) {
+		require.NoError(t, server.RegisterName(api.Namespace, api.Service))
+	}
+	remote, err := NewRemoteSignerWithClient(rpc.DialInProc(server))
+	require.NoError(t, err)
+	defer remote.Close()
+	assert.Equal(t, local.Address(), remote.Address())
+
+	payload := votePayload(t, msgPrevote, 1, 0, common.HexToHash("0x01"))
+	signature, err := remote.Sign(payload)
+	require.NoError(t, err)
+	expected, err := local.Sign(payload)
+	require.NoError(t, err)
+	assert.Equal(t, expected, signature)
+	_, err = remote.Sign(votePayload(t, msgPrevote, 1, 0, common.HexToHash("0x02")))
+	assert.EqualError(t, err, ErrDoubleSign.Error())
+
+	seal := utils.PrepareCommittedSeal(common.HexToHash("0x01"))
+	blsSignature, err := remote.SignBLS(seal)
+	require.NoError(t, err)
+	expected, err = local.SignBLS(seal)
+	require.NoError(t, err)
+	assert.Equal(t, expected, blsSignature)
+	_, err = remote.SignBLS(utils.PrepareCommittedSeal(common.HexToHash("0x02")))
+	assert.EqualError(t, err, ErrDoubleSign.Error())
+
+	publicKey, proof, err := remote.BLSKeyRegistration()
+	require.NoError(t, err)
+	expectedKey, expectedProof, err := local.BLSKeyRegistration()
+	require.NoError(t, err)
+	assert.Equal(t, expectedKey, publicKey)
+	assert.Equal(t, expectedProof, proof)
+}
+
+func TestLoadBLSKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tmsigner")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "blskey")
+
+	key, err := LoadBLSKey(path)
+	require.NoError(t, err)
+	loaded, err := LoadBLSKey(path)
+	require.NoError(t, err)
+	assert.Equal(t, key.PublicKey().Marshal(), loaded.PublicKey().Marshal())
+
+	// the key is rotated by replacing the file
+	require.NoError(t, os.Remove(path))
+	rotated, err := LoadBLSKey(path)
+	require.NoError(t, err)
+	assert.NotEqual(t, key.PublicKey().Marshal(), rotated.PublicKey().Marshal())
+
+	nodeKey, _ := crypto.GenerateKey()
+	_, err = NewLocalSigner(nodeKey, nil).SignBLS([]byte("seal"))
+	assert.Equal(t, ErrNoBLSKey, err)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/Evrynetlabs/evrynet-node/core"
 	"github.com/Evrynetlabs/evrynet-node/core/types"
 	"github.com/Evrynetlabs/evrynet-node/crypto"
+	"github.com/Evrynetlabs/evrynet-node/crypto/bls"
 	"github.com/Evrynetlabs/evrynet-node/event"
 	"github.com/Evrynetlabs/evrynet-node/log"
 	"github.com/Evrynetlabs/evrynet-node/params"
@@ -22,6 +23,7 @@ type MockBackend struct {
 	config             *tendermint.Config
 	tendermintEventMux *event.TypeMux
 	privateKey         *ecdsa.PrivateKey
+	blsKey             *bls.SecretKey
 	address            common.Address
 	validators         []common.Address
 
@@ -34,6 +36,8 @@ type MockBackend struct {
 	currentBlock func() *types.Block
 	// SendEventMux is used for receiving output msg from core
 	SendEventMux *event.TypeMux
+	// Evidences stores the evidences reported by core
+	Evidences map[common.Hash]*types.TendermintEvidence
 }
 
 //SentMsgEvent represents an action send to an peer
@@ -47,12 +51,14 @@ func NewMockBackend(privateKey *ecdsa.PrivateKey, blockchain *MockChainReader, v
 		config:             tendermint.DefaultConfig,
 		tendermintEventMux: new(event.TypeMux),
 		privateKey:         privateKey,
+		blsKey:             bls.DeriveSecretKey(crypto.FromECDSA(privateKey)),
 		address:            crypto.PubkeyToAddress(privateKey.PublicKey),
 		mutex:              &sync.RWMutex{},
 		chain:              blockchain,
 		currentBlock:       blockchain.CurrentBlock,
 		validators:         validators,
 		SendEventMux:       new(event.TypeMux),
+		Evidences:          make(map[common.Hash]*types.TendermintEvidence),
 	}
 }
 
@@ -85,6 +91,11 @@ func (mb *MockBackend) Sign(data []byte) ([]byte, error) {
 	return crypto.Sign(hashData, mb.privateKey)
 }
 
+// SignBLS implements tendermint.Backend.SignBLS
+func (mb *MockBackend) SignBLS(data []byte) ([]byte, error) {
+	return mb.blsKey.Sign(data).Marshal(), nil
+}
+
 // Address implements tendermint.Backend.Address
 func (mb *MockBackend) Address() common.Address {
 	return mb.address
@@ -163,6 +174,18 @@ func (mb *MockBackend) Cancel(block *types.Block) {
 	log.Error("not implemented")
 }
 
+// ReportEvidence implements tendermint.Backend.ReportEvidence
+func (mb *MockBackend) ReportEvidence(evidence *types.TendermintEvidence) bool {
+	mb.mutex.Lock()
+	defer mb.mutex.Unlock()
+	hash := evidence.Hash()
+	if _, ok := mb.Evidences[hash]; ok {
+		return false
+	}
+	mb.Evidences[hash] = evidence
+	return true
+}
+
 func MustCreateAndStartNewBackend(t *testing.T, nodePrivateKey *ecdsa.PrivateKey, genesisHeader *types.Header, validators []common.Address) (tendermint.Backend, *core.TxPool) {
 	var (
 		address = crypto.PubkeyToAddress(nodePrivateKey.PublicKey)
@@ -0,0 +1,165 @@
+package utils
+
+import (
+	"errors"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint"
+	"github.com/Evrynetlabs/evrynet-node/core/types"
+	"github.com/Evrynetlabs/evrynet-node/crypto/bls"
+)
+
+var (
+	// ErrMissingAggregatedSeal is returned if a block of a BLS-enabled epoch is committed without an aggregated seal
+	ErrMissingAggregatedSeal = errors.New("missing aggregated committed seal")
+	// ErrDuplicatedCommittedSeal is returned if a validator signed more than one committed seal of a block
+	ErrDuplicatedCommittedSeal = errors.New("duplicated committed seal")
+)
+
+// GetProposer returns the address of the proposer who signed the header
+func GetProposer(header *types.Header) (common.Address, error) {
+	extra, err := types.ExtractTendermintExtra(header)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return GetSignatureAddress(SigHash(header).Bytes(), extra.Seal)
+}
+
+// CommittedSealSigners returns the addresses of the validators who signed the ECDSA committed seals of the header.
+func CommittedSealSigners(header *types.Header) ([]common.Address, error) {
+	extra, err := types.ExtractTendermintExtra(header)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		commitHash = PrepareCommittedSeal(header.Hash())
+		signers    = make([]common.Address, 0, len(extra.CommittedSeal))
+		seen       = make(map[common.Address]bool, len(extra.CommittedSeal))
+	)
+	for _, seal := range extra.CommittedSeal {
+		addr, err := GetSignatureAddress(commitHash, seal)
+		if err != nil {
+			return nil, tendermint.ErrInvalidSignature
+		}
+		if seen[addr] {
+			return nil, ErrDuplicatedCommittedSeal
+		}
+		seen[addr] = true
+		signers = append(signers, addr)
+	}
+	return signers, nil
+}
+
+// VerifyCommittedSeals checks that the header is committed by validators of valSet with at least the min majority
+// voting power. The seals are aggregated BLS signatures if every validator of valSet has a BLS key, ECDSA signatures otherwise.
+func VerifyCommittedSeals(header *types.Header, valSet tendermint.ValidatorSet) error {
+	extra, err := types.ExtractTendermintExtra(header)
+	if err != nil {
+		return err
+	}
+	// the blocks of an epoch whose validators all registered BLS keys are committed with an aggregated seal
+	if UseBLSSeals(valSet) {
+		return verifyAggregatedSeal(header, extra, valSet)
+	}
+	if len(extra.AggregatedSeal) != 0 || len(extra.SignersBitmap) != 0 {
+		return tendermint.ErrInvalidCommittedSeals
+	}
+	// The length of Committed seals should be larger than 0
+	if len(extra.CommittedSeal) == 0 {
+		return tendermint.ErrEmptyCommittedSeals
+	}
+
+	signers, err := CommittedSealSigners(header)
+	if err == ErrDuplicatedCommittedSeal {
+		return tendermint.ErrInvalidCommittedSeals
+	}
+	if err != nil {
+		return err
+	}
+	// Check whether the committed seals are generated by parent's validators
+	var validPower int64
+	for _, addr := range signers {
+		_, val := valSet.GetByAddress(addr)
+		if val == nil {
+			return tendermint.ErrInvalidCommittedSeals
+		}
+		validPower += val.VotingPower()
+	}
+
+	// The voting power of valid seals should be larger or equal than min majority power,
+	// which is the number of validators minus maximum faulty if all validators have the same voting power
+	if validPower < valSet.MinMajorityPower() {
+		return tendermint.ErrInvalidCommittedSeals
+	}
+	return nil
+}
+
+// verifyAggregatedSeal checks that the aggregated committed seal is signed by validators of valSet
+// with at least the min majority voting power
+func verifyAggregatedSeal(header *types.Header, extra *types.TendermintExtra, valSet tendermint.ValidatorSet) error {
+	if len(extra.AggregatedSeal) == 0 {
+		if len(extra.CommittedSeal) == 0 {
+			return tendermint.ErrEmptyCommittedSeals
+		}
+		return ErrMissingAggregatedSeal
+	}
+	if len(extra.CommittedSeal) != 0 {
+		return tendermint.ErrInvalidCommittedSeals
+	}
+	signers, err := SignersFromBitmap(extra.SignersBitmap, valSet.Size())
+	if err != nil {
+		return err
+	}
+	if len(signers) == 0 {
+		return tendermint.ErrEmptyCommittedSeals
+	}
+	var (
+		validPower int64
+		publicKeys = make([]*bls.PublicKey, 0, len(signers))
+	)
+	for _, index := range signers {
+		val := valSet.GetByIndex(int64(index))
+		publicKey, err := bls.UnmarshalPublicKey(val.BLSPublicKey())
+		if err != nil {
+			return err
+		}
+		publicKeys = append(publicKeys, publicKey)
+		validPower += val.VotingPower()
+	}
+	if validPower < valSet.MinMajorityPower() {
+		return tendermint.ErrInvalidCommittedSeals
+	}
+	aggregatedKey, err := bls.AggregatePublicKeys(publicKeys)
+	if err != nil {
+		return err
+	}
+	seal, err := bls.UnmarshalSignature(extra.AggregatedSeal)
+	if err != nil {
+		return tendermint.ErrInvalidSignature
+	}
+	if !bls.Verify(aggregatedKey, PrepareCommittedSeal(header.Hash()), seal) {
+		return tendermint.ErrInvalidCommittedSeals
+	}
+	return nil
+}
+
+// CommittedSigners returns the addresses of the validators of valSet who committed the header,
+// from either the aggregated BLS seal or the ECDSA committed seals.
+func CommittedSigners(header *types.Header, valSet tendermint.ValidatorSet) ([]common.Address, error) {
+	extra, err := types.ExtractTendermintExtra(header)
+	if err != nil {
+		return nil, err
+	}
+	if len(extra.AggregatedSeal) == 0 {
+		return CommittedSealSigners(header)
+	}
+	indexes, err := SignersFromBitmap(extra.SignersBitmap, valSet.Size())
+	if err != nil {
+		return nil, err
+	}
+	signers := make([]common.Address, 0, len(indexes))
+	for _, index := range indexes {
+		signers = append(signers, valSet.GetByIndex(int64(index)).Address())
+	}
+	return signers, nil
+}
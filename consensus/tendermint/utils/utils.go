@@ -8,13 +8,19 @@ import (
 
 	"github.com/Evrynetlabs/evrynet-node/common"
 	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/validator"
 	"github.com/Evrynetlabs/evrynet-node/core/types"
 	"github.com/Evrynetlabs/evrynet-node/crypto"
+	"github.com/Evrynetlabs/evrynet-node/crypto/bls"
 	"github.com/Evrynetlabs/evrynet-node/rlp"
 )
 
 var (
 	ErrInvalidSealLength = errors.New("seal is expected to be multiplication of 65")
+	// ErrInvalidVotingPower is returned if a voting power in the header is 0 or the total voting power is too large
+	ErrInvalidVotingPower = errors.New("invalid voting power")
+	// ErrInvalidSignersBitmap is returned if the signers bitmap of an aggregated seal does not match the val-set
+	ErrInvalidSignersBitmap = errors.New("invalid signers bitmap")
 )
 
 const (
@@ -79,6 +85,83 @@ func WriteValSet(h *types.Header, validators []common.Address) error {
 	return nil
 }
 
+// WriteValSetPowers writes the voting powers of the val-set to the extra-data field of the given header.
+// The powers must be in the same order as the val-set's addresses.
+func WriteValSetPowers(h *types.Header, powers []uint64) error {
+	tendermintExtra, err := types.ExtractTendermintExtra(h)
+	if err != nil {
+		return err
+	}
+	tendermintExtra.ValidatorPowers = powers
+
+	payload, err := rlp.EncodeToBytes(&tendermintExtra)
+	if err != nil {
+		return err
+	}
+
+	h.Extra = append(h.Extra[:types.TendermintExtraVanity], payload...)
+	return nil
+}
+
+// WriteValSetBLSKeys writes the BLS public keys of the val-set to the extra-data field of the given header.
+// The keys must be in the same order as the val-set's addresses.
+func WriteValSetBLSKeys(h *types.Header, keys [][]byte) error {
+	tendermintExtra, err := types.ExtractTendermintExtra(h)
+	if err != nil {
+		return err
+	}
+	tendermintExtra.ValidatorBLSKeys = keys
+
+	payload, err := rlp.EncodeToBytes(&tendermintExtra)
+	if err != nil {
+		return err
+	}
+
+	h.Extra = append(h.Extra[:types.TendermintExtraVanity], payload...)
+	return nil
+}
+
+// WriteBLSKeyRegistration writes the BLS public key of the proposer and its proof of possession to the extra-data field
+// of the given header.
+func WriteBLSKeyRegistration(h *types.Header, publicKey []byte, proof []byte) error {
+	tendermintExtra, err := types.ExtractTendermintExtra(h)
+	if err != nil {
+		return err
+	}
+	tendermintExtra.BLSPublicKey = publicKey
+	tendermintExtra.BLSProof = proof
+
+	payload, err := rlp.EncodeToBytes(&tendermintExtra)
+	if err != nil {
+		return err
+	}
+
+	h.Extra = append(h.Extra[:types.TendermintExtraVanity], payload...)
+	return nil
+}
+
+// WriteAggregatedSeal writes the extra-data field of a block header with the aggregated BLS committed seal
+// and the bitmap of its signers.
+func WriteAggregatedSeal(h *types.Header, seal []byte, signers []byte) error {
+	if len(seal) != bls.SignatureLength {
+		return ErrInvalidSealLength
+	}
+	tendermintExtra, err := types.ExtractTendermintExtra(h)
+	if err != nil {
+		return err
+	}
+	tendermintExtra.AggregatedSeal = seal
+	tendermintExtra.SignersBitmap = signers
+
+	payload, err := rlp.EncodeToBytes(&tendermintExtra)
+	if err != nil {
+		return err
+	}
+
+	h.Extra = append(h.Extra[:types.TendermintExtraVanity], payload...)
+	return nil
+}
+
 // WriteCommittedSeals writes the extra-data field of a block header with given committed seals.
 func WriteCommittedSeals(h *types.Header, committedSeals [][]byte) error {
 	if len(committedSeals) == 0 {
@@ -128,6 +211,11 @@ func PrepareCommittedSeal(hash common.Hash) []byte {
 	return buf.Bytes()
 }
 
+// IsCommittedSeal returns true if data is a committed seal as returned by PrepareCommittedSeal
+func IsCommittedSeal(data []byte) bool {
+	return len(data) == common.HashLength+1 && data[common.HashLength] == byte(msgCommit)
+}
+
 // GetCheckpointNumber returns check-point block where header contains valset of current epoch
 func GetCheckpointNumber(epochDuration uint64, blockNumber uint64) uint64 {
 	if blockNumber == 0 || blockNumber < epochDuration {
@@ -155,3 +243,112 @@ func GetValSetAddresses(h *types.Header) ([]common.Address, error) {
 
 	return validators, nil
 }
+
+// GetValSetPowers returns the voting powers of validators from the extra-data field.
+// It returns nil if the header does not contain voting powers, i.e, all validators have the same voting power.
+func GetValSetPowers(h *types.Header) ([]uint64, error) {
+	tdmExtra, err := types.ExtractTendermintExtra(h)
+	if err != nil {
+		return nil, err
+	}
+	return tdmExtra.ValidatorPowers, nil
+}
+
+// GetValSet returns the validator set of blockNumber from the checkpoint header which contains the val-set.
+// It returns a stake-weighted set if the checkpoint header contains voting powers.
+func GetValSet(checkpoint *types.Header, policy tendermint.ProposerPolicy, blockNumber int64) (tendermint.ValidatorSet, error) {
+	validators, err := GetValSetAddresses(checkpoint)
+	if err != nil {
+		return nil, err
+	}
+	powers, err := GetValSetPowers(checkpoint)
+	if err != nil {
+		return nil, err
+	}
+	blsKeys, err := getValSetBLSKeys(checkpoint, validators)
+	if err != nil {
+		return nil, err
+	}
+	if len(powers) == 0 {
+		return validator.WithBLSPublicKeys(validator.NewSet(validators, policy, blockNumber), blsKeys), nil
+	}
+	if len(powers) != len(validators) {
+		return nil, tendermint.ErrMismatchValSet
+	}
+	var (
+		votingPowers = make([]int64, len(powers))
+		total        uint64
+	)
+	for i, power := range powers {
+		total += power
+		if power == 0 || power > validator.MaxTotalVotingPower || total > validator.MaxTotalVotingPower {
+			return nil, ErrInvalidVotingPower
+		}
+		votingPowers[i] = int64(power)
+	}
+	return validator.WithBLSPublicKeys(validator.NewWeightedSet(validators, votingPowers, blockNumber, checkpoint.Number.Int64()), blsKeys), nil
+}
+
+// getValSetBLSKeys returns the BLS public keys of validators from the extra-data field, mapped by validator address.
+func getValSetBLSKeys(h *types.Header, validators []common.Address) (map[common.Address][]byte, error) {
+	tdmExtra, err := types.ExtractTendermintExtra(h)
+	if err != nil {
+		return nil, err
+	}
+	if len(tdmExtra.ValidatorBLSKeys) == 0 {
+		return nil, nil
+	}
+	if len(tdmExtra.ValidatorBLSKeys) != len(validators) {
+		return nil, tendermint.ErrMismatchValSet
+	}
+	keys := make(map[common.Address][]byte, len(validators))
+	for i, addr := range validators {
+		if len(tdmExtra.ValidatorBLSKeys[i]) != bls.PublicKeyLength {
+			return nil, bls.ErrInvalidPublicKey
+		}
+		keys[addr] = tdmExtra.ValidatorBLSKeys[i]
+	}
+	return keys, nil
+}
+
+// UseBLSSeals returns true if every validator of valSet has a BLS public key,
+// in which case blocks are committed with an aggregated BLS seal.
+func UseBLSSeals(valSet tendermint.ValidatorSet) bool {
+	if valSet == nil || valSet.Size() == 0 {
+		return false
+	}
+	for _, val := range valSet.List() {
+		if len(val.BLSPublicKey()) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// NewSignersBitmap returns a bitmap of size validators with the bits at the given indexes set
+func NewSignersBitmap(size int, indexes []int) []byte {
+	bitmap := make([]byte, (size+7)/8)
+	for _, i := range indexes {
+		bitmap[i/8] |= 1 << uint(i%8)
+	}
+	return bitmap
+}
+
+// SignersFromBitmap returns the indexes of the validators set in the bitmap.
+// It returns an error if the bitmap does not match a val-set of the given size.
+func SignersFromBitmap(bitmap []byte, size int) ([]int, error) {
+	if len(bitmap) != (size+7)/8 {
+		return nil, ErrInvalidSignersBitmap
+	}
+	var indexes []int
+	for i := 0; i < len(bitmap)*8; i++ {
+		if bitmap[i/8]&(1<<uint(i%8)) == 0 {
+			continue
+		}
+		if i >= size {
+			return nil, ErrInvalidSignersBitmap
+		}
+		indexes = append(indexes, i)
+	}
+	return indexes, nil
+}
@@ -1,6 +1,18 @@
 package utils
 
-import "testing"
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint"
+	"github.com/Evrynetlabs/evrynet-node/core/types"
+	"github.com/Evrynetlabs/evrynet-node/crypto/bls"
+	"github.com/Evrynetlabs/evrynet-node/rlp"
+)
 
 func TestGetCheckpointNumber(t *testing.T) {
 	type args struct {
@@ -45,3 +57,80 @@ func TestGetCheckpointNumber(t *testing.T) {
 		})
 	}
 }
+
+func TestGetValSet(t *testing.T) {
+	var (
+		validators = []common.Address{common.HexToAddress("0x1"), common.HexToAddress("0x2")}
+		header     = &types.Header{Number: big.NewInt(10), Extra: make([]byte, types.TendermintExtraVanity)}
+	)
+	payload, err := rlp.EncodeToBytes(&types.TendermintExtra{})
+	require.NoError(t, err)
+	header.Extra = append(header.Extra, payload...)
+	require.NoError(t, WriteValSet(header, validators))
+
+	valSet, err := GetValSet(header, tendermint.RoundRobin, 11)
+	require.NoError(t, err)
+	assert.Equal(t, tendermint.RoundRobin, valSet.Policy())
+	assert.Equal(t, int64(2), valSet.TotalVotingPower())
+
+	require.NoError(t, WriteValSetPowers(header, []uint64{1, 5}))
+	valSet, err = GetValSet(header, tendermint.RoundRobin, 11)
+	require.NoError(t, err)
+	assert.Equal(t, tendermint.WeightedPriority, valSet.Policy())
+	assert.Equal(t, int64(6), valSet.TotalVotingPower())
+	assert.Equal(t, validators[1], valSet.GetProposer().Address())
+
+	require.NoError(t, WriteValSetPowers(header, []uint64{1}))
+	_, err = GetValSet(header, tendermint.RoundRobin, 11)
+	assert.Equal(t, tendermint.ErrMismatchValSet, err)
+
+	require.NoError(t, WriteValSetPowers(header, []uint64{0, 1}))
+	_, err = GetValSet(header, tendermint.RoundRobin, 11)
+	assert.Equal(t, ErrInvalidVotingPower, err)
+}
+
+func TestGetValSetBLSKeys(t *testing.T) {
+	var (
+		validators = []common.Address{common.HexToAddress("0x1"), common.HexToAddress("0x2")}
+		header     = &types.Header{Number: big.NewInt(10), Extra: make([]byte, types.TendermintExtraVanity)}
+		keys       = [][]byte{bls.DeriveSecretKey([]byte{1}).PublicKey().Marshal(), bls.DeriveSecretKey([]byte{2}).PublicKey().Marshal()}
+	)
+	payload, err := rlp.EncodeToBytes(&types.TendermintExtra{})
+	require.NoError(t, err)
+	header.Extra = append(header.Extra, payload...)
+	require.NoError(t, WriteValSet(header, validators))
+
+	valSet, err := GetValSet(header, tendermint.RoundRobin, 11)
+	require.NoError(t, err)
+	assert.False(t, UseBLSSeals(valSet))
+
+	require.NoError(t, WriteValSetBLSKeys(header, keys))
+	valSet, err = GetValSet(header, tendermint.RoundRobin, 11)
+	require.NoError(t, err)
+	assert.True(t, UseBLSSeals(valSet))
+	_, val := valSet.GetByAddress(validators[1])
+	assert.Equal(t, keys[1], val.BLSPublicKey())
+	assert.True(t, UseBLSSeals(valSet.Copy()))
+
+	// the BLS keys are not part of the block hash
+	hash := header.Hash()
+	require.NoError(t, WriteAggregatedSeal(header, make([]byte, bls.SignatureLength), NewSignersBitmap(2, []int{0, 1})))
+	assert.Equal(t, hash, header.Hash())
+
+	require.NoError(t, WriteValSetBLSKeys(header, keys[:1]))
+	_, err = GetValSet(header, tendermint.RoundRobin, 11)
+	assert.Equal(t, tendermint.ErrMismatchValSet, err)
+}
+
+func TestSignersBitmap(t *testing.T) {
+	bitmap := NewSignersBitmap(10, []int{0, 3, 9})
+	assert.Len(t, bitmap, 2)
+	signers, err := SignersFromBitmap(bitmap, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 3, 9}, signers)
+
+	_, err = SignersFromBitmap(bitmap, 17)
+	assert.Equal(t, ErrInvalidSignersBitmap, err)
+	_, err = SignersFromBitmap(bitmap, 9)
+	assert.Equal(t, ErrInvalidSignersBitmap, err)
+}
@@ -11,6 +11,13 @@ type Validator interface {
 	// Address returns address
 	Address() common.Address
 
+	// VotingPower returns the weight of the validator's vote
+	VotingPower() int64
+
+	// BLSPublicKey returns the registered BLS public key of the validator, it is nil if the validator set
+	// does not use aggregated BLS seals
+	BLSPublicKey() []byte
+
 	// String representation of Validator
 	String() string
 }
@@ -57,6 +64,11 @@ type ValidatorSet interface {
 	Copy() ValidatorSet
 	// Get the minimum number of votes for a polka
 	MinMajority() int
+	// TotalVotingPower returns the sum of voting power of all validators
+	TotalVotingPower() int64
+	// MinMajorityPower returns the minimum voting power for a polka.
+	// It equals MinMajority() if every validator has a voting power of 1.
+	MinMajorityPower() int64
 	// Get the minimum number of peers to archive consensus
 	MinPeers() int
 	// Get the maximum number of faulty nodes
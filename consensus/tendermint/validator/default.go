@@ -10,7 +10,8 @@ import (
 )
 
 type defaultValidator struct {
-	address common.Address
+	address      common.Address
+	blsPublicKey []byte
 }
 
 // Address will return address of defaultValidator
@@ -18,6 +19,16 @@ func (val *defaultValidator) Address() common.Address {
 	return val.address
 }
 
+// VotingPower returns 1 as every defaultValidator has the same weight
+func (val *defaultValidator) VotingPower() int64 {
+	return 1
+}
+
+// BLSPublicKey returns the BLS public key of defaultValidator
+func (val *defaultValidator) BLSPublicKey() []byte {
+	return val.blsPublicKey
+}
+
 // String will parse address of defaultValidator to string and return it
 func (val *defaultValidator) String() string {
 	return val.Address().String()
@@ -182,10 +193,16 @@ func (valSet *defaultSet) Copy() tendermint.ValidatorSet {
 	defer valSet.validatorMu.RUnlock()
 
 	addresses := make([]common.Address, 0, len(valSet.validators))
+	blsKeys := make(map[common.Address][]byte)
 	for _, v := range valSet.validators {
 		addresses = append(addresses, v.Address())
+		if key := v.BLSPublicKey(); key != nil {
+			blsKeys[v.Address()] = key
+		}
 	}
-	return NewSet(addresses, valSet.policy, valSet.height)
+	cpy := NewSet(addresses, valSet.policy, valSet.height)
+	setBLSPublicKeys(cpy, blsKeys)
+	return cpy
 }
 
 // Get the minimum number of peers to archive consensus
@@ -198,6 +215,16 @@ func (valSet *defaultSet) MinMajority() int {
 	return valSet.Size() - valSet.F()
 }
 
+// TotalVotingPower returns the number of validators as each of them has a voting power of 1
+func (valSet *defaultSet) TotalVotingPower() int64 {
+	return int64(valSet.Size())
+}
+
+// MinMajorityPower returns the minimum voting power for a polka, which is the same as MinMajority
+func (valSet *defaultSet) MinMajorityPower() int64 {
+	return int64(valSet.MinMajority())
+}
+
 // F get the maximum number of faulty nodes
 func (valSet *defaultSet) F() int { return int(math.Ceil(float64(valSet.Size())/3)) - 1 }
 
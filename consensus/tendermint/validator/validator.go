@@ -19,6 +19,34 @@ func NewSet(addrs []common.Address, policy tendermint.ProposerPolicy, height int
 	return newDefaultSet(addrs, policy, height)
 }
 
+// NewWeightedSet will create new validator set in which the vote of each validator is weighted by its voting power.
+// The proposer of a block is selected after (height - epochStart) steps of the proposer priority algorithm.
+func NewWeightedSet(addrs []common.Address, powers []int64, height int64, epochStart int64) tendermint.ValidatorSet {
+	return newWeightedSet(addrs, powers, height, epochStart)
+}
+
+// WithBLSPublicKeys sets the BLS public keys of the validators in valSet, so that the set is committed with aggregated
+// BLS seals. keys maps a validator address to its marshaled public key.
+func WithBLSPublicKeys(valSet tendermint.ValidatorSet, keys map[common.Address][]byte) tendermint.ValidatorSet {
+	setBLSPublicKeys(valSet, keys)
+	return valSet
+}
+
+func setBLSPublicKeys(valSet tendermint.ValidatorSet, keys map[common.Address][]byte) {
+	for _, val := range valSet.List() {
+		key, ok := keys[val.Address()]
+		if !ok {
+			continue
+		}
+		switch v := val.(type) {
+		case *defaultValidator:
+			v.blsPublicKey = key
+		case *weightedValidator:
+			v.blsPublicKey = key
+		}
+	}
+}
+
 // IsProposer will be checking whether the validator with given address is a proposer
 func (valSet *defaultSet) IsProposer(address common.Address) bool {
 	_, val := valSet.GetByAddress(address)
@@ -0,0 +1,264 @@
+package validator
+
+import (
+	"bytes"
+	"math"
+	"math/big"
+	"sort"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint"
+)
+
+const (
+	// MaxTotalVotingPower is the maximum total voting power of a validator set,
+	// it is small enough so that the proposer priorities never overflow
+	MaxTotalVotingPower = math.MaxInt64 / 8
+	// priorityWindowSizeFactor bounds the difference between the max and min proposer priority
+	// to priorityWindowSizeFactor * totalVotingPower
+	priorityWindowSizeFactor = 2
+)
+
+// weightedValidator is a validator with a voting power and a proposer priority
+type weightedValidator struct {
+	address          common.Address
+	votingPower      int64
+	proposerPriority int64
+	blsPublicKey     []byte
+}
+
+// Address will return address of weightedValidator
+func (val *weightedValidator) Address() common.Address {
+	return val.address
+}
+
+// VotingPower returns the voting power of weightedValidator
+func (val *weightedValidator) VotingPower() int64 {
+	return val.votingPower
+}
+
+// BLSPublicKey returns the BLS public key of weightedValidator
+func (val *weightedValidator) BLSPublicKey() []byte {
+	return val.blsPublicKey
+}
+
+// String will parse address of weightedValidator to string and return it
+func (val *weightedValidator) String() string {
+	return val.Address().String()
+}
+
+// ----------------------------------------------------------------------------
+
+// weightedSet is a validator set in which the vote of each validator is weighted by its voting power.
+// The proposer is selected by the proposer priority algorithm of Tendermint:
+// at each step, all priorities are increased by the voting power, the validator with the highest priority is the proposer
+// and its priority is decreased by the total voting power.
+type weightedSet struct {
+	*defaultSet
+	totalVotingPower int64
+}
+
+func newWeightedSet(addrs []common.Address, powers []int64, height int64, epochStart int64) *weightedSet {
+	valSet := &weightedSet{
+		defaultSet: &defaultSet{
+			policy: tendermint.WeightedPriority,
+			height: height,
+		},
+	}
+	valSet.validators = make([]tendermint.Validator, len(addrs))
+	for i, addr := range addrs {
+		power := int64(1)
+		if i < len(powers) && powers[i] > 0 {
+			power = powers[i]
+		}
+		valSet.validators[i] = &weightedValidator{address: addr, votingPower: power}
+		valSet.totalVotingPower += power
+	}
+	sort.Sort(valSet.validators)
+
+	if len(valSet.validators) > 0 {
+		// the proposer of the first block after epochStart is selected after 1 step
+		steps := height - epochStart
+		if steps < 1 {
+			steps = 1
+		}
+		valSet.incrementProposerPriority(steps)
+	}
+	return valSet
+}
+
+// TotalVotingPower returns the sum of voting power of all validators
+func (valSet *weightedSet) TotalVotingPower() int64 {
+	valSet.validatorMu.RLock()
+	defer valSet.validatorMu.RUnlock()
+	return valSet.totalVotingPower
+}
+
+// MinMajorityPower returns the minimum voting power for a polka, which is more than 2/3 of total voting power
+func (valSet *weightedSet) MinMajorityPower() int64 {
+	return valSet.TotalVotingPower()*2/3 + 1
+}
+
+// AddValidator adds a validator with a voting power of 1
+func (valSet *weightedSet) AddValidator(address common.Address) bool {
+	valSet.validatorMu.Lock()
+	defer valSet.validatorMu.Unlock()
+	for _, v := range valSet.validators {
+		if v.Address() == address {
+			return false
+		}
+	}
+	valSet.validators = append(valSet.validators, &weightedValidator{address: address, votingPower: 1})
+	valSet.totalVotingPower++
+	return true
+}
+
+// RemoveValidator will remove a validator from validator set
+func (valSet *weightedSet) RemoveValidator(address common.Address) bool {
+	valSet.validatorMu.Lock()
+	defer valSet.validatorMu.Unlock()
+
+	for i, v := range valSet.validators {
+		if v.Address() == address {
+			valSet.totalVotingPower -= v.VotingPower()
+			valSet.validators = append(valSet.validators[:i], valSet.validators[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Copy returns a deep copy of the validator set, including the proposer priorities
+func (valSet *weightedSet) Copy() tendermint.ValidatorSet {
+	valSet.validatorMu.RLock()
+	defer valSet.validatorMu.RUnlock()
+
+	cpy := &weightedSet{
+		defaultSet: &defaultSet{
+			policy: valSet.policy,
+			height: valSet.height,
+		},
+		totalVotingPower: valSet.totalVotingPower,
+	}
+	cpy.validators = make([]tendermint.Validator, len(valSet.validators))
+	for i, v := range valSet.validators {
+		val := *(v.(*weightedValidator))
+		cpy.validators[i] = &val
+		if valSet.proposer != nil && valSet.proposer.Address() == val.address {
+			cpy.proposer = cpy.validators[i]
+		}
+	}
+	return cpy
+}
+
+// CalcProposer moves the proposer priority forward by roundDiff steps.
+// The last proposer is implied by the current priorities so it is not used.
+func (valSet *weightedSet) CalcProposer(lastProposer common.Address, roundDiff int64) {
+	valSet.validatorMu.Lock()
+	defer valSet.validatorMu.Unlock()
+	valSet.incrementProposerPriority(roundDiff)
+}
+
+// IsProposer will be checking whether the validator with given address is a proposer
+func (valSet *weightedSet) IsProposer(address common.Address) bool {
+	proposer := valSet.GetProposer()
+	return proposer != nil && proposer.Address() == address
+}
+
+// incrementProposerPriority runs the proposer priority algorithm for the given number of steps.
+// The caller must hold validatorMu.
+func (valSet *weightedSet) incrementProposerPriority(steps int64) {
+	if len(valSet.validators) == 0 || steps <= 0 {
+		return
+	}
+	valSet.rescalePriorities(priorityWindowSizeFactor * valSet.totalVotingPower)
+	valSet.shiftByAvgProposerPriority()
+
+	var proposer *weightedValidator
+	for i := int64(0); i < steps; i++ {
+		for _, v := range valSet.validators {
+			val := v.(*weightedValidator)
+			val.proposerPriority = safeAddClip(val.proposerPriority, val.votingPower)
+		}
+		proposer = valSet.validatorWithMostPriority()
+		proposer.proposerPriority = safeSubClip(proposer.proposerPriority, valSet.totalVotingPower)
+	}
+	valSet.proposer = proposer
+}
+
+// rescalePriorities divides all priorities so that the difference between max and min priority is at most diffMax
+func (valSet *weightedSet) rescalePriorities(diffMax int64) {
+	if diffMax <= 0 {
+		return
+	}
+	var (
+		max int64 = math.MinInt64
+		min int64 = math.MaxInt64
+	)
+	for _, v := range valSet.validators {
+		priority := v.(*weightedValidator).proposerPriority
+		if priority > max {
+			max = priority
+		}
+		if priority < min {
+			min = priority
+		}
+	}
+	diff := max - min
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff > diffMax {
+		ratio := (diff + diffMax - 1) / diffMax
+		for _, v := range valSet.validators {
+			val := v.(*weightedValidator)
+			val.proposerPriority /= ratio
+		}
+	}
+}
+
+// shiftByAvgProposerPriority centers the priorities around 0
+func (valSet *weightedSet) shiftByAvgProposerPriority() {
+	sum := new(big.Int)
+	for _, v := range valSet.validators {
+		sum.Add(sum, big.NewInt(v.(*weightedValidator).proposerPriority))
+	}
+	avg := sum.Div(sum, big.NewInt(int64(len(valSet.validators)))).Int64()
+	for _, v := range valSet.validators {
+		val := v.(*weightedValidator)
+		val.proposerPriority = safeSubClip(val.proposerPriority, avg)
+	}
+}
+
+// validatorWithMostPriority returns the validator with the highest priority, the lower address wins if there is a tie
+func (valSet *weightedSet) validatorWithMostPriority() *weightedValidator {
+	var res *weightedValidator
+	for _, v := range valSet.validators {
+		val := v.(*weightedValidator)
+		if res == nil || val.proposerPriority > res.proposerPriority ||
+			(val.proposerPriority == res.proposerPriority && bytes.Compare(val.address[:], res.address[:]) < 0) {
+			res = val
+		}
+	}
+	return res
+}
+
+func safeAddClip(a, b int64) int64 {
+	if b > 0 && a > math.MaxInt64-b {
+		return math.MaxInt64
+	}
+	if b < 0 && a < math.MinInt64-b {
+		return math.MinInt64
+	}
+	return a + b
+}
+
+func safeSubClip(a, b int64) int64 {
+	if b > 0 && a < math.MinInt64+b {
+		return math.MinInt64
+	}
+	if b < 0 && a > math.MaxInt64+b {
+		return math.MaxInt64
+	}
+	return a - b
+}
@@ -0,0 +1,71 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+)
+
+func TestWeightedSet_VotingPower(t *testing.T) {
+	var (
+		a, _   = common.EvryAddressStringToAddressCheck("EH9uVaqWRxHuzJbroqzX18yxmeW8XVJyV9")
+		b, _   = common.EvryAddressStringToAddressCheck("EH9uVaqWRxHuzJbroqzX18yxmeW8fmHkiJ")
+		c, _   = common.EvryAddressStringToAddressCheck("EH9uVaqWRxHuzJbroqzX18yxmeW8hGraaK")
+		valSet = NewWeightedSet([]common.Address{a, b, c}, []int64{3, 3, 5}, 1, 0)
+	)
+	require.Equal(t, 3, valSet.Size())
+	assert.Equal(t, int64(11), valSet.TotalVotingPower())
+	assert.Equal(t, int64(8), valSet.MinMajorityPower())
+	_, val := valSet.GetByAddress(c)
+	require.NotNil(t, val)
+	assert.Equal(t, int64(5), val.VotingPower())
+
+	// the validator with the most voting power does not reach the majority alone, but does with another one
+	assert.True(t, val.VotingPower() < valSet.MinMajorityPower())
+	assert.True(t, val.VotingPower()+3 >= valSet.MinMajorityPower())
+
+	// removing a validator reduces total voting power
+	cpy := valSet.Copy()
+	require.True(t, cpy.RemoveValidator(c))
+	assert.Equal(t, int64(6), cpy.TotalVotingPower())
+	assert.Equal(t, int64(11), valSet.TotalVotingPower())
+}
+
+func TestWeightedSet_ProposerPriority(t *testing.T) {
+	var (
+		a, _   = common.EvryAddressStringToAddressCheck("EH9uVaqWRxHuzJbroqzX18yxmeW8XVJyV9")
+		b, _   = common.EvryAddressStringToAddressCheck("EH9uVaqWRxHuzJbroqzX18yxmeW8fmHkiJ")
+		c, _   = common.EvryAddressStringToAddressCheck("EH9uVaqWRxHuzJbroqzX18yxmeW8hGraaK")
+		valSet = NewWeightedSet([]common.Address{a, b, c}, []int64{1, 2, 3}, 1, 0)
+		counts = make(map[common.Address]int)
+	)
+	// the first proposer is the validator with the highest voting power
+	assert.Equal(t, c, valSet.GetProposer().Address())
+	assert.True(t, valSet.IsProposer(c))
+
+	// over a full cycle of total voting power rounds, the number of proposals is proportional to voting power
+	for i := 0; i < 6; i++ {
+		counts[valSet.GetProposer().Address()]++
+		valSet.CalcProposer(valSet.GetProposer().Address(), 1)
+	}
+	assert.Equal(t, 1, counts[a])
+	assert.Equal(t, 2, counts[b])
+	assert.Equal(t, 3, counts[c])
+
+	// a copy selects the same proposers as the original set
+	cpy := valSet.Copy()
+	valSet.CalcProposer(valSet.GetProposer().Address(), 2)
+	cpy.CalcProposer(cpy.GetProposer().Address(), 2)
+	assert.Equal(t, valSet.GetProposer().Address(), cpy.GetProposer().Address())
+
+	// the proposer of a block does not depend on the previous blocks' sets
+	for height := int64(1); height <= 6; height++ {
+		expected := NewWeightedSet([]common.Address{a, b, c}, []int64{1, 2, 3}, 1, 0)
+		expected.CalcProposer(common.Address{}, height-1)
+		assert.Equal(t, expected.GetProposer().Address(),
+			NewWeightedSet([]common.Address{a, b, c}, []int64{1, 2, 3}, height, 0).GetProposer().Address())
+	}
+}
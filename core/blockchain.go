@@ -684,6 +684,39 @@ func (bc *BlockChain) GetEvilBlockRLP(hash common.Hash) rlp.RawValue {
 	return block
 }
 
+// GetFraudProof retrieves the fraud proof of an evil block by hash, generating it from the state of
+// the block's parent if it has not been stored yet.
+func (bc *BlockChain) GetFraudProof(hash common.Hash) (*types.FraudProof, error) {
+	if proof := rawdb.ReadFraudProof(bc.db, hash); proof != nil {
+		return proof, nil
+	}
+	block := bc.GetBlockByHash(hash)
+	if block == nil {
+		if number := rawdb.ReadEvilHeaderNumber(bc.db, hash, false); number != nil {
+			block = rawdb.ReadEvilBlock(bc.db, hash, *number, false)
+		}
+	}
+	if block == nil {
+		return nil, errors.New("unknown evil block")
+	}
+	return bc.GenerateFraudProof(block)
+}
+
+// GenerateFraudProof re-executes the block on the state of its parent, then stores and returns the proof
+// of its invalid state transition. It returns ErrNoFraud if the state transition is valid.
+func (bc *BlockChain) GenerateFraudProof(block *types.Block) (*types.FraudProof, error) {
+	parent := bc.GetHeader(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return nil, consensus.ErrUnknownAncestor
+	}
+	proof, err := GenerateFraudProof(bc.chainConfig, bc, bc.db, parent, block)
+	if err != nil {
+		return nil, err
+	}
+	rawdb.WriteFraudProof(bc.db, block.Hash(), proof)
+	return proof, nil
+}
+
 // HasBlock checks if a block is fully present in the database or not.
 func (bc *BlockChain) HasBlock(hash common.Hash, number uint64) bool {
 	if bc.blockCache.Contains(hash) {
@@ -0,0 +1,309 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/common/hexutil"
+	"github.com/Evrynetlabs/evrynet-node/consensus"
+	"github.com/Evrynetlabs/evrynet-node/core/rawdb"
+	"github.com/Evrynetlabs/evrynet-node/core/state"
+	"github.com/Evrynetlabs/evrynet-node/core/types"
+	"github.com/Evrynetlabs/evrynet-node/core/vm"
+	"github.com/Evrynetlabs/evrynet-node/crypto"
+	"github.com/Evrynetlabs/evrynet-node/evrdb"
+	"github.com/Evrynetlabs/evrynet-node/params"
+)
+
+var (
+	// ErrNoFraud is returned if the re-execution of a block matches its header.
+	ErrNoFraud = errors.New("block state transition is valid")
+
+	// ErrIncompleteFraudProof is returned if the re-execution of a fraud proof needs some state it does not hold.
+	ErrIncompleteFraudProof = errors.New("incomplete fraud proof")
+
+	// ErrInvalidFraudProof is returned if a fraud proof is malformed.
+	ErrInvalidFraudProof = errors.New("invalid fraud proof")
+
+	// ErrInvalidBlockTransaction is returned if a transaction of the replayed block can not be applied.
+	// Such a block is rejected by the block validation of every node, its state transition is not compared with the header.
+	ErrInvalidBlockTransaction = errors.New("block has a transaction which can not be applied")
+)
+
+// FraudProofChain is the chain a block is replayed on to generate or verify its fraud proof.
+type FraudProofChain interface {
+	consensus.FullChainReader
+
+	// Engine retrieves the chain's consensus engine.
+	Engine() consensus.Engine
+}
+
+// GenerateFraudProof re-executes the block on the state of its parent and returns the proof of its invalid state transition.
+// The block is replayed the way the chain processes it: its transactions are applied, then the consensus engine of
+// the chain finalizes the state, i.e. rewards, slashing and liveness, and the state root and the gas used are compared
+// to the header. It returns ErrNoFraud if they match.
+func GenerateFraudProof(config *params.ChainConfig, chain FraudProofChain, db evrdb.Database, parent *types.Header, block *types.Block) (*types.FraudProof, error) {
+	if block.ParentHash() != parent.Hash() {
+		return nil, ErrInvalidFraudProof
+	}
+	var (
+		recorder  = &recordingDatabase{Database: db, values: make(map[common.Hash][]byte)}
+		ancestors = &recordingChain{chain: chain, db: state.NewDatabase(recorder), headers: make(map[common.Hash]*types.Header)}
+	)
+	statedb, err := state.New(parent.Root, ancestors.db)
+	if err != nil {
+		return nil, err
+	}
+	switch err := replayBlock(config, chain.Engine(), ancestors, statedb, block.Header(), block.Transactions()); err {
+	case nil:
+		return &types.FraudProof{
+			Header:       block.Header(),
+			Parent:       parent,
+			Transactions: block.Transactions(),
+			Ancestors:    ancestors.sorted(),
+			Nodes:        recorder.sorted(),
+		}, nil
+	case ErrIncompleteFraudProof:
+		// the state of the parent is not fully available, e.g it has been pruned
+		return nil, statedb.Error()
+	default:
+		return nil, err
+	}
+}
+
+// VerifyFraudProof re-executes the block of the proof against only the state it holds, the state is finalized by engine.
+// It returns nil if the proof shows that the state transition of the header is invalid.
+func VerifyFraudProof(config *params.ChainConfig, engine consensus.Engine, proof *types.FraudProof) error {
+	if proof.Header == nil || proof.Parent == nil || proof.Parent.Hash() != proof.Header.ParentHash ||
+		types.DeriveSha(proof.Transactions) != proof.Header.TxHash {
+		return ErrInvalidFraudProof
+	}
+	db := rawdb.NewMemoryDatabase()
+	for _, node := range proof.Nodes {
+		if err := db.Put(crypto.Keccak256(node), node); err != nil {
+			return err
+		}
+	}
+	chain := &proofChain{
+		config:  config,
+		engine:  engine,
+		db:      state.NewDatabase(db),
+		headers: make(map[common.Hash]*types.Header),
+		numbers: make(map[uint64]*types.Header),
+	}
+	for _, header := range append(proof.Ancestors, proof.Parent) {
+		chain.headers[header.Hash()] = header
+		chain.numbers[header.Number.Uint64()] = header
+	}
+	statedb, err := state.New(proof.Parent.Root, chain.db)
+	if err != nil {
+		return ErrIncompleteFraudProof
+	}
+	err = replayBlock(config, engine, chain, statedb, proof.Header, proof.Transactions)
+	// a missing ancestor or state would change the result of the BLOCKHASH opcode or of the finalization
+	if err != ErrInvalidFraudProof && chain.missing {
+		return ErrIncompleteFraudProof
+	}
+	return err
+}
+
+// replayBlock applies the transactions to the state, finalizes it with engine and compares the result with the header.
+// It returns nil if they do not match, ErrNoFraud if they do, ErrIncompleteFraudProof if some state is missing
+// and ErrInvalidBlockTransaction if a transaction can not be applied.
+func replayBlock(config *params.ChainConfig, engine consensus.Engine, chain FraudProofChain, statedb *state.StateDB, header *types.Header, txs types.Transactions) error {
+	var (
+		gp      = new(GasPool).AddGas(header.GasLimit)
+		gasUsed = uint64(0)
+	)
+	for i, tx := range txs {
+		statedb.Prepare(tx.Hash(), header.Hash(), i)
+		_, _, err := ApplyTransaction(config, chain, nil, gp, statedb, header, tx, &gasUsed, vm.Config{})
+		if statedb.Error() != nil {
+			return ErrIncompleteFraudProof
+		}
+		if err != nil {
+			return ErrInvalidBlockTransaction
+		}
+	}
+	// the engine sets the root of the header it finalizes, so it is given a copy
+	if err := engine.Finalize(chain, types.CopyHeader(header), statedb, txs, nil); err != nil {
+		if statedb.Error() != nil {
+			return ErrIncompleteFraudProof
+		}
+		return err
+	}
+	root := statedb.IntermediateRoot(true)
+	if statedb.Error() != nil {
+		return ErrIncompleteFraudProof
+	}
+	if root != header.Root || gasUsed != header.GasUsed {
+		return nil
+	}
+	return ErrNoFraud
+}
+
+// recordingDatabase records the trie nodes and contract codes read from the database
+type recordingDatabase struct {
+	evrdb.Database
+	lock   sync.Mutex
+	values map[common.Hash][]byte
+}
+
+func (db *recordingDatabase) Get(key []byte) ([]byte, error) {
+	value, err := db.Database.Get(key)
+	// the trie nodes and the contract codes are stored by hash
+	if err == nil && len(key) == common.HashLength && bytes.Equal(crypto.Keccak256(value), key) {
+		db.lock.Lock()
+		db.values[common.BytesToHash(key)] = common.CopyBytes(value)
+		db.lock.Unlock()
+	}
+	return value, err
+}
+
+// sorted returns the recorded values sorted by hash, so that a proof is deterministic
+func (db *recordingDatabase) sorted() []hexutil.Bytes {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	hashes := make([]common.Hash, 0, len(db.values))
+	for hash := range db.values {
+		hashes = append(hashes, hash)
+	}
+	sort.Slice(hashes, func(i, j int) bool { return bytes.Compare(hashes[i][:], hashes[j][:]) < 0 })
+	values := make([]hexutil.Bytes, len(hashes))
+	for i, hash := range hashes {
+		values[i] = db.values[hash]
+	}
+	return values
+}
+
+// recordingChain records the headers accessed by the BLOCKHASH opcode and the consensus engine,
+// the states opened by the engine are read from the recording database
+type recordingChain struct {
+	chain   FraudProofChain
+	db      state.Database
+	headers map[common.Hash]*types.Header
+}
+
+// record records the header if it exists and returns it
+func (c *recordingChain) record(header *types.Header) *types.Header {
+	if header != nil {
+		c.headers[header.Hash()] = header
+	}
+	return header
+}
+
+func (c *recordingChain) Engine() consensus.Engine {
+	return c.chain.Engine()
+}
+
+func (c *recordingChain) Config() *params.ChainConfig {
+	return c.chain.Config()
+}
+
+func (c *recordingChain) CurrentHeader() *types.Header {
+	return c.record(c.chain.CurrentHeader())
+}
+
+func (c *recordingChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	return c.record(c.chain.GetHeader(hash, number))
+}
+
+func (c *recordingChain) GetHeaderByNumber(number uint64) *types.Header {
+	return c.record(c.chain.GetHeaderByNumber(number))
+}
+
+func (c *recordingChain) GetHeaderByHash(hash common.Hash) *types.Header {
+	return c.record(c.chain.GetHeaderByHash(hash))
+}
+
+func (c *recordingChain) GetBlock(hash common.Hash, number uint64) *types.Block {
+	block := c.chain.GetBlock(hash, number)
+	if block != nil {
+		c.record(block.Header())
+	}
+	return block
+}
+
+func (c *recordingChain) StateAt(root common.Hash) (*state.StateDB, error) {
+	return state.New(root, c.db)
+}
+
+// sorted returns the recorded headers sorted by number
+func (c *recordingChain) sorted() []*types.Header {
+	headers := make([]*types.Header, 0, len(c.headers))
+	for _, header := range c.headers {
+		headers = append(headers, header)
+	}
+	sort.Slice(headers, func(i, j int) bool { return headers[i].Number.Cmp(headers[j].Number) < 0 })
+	return headers
+}
+
+// proofChain serves the ancestors and the state held by a fraud proof, it records if a missing one is accessed
+type proofChain struct {
+	config  *params.ChainConfig
+	engine  consensus.Engine
+	db      state.Database
+	headers map[common.Hash]*types.Header
+	numbers map[uint64]*types.Header
+	missing bool
+}
+
+func (c *proofChain) Engine() consensus.Engine {
+	return c.engine
+}
+
+func (c *proofChain) Config() *params.ChainConfig {
+	return c.config
+}
+
+// CurrentHeader returns the highest header held by the proof
+func (c *proofChain) CurrentHeader() *types.Header {
+	var current *types.Header
+	for _, header := range c.headers {
+		if current == nil || header.Number.Cmp(current.Number) > 0 {
+			current = header
+		}
+	}
+	return current
+}
+
+func (c *proofChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if header, ok := c.headers[hash]; ok && header.Number.Uint64() == number {
+		return header
+	}
+	c.missing = true
+	return nil
+}
+
+func (c *proofChain) GetHeaderByNumber(number uint64) *types.Header {
+	if header, ok := c.numbers[number]; ok {
+		return header
+	}
+	c.missing = true
+	return nil
+}
+
+func (c *proofChain) GetHeaderByHash(hash common.Hash) *types.Header {
+	if header, ok := c.headers[hash]; ok {
+		return header
+	}
+	c.missing = true
+	return nil
+}
+
+// GetBlock returns nil as a fraud proof does not hold the bodies of the ancestors
+func (c *proofChain) GetBlock(hash common.Hash, number uint64) *types.Block {
+	c.missing = true
+	return nil
+}
+
+func (c *proofChain) StateAt(root common.Hash) (*state.StateDB, error) {
+	statedb, err := state.New(root, c.db)
+	if err != nil {
+		c.missing = true
+	}
+	return statedb, err
+}
@@ -0,0 +1,115 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/consensus/ethash"
+	"github.com/Evrynetlabs/evrynet-node/core/rawdb"
+	"github.com/Evrynetlabs/evrynet-node/core/state"
+	"github.com/Evrynetlabs/evrynet-node/core/types"
+	"github.com/Evrynetlabs/evrynet-node/core/vm"
+	"github.com/Evrynetlabs/evrynet-node/crypto"
+	"github.com/Evrynetlabs/evrynet-node/params"
+)
+
+func TestFraudProof(t *testing.T) {
+	var (
+		key, _   = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr     = crypto.PubkeyToAddress(key.PublicKey)
+		coinbase = common.HexToAddress("0x0000000000000000000000000000000000000bad")
+		db       = rawdb.NewMemoryDatabase()
+		gspec    = &Genesis{Config: params.TestChainConfig, Alloc: GenesisAlloc{addr: {Balance: big.NewInt(1000000000000000000)}}}
+		genesis  = gspec.MustCommit(db)
+		signer   = types.MakeSigner(gspec.Config, common.Big1)
+	)
+	chain, err := NewBlockChain(db, nil, gspec.Config, ethash.NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	defer chain.Stop()
+
+	// makeBlock packs the transfers on top of the genesis and finalizes the state with the engine,
+	// tamper modifies the header after the execution
+	makeBlock := func(tamper func(header *types.Header)) *types.Block {
+		statedb, _ := state.New(genesis.Root(), state.NewDatabase(db))
+		header := &types.Header{
+			ParentHash: genesis.Hash(),
+			Number:     common.Big1,
+			GasLimit:   genesis.GasLimit(),
+			Coinbase:   coinbase,
+			Time:       genesis.Time() + 10,
+			Difficulty: genesis.Difficulty(),
+		}
+		var (
+			txs      types.Transactions
+			receipts types.Receipts
+			gp       = new(GasPool).AddGas(header.GasLimit)
+		)
+		for i := uint64(0); i < 2; i++ {
+			tx, _ := types.SignTx(types.NewTransaction(i, common.BigToAddress(big.NewInt(int64(i+1))), big.NewInt(1000), params.TxGas, big.NewInt(params.GasPriceConfig), nil), signer, key)
+			statedb.Prepare(tx.Hash(), common.Hash{}, len(txs))
+			receipt, _, err := ApplyTransaction(gspec.Config, chain, &coinbase, gp, statedb, header, tx, &header.GasUsed, vm.Config{})
+			if err != nil {
+				t.Fatalf("failed to apply transaction: %v", err)
+			}
+			txs, receipts = append(txs, tx), append(receipts, receipt)
+		}
+		if err := chain.Engine().Finalize(chain, header, statedb, txs, nil); err != nil {
+			t.Fatalf("failed to finalize block: %v", err)
+		}
+		if tamper != nil {
+			tamper(header)
+		}
+		return types.NewBlock(header, txs, nil, receipts)
+	}
+
+	// An honest block can not be proven evil
+	honest := makeBlock(nil)
+	if _, err := GenerateFraudProof(gspec.Config, chain, db, genesis.Header(), honest); err != ErrNoFraud {
+		t.Fatalf("honest block: error mismatch: have %v, want %v", err, ErrNoFraud)
+	}
+	// A block with a transaction which can not be applied is invalid, not a wrong state transition
+	future, _ := types.SignTx(types.NewTransaction(5, common.Address{}, big.NewInt(1000), params.TxGas, big.NewInt(params.GasPriceConfig), nil), signer, key)
+	invalid := types.NewBlock(honest.Header(), append(honest.Transactions(), future), nil, nil)
+	if _, err := GenerateFraudProof(gspec.Config, chain, db, genesis.Header(), invalid); err != ErrInvalidBlockTransaction {
+		t.Fatalf("invalid block: error mismatch: have %v, want %v", err, ErrInvalidBlockTransaction)
+	}
+
+	for i, tamper := range []func(header *types.Header){
+		func(header *types.Header) { header.Root = common.HexToHash("0xdeadbeef") },
+		func(header *types.Header) { header.GasUsed++ },
+	} {
+		block := makeBlock(tamper)
+		proof, err := GenerateFraudProof(gspec.Config, chain, db, genesis.Header(), block)
+		if err != nil {
+			t.Fatalf("test %d: failed to generate fraud proof: %v", i, err)
+		}
+		if len(proof.Nodes) == 0 {
+			t.Fatalf("test %d: fraud proof has no state", i)
+		}
+		if err := VerifyFraudProof(gspec.Config, chain.Engine(), proof); err != nil {
+			t.Errorf("test %d: failed to verify fraud proof: %v", i, err)
+		}
+
+		// The proof is rejected without the state of a touched account
+		incomplete := *proof
+		incomplete.Nodes = proof.Nodes[:len(proof.Nodes)-1]
+		if err := VerifyFraudProof(gspec.Config, chain.Engine(), &incomplete); err != ErrIncompleteFraudProof {
+			t.Errorf("test %d: incomplete proof: error mismatch: have %v, want %v", i, err, ErrIncompleteFraudProof)
+		}
+
+		// The proof is rejected if the header is not bound to its parent and transactions
+		orphan := *proof
+		orphan.Parent = block.Header()
+		if err := VerifyFraudProof(gspec.Config, chain.Engine(), &orphan); err != ErrInvalidFraudProof {
+			t.Errorf("test %d: orphan proof: error mismatch: have %v, want %v", i, err, ErrInvalidFraudProof)
+		}
+		forged := *proof
+		forged.Transactions = proof.Transactions[:1]
+		if err := VerifyFraudProof(gspec.Config, chain.Engine(), &forged); err != ErrInvalidFraudProof {
+			t.Errorf("test %d: forged proof: error mismatch: have %v, want %v", i, err, ErrInvalidFraudProof)
+		}
+	}
+}
@@ -159,6 +159,9 @@ func SetupGenesisBlockWithOverride(db evrdb.Database, genesis *Genesis, isFinalC
 	}
 	if genesis != nil && genesis.Config != nil {
 		isFinalChain = genesis.Config.IsFinalChain
+		if err := genesis.Config.CheckPacking(); err != nil {
+			return genesis.Config, common.Hash{}, err
+		}
 	}
 	// Just commit the new block if there is no stored genesis block.
 
@@ -326,7 +329,7 @@ func GenesisBlockForTesting(db evrdb.Database, addr common.Address, balance *big
 func GenesisBlockForNewTesting(db evrdb.Database, addr common.Address, balance *big.Int, isFinalChain bool) *types.Block {
 	g := Genesis{Alloc: GenesisAlloc{addr: {Balance: balance}}}
 	g.Config = &params.ChainConfig{big.NewInt(1), big.NewInt(params.GasPriceConfig),
-		nil, nil, new(params.EthashConfig), nil, nil, isFinalChain}
+		nil, nil, nil, nil, new(params.EthashConfig), nil, nil, nil, isFinalChain}
 	return g.MustCommit(db)
 }
 
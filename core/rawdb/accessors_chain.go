@@ -826,6 +826,56 @@ func WriteBlockBase(db evrdb.KeyValueWriter, block *types.Block, isFinalChain bo
 	WriteHeaderBase(db, block.Header(), isFinalChain, isEvil)
 }
 
+// ReadFraudProof retrieves the fraud proof of an evil header.
+func ReadFraudProof(db evrdb.KeyValueReader, hash common.Hash) *types.FraudProof {
+	data, _ := db.Get(fraudProofKey(hash))
+	if len(data) == 0 {
+		return nil
+	}
+	proof := new(types.FraudProof)
+	if err := rlp.Decode(bytes.NewReader(data), proof); err != nil {
+		log.Error("Invalid fraud proof RLP", "hash", hash, "err", err)
+		return nil
+	}
+	return proof
+}
+
+// WriteFraudProof stores the fraud proof of an evil header.
+func WriteFraudProof(db evrdb.KeyValueWriter, hash common.Hash, proof *types.FraudProof) {
+	data, err := rlp.EncodeToBytes(proof)
+	if err != nil {
+		log.Crit("Failed to RLP encode fraud proof", "err", err)
+	}
+	if err := db.Put(fraudProofKey(hash), data); err != nil {
+		log.Crit("Failed to store fraud proof", "err", err)
+	}
+}
+
+// ReadEpochRewards retrieves the reward ledger of the epoch ended by the block with the given hash.
+func ReadEpochRewards(db evrdb.KeyValueReader, hash common.Hash) []*types.ValidatorReward {
+	data, _ := db.Get(epochRewardsKey(hash))
+	if len(data) == 0 {
+		return nil
+	}
+	var rewards []*types.ValidatorReward
+	if err := rlp.Decode(bytes.NewReader(data), &rewards); err != nil {
+		log.Error("Invalid epoch rewards RLP", "hash", hash, "err", err)
+		return nil
+	}
+	return rewards
+}
+
+// WriteEpochRewards stores the reward ledger of the epoch ended by the block with the given hash.
+func WriteEpochRewards(db evrdb.KeyValueWriter, hash common.Hash, rewards []*types.ValidatorReward) {
+	data, err := rlp.EncodeToBytes(rewards)
+	if err != nil {
+		log.Crit("Failed to RLP encode epoch rewards", "err", err)
+	}
+	if err := db.Put(epochRewardsKey(hash), data); err != nil {
+		log.Crit("Failed to store epoch rewards", "err", err)
+	}
+}
+
 // WriteAncientBlock writes entire block data into ancient store and returns the total written size.
 func WriteAncientBlock(db evrdb.AncientWriter, block *types.Block, receipts types.Receipts, td *big.Int, isFinalChain bool) int {
 	// Encode all block components to RLP format.
@@ -58,6 +58,10 @@ var (
 
 	tendermintPrefix = []byte("tendermint-snapshot-")
 
+	fraudProofPrefix = []byte("fraud-proof-") // fraudProofPrefix + evil header hash -> fraud proof
+
+	epochRewardsPrefix = []byte("epoch-rewards-") // epochRewardsPrefix + epoch block hash -> reward ledger of the epoch
+
 	// Chain index prefixes (use `i` + single byte to avoid mixing data types).
 	BloomBitsIndexPrefix = []byte("iB") // BloomBitsIndexPrefix is the data table of a chain indexer to track its progress
 
@@ -137,6 +141,16 @@ func evilHeaderKeyPrefix(number uint64) []byte {
 	return getEvilInfoKey(headerKeyPrefix(number))
 }
 
+// fraudProofKey = fraudProofPrefix + hash
+func fraudProofKey(hash common.Hash) []byte {
+	return append(fraudProofPrefix, hash.Bytes()...)
+}
+
+// epochRewardsKey = epochRewardsPrefix + hash
+func epochRewardsKey(hash common.Hash) []byte {
+	return append(epochRewardsPrefix, hash.Bytes()...)
+}
+
 // headerKey = headerPrefix + num (uint64 big endian) + hash
 func headerKey(number uint64, hash common.Hash) []byte {
 	return append(append(headerPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
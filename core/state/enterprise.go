@@ -0,0 +1,165 @@
+package state
+
+import (
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/params"
+)
+
+// Enterprise is the data of an enterprise contract besides its owner and providers
+type Enterprise struct {
+	ProviderPolicies []ProviderPolicy
+	PendingOwner     *common.Address  `rlp:"nil"` // owner proposed by the owners, waiting for its acceptance
+	Owners           []common.Address // owners of a multisig, empty if the contract has a single owner
+	Threshold        uint64           // approvals of the owners of a multisig required for an action
+	Approvals        []OwnerApproval
+}
+
+// OwnerApproval is the owners of a multisig who approved an action
+type OwnerApproval struct {
+	Action    common.Hash
+	Approvers []common.Address
+}
+
+// isEmpty returns true if the enterprise contract has no data besides its owner and providers
+func (e *Enterprise) isEmpty() bool {
+	return len(e.ProviderPolicies) == 0 && e.PendingOwner == nil && len(e.Owners) == 0 && e.Threshold == 0 && len(e.Approvals) == 0
+}
+
+// ValidateOwners returns an error if the owners can not be set with the threshold of approvals
+func ValidateOwners(owners []common.Address, threshold uint64) error {
+	if len(owners) == 0 || len(owners) > params.MaxOwner {
+		return ErrInvalidOwners
+	}
+	if threshold == 0 || threshold > uint64(len(owners)) {
+		return ErrInvalidOwnerThreshold
+	}
+	seen := make(map[common.Address]bool, len(owners))
+	for _, owner := range owners {
+		if owner == (common.Address{}) || seen[owner] {
+			return ErrInvalidOwners
+		}
+		seen[owner] = true
+	}
+	return nil
+}
+
+// enterprise returns the data of the enterprise contract, the slices of the returned value must not be modified
+// as they are kept in the journal
+func (s *stateObject) enterprise() Enterprise {
+	if len(s.data.Enterprise) == 0 {
+		return Enterprise{}
+	}
+	return s.data.Enterprise[0]
+}
+
+func (s *stateObject) SetEnterprise(enterprise Enterprise) {
+	s.db.journal.append(enterpriseChange{
+		account: &s.address,
+		prev:    s.data.Enterprise,
+	})
+	if enterprise.isEmpty() {
+		s.setEnterprise(nil)
+		return
+	}
+	s.setEnterprise([]Enterprise{enterprise})
+}
+
+func (s *stateObject) setEnterprise(enterprise []Enterprise) {
+	s.data.Enterprise = enterprise
+}
+
+func (s *stateObject) SetOwner(owner *common.Address) {
+	s.db.journal.append(ownerChange{
+		account: &s.address,
+		prev:    s.data.OwnerAddress,
+	})
+	s.setOwner(owner)
+}
+
+func (s *stateObject) setOwner(owner *common.Address) {
+	s.data.OwnerAddress = owner
+}
+
+// Owners returns the owners and the threshold of approvals for their actions
+func (s *stateObject) Owners() ([]common.Address, uint64) {
+	if s.data.OwnerAddress == nil {
+		return nil, 0
+	}
+	if enterprise := s.enterprise(); len(enterprise.Owners) != 0 {
+		return enterprise.Owners, enterprise.Threshold
+	}
+	return []common.Address{*s.data.OwnerAddress}, 1
+}
+
+// ApproveOwnerAction records the approval of an action by an owner, it returns true once the action
+// is approved by enough owners and forgets the approvals of the action
+func (s *stateObject) ApproveOwnerAction(owner common.Address, action common.Hash) (bool, error) {
+	if err := s.CheckOwner(owner); err != nil {
+		return false, err
+	}
+	enterprise := s.enterprise()
+	if enterprise.Threshold <= 1 {
+		return true, nil
+	}
+	var (
+		approvals []OwnerApproval
+		approvers []common.Address
+	)
+	for _, approval := range enterprise.Approvals {
+		if approval.Action != action {
+			approvals = append(approvals, approval)
+			continue
+		}
+		if owner.InList(approval.Approvers) {
+			return false, ErrAlreadyApproved
+		}
+		approvers = approval.Approvers
+	}
+	approvers = append(append([]common.Address{}, approvers...), owner)
+	approved := uint64(len(approvers)) >= enterprise.Threshold
+	if !approved {
+		approvals = append(approvals, OwnerApproval{Action: action, Approvers: approvers})
+	}
+	enterprise.Approvals = approvals
+	s.SetEnterprise(enterprise)
+	return approved, nil
+}
+
+// ProposeOwner assumes that the permission for proposing an owner here is valid, a zero owner cancels the proposal
+func (s *stateObject) ProposeOwner(owner common.Address) {
+	enterprise := s.enterprise()
+	enterprise.PendingOwner = nil
+	if owner != (common.Address{}) {
+		enterprise.PendingOwner = &owner
+	}
+	s.SetEnterprise(enterprise)
+}
+
+// AcceptOwnership makes the proposed owner the single owner of the contract
+func (s *stateObject) AcceptOwnership(owner common.Address) error {
+	enterprise := s.enterprise()
+	if enterprise.PendingOwner == nil || *enterprise.PendingOwner != owner {
+		return ErrOnlyPendingOwner
+	}
+	enterprise.PendingOwner, enterprise.Owners, enterprise.Threshold, enterprise.Approvals = nil, nil, 0, nil
+	s.SetEnterprise(enterprise)
+	s.SetOwner(&owner)
+	return nil
+}
+
+// SetOwners assumes that the permission for setting the owners here is valid, the pending approvals are dropped
+// as they were given by the previous owners
+func (s *stateObject) SetOwners(owners []common.Address, threshold uint64) error {
+	if err := ValidateOwners(owners, threshold); err != nil {
+		return err
+	}
+	enterprise := s.enterprise()
+	enterprise.Owners, enterprise.Threshold, enterprise.Approvals = nil, 0, nil
+	if len(owners) > 1 {
+		enterprise.Owners, enterprise.Threshold = append([]common.Address{}, owners...), threshold
+	}
+	s.SetEnterprise(enterprise)
+	owner := owners[0]
+	s.SetOwner(&owner)
+	return nil
+}
@@ -7,4 +7,15 @@ var (
 	ErrOnlyProvider  = errors.New("only provider can execute transaction to enterprise contract")
 	ErrOnlyOwner     = errors.New("only owner can add or remove provider")
 	ErrOwnerNotFound = errors.New("adding or removing provider transaction should be sent to enterprise contract")
+
+	ErrProviderNotFound         = errors.New("provider is not in the providers of enterprise contract")
+	ErrProviderMaxGasExceeded   = errors.New("gas limit exceeds the maximum gas per transaction of the provider")
+	ErrProviderBudgetExceeded   = errors.New("gas limit exceeds the remaining epoch gas budget of the provider")
+	ErrProviderSenderNotAllowed = errors.New("sender is not allowed by the provider")
+	ErrProviderMethodNotAllowed = errors.New("method is not allowed by the provider")
+
+	ErrOnlyPendingOwner      = errors.New("only the proposed owner can accept the ownership")
+	ErrAlreadyApproved       = errors.New("action is already approved by the owner")
+	ErrInvalidOwners         = errors.New("owners must be distinct non-zero addresses within the maximum owners")
+	ErrInvalidOwnerThreshold = errors.New("threshold must be between 1 and the number of owners")
 )
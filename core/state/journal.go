@@ -136,6 +136,14 @@ type (
 		account *common.Address
 		prev    []common.Address
 	}
+	enterpriseChange struct {
+		account *common.Address
+		prev    []Enterprise
+	}
+	ownerChange struct {
+		account *common.Address
+		prev    *common.Address
+	}
 )
 
 func (ch createObjectChange) revert(s *StateDB) {
@@ -245,3 +253,19 @@ func (ch providersChange) revert(s *StateDB) {
 func (ch providersChange) dirtied() *common.Address {
 	return ch.account
 }
+
+func (ch enterpriseChange) revert(s *StateDB) {
+	s.getStateObject(*ch.account).setEnterprise(ch.prev)
+}
+
+func (ch enterpriseChange) dirtied() *common.Address {
+	return ch.account
+}
+
+func (ch ownerChange) revert(s *StateDB) {
+	s.getStateObject(*ch.account).setOwner(ch.prev)
+}
+
+func (ch ownerChange) dirtied() *common.Address {
+	return ch.account
+}
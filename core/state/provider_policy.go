@@ -0,0 +1,149 @@
+package state
+
+import (
+	"bytes"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/core/types"
+)
+
+// ProviderPolicy is the policy set by the owner of an enterprise contract on a provider
+// along with the gas the provider paid for in the epoch of its last transaction
+type ProviderPolicy struct {
+	types.ProviderPolicyMsg
+	Epoch   uint64
+	GasUsed uint64
+}
+
+// gasUsedAt returns the gas paid by the provider in the epoch
+func (p *ProviderPolicy) gasUsedAt(epoch uint64) uint64 {
+	if p.Epoch != epoch {
+		return 0
+	}
+	return p.GasUsed
+}
+
+// check returns an error if the provider is not allowed to pay gas for a transaction from the sender with the data
+func (p *ProviderPolicy) check(from common.Address, data []byte, gas uint64, epoch uint64) error {
+	if p.MaxGasPerTx != 0 && gas > p.MaxGasPerTx {
+		return ErrProviderMaxGasExceeded
+	}
+	if p.EpochGasBudget != 0 {
+		used := p.gasUsedAt(epoch)
+		if used > p.EpochGasBudget || gas > p.EpochGasBudget-used {
+			return ErrProviderBudgetExceeded
+		}
+	}
+	if len(p.AllowedSenders) != 0 && !from.InList(p.AllowedSenders) {
+		return ErrProviderSenderNotAllowed
+	}
+	if len(p.AllowedMethods) != 0 {
+		if len(data) < 4 {
+			return ErrProviderMethodNotAllowed
+		}
+		for _, method := range p.AllowedMethods {
+			if bytes.Equal(method[:], data[:4]) {
+				return nil
+			}
+		}
+		return ErrProviderMethodNotAllowed
+	}
+	return nil
+}
+
+// providerPolicy returns the index of the policy of the provider, -1 if the provider has no policy
+func (s *stateObject) providerPolicy(provider common.Address) int {
+	policies := s.enterprise().ProviderPolicies
+	for i := range policies {
+		if policies[i].Provider == provider {
+			return i
+		}
+	}
+	return -1
+}
+
+// ProviderPolicy returns a copy of the policy of the provider, nil if the provider has no policy
+func (s *stateObject) ProviderPolicy(provider common.Address) *ProviderPolicy {
+	index := s.providerPolicy(provider)
+	if index == -1 {
+		return nil
+	}
+	policy := s.enterprise().ProviderPolicies[index]
+	return &policy
+}
+
+// SetProviderPolicy assumes that the permission for set provider policy here is valid, an empty policy removes it
+func (s *stateObject) SetProviderPolicy(msg types.ProviderPolicyMsg) error {
+	if !msg.Provider.InList(s.data.ProviderAddresses) {
+		return ErrProviderNotFound
+	}
+	index := s.providerPolicy(msg.Provider)
+	policy := ProviderPolicy{ProviderPolicyMsg: msg}
+	if index != -1 {
+		// keep the usage of the current epoch so the owner can not reset the budget by setting the policy again
+		prev := s.enterprise().ProviderPolicies[index]
+		policy.Epoch, policy.GasUsed = prev.Epoch, prev.GasUsed
+	}
+	s.updateProviderPolicy(index, policy, msg.IsEmpty())
+	return nil
+}
+
+// UseProviderGas checks the policy of the provider and records the gas it pays for
+func (s *stateObject) UseProviderGas(provider common.Address, from common.Address, data []byte, gas uint64, epoch uint64) error {
+	index := s.providerPolicy(provider)
+	if index == -1 {
+		return nil
+	}
+	policy := s.enterprise().ProviderPolicies[index]
+	if err := policy.check(from, data, gas, epoch); err != nil {
+		return err
+	}
+	if policy.EpochGasBudget == 0 {
+		return nil
+	}
+	policy.GasUsed = policy.gasUsedAt(epoch) + gas
+	policy.Epoch = epoch
+	s.updateProviderPolicy(index, policy, false)
+	return nil
+}
+
+// RefundProviderGas gives back the gas which is not used by the transaction to the epoch gas budget of the provider
+func (s *stateObject) RefundProviderGas(provider common.Address, gas uint64, epoch uint64) {
+	index := s.providerPolicy(provider)
+	if index == -1 || gas == 0 {
+		return
+	}
+	policy := s.enterprise().ProviderPolicies[index]
+	if policy.EpochGasBudget == 0 || policy.Epoch != epoch {
+		return
+	}
+	if gas > policy.GasUsed {
+		gas = policy.GasUsed
+	}
+	policy.GasUsed -= gas
+	s.updateProviderPolicy(index, policy, false)
+}
+
+// updateProviderPolicy replaces the policy at index (appends it if index is -1) or removes it,
+// the policies are copied as the previous ones are kept in the journal
+func (s *stateObject) updateProviderPolicy(index int, policy ProviderPolicy, remove bool) {
+	var (
+		enterprise = s.enterprise()
+		policies   []ProviderPolicy
+	)
+	switch {
+	case index == -1 && remove:
+		return
+	case index == -1:
+		policies = append(policies, enterprise.ProviderPolicies...)
+		policies = append(policies, policy)
+	case remove:
+		policies = append(policies, enterprise.ProviderPolicies[:index]...)
+		policies = append(policies, enterprise.ProviderPolicies[index+1:]...)
+	default:
+		policies = append(policies, enterprise.ProviderPolicies...)
+		policies[index] = policy
+	}
+	enterprise.ProviderPolicies = policies
+	s.SetEnterprise(enterprise)
+}
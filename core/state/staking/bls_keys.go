@@ -0,0 +1,52 @@
+package staking
+
+import (
+	"math/big"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/core/state"
+	"github.com/Evrynetlabs/evrynet-node/crypto"
+)
+
+// blsPublicKeyPrefix is hashed with a candidate address to get the first storage slot of its BLS public key.
+// The key is stored in consecutive slots, it is not used by the contract itself but by the consensus engine only.
+var blsPublicKeyPrefix = []byte("evrynet-staking-bls-public-key")
+
+// blsPublicKeySlots is the number of slots to store a BLS public key
+const blsPublicKeySlots = 4
+
+// SetBLSPublicKey registers the marshaled BLS public key of the candidate, the key must be 128 bytes long.
+func SetBLSPublicKey(stateDB *state.StateDB, scAddress common.Address, candidate common.Address, key []byte) {
+	loc := blsPublicKeyLoc(candidate)
+	for i := 0; i < blsPublicKeySlots; i++ {
+		var slot common.Hash
+		if len(key) >= (i+1)*common.HashLength {
+			slot = common.BytesToHash(key[i*common.HashLength : (i+1)*common.HashLength])
+		}
+		stateDB.SetState(scAddress, addOffsetToLoc(loc, big.NewInt(int64(i))), slot)
+	}
+}
+
+// GetBLSPublicKey returns the registered BLS public key of the candidate, nil if the candidate has not registered one.
+func GetBLSPublicKey(stateDB *state.StateDB, scAddress common.Address, candidate common.Address) []byte {
+	var (
+		loc   = blsPublicKeyLoc(candidate)
+		key   = make([]byte, 0, blsPublicKeySlots*common.HashLength)
+		empty = true
+	)
+	for i := 0; i < blsPublicKeySlots; i++ {
+		slot := stateDB.GetState(scAddress, addOffsetToLoc(loc, big.NewInt(int64(i))))
+		if slot != (common.Hash{}) {
+			empty = false
+		}
+		key = append(key, slot.Bytes()...)
+	}
+	if empty {
+		return nil
+	}
+	return key
+}
+
+func blsPublicKeyLoc(candidate common.Address) common.Hash {
+	return crypto.Keccak256Hash(blsPublicKeyPrefix, candidate.Bytes())
+}
@@ -0,0 +1,28 @@
+package staking
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/core/rawdb"
+	"github.com/Evrynetlabs/evrynet-node/core/state"
+)
+
+func TestBLSPublicKey(t *testing.T) {
+	var (
+		scAddress = common.HexToAddress("0x1000")
+		candidate = common.HexToAddress("0x2000")
+		key       = bytes.Repeat([]byte{0x01, 0x02}, 64)
+	)
+	stateDB, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	require.NoError(t, err)
+
+	assert.Nil(t, GetBLSPublicKey(stateDB, scAddress, candidate))
+	SetBLSPublicKey(stateDB, scAddress, candidate, key)
+	assert.Equal(t, key, GetBLSPublicKey(stateDB, scAddress, candidate))
+	assert.Nil(t, GetBLSPublicKey(stateDB, scAddress, common.HexToAddress("0x3000")))
+}
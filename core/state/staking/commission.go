@@ -0,0 +1,39 @@
+package staking
+
+import (
+	"math/big"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/core/state"
+	"github.com/Evrynetlabs/evrynet-node/crypto"
+)
+
+var (
+	// commissionRatePrefix is hashed with a candidate address to get the storage slot of its commission rate.
+	// The slot is not used by the contract itself but by the consensus engine only.
+	commissionRatePrefix = []byte("evrynet-staking-commission-rate")
+
+	// CommissionAddress is the recipient of the commission transactions. A transaction sent to this address by the owner
+	// of a candidate, with the candidate address followed by the 32 bytes commission rate as data, sets the commission rate.
+	CommissionAddress = common.HexToAddress("0x00000000000000000000000000000000636f6d6d")
+)
+
+// SetCommissionRate sets the percentage of the rewards of the candidate kept by its owner.
+func SetCommissionRate(stateDB *state.StateDB, scAddress common.Address, candidate common.Address, rate uint64) {
+	// the rate is stored plus one, so that an empty slot means the rate is not set
+	stateDB.SetState(scAddress, commissionRateLoc(candidate), common.BigToHash(new(big.Int).SetUint64(rate+1)))
+}
+
+// GetCommissionRate returns the commission rate of the candidate, nil if the candidate has not set one.
+func GetCommissionRate(stateDB *state.StateDB, scAddress common.Address, candidate common.Address) *uint64 {
+	value := stateDB.GetState(scAddress, commissionRateLoc(candidate)).Big().Uint64()
+	if value == 0 {
+		return nil
+	}
+	rate := value - 1
+	return &rate
+}
+
+func commissionRateLoc(candidate common.Address) common.Hash {
+	return crypto.Keccak256Hash(commissionRatePrefix, candidate.Bytes())
+}
@@ -0,0 +1,39 @@
+package staking
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/core/rawdb"
+	"github.com/Evrynetlabs/evrynet-node/core/state"
+)
+
+func TestCommissionRate(t *testing.T) {
+	var (
+		scAddress = common.HexToAddress("0x1000")
+		candidate = common.HexToAddress("0x2000")
+	)
+	stateDB, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	require.NoError(t, err)
+	setCandidateStakes(stateDB, DefaultConfig, scAddress, candidate, map[common.Address]*big.Int{candidate: big.NewInt(1000)})
+
+	assert.Nil(t, GetCommissionRate(stateDB, scAddress, candidate))
+	data, err := NewStateDbStakingCaller(stateDB, DefaultConfig).GetValidatorsData(scAddress, []common.Address{candidate})
+	require.NoError(t, err)
+	assert.Nil(t, data[candidate].CommissionRate)
+
+	// a zero rate is distinguished from an unset one
+	SetCommissionRate(stateDB, scAddress, candidate, 0)
+	require.NotNil(t, GetCommissionRate(stateDB, scAddress, candidate))
+	assert.Equal(t, uint64(0), *GetCommissionRate(stateDB, scAddress, candidate))
+
+	SetCommissionRate(stateDB, scAddress, candidate, 15)
+	data, err = NewStateDbStakingCaller(stateDB, DefaultConfig).GetValidatorsData(scAddress, []common.Address{candidate})
+	require.NoError(t, err)
+	require.NotNil(t, data[candidate].CommissionRate)
+	assert.Equal(t, uint64(15), *data[candidate].CommissionRate)
+}
@@ -86,7 +86,7 @@ func (caller *evmStakingCaller) GetValidators(scAddress common.Address) ([]commo
 	return candidatesArr[:int(data.ValidatorSize.Int64())], err
 }
 
-// GetValidatorsData return information of validators including owner, totalStake and voterStakes
+// GetValidatorsData return information of validators including owner, totalStake, voterStakes and commission rate
 func (caller *evmStakingCaller) GetValidatorsData(scAddress common.Address, candidates []common.Address) (map[common.Address]CandidateData, error) {
 	sc, err := staking_contracts.NewStakingContractsCaller(scAddress, caller)
 	if err != nil {
@@ -118,6 +118,8 @@ func (caller *evmStakingCaller) GetValidatorsData(scAddress common.Address, cand
 			VoterStakes: voteStakes,
 			Owner:       candidateData.Owner,
 			TotalStake:  candidateData.TotalStake,
+			// the commission rate is not stored by the contract itself, so it has no getter
+			CommissionRate: GetCommissionRate(caller.stateDB, scAddress, candidate),
 		}
 	}
 	return allVoterStake, nil
@@ -192,6 +194,8 @@ func (m callmsg) Data() []byte                  { return m.CallMsg.Data }
 func (m callmsg) TxType() types.TransactionType { return types.NormalTxType }
 func (m callmsg) ExtraData() interface{}        { return nil }
 func (m callmsg) HasProviderSignature() bool    { return false }
+func (m callmsg) ProviderValidUntil() uint64    { return 0 }
+func (m callmsg) ProviderMaxFee() *big.Int      { return nil }
 
 type chainContextWrapper struct {
 	engine      consensus.Engine
@@ -0,0 +1,275 @@
+package staking
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+
+	"github.com/pkg/errors"
+
+	"github.com/Evrynetlabs/evrynet-node/accounts/abi/bind"
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/consensus/staking_contracts"
+	"github.com/Evrynetlabs/evrynet-node/core"
+	"github.com/Evrynetlabs/evrynet-node/core/state"
+	"github.com/Evrynetlabs/evrynet-node/core/types"
+	"github.com/Evrynetlabs/evrynet-node/params"
+)
+
+// StakingContractName is the name of the staking contract in the solc output
+const StakingContractName = "EvrynetStaking"
+
+var (
+	// ErrStakingLayoutNotFound is returned if the solc output has no storage layout for the staking contract
+	ErrStakingLayoutNotFound = errors.New("storage layout of the staking contract not found")
+	// ErrStakingLayoutMismatch is returned if the state read with the index configs differs from the one returned by the contract
+	ErrStakingLayoutMismatch = errors.New("staking layout does not match the staking contract")
+)
+
+// storageLayout is the storage layout of a contract as output by solc with the storageLayout output selection
+type storageLayout struct {
+	Storage []storageVariable      `json:"storage"`
+	Types   map[string]storageType `json:"types"`
+}
+
+type storageVariable struct {
+	Label  string `json:"label"`
+	Offset uint16 `json:"offset"`
+	Slot   uint64 `json:"slot,string"`
+	Type   string `json:"type"`
+}
+
+type storageType struct {
+	Encoding string            `json:"encoding"`
+	Label    string            `json:"label"`
+	Value    string            `json:"value"`
+	Members  []storageVariable `json:"members"`
+}
+
+// LoadIndexConfigs builds the index configs from the solc storage layout file of the staking contract
+func LoadIndexConfigs(path string) (*IndexConfigs, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewIndexConfigsFromStorageLayout(data)
+}
+
+// NewIndexConfigsFromStorageLayout builds the index configs from the solc storage layout of the staking contract.
+// The data is either the standard JSON output of solc, the output of the staking contract or its storageLayout field.
+func NewIndexConfigsFromStorageLayout(data []byte) (*IndexConfigs, error) {
+	layout, err := findStorageLayout(data)
+	if err != nil {
+		return nil, err
+	}
+	variables := make(map[string]storageVariable, len(layout.Storage))
+	for _, variable := range layout.Storage {
+		variables[variable.Label] = variable
+	}
+	cfg := &IndexConfigs{}
+	for label, layOut := range map[string]*LayOut{
+		"withdrawsState":    &cfg.WithdrawsStateLayout,
+		"candidateVoters":   &cfg.CandidateVotersLayout,
+		"candidateData":     &cfg.CandidateDataLayout,
+		"candidates":        &cfg.CandidatesLayout,
+		"startBlock":        &cfg.StartBlockLayout,
+		"epochPeriod":       &cfg.EpochPeriodLayout,
+		"maxValidatorSize":  &cfg.MaxValidatorSizeLayout,
+		"minValidatorStake": &cfg.MinValidatorStakeLayout,
+		"minVoterCap":       &cfg.MinVoterCapLayout,
+		"admin":             &cfg.AdminLayout,
+	} {
+		variable, ok := variables[label]
+		if !ok {
+			return nil, errors.Errorf("missing state variable %q in staking layout", label)
+		}
+		*layOut = NewLayOut(variable.Slot, variable.Offset)
+	}
+	if err := layout.readMembers(variables["candidateData"], map[string]*LayOut{
+		"totalStake": &cfg.CandidateDataStruct.TotalStake,
+		"owner":      &cfg.CandidateDataStruct.Owner,
+		"voterStake": &cfg.CandidateDataStruct.VotersStakes,
+	}); err != nil {
+		return nil, err
+	}
+	if err := layout.readMembers(variables["withdrawsState"], map[string]*LayOut{
+		"caps":   &cfg.WithdrawStateStruct.Caps,
+		"epochs": &cfg.WithdrawStateStruct.Epochs,
+	}); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// readMembers reads the layout of the members of the struct stored by the variable, a mapping to a struct
+func (layout *storageLayout) readMembers(variable storageVariable, members map[string]*LayOut) error {
+	typ, ok := layout.Types[variable.Type]
+	if !ok {
+		return errors.Errorf("missing type %q in staking layout", variable.Type)
+	}
+	if typ.Encoding == "mapping" {
+		if typ, ok = layout.Types[typ.Value]; !ok {
+			return errors.Errorf("missing value type of %q in staking layout", variable.Label)
+		}
+	}
+	for label, layOut := range members {
+		found := false
+		for _, member := range typ.Members {
+			if member.Label == label {
+				*layOut = NewLayOut(member.Slot, member.Offset)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errors.Errorf("missing member %q of %q in staking layout", label, variable.Label)
+		}
+	}
+	return nil
+}
+
+// findStorageLayout finds the storage layout of the staking contract in the solc output
+func findStorageLayout(data []byte) (*storageLayout, error) {
+	var output struct {
+		Contracts     map[string]map[string]json.RawMessage `json:"contracts"`
+		StorageLayout json.RawMessage                       `json:"storageLayout"`
+		Storage       json.RawMessage                       `json:"storage"`
+	}
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, err
+	}
+	switch {
+	case output.Storage != nil:
+		break
+	case output.StorageLayout != nil:
+		return findStorageLayout(output.StorageLayout)
+	default:
+		for _, contracts := range output.Contracts {
+			if contract, ok := contracts[StakingContractName]; ok {
+				return findStorageLayout(contract)
+			}
+		}
+		return nil, ErrStakingLayoutNotFound
+	}
+	layout := new(storageLayout)
+	if err := json.Unmarshal(data, layout); err != nil {
+		return nil, err
+	}
+	return layout, nil
+}
+
+// VerifyIndexConfigs cross-checks the state of the staking contract read from the state DB with the index configs
+// against the one returned by the contract through the EVM, so a layout which does not match the deployed contract
+// is detected before it is used to elect the validators.
+func VerifyIndexConfigs(stateDB *state.StateDB, chainContext core.ChainContext, header *types.Header,
+	chainConfig *params.ChainConfig, scAddress common.Address, cfg *IndexConfigs) error {
+	evmCaller := &evmStakingCaller{
+		stateDB:      stateDB,
+		chainContext: chainContext,
+		blockNumber:  header.Number,
+		header:       header,
+		chainConfig:  chainConfig,
+	}
+	sc, err := staking_contracts.NewStakingContractsCaller(scAddress, evmCaller)
+	if err != nil {
+		return err
+	}
+	var (
+		caller   = NewStateDbStakingCaller(stateDB, cfg)
+		opts     = &bind.CallOpts{}
+		mismatch = func(field string, args ...interface{}) error {
+			return errors.Wrapf(ErrStakingLayoutMismatch, field, args...)
+		}
+	)
+	data, err := sc.GetListCandidates(opts)
+	if err != nil {
+		return err
+	}
+	candidates, err := caller.GetCandidates(scAddress)
+	if err != nil && err != ErrEmptyValidatorSet {
+		return err
+	}
+	if len(candidates) != len(data.Candidates) {
+		return mismatch("candidates")
+	}
+	if !equalBig(caller.GetMaxValidatorSize(scAddress), data.ValidatorSize) {
+		return mismatch("maxValidatorSize")
+	}
+	if !equalBig(caller.GetMinValidatorStake(scAddress), data.MinValidatorCap) {
+		return mismatch("minValidatorStake")
+	}
+	for name, get := range map[string]struct {
+		evm   func(*bind.CallOpts) (*big.Int, error)
+		state func(common.Address) *big.Int
+	}{
+		"startBlock":  {sc.StartBlock, caller.GetStartBlock},
+		"epochPeriod": {sc.EpochPeriod, caller.GetEpochPeriod},
+		"minVoterCap": {sc.MinVoterCap, caller.GetMinVoterCap},
+	} {
+		expected, err := get.evm(opts)
+		if err != nil {
+			return err
+		}
+		if !equalBig(get.state(scAddress), expected) {
+			return mismatch(name)
+		}
+	}
+	admin, err := sc.Admin(opts)
+	if err != nil {
+		return err
+	}
+	if caller.GetAdmin(scAddress) != admin {
+		return mismatch("admin")
+	}
+
+	for i, candidate := range data.Candidates {
+		if candidates[i] != candidate {
+			return mismatch("candidates[%d]", i)
+		}
+		candidateData := caller.GetCandidateData(scAddress, candidate)
+		if !equalBig(candidateData.TotalStake, data.Stakes[i]) {
+			return mismatch("totalStake of %s", candidate.Hex())
+		}
+		owner, err := sc.GetCandidateOwner(opts, candidate)
+		if err != nil {
+			return err
+		}
+		if candidateData.Owner != owner {
+			return mismatch("owner of %s", candidate.Hex())
+		}
+		voters, err := sc.GetVoters(opts, candidate)
+		if err != nil {
+			return err
+		}
+		stakes, err := sc.GetVoterStakes(opts, candidate, voters)
+		if err != nil {
+			return err
+		}
+		if len(voters) != len(candidateData.VoterStakes) {
+			return mismatch("voters of %s", candidate.Hex())
+		}
+		for j, voter := range voters {
+			if stake, ok := candidateData.VoterStakes[voter]; !ok || !equalBig(stake, stakes[j]) {
+				return mismatch("voterStake of %s for %s", voter.Hex(), candidate.Hex())
+			}
+		}
+		withdraws, err := sc.GetWithdrawEpochsAndCaps(&bind.CallOpts{From: owner})
+		if err != nil {
+			return err
+		}
+		epochs := caller.GetWithdrawEpochs(scAddress, owner)
+		if len(epochs) != len(withdraws.Epochs) {
+			return mismatch("withdraw epochs of %s", owner.Hex())
+		}
+		for j, epoch := range epochs {
+			if !equalBig(epoch, withdraws.Epochs[j]) || !equalBig(caller.GetWithdrawCap(scAddress, owner, epoch), withdraws.Caps[j]) {
+				return mismatch("withdraw caps of %s", owner.Hex())
+			}
+		}
+	}
+	return nil
+}
+
+func equalBig(a, b *big.Int) bool {
+	return a.Cmp(b) == 0
+}
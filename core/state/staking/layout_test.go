@@ -0,0 +1,89 @@
+package staking_test
+
+import (
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/Evrynetlabs/evrynet-node/accounts/abi"
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/consensus/ethash"
+	"github.com/Evrynetlabs/evrynet-node/consensus/staking_contracts"
+	"github.com/Evrynetlabs/evrynet-node/core"
+	"github.com/Evrynetlabs/evrynet-node/core/rawdb"
+	"github.com/Evrynetlabs/evrynet-node/core/state"
+	"github.com/Evrynetlabs/evrynet-node/core/state/staking"
+	"github.com/Evrynetlabs/evrynet-node/core/types"
+	"github.com/Evrynetlabs/evrynet-node/core/vm"
+	"github.com/Evrynetlabs/evrynet-node/params"
+)
+
+func TestNewIndexConfigsFromStorageLayout(t *testing.T) {
+	data, err := ioutil.ReadFile(storageLayoutPath)
+	require.NoError(t, err)
+
+	// the standard solc output, the contract output and the storage layout itself are accepted
+	cfg, err := staking.NewIndexConfigsFromStorageLayout(data)
+	require.NoError(t, err)
+	require.Equal(t, staking.DefaultConfig, cfg)
+	for _, path := range []string{`contracts.\./EvrynetStaking\.sol.EvrynetStaking`, gjsonPath} {
+		cfg, err = staking.NewIndexConfigsFromStorageLayout([]byte(gjson.GetBytes(data, path).Raw))
+		require.NoError(t, err)
+		require.Equal(t, staking.DefaultConfig, cfg)
+	}
+
+	// a recompiled contract with another field order
+	reordered := strings.Replace(string(data), `"label":"startBlock","offset":0,"slot":"5"`, `"label":"startBlock","offset":0,"slot":"11"`, 1)
+	reordered = strings.Replace(reordered, `"label":"owner","offset":0,"slot":"2"`, `"label":"owner","offset":0,"slot":"4"`, 1)
+	cfg, err = staking.NewIndexConfigsFromStorageLayout([]byte(reordered))
+	require.NoError(t, err)
+	require.Equal(t, uint64(11), cfg.StartBlockLayout.Slot)
+	require.Equal(t, uint64(4), cfg.CandidateDataStruct.Owner.Slot)
+
+	_, err = staking.NewIndexConfigsFromStorageLayout([]byte(strings.Replace(string(data), `"label":"admin"`, `"label":"owner"`, 1)))
+	require.Error(t, err)
+	_, err = staking.NewIndexConfigsFromStorageLayout([]byte(`{"contracts": {}}`))
+	require.Equal(t, staking.ErrStakingLayoutNotFound, err)
+}
+
+func TestVerifyIndexConfigs(t *testing.T) {
+	var (
+		a          = common.HexToAddress("0x1000")
+		b          = common.HexToAddress("0x2000")
+		deployer   = common.HexToAddress("0x3000")
+		candidates = []common.Address{a, b}
+		header     = &types.Header{Number: big.NewInt(1), GasLimit: gasLimit, Difficulty: common.Big1, Time: 1}
+		config     = params.TestChainConfig
+	)
+	stateDB, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	require.NoError(t, err)
+
+	parsed, err := abi.JSON(strings.NewReader(staking_contracts.StakingContractsABI))
+	require.NoError(t, err)
+	input, err := parsed.Pack("", candidates, candidates, big.NewInt(40), big.NewInt(1), big.NewInt(100),
+		big.NewInt(20), big.NewInt(10), deployer)
+	require.NoError(t, err)
+	chainContext := staking.NewChainContextWrapper(ethash.NewFaker(), func(common.Hash, uint64) *types.Header { return nil })
+	evm := vm.NewEVM(core.NewEVMContext(types.NewMessage(deployer, nil, 0, new(big.Int), gasLimit, new(big.Int), nil, false),
+		header, chainContext, &common.Address{}), stateDB, config, vm.Config{})
+	code := append(common.FromHex(staking_contracts.StakingContractsBin), input...)
+	_, scAddress, _, err := evm.Create(vm.AccountRef(deployer), code, gasLimit, new(big.Int))
+	require.NoError(t, err)
+
+	require.NoError(t, staking.VerifyIndexConfigs(stateDB, chainContext, header, config, scAddress, staking.DefaultConfig))
+
+	wrong := *staking.DefaultConfig
+	wrong.StartBlockLayout, wrong.EpochPeriodLayout = wrong.EpochPeriodLayout, wrong.StartBlockLayout
+	err = staking.VerifyIndexConfigs(stateDB, chainContext, header, config, scAddress, &wrong)
+	require.Equal(t, staking.ErrStakingLayoutMismatch, errors.Cause(err))
+
+	wrong = *staking.DefaultConfig
+	wrong.CandidateDataStruct.Owner = staking.NewLayOut(0, 0)
+	err = staking.VerifyIndexConfigs(stateDB, chainContext, header, config, scAddress, &wrong)
+	require.Equal(t, staking.ErrStakingLayoutMismatch, errors.Cause(err))
+}
@@ -0,0 +1,110 @@
+package staking
+
+import (
+	"math/big"
+
+	"github.com/pkg/errors"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/core/state"
+	"github.com/Evrynetlabs/evrynet-node/crypto"
+)
+
+var (
+	// missedBlocksPrefix, missedCountPrefix and downtimeJailedPrefix are hashed with a candidate address to get
+	// a storage slot of the staking contract. These slots are not used by the contract itself but by the consensus engine only.
+	missedBlocksPrefix   = []byte("evrynet-staking-missed-blocks")
+	missedCountPrefix    = []byte("evrynet-staking-missed-count")
+	downtimeJailedPrefix = []byte("evrynet-staking-downtime-jailed")
+
+	// UnjailAddress is the recipient of the unjail transactions. A transaction sent to this address by a candidate,
+	// or by its owner with the candidate address as data, releases the candidate jailed for downtime once its jail period is over.
+	UnjailAddress = common.HexToAddress("0x00000000000000000000000000000000756e6a61")
+
+	// ErrNotJailedForDowntime is returned if an unjailed candidate or a candidate jailed for double signing is unjailed
+	ErrNotJailedForDowntime = errors.New("candidate is not jailed for downtime")
+	// ErrJailPeriodNotOver is returned if the candidate is unjailed before the end of its jail period
+	ErrJailPeriodNotOver = errors.New("jail period is not over")
+)
+
+// bitsPerSlot is the number of blocks whose signing is recorded in a storage slot
+const bitsPerSlot = 256
+
+// RecordSigning records whether the validator signed the block number in its sliding window of signed blocks.
+// It returns the number of blocks missed by the validator in the window.
+func RecordSigning(stateDB *state.StateDB, scAddress common.Address, validator common.Address, number uint64, window uint64, signed bool) uint64 {
+	var (
+		index   = number % window
+		slotLoc = addOffsetToLoc(missedBlocksLoc(validator), new(big.Int).SetUint64(index/bitsPerSlot))
+		bitmap  = stateDB.GetState(scAddress, slotLoc).Big()
+		bit     = uint(index % bitsPerSlot)
+		missed  = MissedBlocks(stateDB, scAddress, validator)
+	)
+	switch {
+	case !signed && bitmap.Bit(int(bit)) == 0:
+		bitmap.SetBit(bitmap, int(bit), 1)
+		missed++
+	case signed && bitmap.Bit(int(bit)) == 1:
+		bitmap.SetBit(bitmap, int(bit), 0)
+		missed--
+	default:
+		return missed
+	}
+	stateDB.SetState(scAddress, slotLoc, common.BigToHash(bitmap))
+	stateDB.SetState(scAddress, missedCountLoc(validator), common.BigToHash(new(big.Int).SetUint64(missed)))
+	return missed
+}
+
+// MissedBlocks returns the number of blocks missed by the validator in its sliding window of signed blocks.
+func MissedBlocks(stateDB *state.StateDB, scAddress common.Address, validator common.Address) uint64 {
+	return stateDB.GetState(scAddress, missedCountLoc(validator)).Big().Uint64()
+}
+
+// ResetSigning clears the sliding window of signed blocks of the validator.
+func ResetSigning(stateDB *state.StateDB, scAddress common.Address, validator common.Address, window uint64) {
+	loc := missedBlocksLoc(validator)
+	for i := uint64(0); i*bitsPerSlot < window; i++ {
+		slotLoc := addOffsetToLoc(loc, new(big.Int).SetUint64(i))
+		if stateDB.GetState(scAddress, slotLoc) != (common.Hash{}) {
+			stateDB.SetState(scAddress, slotLoc, common.Hash{})
+		}
+	}
+	stateDB.SetState(scAddress, missedCountLoc(validator), common.Hash{})
+}
+
+// JailForDowntime excludes the candidate from the validator set until it is unjailed by an unjail transaction,
+// which is accepted from the given block number.
+func JailForDowntime(stateDB *state.StateDB, scAddress common.Address, candidate common.Address, until uint64) {
+	Jail(stateDB, scAddress, candidate, until)
+	stateDB.SetState(scAddress, downtimeJailedLoc(candidate), common.BigToHash(big.NewInt(1)))
+}
+
+// IsJailedForDowntime returns true if the candidate is jailed for downtime and has not been unjailed yet.
+func IsJailedForDowntime(stateDB *state.StateDB, scAddress common.Address, candidate common.Address) bool {
+	return stateDB.GetState(scAddress, downtimeJailedLoc(candidate)) != (common.Hash{})
+}
+
+// Unjail releases the candidate jailed for downtime if its jail period is over at the given block number.
+func Unjail(stateDB *state.StateDB, scAddress common.Address, candidate common.Address, number uint64) error {
+	if !IsJailedForDowntime(stateDB, scAddress, candidate) || JailedUntil(stateDB, scAddress, candidate) == Tombstoned {
+		return ErrNotJailedForDowntime
+	}
+	if JailedUntil(stateDB, scAddress, candidate) > number {
+		return ErrJailPeriodNotOver
+	}
+	stateDB.SetState(scAddress, jailedUntilLoc(candidate), common.Hash{})
+	stateDB.SetState(scAddress, downtimeJailedLoc(candidate), common.Hash{})
+	return nil
+}
+
+func missedBlocksLoc(validator common.Address) common.Hash {
+	return crypto.Keccak256Hash(missedBlocksPrefix, validator.Bytes())
+}
+
+func missedCountLoc(validator common.Address) common.Hash {
+	return crypto.Keccak256Hash(missedCountPrefix, validator.Bytes())
+}
+
+func downtimeJailedLoc(candidate common.Address) common.Hash {
+	return crypto.Keccak256Hash(downtimeJailedPrefix, candidate.Bytes())
+}
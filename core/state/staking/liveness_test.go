@@ -0,0 +1,65 @@
+package staking
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/core/rawdb"
+	"github.com/Evrynetlabs/evrynet-node/core/state"
+)
+
+func TestRecordSigning(t *testing.T) {
+	var (
+		scAddress = common.HexToAddress("0x1000")
+		validator = common.HexToAddress("0x2000")
+		window    = uint64(300)
+	)
+	stateDB, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	require.NoError(t, err)
+
+	// miss every other block of the first window, the bits span 2 slots
+	for number := uint64(0); number < window; number++ {
+		RecordSigning(stateDB, scAddress, validator, number, window, number%2 == 0)
+	}
+	assert.Equal(t, window/2, MissedBlocks(stateDB, scAddress, validator))
+
+	// signing a block of the next window clears the missed block it replaces
+	assert.Equal(t, window/2-1, RecordSigning(stateDB, scAddress, validator, window+1, window, true))
+	assert.Equal(t, window/2-1, RecordSigning(stateDB, scAddress, validator, window+2, window, true))
+	assert.Equal(t, window/2, RecordSigning(stateDB, scAddress, validator, window+4, window, false))
+
+	ResetSigning(stateDB, scAddress, validator, window)
+	assert.Equal(t, uint64(0), MissedBlocks(stateDB, scAddress, validator))
+	assert.Equal(t, uint64(1), RecordSigning(stateDB, scAddress, validator, window+5, window, false))
+}
+
+func TestJailForDowntime(t *testing.T) {
+	var (
+		scAddress = common.HexToAddress("0x1000")
+		candidate = common.HexToAddress("0x2000")
+		offender  = common.HexToAddress("0x3000")
+	)
+	stateDB, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	require.NoError(t, err)
+
+	assert.Equal(t, ErrNotJailedForDowntime, Unjail(stateDB, scAddress, candidate, 10))
+
+	JailForDowntime(stateDB, scAddress, candidate, 100)
+	assert.True(t, IsJailed(stateDB, scAddress, candidate, 10))
+	assert.Equal(t, ErrJailPeriodNotOver, Unjail(stateDB, scAddress, candidate, 99))
+
+	// the candidate stays jailed after its jail period until it is unjailed
+	assert.True(t, IsJailed(stateDB, scAddress, candidate, 200))
+	require.NoError(t, Unjail(stateDB, scAddress, candidate, 100))
+	assert.False(t, IsJailed(stateDB, scAddress, candidate, 200))
+	assert.False(t, IsJailedForDowntime(stateDB, scAddress, candidate))
+
+	// a tombstoned candidate can not be unjailed
+	JailForDowntime(stateDB, scAddress, offender, 100)
+	Jail(stateDB, scAddress, offender, Tombstoned)
+	assert.Equal(t, ErrNotJailedForDowntime, Unjail(stateDB, scAddress, offender, 200))
+	assert.True(t, IsJailed(stateDB, scAddress, offender, 200))
+}
@@ -0,0 +1,86 @@
+package staking
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/core/state"
+	"github.com/Evrynetlabs/evrynet-node/crypto"
+)
+
+// Tombstoned is the jailedUntil value of a candidate who is jailed forever, i.e, for double signing
+const Tombstoned uint64 = math.MaxUint64
+
+var (
+	// jailedUntilPrefix and slashedOffencePrefix are hashed with a key to get a storage slot of the staking contract.
+	// These slots are not used by the contract itself but by the consensus engine only.
+	jailedUntilPrefix    = []byte("evrynet-staking-jailed-until")
+	slashedOffencePrefix = []byte("evrynet-staking-slashed-offence")
+)
+
+// Slash burns a percentage of the stakes of the candidate and all its voters.
+// It returns the total amount slashed, which is removed from the staking contract's balance.
+func Slash(stateDB *state.StateDB, cfg *IndexConfigs, scAddress common.Address, candidate common.Address, percentage uint64) *big.Int {
+	var (
+		c               = &stateDBStakingCaller{stateDB: stateDB, config: cfg}
+		loc             = getMappingElementLoc(cfg.CandidateDataLayout.slotHash(), candidate.Hash())
+		totalStakeLoc   = addOffsetToLoc(loc, new(big.Int).SetUint64(cfg.CandidateDataStruct.TotalStake.Slot))
+		voterStakesSlot = addOffsetToLoc(loc, new(big.Int).SetUint64(cfg.CandidateDataStruct.VotersStakes.Slot))
+		totalSlashed    = big.NewInt(0)
+	)
+	for _, voter := range c.GetVoters(scAddress, candidate) {
+		voterStakeLoc := getMappingElementLoc(voterStakesSlot, voter.Hash())
+		stake := c.getBigInt(scAddress, voterStakeLoc)
+		slashed := new(big.Int).Div(new(big.Int).Mul(stake, new(big.Int).SetUint64(percentage)), big.NewInt(100))
+		stateDB.SetState(scAddress, voterStakeLoc, common.BigToHash(new(big.Int).Sub(stake, slashed)))
+		totalSlashed.Add(totalSlashed, slashed)
+	}
+	totalStake := c.getBigInt(scAddress, totalStakeLoc)
+	if totalSlashed.Cmp(totalStake) > 0 {
+		totalSlashed.Set(totalStake)
+	}
+	stateDB.SetState(scAddress, totalStakeLoc, common.BigToHash(new(big.Int).Sub(totalStake, totalSlashed)))
+	stateDB.SubBalance(scAddress, totalSlashed)
+	return totalSlashed
+}
+
+// Jail excludes the candidate from the validator set until the given block number.
+func Jail(stateDB *state.StateDB, scAddress common.Address, candidate common.Address, until uint64) {
+	stateDB.SetState(scAddress, jailedUntilLoc(candidate), common.BigToHash(new(big.Int).SetUint64(until)))
+}
+
+// JailedUntil returns the block number until which the candidate is jailed, 0 if it is not jailed.
+func JailedUntil(stateDB *state.StateDB, scAddress common.Address, candidate common.Address) uint64 {
+	return stateDB.GetState(scAddress, jailedUntilLoc(candidate)).Big().Uint64()
+}
+
+// IsJailed returns true if the candidate is jailed at the given block number.
+// A candidate jailed for downtime stays jailed until it is unjailed by an unjail transaction.
+func IsJailed(stateDB *state.StateDB, scAddress common.Address, candidate common.Address, number uint64) bool {
+	return JailedUntil(stateDB, scAddress, candidate) > number || IsJailedForDowntime(stateDB, scAddress, candidate)
+}
+
+// IsTombstoned returns true if the candidate is jailed forever.
+func IsTombstoned(stateDB *state.StateDB, scAddress common.Address, candidate common.Address) bool {
+	return JailedUntil(stateDB, scAddress, candidate) == Tombstoned
+}
+
+// MarkOffenceSlashed records that the offence has been slashed so that it will not be slashed again,
+// whichever evidence proves it.
+func MarkOffenceSlashed(stateDB *state.StateDB, scAddress common.Address, offenceKey common.Hash) {
+	stateDB.SetState(scAddress, slashedOffenceLoc(offenceKey), common.BigToHash(big.NewInt(1)))
+}
+
+// IsOffenceSlashed returns true if the offence has been slashed.
+func IsOffenceSlashed(stateDB *state.StateDB, scAddress common.Address, offenceKey common.Hash) bool {
+	return stateDB.GetState(scAddress, slashedOffenceLoc(offenceKey)) != (common.Hash{})
+}
+
+func jailedUntilLoc(candidate common.Address) common.Hash {
+	return crypto.Keccak256Hash(jailedUntilPrefix, candidate.Bytes())
+}
+
+func slashedOffenceLoc(offenceKey common.Hash) common.Hash {
+	return crypto.Keccak256Hash(slashedOffencePrefix, offenceKey.Bytes())
+}
@@ -0,0 +1,70 @@
+package staking
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/core/rawdb"
+	"github.com/Evrynetlabs/evrynet-node/core/state"
+)
+
+// setCandidateStakes writes the voters and their stakes of a candidate to the staking contract storage
+func setCandidateStakes(stateDB *state.StateDB, cfg *IndexConfigs, scAddress, candidate common.Address, stakes map[common.Address]*big.Int) {
+	var (
+		votersSlot      = getMappingElementLoc(cfg.CandidateVotersLayout.slotHash(), candidate.Hash())
+		loc             = getMappingElementLoc(cfg.CandidateDataLayout.slotHash(), candidate.Hash())
+		voterStakesSlot = addOffsetToLoc(loc, new(big.Int).SetUint64(cfg.CandidateDataStruct.VotersStakes.Slot))
+		totalStake      = big.NewInt(0)
+		i               uint64
+	)
+	for voter, stake := range stakes {
+		stateDB.SetState(scAddress, getElementArrayLoc(votersSlot, i, defaultElementSize), voter.Hash())
+		stateDB.SetState(scAddress, getMappingElementLoc(voterStakesSlot, voter.Hash()), common.BigToHash(stake))
+		totalStake.Add(totalStake, stake)
+		i++
+	}
+	stateDB.SetState(scAddress, votersSlot, common.BigToHash(new(big.Int).SetUint64(i)))
+	stateDB.SetState(scAddress, addOffsetToLoc(loc, new(big.Int).SetUint64(cfg.CandidateDataStruct.TotalStake.Slot)), common.BigToHash(totalStake))
+	stateDB.AddBalance(scAddress, totalStake)
+}
+
+func TestSlashAndJail(t *testing.T) {
+	var (
+		scAddress = common.HexToAddress("0x1000")
+		candidate = common.HexToAddress("0x2000")
+		voter     = common.HexToAddress("0x3000")
+		other     = common.HexToAddress("0x4000")
+	)
+	stateDB, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	require.NoError(t, err)
+	setCandidateStakes(stateDB, DefaultConfig, scAddress, candidate, map[common.Address]*big.Int{
+		candidate: big.NewInt(1000),
+		voter:     big.NewInt(30),
+	})
+
+	slashed := Slash(stateDB, DefaultConfig, scAddress, candidate, 10)
+	assert.Equal(t, big.NewInt(103), slashed)
+	assert.Equal(t, big.NewInt(927), stateDB.GetBalance(scAddress))
+
+	data, err := NewStateDbStakingCaller(stateDB, DefaultConfig).GetValidatorsData(scAddress, []common.Address{candidate})
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(900), data[candidate].VoterStakes[candidate])
+	assert.Equal(t, big.NewInt(27), data[candidate].VoterStakes[voter])
+	assert.Equal(t, big.NewInt(927), data[candidate].TotalStake)
+
+	assert.False(t, IsJailed(stateDB, scAddress, candidate, 10))
+	Jail(stateDB, scAddress, candidate, Tombstoned)
+	assert.True(t, IsJailed(stateDB, scAddress, candidate, 10))
+	assert.True(t, IsTombstoned(stateDB, scAddress, candidate))
+	assert.False(t, IsJailed(stateDB, scAddress, other, 10))
+	assert.False(t, IsTombstoned(stateDB, scAddress, other))
+
+	offenceKey := common.HexToHash("0x01")
+	assert.False(t, IsOffenceSlashed(stateDB, scAddress, offenceKey))
+	MarkOffenceSlashed(stateDB, scAddress, offenceKey)
+	assert.True(t, IsOffenceSlashed(stateDB, scAddress, offenceKey))
+}
@@ -22,12 +22,36 @@ var (
 type StakingCaller interface {
 	// GetValidators returns list of validators, calculate from current stateDB
 	GetValidators(common.Address) ([]common.Address, error)
-	// GetValidatorsData return information of validators including owner, totalStake and voterStakes
+	// GetValidatorsData return information of validators including owner, totalStake, voterStakes and commission rate
 	GetValidatorsData(common.Address, []common.Address) (map[common.Address]CandidateData, error)
 }
 
+// StakingReader reads the state of the staking contract beyond the validators, for the RPC and the tools
+type StakingReader interface {
+	StakingCaller
+	// GetCandidates returns the list of the registered candidates
+	GetCandidates(common.Address) ([]common.Address, error)
+	// GetCandidateData returns the owner, the total stake, the voter stakes and the commission rate of a candidate
+	GetCandidateData(common.Address, common.Address) CandidateData
+	// GetVoters returns the voters of a candidate
+	GetVoters(common.Address, common.Address) []common.Address
+	// GetVoterStake returns the stake of a voter for a candidate
+	GetVoterStake(common.Address, common.Address, common.Address) *big.Int
+	// GetWithdrawEpochs returns the epochs from which the unvoted stakes of an address can be withdrawn
+	GetWithdrawEpochs(common.Address, common.Address) []*big.Int
+	// GetWithdrawCap returns the amount an address can withdraw from an epoch
+	GetWithdrawCap(common.Address, common.Address, *big.Int) *big.Int
+	GetStartBlock(common.Address) *big.Int
+	GetEpochPeriod(common.Address) *big.Int
+	GetMaxValidatorSize(common.Address) *big.Int
+	GetMinValidatorStake(common.Address) *big.Int
+	GetMinVoterCap(common.Address) *big.Int
+	GetAdmin(common.Address) common.Address
+}
+
 type CandidateData struct {
-	Owner       common.Address
-	VoterStakes map[common.Address]*big.Int
-	TotalStake  *big.Int
+	Owner          common.Address
+	VoterStakes    map[common.Address]*big.Int
+	TotalStake     *big.Int
+	CommissionRate *uint64 // The percentage of the rewards kept by the owner, nil if the candidate has not set one
 }
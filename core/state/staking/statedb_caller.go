@@ -24,7 +24,7 @@ type stateDBStakingCaller struct {
 }
 
 // NewStateDbStakingCaller return instance of StakingCaller which reads data directly from state DB
-func NewStateDbStakingCaller(state *state.StateDB, cfg *IndexConfigs) StakingCaller {
+func NewStateDbStakingCaller(state *state.StateDB, cfg *IndexConfigs) StakingReader {
 	return &stateDBStakingCaller{
 		stateDB: state,
 		config:  cfg,
@@ -88,7 +88,7 @@ func (c *stateDBStakingCaller) GetValidators(stakingContractAddr common.Address)
 	return candidates[:maxValSize], nil
 }
 
-// GetValidatorsData return information of validators including owner, totalStake and voterStakes
+// GetValidatorsData return information of validators including owner, totalStake, voterStakes and commission rate
 func (c *stateDBStakingCaller) GetValidatorsData(scAddress common.Address, candidates []common.Address) (map[common.Address]CandidateData, error) {
 	allVoterStake := make(map[common.Address]CandidateData)
 	for _, candidate := range candidates {
@@ -109,16 +109,14 @@ func (c *stateDBStakingCaller) GetCandidateData(stakingContractAddr common.Addre
 
 	voteStakes := make(map[common.Address]*big.Int)
 	for _, voter := range c.GetVoters(stakingContractAddr, candidate) {
-		voterStakesSlot := addOffsetToLoc(loc, new(big.Int).SetUint64(c.config.CandidateDataStruct.VotersStakes.Slot))
-		voterStakeSlot := getMappingElementLoc(voterStakesSlot, voter.Hash())
-		stake := c.getBigInt(stakingContractAddr, voterStakeSlot)
-		voteStakes[voter] = stake
+		voteStakes[voter] = c.GetVoterStake(stakingContractAddr, candidate, voter)
 	}
 
 	return CandidateData{
-		Owner:       owner,
-		TotalStake:  totalStake,
-		VoterStakes: voteStakes,
+		Owner:          owner,
+		TotalStake:     totalStake,
+		VoterStakes:    voteStakes,
+		CommissionRate: GetCommissionRate(c.stateDB, stakingContractAddr, candidate),
 	}
 }
 
@@ -133,6 +131,32 @@ func (c *stateDBStakingCaller) GetVoters(stakingAddr common.Address, candidate c
 	return voters
 }
 
+// GetVoterStake returns current stake of a voter for a candidate
+func (c *stateDBStakingCaller) GetVoterStake(stakingContractAddr common.Address, candidate common.Address, voter common.Address) *big.Int {
+	loc := getMappingElementLoc(c.config.CandidateDataLayout.slotHash(), candidate.Hash())
+	voterStakesSlot := addOffsetToLoc(loc, new(big.Int).SetUint64(c.config.CandidateDataStruct.VotersStakes.Slot))
+	return c.getBigInt(stakingContractAddr, getMappingElementLoc(voterStakesSlot, voter.Hash()))
+}
+
+// GetWithdrawEpochs returns the epochs from which the unvoted stakes of the staker can be withdrawn
+func (c *stateDBStakingCaller) GetWithdrawEpochs(scAddress common.Address, staker common.Address) []*big.Int {
+	loc := getMappingElementLoc(c.config.WithdrawsStateLayout.slotHash(), staker.Hash())
+	epochsSlot := addOffsetToLoc(loc, new(big.Int).SetUint64(c.config.WithdrawStateStruct.Epochs.Slot))
+	epochsLength := c.getBigInt(scAddress, epochsSlot).Uint64()
+	epochs := make([]*big.Int, 0, epochsLength)
+	for i := uint64(0); i < epochsLength; i++ {
+		epochs = append(epochs, c.getBigInt(scAddress, getElementArrayLoc(epochsSlot, i, defaultElementSize)))
+	}
+	return epochs
+}
+
+// GetWithdrawCap returns the amount the staker can withdraw from the epoch
+func (c *stateDBStakingCaller) GetWithdrawCap(scAddress common.Address, staker common.Address, epoch *big.Int) *big.Int {
+	loc := getMappingElementLoc(c.config.WithdrawsStateLayout.slotHash(), staker.Hash())
+	capsSlot := addOffsetToLoc(loc, new(big.Int).SetUint64(c.config.WithdrawStateStruct.Caps.Slot))
+	return c.getBigInt(scAddress, getMappingElementLoc(capsSlot, common.BigToHash(epoch)))
+}
+
 // GetCandidateStake returns current stake of a candidate
 func (c *stateDBStakingCaller) GetCandidateStake(scAddress common.Address, candidate common.Address) *big.Int {
 	loc := getMappingElementLoc(c.config.CandidateDataLayout.slotHash(), candidate.Hash())
@@ -30,6 +30,7 @@ type IndexConfigs struct {
 	AdminLayout             LayOut //10
 
 	CandidateDataStruct CandidateDataStructIndex
+	WithdrawStateStruct WithdrawStateStructIndex
 }
 
 // layout inside candidateData struct
@@ -39,6 +40,12 @@ type CandidateDataStructIndex struct {
 	VotersStakes LayOut
 }
 
+// layout inside withdrawState struct
+type WithdrawStateStructIndex struct {
+	Caps   LayOut
+	Epochs LayOut
+}
+
 // DefaultConfig represents he default configuration.
 var DefaultConfig = &IndexConfigs{
 	WithdrawsStateLayout:    NewLayOut(1, 0),
@@ -56,6 +63,10 @@ var DefaultConfig = &IndexConfigs{
 		Owner:        NewLayOut(2, 0),
 		VotersStakes: NewLayOut(3, 0),
 	},
+	WithdrawStateStruct: WithdrawStateStructIndex{
+		Caps:   NewLayOut(0, 0),
+		Epochs: NewLayOut(1, 0),
+	},
 }
 
 // NewLayOut returns new instance of a LayOut
@@ -33,6 +33,10 @@ const (
 	TotalStakeField     = "totalStake"
 	OwnerField          = "owner"
 	VoterStakeField     = "voterStake"
+
+	withdrawStructName = "struct EvrynetStaking.WithdrawState"
+	CapsField          = "caps"
+	EpochsField        = "epochs"
 )
 
 type variableConfig struct {
@@ -97,6 +101,18 @@ func TestDefaultConfig(t *testing.T) {
 
 	//test layout position inside struct
 	for _, structCfg := range storageLayout.StructConfigs {
+		if structCfg.Label == withdrawStructName {
+			for _, member := range structCfg.Members {
+				switch member.Label {
+				case CapsField:
+					require.Equal(t, staking.DefaultConfig.WithdrawStateStruct.Caps.Slot, member.Slot)
+					require.Equal(t, uint64(0), member.Offset)
+				case EpochsField:
+					require.Equal(t, staking.DefaultConfig.WithdrawStateStruct.Epochs.Slot, member.Slot)
+					require.Equal(t, uint64(0), member.Offset)
+				}
+			}
+		}
 		if structCfg.Label != candidateStructName {
 			continue
 		}
@@ -14,6 +14,9 @@ import (
 	"github.com/Evrynetlabs/evrynet-node/common"
 	"github.com/Evrynetlabs/evrynet-node/consensus/staking_contracts"
 	"github.com/Evrynetlabs/evrynet-node/core"
+	"github.com/Evrynetlabs/evrynet-node/core/rawdb"
+	"github.com/Evrynetlabs/evrynet-node/core/state"
+	"github.com/Evrynetlabs/evrynet-node/core/state/staking"
 	"github.com/Evrynetlabs/evrynet-node/crypto"
 )
 
@@ -79,3 +82,40 @@ func TestCheckIndex(t *testing.T) {
 	minVoteCapData := stateDB.GetState(scAddress, common.BigToHash(new(big.Int).SetUint64(9)))
 	assert.Equal(t, minVoteCapData.Big(), minVoteCap)
 }
+
+func TestStateDBStakingCaller_Withdraws(t *testing.T) {
+	var (
+		scAddress = common.HexToAddress("0x1000")
+		candidate = common.HexToAddress("0x2000")
+		voter     = common.HexToAddress("0x3000")
+		epochs    = []*big.Int{big.NewInt(3), big.NewInt(5)}
+		caps      = []*big.Int{big.NewInt(400), big.NewInt(100)}
+		slot      = func(n int64) []byte { return common.BigToHash(big.NewInt(n)).Bytes() }
+	)
+	stateDB, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	require.NoError(t, err)
+
+	// candidateData[candidate].voterStake[voter] = 600
+	candidateLoc := crypto.Keccak256Hash(candidate.Hash().Bytes(), slot(3)).Big()
+	voterStakesLoc := common.BigToHash(new(big.Int).Add(candidateLoc, big.NewInt(3)))
+	stateDB.SetState(scAddress, crypto.Keccak256Hash(voter.Hash().Bytes(), voterStakesLoc.Bytes()), common.BigToHash(big.NewInt(600)))
+
+	// withdrawsState[voter] = WithdrawState{caps: {3: 400, 5: 100}, epochs: [3, 5]}
+	withdrawLoc := crypto.Keccak256Hash(voter.Hash().Bytes(), slot(1))
+	epochsLoc := common.BigToHash(new(big.Int).Add(withdrawLoc.Big(), big.NewInt(1)))
+	stateDB.SetState(scAddress, epochsLoc, common.BigToHash(big.NewInt(int64(len(epochs)))))
+	for i, epoch := range epochs {
+		elementLoc := new(big.Int).Add(crypto.Keccak256Hash(epochsLoc.Bytes()).Big(), big.NewInt(int64(i)))
+		stateDB.SetState(scAddress, common.BigToHash(elementLoc), common.BigToHash(epoch))
+		stateDB.SetState(scAddress, crypto.Keccak256Hash(common.BigToHash(epoch).Bytes(), withdrawLoc.Bytes()), common.BigToHash(caps[i]))
+	}
+
+	caller := staking.NewStateDbStakingCaller(stateDB, staking.DefaultConfig)
+	assert.Equal(t, big.NewInt(600), caller.GetVoterStake(scAddress, candidate, voter))
+	assert.Zero(t, caller.GetVoterStake(scAddress, candidate, candidate).Sign())
+	require.Equal(t, epochs, caller.GetWithdrawEpochs(scAddress, voter))
+	for i, epoch := range epochs {
+		assert.Equal(t, caps[i], caller.GetWithdrawCap(scAddress, voter, epoch))
+	}
+	assert.Empty(t, caller.GetWithdrawEpochs(scAddress, candidate))
+}
@@ -105,6 +105,10 @@ type Account struct {
 	CodeHash          []byte
 	OwnerAddress      *common.Address  `rlp:"nil"`
 	ProviderAddresses []common.Address `rlp:"nil"`
+	// Enterprise holds at most one element, it is omitted from the encoding when empty
+	// so that the accounts without enterprise settings keep their encoding. It is only
+	// written by the transactions activated by the Enterprise fork.
+	Enterprise []Enterprise `rlp:"tail"`
 }
 
 // AccountWithoutProvider represent an account without provider
@@ -432,10 +436,17 @@ func (s *stateObject) ProviderAddresses() []common.Address {
 	return s.data.ProviderAddresses
 }
 
+// CheckOwner returns an error if the address is not the owner or one of the owners of a multisig
 func (s *stateObject) CheckOwner(owner common.Address) error {
 	if s.data.OwnerAddress == nil {
 		return ErrOwnerNotFound
 	}
+	if owners := s.enterprise().Owners; len(owners) != 0 {
+		if !owner.InList(owners) {
+			return ErrOnlyOwner
+		}
+		return nil
+	}
 	if *s.data.OwnerAddress != owner {
 		return ErrOnlyOwner
 	}
@@ -477,6 +488,7 @@ func (s *stateObject) RemoveProvider(providerAddress common.Address) error {
 	newProviders = append(newProviders, s.data.ProviderAddresses[:index]...)
 	newProviders = append(newProviders, s.data.ProviderAddresses[index+1:]...)
 	s.SetProvider(newProviders)
+	s.updateProviderPolicy(s.providerPolicy(providerAddress), ProviderPolicy{}, true)
 	return nil
 }
 
@@ -227,6 +227,30 @@ func (self *StateDB) GetOwner(addr common.Address) *common.Address {
 	return nil
 }
 
+// GetOwners returns the owners of account and the threshold of approvals for their actions
+func (self *StateDB) GetOwners(addr common.Address) ([]common.Address, uint64) {
+	stateObject := self.getStateObject(addr)
+	if stateObject != nil {
+		return stateObject.Owners()
+	}
+	return nil, 0
+}
+
+// IsOwner returns true if the address is the owner or one of the owners of account
+func (self *StateDB) IsOwner(addr common.Address, owner common.Address) bool {
+	stateObject := self.getStateObject(addr)
+	return stateObject != nil && stateObject.CheckOwner(owner) == nil
+}
+
+// GetPendingOwner returns the owner proposed for account, nil if there is no proposal
+func (self *StateDB) GetPendingOwner(addr common.Address) *common.Address {
+	stateObject := self.getStateObject(addr)
+	if stateObject != nil {
+		return stateObject.enterprise().PendingOwner
+	}
+	return nil
+}
+
 // GetProviders returns providers of account
 func (self *StateDB) GetProviders(addr common.Address) []common.Address {
 	so := self.getStateObject(addr)
@@ -383,6 +407,102 @@ func (self *StateDB) RemoveProvider(addr common.Address, from common.Address, pr
 	return stateObject.RemoveProvider(providerAddress)
 }
 
+// ApproveOwnerAction records the approval of an action of the owners of an enterprise contract,
+// it returns true once the action is approved by enough owners
+func (self *StateDB) ApproveOwnerAction(addr common.Address, from common.Address, action common.Hash) (bool, error) {
+	stateObject := self.getStateObject(addr)
+	if stateObject == nil {
+		return false, ErrOwnerNotFound
+	}
+	return stateObject.ApproveOwnerAction(from, action)
+}
+
+// TransferOwnership proposes a new owner of an enterprise contract
+func (self *StateDB) TransferOwnership(addr common.Address, from common.Address, owner common.Address) error {
+	stateObject := self.getStateObject(addr)
+	if stateObject == nil {
+		return ErrOwnerNotFound
+	}
+	if err := stateObject.CheckOwner(from); err != nil {
+		return err
+	}
+	stateObject.ProposeOwner(owner)
+	return nil
+}
+
+// AcceptOwnership transfers the ownership of an enterprise contract to the proposed owner
+func (self *StateDB) AcceptOwnership(addr common.Address, from common.Address) error {
+	stateObject := self.getStateObject(addr)
+	if stateObject == nil || stateObject.OwnerAddress() == nil {
+		return ErrOwnerNotFound
+	}
+	return stateObject.AcceptOwnership(from)
+}
+
+// SetOwners sets the owners of an enterprise contract and the threshold of approvals for their actions
+func (self *StateDB) SetOwners(addr common.Address, from common.Address, owners []common.Address, threshold uint64) error {
+	stateObject := self.getStateObject(addr)
+	if stateObject == nil {
+		return ErrOwnerNotFound
+	}
+	if err := stateObject.CheckOwner(from); err != nil {
+		return err
+	}
+	return stateObject.SetOwners(owners, threshold)
+}
+
+// SetProviderPolicy sets the policy of a provider of an enterprise contract
+func (self *StateDB) SetProviderPolicy(addr common.Address, from common.Address, policy types.ProviderPolicyMsg) error {
+	stateObject := self.getStateObject(addr)
+	if stateObject == nil {
+		return ErrOwnerNotFound
+	}
+	if err := stateObject.CheckOwner(from); err != nil {
+		return err
+	}
+	return stateObject.SetProviderPolicy(policy)
+}
+
+// CheckProviderGas returns an error if the policy of the provider of an enterprise contract
+// does not allow it to pay the gas of a transaction from the sender with the data in the epoch
+func (self *StateDB) CheckProviderGas(addr common.Address, provider common.Address, from common.Address, data []byte, gas uint64, epoch uint64) error {
+	stateObject := self.getStateObject(addr)
+	if stateObject == nil {
+		return nil
+	}
+	if policy := stateObject.ProviderPolicy(provider); policy != nil {
+		return policy.check(from, data, gas, epoch)
+	}
+	return nil
+}
+
+// UseProviderGas checks the policy of the provider of an enterprise contract
+// and records the gas it pays for a transaction from the sender with the data in the epoch
+func (self *StateDB) UseProviderGas(addr common.Address, provider common.Address, from common.Address, data []byte, gas uint64, epoch uint64) error {
+	stateObject := self.getStateObject(addr)
+	if stateObject == nil {
+		return nil
+	}
+	return stateObject.UseProviderGas(provider, from, data, gas, epoch)
+}
+
+// RefundProviderGas gives back the unused gas of a transaction to the epoch gas budget of the provider of an enterprise contract
+func (self *StateDB) RefundProviderGas(addr common.Address, provider common.Address, gas uint64, epoch uint64) {
+	stateObject := self.getStateObject(addr)
+	if stateObject != nil {
+		stateObject.RefundProviderGas(provider, gas, epoch)
+	}
+}
+
+// GetProviderPolicy returns the policy of a provider of an enterprise contract, nil if the provider has no policy
+func (self *StateDB) GetProviderPolicy(addr common.Address, provider common.Address) *ProviderPolicy {
+	stateObject := self.getStateObject(addr)
+	if stateObject != nil {
+		return stateObject.ProviderPolicy(provider)
+	}
+	return nil
+}
+
 // AddBalance adds amount to the account associated with addr.
 func (self *StateDB) AddBalance(addr common.Address, amount *big.Int) {
 	stateObject := self.GetOrNewStateObject(addr)
@@ -497,3 +497,141 @@ func TestEVM_RemoveProvider(t *testing.T) {
 	require.NoError(t, statedb.RemoveProvider(contractAddr, ownerAddr, providerAddr))
 	require.Equal(t, len(statedb.GetProviders(contractAddr)), 0)
 }
+
+func TestStateDB_ProviderPolicy(t *testing.T) {
+	var (
+		contractAddr, _ = common.EvryAddressStringToAddressCheck("EH9uVaqWRxHuzJbroqzX18zc1uiRuj2DGN")
+		ownerAddr, _    = common.EvryAddressStringToAddressCheck("EQzeFSroGjB4xodbMYP1qydXeWYgypGSJe")
+		providerAddr, _ = common.EvryAddressStringToAddressCheck("EWmMyKETQCsTYEC3W51dZ3bpUWvn3XtrwG")
+		senderAddr, _   = common.EvryAddressStringToAddressCheck("ENgapvtxruaDvhhygA4jeqSQkgufFnHoAH")
+		addr, _         = common.EvryAddressStringToAddressCheck("EH9uVaqWRxHuzJbroqzX18yxmeWAULFYZT")
+		method          = [4]byte{0xa9, 0x05, 0x9c, 0xbb}
+		data            = append(method[:], make([]byte, 32)...)
+		policy          = types.ProviderPolicyMsg{
+			Provider:       providerAddr,
+			EpochGasBudget: 100000,
+			MaxGasPerTx:    60000,
+			AllowedSenders: []common.Address{senderAddr},
+			AllowedMethods: [][4]byte{method},
+		}
+	)
+
+	db := NewDatabase(rawdb.NewMemoryDatabase())
+	statedb, _ := New(common.Hash{}, db)
+	statedb.CreateAccount(contractAddr, types.CreateAccountOption{OwnerAddress: &ownerAddr, ProviderAddress: &providerAddr})
+	root, _ := statedb.Commit(false)
+	statedb, _ = New(root, db)
+
+	require.Equal(t, ErrOnlyOwner, statedb.SetProviderPolicy(contractAddr, addr, policy))
+	unknown := policy
+	unknown.Provider = addr
+	require.Equal(t, ErrProviderNotFound, statedb.SetProviderPolicy(contractAddr, ownerAddr, unknown))
+	require.NoError(t, statedb.SetProviderPolicy(contractAddr, ownerAddr, policy))
+
+	// the policy is kept in the account
+	root, _ = statedb.Commit(false)
+	statedb, _ = New(root, db)
+	require.Equal(t, &ProviderPolicy{ProviderPolicyMsg: policy}, statedb.GetProviderPolicy(contractAddr, providerAddr))
+	require.Equal(t, []common.Address{providerAddr}, statedb.GetProviders(contractAddr))
+
+	require.Equal(t, ErrProviderMaxGasExceeded, statedb.CheckProviderGas(contractAddr, providerAddr, senderAddr, data, 60001, 1))
+	require.Equal(t, ErrProviderSenderNotAllowed, statedb.CheckProviderGas(contractAddr, providerAddr, addr, data, 50000, 1))
+	require.Equal(t, ErrProviderMethodNotAllowed, statedb.CheckProviderGas(contractAddr, providerAddr, senderAddr, nil, 50000, 1))
+	require.NoError(t, statedb.CheckProviderGas(contractAddr, providerAddr, senderAddr, data, 50000, 1))
+	// other providers are not restricted
+	require.NoError(t, statedb.CheckProviderGas(contractAddr, addr, addr, nil, 1000000, 1))
+
+	// the gas budget is spent within an epoch, refunded gas can be spent again
+	require.NoError(t, statedb.UseProviderGas(contractAddr, providerAddr, senderAddr, data, 60000, 1))
+	statedb.RefundProviderGas(contractAddr, providerAddr, 10000, 1)
+	require.NoError(t, statedb.UseProviderGas(contractAddr, providerAddr, senderAddr, data, 50000, 1))
+	require.Equal(t, ErrProviderBudgetExceeded, statedb.UseProviderGas(contractAddr, providerAddr, senderAddr, data, 1, 1))
+
+	// setting the policy again does not reset the budget and reverting the state restores the usage
+	snapshot := statedb.Snapshot()
+	require.NoError(t, statedb.SetProviderPolicy(contractAddr, ownerAddr, policy))
+	require.Equal(t, ErrProviderBudgetExceeded, statedb.CheckProviderGas(contractAddr, providerAddr, senderAddr, data, 1, 1))
+	require.NoError(t, statedb.UseProviderGas(contractAddr, providerAddr, senderAddr, data, 50000, 2))
+	statedb.RevertToSnapshot(snapshot)
+	require.Equal(t, uint64(100000), statedb.GetProviderPolicy(contractAddr, providerAddr).GasUsed)
+
+	// the budget is renewed in the next epoch
+	require.NoError(t, statedb.CheckProviderGas(contractAddr, providerAddr, senderAddr, data, 50000, 2))
+
+	// an empty policy or removing the provider removes its policy
+	require.NoError(t, statedb.SetProviderPolicy(contractAddr, ownerAddr, types.ProviderPolicyMsg{Provider: providerAddr}))
+	require.Nil(t, statedb.GetProviderPolicy(contractAddr, providerAddr))
+	require.NoError(t, statedb.SetProviderPolicy(contractAddr, ownerAddr, policy))
+	require.NoError(t, statedb.RemoveProvider(contractAddr, ownerAddr, providerAddr))
+	require.Nil(t, statedb.GetProviderPolicy(contractAddr, providerAddr))
+}
+
+func TestStateDB_Ownership(t *testing.T) {
+	var (
+		contractAddr, _ = common.EvryAddressStringToAddressCheck("EH9uVaqWRxHuzJbroqzX18zc1uiRuj2DGN")
+		ownerAddr, _    = common.EvryAddressStringToAddressCheck("EQzeFSroGjB4xodbMYP1qydXeWYgypGSJe")
+		providerAddr, _ = common.EvryAddressStringToAddressCheck("EWmMyKETQCsTYEC3W51dZ3bpUWvn3XtrwG")
+		newOwnerAddr, _ = common.EvryAddressStringToAddressCheck("ENgapvtxruaDvhhygA4jeqSQkgufFnHoAH")
+		addr, _         = common.EvryAddressStringToAddressCheck("EH9uVaqWRxHuzJbroqzX18yxmeWAULFYZT")
+		action          = common.HexToHash("0x01")
+	)
+
+	db := NewDatabase(rawdb.NewMemoryDatabase())
+	statedb, _ := New(common.Hash{}, db)
+	statedb.CreateAccount(contractAddr, types.CreateAccountOption{OwnerAddress: &ownerAddr, ProviderAddress: &providerAddr})
+	root, _ := statedb.Commit(false)
+	statedb, _ = New(root, db)
+
+	// the ownership is transferred once the proposed owner accepts it
+	require.Equal(t, ErrOnlyOwner, statedb.TransferOwnership(contractAddr, addr, newOwnerAddr))
+	require.NoError(t, statedb.TransferOwnership(contractAddr, ownerAddr, newOwnerAddr))
+	require.Equal(t, &newOwnerAddr, statedb.GetPendingOwner(contractAddr))
+	require.Equal(t, ErrOnlyPendingOwner, statedb.AcceptOwnership(contractAddr, addr))
+	require.NoError(t, statedb.AcceptOwnership(contractAddr, newOwnerAddr))
+	require.Equal(t, &newOwnerAddr, statedb.GetOwner(contractAddr))
+	require.Nil(t, statedb.GetPendingOwner(contractAddr))
+	require.False(t, statedb.IsOwner(contractAddr, ownerAddr))
+
+	// the owner is converted into a multisig of which every action needs 2 approvals
+	owners := []common.Address{newOwnerAddr, ownerAddr, addr}
+	require.Equal(t, ErrInvalidOwnerThreshold, statedb.SetOwners(contractAddr, newOwnerAddr, owners, 4))
+	require.Equal(t, ErrInvalidOwners, statedb.SetOwners(contractAddr, newOwnerAddr, []common.Address{ownerAddr, ownerAddr}, 1))
+	require.NoError(t, statedb.SetOwners(contractAddr, newOwnerAddr, owners, 2))
+	root, _ = statedb.Commit(false)
+	statedb, _ = New(root, db)
+	gotOwners, threshold := statedb.GetOwners(contractAddr)
+	require.Equal(t, owners, gotOwners)
+	require.Equal(t, uint64(2), threshold)
+	require.True(t, statedb.IsOwner(contractAddr, ownerAddr))
+	require.Equal(t, []common.Address{providerAddr}, statedb.GetProviders(contractAddr))
+
+	_, err := statedb.ApproveOwnerAction(contractAddr, providerAddr, action)
+	require.Equal(t, ErrOnlyOwner, err)
+	approved, err := statedb.ApproveOwnerAction(contractAddr, ownerAddr, action)
+	require.NoError(t, err)
+	require.False(t, approved)
+	_, err = statedb.ApproveOwnerAction(contractAddr, ownerAddr, action)
+	require.Equal(t, ErrAlreadyApproved, err)
+
+	// reverting the state restores the approvals and the owners
+	snapshot := statedb.Snapshot()
+	approved, err = statedb.ApproveOwnerAction(contractAddr, addr, action)
+	require.NoError(t, err)
+	require.True(t, approved)
+	require.NoError(t, statedb.SetOwners(contractAddr, addr, []common.Address{addr}, 1))
+	require.Equal(t, &addr, statedb.GetOwner(contractAddr))
+	statedb.RevertToSnapshot(snapshot)
+	gotOwners, _ = statedb.GetOwners(contractAddr)
+	require.Equal(t, owners, gotOwners)
+	require.Equal(t, &newOwnerAddr, statedb.GetOwner(contractAddr))
+	_, err = statedb.ApproveOwnerAction(contractAddr, ownerAddr, action)
+	require.Equal(t, ErrAlreadyApproved, err)
+
+	// an approved action forgets its approvals
+	approved, err = statedb.ApproveOwnerAction(contractAddr, newOwnerAddr, action)
+	require.NoError(t, err)
+	require.True(t, approved)
+	approved, err = statedb.ApproveOwnerAction(contractAddr, ownerAddr, action)
+	require.NoError(t, err)
+	require.False(t, approved)
+}
@@ -17,6 +17,8 @@
 package core
 
 import (
+	"math/big"
+
 	"github.com/Evrynetlabs/evrynet-node/common"
 	"github.com/Evrynetlabs/evrynet-node/consensus"
 	"github.com/Evrynetlabs/evrynet-node/core/state"
@@ -81,6 +83,9 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 // for the transaction, gas used and an error if the transaction failed,
 // indicating the block was invalid.
 func ApplyTransaction(config *params.ChainConfig, bc ChainContext, author *common.Address, gp *GasPool, statedb *state.StateDB, header *types.Header, tx *types.Transaction, usedGas *uint64, cfg vm.Config) (*types.Receipt, uint64, error) {
+	if err := ValidateTxEncoding(config, tx, header.Number); err != nil {
+		return nil, 0, err
+	}
 	msg, err := tx.AsMessage(types.MakeSigner(config, header.Number))
 	if err != nil {
 		return nil, 0, err
@@ -119,3 +124,23 @@ func ApplyTransaction(config *params.ChainConfig, bc ChainContext, author *commo
 
 	return receipt, gas, err
 }
+
+// ValidateTxEncoding returns an error if the encoding of the transaction is not valid at the block number.
+// The typed transaction envelopes and the scope of the provider signature are only valid after the TypedTx fork,
+// and the transactions other than the normal ones must be enveloped after it so that each has a single encoding.
+func ValidateTxEncoding(config *params.ChainConfig, tx *types.Transaction, number *big.Int) error {
+	if !config.IsTypedTx(number) {
+		if tx.Enveloped() || tx.ProviderScope() != nil {
+			return types.ErrTxTypeNotSupported
+		}
+		return nil
+	}
+	if tx.Type() != types.NormalEnvelope && !tx.Enveloped() {
+		return types.ErrTxTypeNotSupported
+	}
+	// a sponsored envelope without provider signature would be a second encoding of a normal transaction
+	if tx.Type() == types.SponsoredEnvelope && !tx.HasProviderSignature() {
+		return ErrProviderSignatureIsRequired
+	}
+	return nil
+}
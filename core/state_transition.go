@@ -22,6 +22,7 @@ import (
 	"math/big"
 
 	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/core/state"
 	"github.com/Evrynetlabs/evrynet-node/core/types"
 	"github.com/Evrynetlabs/evrynet-node/core/vm"
 	"github.com/Evrynetlabs/evrynet-node/log"
@@ -66,6 +67,8 @@ type Message interface {
 	From() common.Address
 	GasPayer() common.Address
 	HasProviderSignature() bool
+	ProviderValidUntil() uint64
+	ProviderMaxFee() *big.Int
 	To() *common.Address
 	Owner() *common.Address
 	Provider() *common.Address
@@ -160,6 +163,12 @@ func (st *StateTransition) buyGas() error {
 	if st.state.GetBalance(st.msg.GasPayer()).Cmp(mgval) < 0 {
 		return errInsufficientBalanceForGas
 	}
+	if st.sponsored() {
+		// the provider pays for gas, it must be allowed by its policy on the enterprise contract
+		if err := st.state.UseProviderGas(st.to(), st.msg.GasPayer(), st.msg.From(), st.data, st.msg.Gas(), st.providerGasEpoch()); err != nil {
+			return err
+		}
+	}
 	if err := st.gp.SubGas(st.msg.Gas()); err != nil {
 		return err
 	}
@@ -170,7 +179,55 @@ func (st *StateTransition) buyGas() error {
 	return nil
 }
 
+// sponsored returns true if a provider pays for gas of the message to an enterprise contract
+func (st *StateTransition) sponsored() bool {
+	return st.msg.To() != nil && st.msg.HasProviderSignature()
+}
+
+// providerGasEpoch returns the epoch of the gas budgets of the providers at the current block
+func (st *StateTransition) providerGasEpoch() uint64 {
+	return ProviderGasEpoch(st.evm.BlockNumber)
+}
+
+// ProviderGasEpoch returns the epoch of the gas budgets of the providers at the block number
+func ProviderGasEpoch(number *big.Int) uint64 {
+	return number.Uint64() / params.ProviderGasEpochLength
+}
+
+// CheckProviderScope returns an error if the provider signature of the message is expired at the block number
+// or the fee of the message exceeds the maximum fee signed by the provider
+func CheckProviderScope(msg Message, number *big.Int) error {
+	if !msg.HasProviderSignature() {
+		return nil
+	}
+	if validUntil := msg.ProviderValidUntil(); validUntil != 0 && number.Uint64() > validUntil {
+		return ErrProviderSignatureExpired
+	}
+	if maxFee := msg.ProviderMaxFee(); maxFee != nil {
+		fee := new(big.Int).Mul(new(big.Int).SetUint64(msg.Gas()), msg.GasPrice())
+		if fee.Cmp(maxFee) > 0 {
+			return ErrProviderMaxFeeExceeded
+		}
+	}
+	return nil
+}
+
+// ValidateTxFork returns an error if the type of the message is not activated at the block number
+func ValidateTxFork(config *params.ChainConfig, msg Message, number *big.Int) error {
+	switch msg.TxType() {
+	case types.SetProviderPolicyTxType, types.TransferOwnershipTxType, types.AcceptOwnershipTxType, types.SetOwnersTxType:
+		if !config.IsEnterprise(number) {
+			return types.ErrTxTypeNotSupported
+		}
+	}
+	return nil
+}
+
 func (st *StateTransition) preCheck() error {
+	// Make sure the type of this transaction is activated.
+	if err := ValidateTxFork(st.evm.ChainConfig(), st.msg, st.evm.BlockNumber); err != nil {
+		return err
+	}
 	// Make sure this transaction's nonce is correct.
 	if st.msg.CheckNonce() {
 		nonce := st.state.GetNonce(st.msg.From())
@@ -180,6 +237,10 @@ func (st *StateTransition) preCheck() error {
 			return ErrNonceTooLow
 		}
 	}
+	// Make sure the provider signature is still valid for this transaction.
+	if err := CheckProviderScope(st.msg, st.evm.BlockNumber); err != nil {
+		return err
+	}
 	//TODO: this should check if the address from provider list
 	return st.buyGas()
 }
@@ -222,20 +283,9 @@ func (st *StateTransition) TransitionDb() (ret []byte, usedGas uint64, failed bo
 			option.ProviderAddress = msg.Provider()
 		}
 		ret, _, st.gas, vmerr = evm.Create(sender, st.data, st.gas, st.value, option)
-	case msg.TxType() == types.AddProviderTxType || msg.TxType() == types.RemoveProviderTxType:
-		var (
-			msgData types.ModifyProvidersMsg
-			ok      bool
-		)
-		if msgData, ok = st.msg.ExtraData().(types.ModifyProvidersMsg); !ok { // this should never to be happened
-			return nil, 0, false, errors.New("msg should be type ModifyProvidersMsg")
-		}
+	case msg.TxType() != types.NormalTxType:
 		st.state.SetNonce(msg.From(), st.state.GetNonce(sender.Address())+1)
-		if msg.TxType() == types.AddProviderTxType {
-			vmerr = st.state.AddProvider(st.to(), msg.From(), msgData.Provider)
-		} else {
-			vmerr = st.state.RemoveProvider(st.to(), msg.From(), msgData.Provider)
-		}
+		vmerr = st.applyOwnerTx()
 	default:
 		// Increment the nonce for the next transaction
 		st.state.SetNonce(msg.From(), st.state.GetNonce(sender.Address())+1)
@@ -257,6 +307,37 @@ func (st *StateTransition) TransitionDb() (ret []byte, usedGas uint64, failed bo
 	return ret, st.gasUsed(), vmerr != nil, err
 }
 
+// applyOwnerTx applies a transaction of the owners of an enterprise contract, an action of the owners of a multisig
+// is applied once it is approved by enough owners
+func (st *StateTransition) applyOwnerTx() error {
+	msg := st.msg
+	if msg.TxType() == types.AcceptOwnershipTxType {
+		if msgData, ok := msg.ExtraData().(types.OwnershipMsg); !ok || msgData.Owner != msg.From() {
+			return state.ErrOnlyPendingOwner
+		}
+		return st.state.AcceptOwnership(st.to(), msg.From())
+	}
+	approved, err := st.state.ApproveOwnerAction(st.to(), msg.From(), types.OwnerActionHash(msg.TxType(), msg.ExtraData()))
+	if err != nil || !approved {
+		return err
+	}
+	switch msgData := msg.ExtraData().(type) {
+	case types.ModifyProvidersMsg:
+		if msg.TxType() == types.AddProviderTxType {
+			return st.state.AddProvider(st.to(), msg.From(), msgData.Provider)
+		}
+		return st.state.RemoveProvider(st.to(), msg.From(), msgData.Provider)
+	case types.ProviderPolicyMsg:
+		return st.state.SetProviderPolicy(st.to(), msg.From(), msgData)
+	case types.OwnershipMsg:
+		return st.state.TransferOwnership(st.to(), msg.From(), msgData.Owner)
+	case types.SetOwnersMsg:
+		return st.state.SetOwners(st.to(), msg.From(), msgData.Owners, msgData.Threshold)
+	default: // this should never to be happened
+		return types.ErrInvalidExtraDataType
+	}
+}
+
 func (st *StateTransition) refundGas() {
 	// Apply refund counter, capped to half of the used gas.
 	refund := st.gasUsed() / 2
@@ -268,6 +349,9 @@ func (st *StateTransition) refundGas() {
 	// Return ETH for remaining gas, exchanged at the original rate.
 	remaining := new(big.Int).Mul(new(big.Int).SetUint64(st.gas), st.gasPrice)
 	st.state.AddBalance(st.msg.GasPayer(), remaining)
+	if st.sponsored() {
+		st.state.RefundProviderGas(st.to(), st.msg.GasPayer(), st.gas, st.providerGasEpoch())
+	}
 
 	// Also return remaining gas to the block gas counter so it is
 	// available for the next transaction.
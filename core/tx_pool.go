@@ -17,6 +17,7 @@
 package core
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"math"
@@ -74,6 +75,10 @@ var (
 	// is higher than the balance of the provider's account (fee's paid by provider)
 	ErrProviderInsufficientFunds = errors.New("provider has insufficient funds for gas * price")
 
+	// ErrProviderOvercommitted is returned if the fees of the pending and queued transactions
+	// sponsored by the provider would be higher than the balance of the provider's account
+	ErrProviderOvercommitted = errors.New("provider has insufficient funds for the gas of its sponsored transactions")
+
 	// ErrSenderInsufficientFunds is returned if the transaction value
 	// is higher than the balance of the user's account (fee's paid by provider)
 	ErrSenderInsufficientFunds = errors.New("sender has insufficient funds for value")
@@ -107,8 +112,17 @@ var (
 	// ErrOnlyOwner is returned if modifying providers transaction is not from owner of enterprise contract
 	ErrOnlyOwner = errors.New("only owner can modify providers of enterprise contract")
 
+	// ErrOnlyPendingOwner is returned if accepting ownership transaction is not from the proposed owner of enterprise contract
+	ErrOnlyPendingOwner = errors.New("only the proposed owner can accept the ownership of enterprise contract")
+
 	// ErrInvalidAddressToModifyProviders is returned when modifying providers transaction is sent to non-enterprise contract
 	ErrInvalidAddressToModifyProviders = errors.New("only enterprise contract can modify providers")
+
+	// ErrProviderSignatureExpired is returned if the transaction is past the last block its provider signature is valid in
+	ErrProviderSignatureExpired = errors.New("provider signature expired")
+
+	// ErrProviderMaxFeeExceeded is returned if the fee of the transaction exceeds the maximum fee signed by its provider
+	ErrProviderMaxFeeExceeded = errors.New("fee exceeds the maximum fee signed by the provider")
 )
 
 var (
@@ -129,6 +143,9 @@ var (
 	queuedRateLimitMeter = metrics.NewRegisteredMeter("txpool/queued/ratelimit", nil) // Dropped due to rate limiting
 	queuedNofundsMeter   = metrics.NewRegisteredMeter("txpool/queued/nofunds", nil)   // Dropped due to out-of-funds
 
+	// Metrics for the sponsored transactions
+	sponsoredNofundsMeter = metrics.NewRegisteredMeter("txpool/sponsored/nofunds", nil) // Dropped due to out-of-funds provider
+
 	// General tx metrics
 	validMeter         = metrics.NewRegisteredMeter("txpool/valid", nil)
 	invalidTxMeter     = metrics.NewRegisteredMeter("txpool/invalid", nil)
@@ -283,10 +300,10 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 		pending:     make(map[common.Address]*txList),
 		queue:       make(map[common.Address]*txList),
 		beats:       make(map[common.Address]time.Time),
-		all:         newTxLookup(),
 		chainHeadCh: make(chan ChainHeadEvent, chainHeadChanSize),
 		gasPrice:    new(big.Int).SetUint64(config.PriceLimit),
 	}
+	pool.all = newTxLookup(pool.signer)
 	pool.locals = newAccountSet(pool.signer)
 	for _, addr := range config.Locals {
 		log.Info("Setting new local account", "address", addr)
@@ -631,6 +648,19 @@ func (pool *TxPool) local() map[common.Address]types.Transactions {
 	return txs
 }
 
+// sameNonceTx returns the pending or queued transaction of the account with the nonce, if any.
+func (pool *TxPool) sameNonceTx(from common.Address, nonce uint64) *types.Transaction {
+	if list := pool.pending[from]; list != nil {
+		if tx := list.txs.Get(nonce); tx != nil {
+			return tx
+		}
+	}
+	if list := pool.queue[from]; list != nil {
+		return list.txs.Get(nonce)
+	}
+	return nil
+}
+
 // ValidateTx checks whether a transaction is valid according to the consensus
 // rules and adheres to some heuristic limits of the local node (price and size).
 func (pool *TxPool) ValidateTx(tx *types.Transaction, local bool) error {
@@ -638,6 +668,10 @@ func (pool *TxPool) ValidateTx(tx *types.Transaction, local bool) error {
 	if tx.Size() > 32*1024 {
 		return ErrOversizedData
 	}
+	// Typed transaction envelopes and provider scopes are only accepted once the fork is active in the pending block
+	if err := ValidateTxEncoding(pool.chainconfig, tx, new(big.Int).Add(pool.chain.CurrentBlock().Number(), common.Big1)); err != nil {
+		return err
+	}
 	// Transactions can't be negative. This may never happen using RLP decoded
 	// transactions but may occur if you create a transaction using the RPC.
 	if tx.Value().Sign() < 0 {
@@ -659,35 +693,13 @@ func (pool *TxPool) ValidateTx(tx *types.Transaction, local bool) error {
 		return ErrInvalidGasPrice
 	}
 
-	// Check permission to execute transaction to enterprise contract
-	switch {
-	case txMsg.To() == nil: // nothing need to check
-	case txMsg.TxType() == types.AddProviderTxType || txMsg.TxType() == types.RemoveProviderTxType:
-		owner := pool.currentState.GetOwner(*txMsg.To())
-		// if this is not an enterprise contract, return error
-		if owner == nil {
-			return ErrInvalidAddressToModifyProviders
-		}
-		if *owner != txMsg.From() {
-			return ErrOnlyOwner
-		}
-	default:
-		owner := pool.currentState.GetOwner(*txMsg.To())
-		// if this is not an enterprise contract, there must be no provider signature
-		if owner == nil {
-			if txMsg.HasProviderSignature() {
-				return ErrRedundantProviderSignature
-			}
-			break
-		}
-		// If the destination is an enterprise smart contract, the tx must be signed with valid provider
-		if !txMsg.HasProviderSignature() {
-			return ErrProviderSignatureIsRequired
-		}
-		expectedProviders := pool.currentState.GetProviders(*txMsg.To())
-		if !tx.GasPayer(pool.signer).InList(expectedProviders) {
-			return ErrInvalidProvider
-		}
+	// Check the type and the permission to execute transaction to enterprise contract in the pending block
+	pendingNumber := new(big.Int).Add(pool.chain.CurrentBlock().Number(), common.Big1)
+	if err := ValidateTxFork(pool.chainconfig, txMsg, pendingNumber); err != nil {
+		return err
+	}
+	if err := ValidateEnterpriseTx(pool.currentState, txMsg, pendingNumber); err != nil {
+		return err
 	}
 
 	// Drop non-local transactions under our own minimal accepted gas price
@@ -710,9 +722,21 @@ func (pool *TxPool) ValidateTx(tx *types.Transaction, local bool) error {
 		}
 
 		// Check provider's balance for transaction fee
-		if pool.currentState.GetBalance(txMsg.GasPayer()).Cmp(tx.TransactionFee()) < 0 {
+		providerBalance := pool.currentState.GetBalance(txMsg.GasPayer())
+		if providerBalance.Cmp(tx.TransactionFee()) < 0 {
 			return ErrProviderInsufficientFunds
 		}
+		// Check provider's balance for the fees of all the transactions it sponsors,
+		// except the one replaced by the transaction
+		sponsoredFee := pool.all.SponsoredFee(txMsg.GasPayer())
+		if old := pool.sameNonceTx(from, tx.Nonce()); old != nil {
+			if provider, ok := pool.all.Provider(old.Hash()); ok && provider == txMsg.GasPayer() {
+				sponsoredFee.Sub(sponsoredFee, old.TransactionFee())
+			}
+		}
+		if providerBalance.Cmp(sponsoredFee.Add(sponsoredFee, tx.TransactionFee())) < 0 {
+			return ErrProviderOvercommitted
+		}
 	} else {
 		// Sender pays transaction fee, check sender's balance for tx costs
 		// cost == V + GP * GL
@@ -731,6 +755,60 @@ func (pool *TxPool) ValidateTx(tx *types.Transaction, local bool) error {
 	return nil
 }
 
+// ValidateEnterpriseTx checks the permission of the sender and the provider of a message to an enterprise contract
+// against the state, the message is to be executed in the block of the number.
+func ValidateEnterpriseTx(statedb *state.StateDB, msg types.Message, number *big.Int) error {
+	switch {
+	case msg.To() == nil: // nothing need to check
+	case msg.TxType() == types.AcceptOwnershipTxType:
+		pendingOwner := statedb.GetPendingOwner(*msg.To())
+		msgData, _ := msg.ExtraData().(types.OwnershipMsg)
+		if pendingOwner == nil || *pendingOwner != msg.From() || msgData.Owner != msg.From() {
+			return ErrOnlyPendingOwner
+		}
+	case msg.TxType() != types.NormalTxType:
+		owner := statedb.GetOwner(*msg.To())
+		// if this is not an enterprise contract, return error
+		if owner == nil {
+			return ErrInvalidAddressToModifyProviders
+		}
+		if !statedb.IsOwner(*msg.To(), msg.From()) {
+			return ErrOnlyOwner
+		}
+		if msgData, ok := msg.ExtraData().(types.SetOwnersMsg); ok {
+			if err := state.ValidateOwners(msgData.Owners, msgData.Threshold); err != nil {
+				return err
+			}
+		}
+	default:
+		owner := statedb.GetOwner(*msg.To())
+		// if this is not an enterprise contract, there must be no provider signature
+		if owner == nil {
+			if msg.HasProviderSignature() {
+				return ErrRedundantProviderSignature
+			}
+			break
+		}
+		// If the destination is an enterprise smart contract, the tx must be signed with valid provider
+		if !msg.HasProviderSignature() {
+			return ErrProviderSignatureIsRequired
+		}
+		expectedProviders := statedb.GetProviders(*msg.To())
+		if !msg.GasPayer().InList(expectedProviders) {
+			return ErrInvalidProvider
+		}
+		// The provider signature must be valid for the transaction at the block
+		if err := CheckProviderScope(msg, number); err != nil {
+			return err
+		}
+		// The provider must be allowed by its policy to pay for the transaction
+		if err := statedb.CheckProviderGas(*msg.To(), msg.GasPayer(), msg.From(), msg.Data(), msg.Gas(), ProviderGasEpoch(number)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // add validates a transaction and inserts it into the non-executable queue for
 // later pending promotion and execution. If the transaction is a replacement for
 // an already pending or queued one, it overwrites the previous and returns this
@@ -1047,8 +1125,15 @@ func (pool *TxPool) filterUnpayableTransactions(account common.Address, l *txLis
 		accountBalance      = pool.currentState.GetBalance(account)
 		nonces              []uint64
 		hasEnoughFunds      bool
+		next                = pool.chain.CurrentBlock().NumberU64() + 1
 	)
 	for nonce, tx := range l.txs.items {
+		// the transactions whose provider signature expired can never be included
+		if validUntil := tx.ProviderValidUntil(); validUntil != 0 && validUntil < next {
+			nonces = append(nonces, nonce)
+			filtereds = append(filtereds, tx)
+			continue
+		}
 
 		hasEnoughFunds = true
 		if provider := tx.SignedProvider(pool.signer); provider != nil {
@@ -1326,6 +1411,52 @@ func (pool *TxPool) demoteUnexecutables() {
 			delete(pool.beats, addr)
 		}
 	}
+	// Drop the sponsored transactions the providers can no longer pay for
+	pool.demoteOvercommittedProviders()
+}
+
+// demoteOvercommittedProviders removes the sponsored transactions whose aggregated fee exceeds the balance
+// of their provider, i.e. after the provider spent its funds. The queued transactions are removed before the
+// pending ones, and the highest nonces first, so that the transactions closest to execution are kept.
+func (pool *TxPool) demoteOvercommittedProviders() {
+	for provider, cost := range pool.all.Sponsored() {
+		balance := pool.currentState.GetBalance(provider)
+		if balance.Cmp(cost.Fee) >= 0 {
+			continue
+		}
+		txs := pool.all.SponsoredTxs(provider)
+		pending := make(map[common.Hash]bool, len(txs))
+		for _, tx := range txs {
+			from, _ := types.Sender(pool.signer, tx) // already validated
+			if list := pool.pending[from]; list != nil && list.txs.Get(tx.Nonce()) == tx {
+				pending[tx.Hash()] = true
+			}
+		}
+		sort.Slice(txs, func(i, j int) bool {
+			if pi, pj := pending[txs[i].Hash()], pending[txs[j].Hash()]; pi != pj {
+				return pj
+			}
+			if txs[i].Nonce() != txs[j].Nonce() {
+				return txs[i].Nonce() > txs[j].Nonce()
+			}
+			return bytes.Compare(txs[i].Hash().Bytes(), txs[j].Hash().Bytes()) < 0
+		})
+		var dropped int
+		for _, tx := range txs {
+			if balance.Cmp(pool.all.SponsoredFee(provider)) >= 0 {
+				break
+			}
+			log.Trace("Removed overcommitted sponsored transaction", "hash", tx.Hash(), "provider", provider)
+			pool.removeTx(tx.Hash(), true)
+			dropped++
+		}
+		sponsoredNofundsMeter.Mark(int64(dropped))
+	}
+}
+
+// Sponsored retrieves the aggregated fee of the pending and queued transactions sponsored by each provider.
+func (pool *TxPool) Sponsored() map[common.Address]SponsoredCost {
+	return pool.all.Sponsored()
 }
 
 // addressByHeartbeat is an account address tagged with its last activity timestamp.
@@ -1400,15 +1531,30 @@ func (as *accountSet) flatten() []common.Address {
 // internal mechanisms. The sole purpose of the type is to permit out-of-bound
 // peeking into the pool in TxPool.Get without having to acquire the widely scoped
 // TxPool.mu mutex.
+//
+// It also aggregates the fees of the transactions sponsored by each provider,
+// as every transaction enters and leaves the pool through it.
 type txLookup struct {
-	all  map[common.Hash]*types.Transaction
-	lock sync.RWMutex
+	all       map[common.Hash]*types.Transaction
+	providers map[common.Hash]common.Address    // Providers of the sponsored transactions
+	sponsored map[common.Address]*SponsoredCost // Aggregated fees of the transactions sponsored by each provider
+	signer    types.Signer
+	lock      sync.RWMutex
+}
+
+// SponsoredCost is the aggregated fee of the pending and queued transactions sponsored by a provider.
+type SponsoredCost struct {
+	Fee *big.Int // Sum of gas * price of the transactions
+	Txs int      // Number of transactions
 }
 
 // newTxLookup returns a new txLookup structure.
-func newTxLookup() *txLookup {
+func newTxLookup(signer types.Signer) *txLookup {
 	return &txLookup{
-		all: make(map[common.Hash]*types.Transaction),
+		all:       make(map[common.Hash]*types.Transaction),
+		providers: make(map[common.Hash]common.Address),
+		sponsored: make(map[common.Address]*SponsoredCost),
+		signer:    signer,
 	}
 }
 
@@ -1442,10 +1588,26 @@ func (t *txLookup) Count() int {
 
 // Add adds a transaction to the lookup.
 func (t *txLookup) Add(tx *types.Transaction) {
+	provider := tx.SignedProvider(t.signer) // recovered before locking
+
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
-	t.all[tx.Hash()] = tx
+	hash := tx.Hash()
+	if _, ok := t.all[hash]; ok {
+		return
+	}
+	t.all[hash] = tx
+	if provider != nil {
+		t.providers[hash] = *provider
+		cost := t.sponsored[*provider]
+		if cost == nil {
+			cost = &SponsoredCost{Fee: new(big.Int)}
+			t.sponsored[*provider] = cost
+		}
+		cost.Fee.Add(cost.Fee, tx.TransactionFee())
+		cost.Txs++
+	}
 }
 
 // Remove removes a transaction from the lookup.
@@ -1453,5 +1615,63 @@ func (t *txLookup) Remove(hash common.Hash) {
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
+	tx, ok := t.all[hash]
+	if !ok {
+		return
+	}
 	delete(t.all, hash)
+	if provider, ok := t.providers[hash]; ok {
+		delete(t.providers, hash)
+		cost := t.sponsored[provider]
+		cost.Fee.Sub(cost.Fee, tx.TransactionFee())
+		if cost.Txs--; cost.Txs == 0 {
+			delete(t.sponsored, provider)
+		}
+	}
+}
+
+// SponsoredFee returns the aggregated fee of the transactions sponsored by the provider.
+func (t *txLookup) SponsoredFee(provider common.Address) *big.Int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if cost := t.sponsored[provider]; cost != nil {
+		return new(big.Int).Set(cost.Fee)
+	}
+	return new(big.Int)
+}
+
+// Provider returns the provider sponsoring the transaction with the hash, if it is sponsored.
+func (t *txLookup) Provider(hash common.Hash) (common.Address, bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	provider, ok := t.providers[hash]
+	return provider, ok
+}
+
+// Sponsored returns a copy of the aggregated fees of the transactions sponsored by each provider.
+func (t *txLookup) Sponsored() map[common.Address]SponsoredCost {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	sponsored := make(map[common.Address]SponsoredCost, len(t.sponsored))
+	for provider, cost := range t.sponsored {
+		sponsored[provider] = SponsoredCost{Fee: new(big.Int).Set(cost.Fee), Txs: cost.Txs}
+	}
+	return sponsored
+}
+
+// SponsoredTxs returns the transactions sponsored by the provider.
+func (t *txLookup) SponsoredTxs(provider common.Address) types.Transactions {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	var txs types.Transactions
+	for hash, p := range t.providers {
+		if p == provider {
+			txs = append(txs, t.all[hash])
+		}
+	}
+	return txs
 }
@@ -78,11 +78,15 @@ func pricedTransaction(nonce uint64, gaslimit uint64, gasprice *big.Int, key *ec
 }
 
 func setupTxPool() (*TxPool, *ecdsa.PrivateKey) {
+	return setupTxPoolWithConfig(params.TestChainConfig)
+}
+
+func setupTxPoolWithConfig(config *params.ChainConfig) (*TxPool, *ecdsa.PrivateKey) {
 	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
 	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
 
 	key, _ := crypto.GenerateKey()
-	pool := NewTxPool(testTxPoolConfig, params.TestChainConfig, blockchain)
+	pool := NewTxPool(testTxPoolConfig, config, blockchain)
 
 	return pool, key
 }
@@ -1345,6 +1349,361 @@ func TestTransactionStatusCheck(t *testing.T) {
 	}
 }
 
+// Tests that the fees of the transactions sponsored by a provider are aggregated, so
+// that a provider cannot sponsor more than its balance, and that the sponsored
+// transactions beyond the balance are dropped when the provider spends its funds.
+func TestTransactionProviderOvercommit(t *testing.T) {
+	t.Parallel()
+
+	pool, _ := setupTxPool()
+	defer pool.Stop()
+
+	var (
+		ownerKey, _    = crypto.GenerateKey()
+		providerKey, _ = crypto.GenerateKey()
+		owner          = crypto.PubkeyToAddress(ownerKey.PublicKey)
+		provider       = crypto.PubkeyToAddress(providerKey.PublicKey)
+		contract       = common.HexToAddress("0xc0")
+		fee            = new(big.Int).Mul(big.NewInt(100000), big.NewInt(params.GasPriceConfig))
+	)
+	pool.currentState.CreateAccount(contract, types.CreateAccountOption{OwnerAddress: &owner, ProviderAddress: &provider})
+	pool.currentState.AddBalance(provider, new(big.Int).Mul(fee, big.NewInt(2)))
+
+	sponsored := func(key *ecdsa.PrivateKey) *types.Transaction {
+		tx, _ := types.SignTx(types.NewTransaction(0, contract, new(big.Int), 100000, big.NewInt(params.GasPriceConfig), nil), types.BaseSigner{}, key)
+		tx, _ = types.ProviderSignTx(tx, types.BaseSigner{}, providerKey)
+		return tx
+	}
+	keys := make([]*ecdsa.PrivateKey, 3)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+	}
+	for i := 0; i < 2; i++ {
+		if err := pool.AddRemote(sponsored(keys[i])); err != nil {
+			t.Fatalf("failed to add sponsored transaction %d: %v", i, err)
+		}
+	}
+	if err := pool.AddRemote(sponsored(keys[2])); err != ErrProviderOvercommitted {
+		t.Fatalf("overcommitting error mismatch: have %v, want %v", err, ErrProviderOvercommitted)
+	}
+	cost := pool.Sponsored()[provider]
+	if cost.Txs != 2 || cost.Fee.Cmp(new(big.Int).Mul(fee, big.NewInt(2))) != 0 {
+		t.Fatalf("sponsored cost mismatch: have %d txs for %v, want %d txs for %v", cost.Txs, cost.Fee, 2, new(big.Int).Mul(fee, big.NewInt(2)))
+	}
+	// Ensure the fee of the transaction being replaced is not counted twice
+	replacement := func(gas uint64) *types.Transaction {
+		tx, _ := types.SignTx(types.NewTransaction(0, contract, new(big.Int), gas, big.NewInt(params.GasPriceConfig), []byte{0x01}), types.BaseSigner{}, keys[0])
+		tx, _ = types.ProviderSignTx(tx, types.BaseSigner{}, providerKey)
+		return tx
+	}
+	pool.mu.Lock()
+	sameFeeErr := pool.ValidateTx(replacement(100000), false)
+	higherFeeErr := pool.ValidateTx(replacement(150000), false)
+	pool.mu.Unlock()
+	if sameFeeErr != nil {
+		t.Fatalf("replacement validation failed: %v", sameFeeErr)
+	}
+	if higherFeeErr != ErrProviderOvercommitted {
+		t.Fatalf("overcommitting replacement error mismatch: have %v, want %v", higherFeeErr, ErrProviderOvercommitted)
+	}
+	// Spend the funds of the provider and ensure the transactions beyond its balance are dropped
+	pool.currentState.SubBalance(provider, fee)
+	pool.lockedReset(nil, nil)
+
+	if pending, _ := pool.Stats(); pending != 1 {
+		t.Fatalf("pending transactions mismatched: have %d, want %d", pending, 1)
+	}
+	if cost := pool.Sponsored()[provider]; cost.Txs != 1 || cost.Fee.Cmp(fee) != 0 {
+		t.Fatalf("sponsored cost mismatch: have %d txs for %v, want %d txs for %v", cost.Txs, cost.Fee, 1, fee)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that the transactions sponsored by a provider are rejected if the policy
+// set by the owner of the enterprise contract does not allow the provider to pay
+// for them.
+func TestTransactionProviderPolicy(t *testing.T) {
+	t.Parallel()
+
+	config := *params.TestChainConfig
+	config.EnterpriseBlock = big.NewInt(0)
+	pool, key := setupTxPoolWithConfig(&config)
+	defer pool.Stop()
+
+	var (
+		ownerKey, _    = crypto.GenerateKey()
+		providerKey, _ = crypto.GenerateKey()
+		owner          = crypto.PubkeyToAddress(ownerKey.PublicKey)
+		provider       = crypto.PubkeyToAddress(providerKey.PublicKey)
+		sender         = crypto.PubkeyToAddress(key.PublicKey)
+		contract       = common.HexToAddress("0xc0")
+		method         = [4]byte{0xa9, 0x05, 0x9c, 0xbb}
+	)
+	pool.currentState.CreateAccount(contract, types.CreateAccountOption{OwnerAddress: &owner, ProviderAddress: &provider})
+	pool.currentState.AddBalance(provider, new(big.Int).Mul(big.NewInt(1000000), big.NewInt(params.GasPriceConfig)))
+	pool.currentState.AddBalance(owner, new(big.Int).Mul(big.NewInt(1000000), big.NewInt(params.GasPriceConfig)))
+	if err := pool.currentState.SetProviderPolicy(contract, owner, types.ProviderPolicyMsg{
+		Provider:       provider,
+		MaxGasPerTx:    100000,
+		AllowedSenders: []common.Address{sender},
+		AllowedMethods: [][4]byte{method},
+	}); err != nil {
+		t.Fatalf("failed to set provider policy: %v", err)
+	}
+	sponsored := func(nonce uint64, gas uint64, data []byte, key *ecdsa.PrivateKey) *types.Transaction {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, contract, new(big.Int), gas, big.NewInt(params.GasPriceConfig), data), types.BaseSigner{}, key)
+		tx, _ = types.ProviderSignTx(tx, types.BaseSigner{}, providerKey)
+		return tx
+	}
+	other, _ := crypto.GenerateKey()
+	for i, test := range []struct {
+		tx  *types.Transaction
+		err error
+	}{
+		{sponsored(0, 100001, method[:], key), state.ErrProviderMaxGasExceeded},
+		{sponsored(0, 100000, method[:], other), state.ErrProviderSenderNotAllowed},
+		{sponsored(0, 100000, []byte{0x01, 0x02, 0x03, 0x04}, key), state.ErrProviderMethodNotAllowed},
+		{sponsored(0, 100000, method[:], key), nil},
+	} {
+		if err := pool.AddRemote(test.tx); err != test.err {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, test.err)
+		}
+	}
+	// Only the owner can set the policy of a provider
+	for i, test := range []struct {
+		key *ecdsa.PrivateKey
+		err error
+	}{
+		{key, ErrOnlyOwner},
+		{ownerKey, nil},
+	} {
+		tx, _ := types.NewProviderPolicyTransaction(0, contract, 100000, big.NewInt(params.GasPriceConfig), types.ProviderPolicyMsg{Provider: provider})
+		tx, _ = types.SignTx(tx, types.BaseSigner{}, test.key)
+		if err := pool.AddRemote(tx); err != test.err {
+			t.Errorf("policy test %d: error mismatch: have %v, want %v", i, err, test.err)
+		}
+	}
+	// The policies can only be set once the Enterprise fork is active
+	legacy, _ := setupTxPool()
+	defer legacy.Stop()
+
+	legacy.currentState.CreateAccount(contract, types.CreateAccountOption{OwnerAddress: &owner, ProviderAddress: &provider})
+	legacy.currentState.AddBalance(owner, new(big.Int).Mul(big.NewInt(1000000), big.NewInt(params.GasPriceConfig)))
+	tx, _ := types.NewProviderPolicyTransaction(0, contract, 100000, big.NewInt(params.GasPriceConfig), types.ProviderPolicyMsg{Provider: provider})
+	tx, _ = types.SignTx(tx, types.BaseSigner{}, ownerKey)
+	if err := legacy.AddRemote(tx); err != types.ErrTxTypeNotSupported {
+		t.Errorf("error mismatch: have %v, want %v", err, types.ErrTxTypeNotSupported)
+	}
+}
+
+// Tests that the sponsored transactions are only accepted within the scope of
+// their provider signature.
+func TestTransactionProviderScope(t *testing.T) {
+	t.Parallel()
+
+	config := *params.TestChainConfig
+	config.TypedTxBlock = big.NewInt(0)
+	pool, key := setupTxPoolWithConfig(&config)
+	defer pool.Stop()
+
+	var (
+		ownerKey, _    = crypto.GenerateKey()
+		providerKey, _ = crypto.GenerateKey()
+		owner          = crypto.PubkeyToAddress(ownerKey.PublicKey)
+		provider       = crypto.PubkeyToAddress(providerKey.PublicKey)
+		contract       = common.HexToAddress("0xc0")
+		gasPrice       = big.NewInt(params.GasPriceConfig)
+		maxFee         = new(big.Int).Mul(big.NewInt(100000), gasPrice)
+	)
+	pool.currentState.CreateAccount(contract, types.CreateAccountOption{OwnerAddress: &owner, ProviderAddress: &provider})
+	pool.currentState.AddBalance(provider, new(big.Int).Mul(big.NewInt(1000000), gasPrice))
+
+	signer := types.NewTypedSigner(config.ChainID)
+	sponsored := func(nonce uint64, gas uint64, validUntil uint64, maxFee *big.Int) *types.Transaction {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, contract, new(big.Int), gas, gasPrice, nil), signer, key)
+		tx, _ = types.ProviderSignTx(tx.WithProviderScope(validUntil, maxFee), signer, providerKey)
+		return tx
+	}
+	// The scope is checked against the block the transaction is included in
+	for i, test := range []struct {
+		tx     *types.Transaction
+		number int64
+		err    error
+	}{
+		{sponsored(0, 100000, 10, nil), 10, nil},
+		{sponsored(0, 100000, 10, nil), 11, ErrProviderSignatureExpired},
+		{sponsored(0, 100000, 0, maxFee), 11, nil},
+		{sponsored(0, 100001, 0, maxFee), 1, ErrProviderMaxFeeExceeded},
+	} {
+		msg, err := test.tx.AsMessage(signer)
+		if err != nil {
+			t.Fatalf("test %d: failed to convert transaction: %v", i, err)
+		}
+		if err := ValidateEnterpriseTx(pool.currentState, msg, big.NewInt(test.number)); err != test.err {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, test.err)
+		}
+	}
+	if err := pool.AddRemote(sponsored(0, 100001, 1, maxFee)); err != ErrProviderMaxFeeExceeded {
+		t.Errorf("error mismatch: have %v, want %v", err, ErrProviderMaxFeeExceeded)
+	}
+	if err := pool.AddRemote(sponsored(0, 100000, 1, maxFee)); err != nil {
+		t.Errorf("failed to add transaction within the provider scope: %v", err)
+	}
+	// The scope is refused without a provider signature
+	tx, _ := types.SignTx(types.NewTransaction(1, contract, new(big.Int), 100000, gasPrice, nil), signer, key)
+	tx, _ = types.ProviderSignTx(tx, signer, providerKey)
+	if err := pool.AddRemote(tx.WithProviderScope(1, nil)); err != ErrProviderSignatureIsRequired {
+		t.Errorf("error mismatch: have %v, want %v", err, ErrProviderSignatureIsRequired)
+	}
+	// The scope is refused before the TypedTx fork
+	legacy, _ := setupTxPool()
+	defer legacy.Stop()
+
+	legacy.currentState.CreateAccount(contract, types.CreateAccountOption{OwnerAddress: &owner, ProviderAddress: &provider})
+	legacy.currentState.AddBalance(provider, new(big.Int).Mul(big.NewInt(1000000), gasPrice))
+	tx, _ = types.SignTx(types.NewTransaction(0, contract, new(big.Int), 100000, gasPrice, nil), types.BaseSigner{}, key)
+	tx, _ = types.ProviderSignTx(tx.WithProviderScope(1, maxFee), types.BaseSigner{}, providerKey)
+	if err := legacy.AddRemote(tx); err != types.ErrTxTypeNotSupported {
+		t.Errorf("error mismatch: have %v, want %v", err, types.ErrTxTypeNotSupported)
+	}
+}
+
+// Tests that the typed transaction envelopes are only accepted once the TypedTx fork is active,
+// and that the transactions other than the normal ones must be enveloped after it.
+func TestTransactionTypedEnvelope(t *testing.T) {
+	t.Parallel()
+
+	var (
+		providerKey, _ = crypto.GenerateKey()
+		provider       = crypto.PubkeyToAddress(providerKey.PublicKey)
+		contract       = common.HexToAddress("0xc0")
+		gasPrice       = big.NewInt(params.GasPriceConfig)
+		typedConfig    = *params.TestChainConfig
+	)
+	typedConfig.TypedTxBlock = big.NewInt(0)
+
+	sponsored := func(key *ecdsa.PrivateKey, nonce uint64, signer types.Signer) *types.Transaction {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, contract, new(big.Int), 100000, gasPrice, nil), signer, key)
+		tx, _ = types.ProviderSignTx(tx, signer, providerKey)
+		return tx
+	}
+	for i, test := range []struct {
+		config    *params.ChainConfig
+		err       error // error of the typed transaction envelope
+		legacyErr error // error of the legacy encoding
+	}{
+		{params.TestChainConfig, types.ErrTxTypeNotSupported, nil},
+		{&typedConfig, nil, types.ErrTxTypeNotSupported},
+	} {
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+		pool := NewTxPool(testTxPoolConfig, test.config, &testBlockChain{statedb, 1000000, new(event.Feed)})
+		key, _ := crypto.GenerateKey()
+
+		pool.currentState.CreateAccount(contract, types.CreateAccountOption{OwnerAddress: &provider, ProviderAddress: &provider})
+		pool.currentState.AddBalance(provider, new(big.Int).Mul(big.NewInt(1000000), gasPrice))
+		pool.currentState.AddBalance(crypto.PubkeyToAddress(key.PublicKey), new(big.Int).Mul(big.NewInt(1000000), gasPrice))
+
+		// The signer of the chain only envelopes the transactions once the fork is active
+		if tx := sponsored(key, 0, types.MakeSigner(test.config, common.Big1)); tx.Enveloped() != (test.err == nil) {
+			t.Errorf("test %d: envelope mismatch: have %v, want %v", i, tx.Enveloped(), test.err == nil)
+		}
+		if err := pool.AddRemote(sponsored(key, 0, types.NewTypedSigner(test.config.ChainID))); err != test.err {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, test.err)
+		}
+		if err := pool.AddRemote(sponsored(key, 1, types.NewOmahaSigner(test.config.ChainID))); err != test.legacyErr {
+			t.Errorf("test %d: legacy error mismatch: have %v, want %v", i, err, test.legacyErr)
+		}
+		// The normal transactions keep the legacy encoding whether the fork is active or not
+		tx, _ := types.SignTx(types.NewTransaction(2, common.HexToAddress("0xd0"), new(big.Int), 100000, gasPrice, nil), types.MakeSigner(test.config, common.Big1), key)
+		if err := pool.AddRemote(tx); err != nil || tx.Enveloped() {
+			t.Errorf("test %d: failed to add normal transaction: %v", i, err)
+		}
+		pool.Stop()
+	}
+	// A sponsored envelope is not accepted without the provider signature
+	pool, key := setupTxPoolWithConfig(&typedConfig)
+	defer pool.Stop()
+
+	tx := sponsored(key, 0, types.NewTypedSigner(typedConfig.ChainID)).WithProviderScope(0, nil)
+	if err := pool.AddRemote(tx); err != ErrProviderSignatureIsRequired {
+		t.Errorf("error mismatch: have %v, want %v", err, ErrProviderSignatureIsRequired)
+	}
+}
+
+// Tests that the transactions of the owners of an enterprise contract are only
+// accepted from the owners, and that the ownership can only be accepted by the
+// proposed owner.
+func TestTransactionOwnership(t *testing.T) {
+	t.Parallel()
+
+	config := *params.TestChainConfig
+	config.EnterpriseBlock = big.NewInt(0)
+	pool, _ := setupTxPoolWithConfig(&config)
+	defer pool.Stop()
+
+	keys := make([]*ecdsa.PrivateKey, 3)
+	addrs := make([]common.Address, len(keys))
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		addrs[i] = crypto.PubkeyToAddress(keys[i].PublicKey)
+		pool.currentState.AddBalance(addrs[i], new(big.Int).Mul(big.NewInt(1000000), big.NewInt(params.GasPriceConfig)))
+	}
+	contract := common.HexToAddress("0xc0")
+	pool.currentState.CreateAccount(contract, types.CreateAccountOption{OwnerAddress: &addrs[0], ProviderAddress: &addrs[2]})
+
+	gasPrice := big.NewInt(params.GasPriceConfig)
+	accept, _ := types.NewAcceptOwnershipTransaction(0, contract, 100000, gasPrice, addrs[1])
+	transfer, _ := types.NewTransferOwnershipTransaction(0, contract, 100000, gasPrice, addrs[1])
+	invalidOwners, _ := types.NewSetOwnersTransaction(0, contract, 100000, gasPrice, addrs[:2], 3)
+	setOwners, _ := types.NewSetOwnersTransaction(1, contract, 100000, gasPrice, addrs[:2], 2)
+	for i, test := range []struct {
+		tx  *types.Transaction
+		key *ecdsa.PrivateKey
+		err error
+	}{
+		{accept, keys[1], ErrOnlyPendingOwner},
+		{transfer, keys[1], ErrOnlyOwner},
+		{invalidOwners, keys[0], state.ErrInvalidOwnerThreshold},
+		{transfer, keys[0], nil},
+		{setOwners, keys[0], nil},
+	} {
+		tx, _ := types.SignTx(test.tx, types.BaseSigner{}, test.key)
+		if err := pool.AddRemote(tx); err != test.err {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, test.err)
+		}
+	}
+	// The proposed owner can accept the ownership, and the owners of a multisig can act
+	if err := pool.currentState.TransferOwnership(contract, addrs[0], addrs[1]); err != nil {
+		t.Fatalf("failed to transfer ownership: %v", err)
+	}
+	tx, _ := types.SignTx(accept, types.BaseSigner{}, keys[1])
+	if err := pool.AddRemote(tx); err != nil {
+		t.Errorf("failed to accept ownership: %v", err)
+	}
+	if err := pool.currentState.SetOwners(contract, addrs[0], addrs[:2], 2); err != nil {
+		t.Fatalf("failed to set owners: %v", err)
+	}
+	remove, _ := types.NewModifyProvidersTransaction(1, contract, 100000, gasPrice, addrs[2], false)
+	tx, _ = types.SignTx(remove, types.BaseSigner{}, keys[1])
+	if err := pool.AddRemote(tx); err != nil {
+		t.Errorf("failed to add transaction of multisig owner: %v", err)
+	}
+	// The ownership can only be managed once the Enterprise fork is active
+	legacy, _ := setupTxPool()
+	defer legacy.Stop()
+
+	legacy.currentState.CreateAccount(contract, types.CreateAccountOption{OwnerAddress: &addrs[0], ProviderAddress: &addrs[2]})
+	legacy.currentState.AddBalance(addrs[0], new(big.Int).Mul(big.NewInt(1000000), gasPrice))
+	for i, test := range []*types.Transaction{transfer, setOwners} {
+		tx, _ := types.SignTx(test, types.BaseSigner{}, keys[0])
+		if err := legacy.AddRemote(tx); err != types.ErrTxTypeNotSupported {
+			t.Errorf("legacy test %d: error mismatch: have %v, want %v", i, err, types.ErrTxTypeNotSupported)
+		}
+	}
+}
+
 // Benchmarks the speed of validating the contents of the pending queue of the
 // transaction pool.
 func BenchmarkPendingDemotion100(b *testing.B)   { benchmarkPendingDemotion(b, 100) }
@@ -0,0 +1,70 @@
+package types
+
+import (
+	"time"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/common/hexutil"
+)
+
+// ConsensusState is a snapshot of the state of the Tendermint core at its current block number
+type ConsensusState struct {
+	BlockNumber     hexutil.Uint64   `json:"blockNumber"`
+	Round           int64            `json:"round"`
+	Step            string           `json:"step"`
+	StartTime       time.Time        `json:"startTime"`
+	Proposer        common.Address   `json:"proposer"`
+	Proposal        *ProposalState   `json:"proposal"` // Proposal is nil if no proposal is received at the current round
+	LockedRound     int64            `json:"lockedRound"`
+	LockedBlock     *common.Hash     `json:"lockedBlock"`
+	ValidRound      int64            `json:"validRound"`
+	ValidBlock      *common.Hash     `json:"validBlock"`
+	CommitRound     int64            `json:"commitRound"`
+	PrecommitWaited bool             `json:"precommitWaited"`
+	PendingTimeout  *TimeoutState    `json:"pendingTimeout"` // PendingTimeout is nil if no timeout is scheduled
+	Votes           []*RoundVotes    `json:"votes"`          // Votes are the votes received at each round, by round
+	Validators      []common.Address `json:"validators"`
+}
+
+// ProposalState describes the proposal received at the current round
+type ProposalState struct {
+	BlockHash common.Hash `json:"blockHash"`
+	Round     int64       `json:"round"`
+	POLRound  int64       `json:"polRound"`
+	Complete  bool        `json:"complete"` // Complete is true if the POL round of the proposal has +2/3 prevotes
+}
+
+// TimeoutState describes the timeout scheduled by the Tendermint core
+type TimeoutState struct {
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	Round       int64          `json:"round"`
+	Step        string         `json:"step"`
+	Retry       uint64         `json:"retry"`
+	Deadline    time.Time      `json:"deadline"`
+}
+
+// RoundVotes are the prevotes and precommits received by the Tendermint core at a round
+type RoundVotes struct {
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	Round       int64          `json:"round"`
+	Prevotes    *VoteSet       `json:"prevotes"`
+	Precommits  *VoteSet       `json:"precommits"`
+}
+
+// VoteSet describes the votes of a step of a round
+type VoteSet struct {
+	// Votes are the block hashes voted by the validators, the empty hash is a vote for nil
+	Votes            map[common.Address]common.Hash `json:"votes"`
+	Missing          []common.Address               `json:"missing"` // Missing are the validators not voted yet
+	TwoThirdMajority *common.Hash                   `json:"twoThirdMajority"`
+	VotingPower      int64                          `json:"votingPower"`   // VotingPower is the sum of voting power of the votes
+	MajorityPower    int64                          `json:"majorityPower"` // MajorityPower is the minimum voting power for a polka
+}
+
+// RoundStep is a step transition of the consensus state machine of the Tendermint core
+type RoundStep struct {
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	Round       int64          `json:"round"`
+	Step        string         `json:"step"`
+	Time        time.Time      `json:"time"`
+}
@@ -0,0 +1,24 @@
+package types
+
+import (
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/common/hexutil"
+)
+
+//go:generate gencodec -type Finality -field-override finalityMarshaling -out gen_finality_json.go
+
+// Finality describes the main chain blocks finalized by a final chain block.
+// The main chain blocks up to the packed one are final, an evil header is a main chain block
+// whose state transition could not be reproduced while packing.
+type Finality struct {
+	Number      uint64      `json:"number"      gencodec:"required"` // number of the latest finalized main chain block
+	Hash        common.Hash `json:"hash"        gencodec:"required"` // hash of the latest finalized main chain block
+	FinalNumber uint64      `json:"finalNumber" gencodec:"required"` // number of the final chain block
+	FinalHash   common.Hash `json:"finalHash"   gencodec:"required"` // hash of the final chain block
+	EvilHeader  *Header     `json:"evilHeader"`
+}
+
+type finalityMarshaling struct {
+	Number      hexutil.Uint64
+	FinalNumber hexutil.Uint64
+}
@@ -0,0 +1,39 @@
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+)
+
+func TestFinalityJSON(t *testing.T) {
+	finality := &Finality{
+		Number:      12,
+		Hash:        common.HexToHash("0x01"),
+		FinalNumber: 3,
+		FinalHash:   common.HexToHash("0x02"),
+		EvilHeader:  &Header{Number: big.NewInt(13), Difficulty: big.NewInt(1), Extra: []byte{}},
+	}
+	enc, err := json.Marshal(finality)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dec Finality
+	if err := json.Unmarshal(enc, &dec); err != nil {
+		t.Fatal(err)
+	}
+	if dec.EvilHeader.Hash() != finality.EvilHeader.Hash() {
+		t.Errorf("evil header mismatch: have %x, want %x", dec.EvilHeader.Hash(), finality.EvilHeader.Hash())
+	}
+	dec.EvilHeader = finality.EvilHeader
+	if !reflect.DeepEqual(&dec, finality) {
+		t.Errorf("finality mismatch: have %+v, want %+v", dec, finality)
+	}
+
+	if err := json.Unmarshal([]byte(`{"number":"0xc"}`), &dec); err == nil {
+		t.Error("expected an error for missing required fields")
+	}
+}
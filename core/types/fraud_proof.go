@@ -0,0 +1,22 @@
+package types
+
+import (
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/common/hexutil"
+)
+
+// FraudProof proves that the state transition of an evil header is invalid.
+// It holds the transactions of the block and the pre-state trie nodes and contract codes they touch,
+// so that the block can be re-executed without any other state.
+type FraudProof struct {
+	Header       *Header         `json:"header"       gencodec:"required"` // the evil header
+	Parent       *Header         `json:"parent"       gencodec:"required"` // the parent header, holding the state root before the block
+	Transactions Transactions    `json:"transactions" gencodec:"required"`
+	Ancestors    []*Header       `json:"ancestors"    gencodec:"required"` // the ancestors accessed by the BLOCKHASH opcode
+	Nodes        []hexutil.Bytes `json:"nodes"        gencodec:"required"` // the trie nodes and contract codes, sorted by hash
+}
+
+// Hash returns the hash of the RLP encoding of the proof, it is committed to by the final chain block reporting the evil header.
+func (p *FraudProof) Hash() common.Hash {
+	return rlpHash(p)
+}
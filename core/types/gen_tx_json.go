@@ -31,7 +31,9 @@ func (t txdata) MarshalJSON() ([]byte, error) {
 		PV           *hexutil.Big    `json:"pv"       rlp:"nil"`
 		PR           *hexutil.Big    `json:"pr"       rlp:"nil"`
 		PS           *hexutil.Big    `json:"ps"       rlp:"nil"`
+		Type         *hexutil.Uint64 `json:"type,omitempty" rlp:"-"`
 		Hash         *common.Hash    `json:"hash" rlp:"-"`
+		Scope        []ProviderScope `json:"providerScope,omitempty" rlp:"tail"`
 	}
 	var enc txdata
 	enc.AccountNonce = hexutil.Uint64(t.AccountNonce)
@@ -49,7 +51,9 @@ func (t txdata) MarshalJSON() ([]byte, error) {
 	enc.PV = (*hexutil.Big)(t.PV)
 	enc.PR = (*hexutil.Big)(t.PR)
 	enc.PS = (*hexutil.Big)(t.PS)
+	enc.Type = t.Type
 	enc.Hash = t.Hash
+	enc.Scope = t.Scope
 	return json.Marshal(&enc)
 }
 
@@ -71,7 +75,9 @@ func (t *txdata) UnmarshalJSON(input []byte) error {
 		PV           *hexutil.Big    `json:"pv"       rlp:"nil"`
 		PR           *hexutil.Big    `json:"pr"       rlp:"nil"`
 		PS           *hexutil.Big    `json:"ps"       rlp:"nil"`
+		Type         *hexutil.Uint64 `json:"type,omitempty" rlp:"-"`
 		Hash         *common.Hash    `json:"hash" rlp:"-"`
+		Scope        []ProviderScope `json:"providerScope,omitempty" rlp:"tail"`
 	}
 	var dec txdata
 	if err := json.Unmarshal(input, &dec); err != nil {
@@ -130,8 +136,14 @@ func (t *txdata) UnmarshalJSON(input []byte) error {
 	if dec.PS != nil {
 		t.PS = (*big.Int)(dec.PS)
 	}
+	if dec.Type != nil {
+		t.Type = dec.Type
+	}
 	if dec.Hash != nil {
 		t.Hash = dec.Hash
 	}
+	if dec.Scope != nil {
+		t.Scope = dec.Scope
+	}
 	return nil
 }
@@ -0,0 +1,46 @@
+package types
+
+import (
+	"math/big"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/common/hexutil"
+)
+
+//go:generate gencodec -type ValidatorReward -field-override validatorRewardMarshaling -out gen_reward_json.go
+
+// ValidatorReward is the reward distribution of a validator at the end of a Tendermint epoch.
+// The owner keeps the commission of the gross reward, the rest is shared among the voters by their stakes.
+type ValidatorReward struct {
+	Epoch          uint64           `json:"epoch"          gencodec:"required"` // epoch ended by the block distributing the reward
+	Validator      common.Address   `json:"validator"      gencodec:"required"`
+	Owner          common.Address   `json:"owner"          gencodec:"required"`
+	BlockReward    *big.Int         `json:"blockReward"    gencodec:"required"` // block rewards of the blocks proposed by the validator
+	TxFee          *big.Int         `json:"txFee"          gencodec:"required"` // fees of the transactions of the blocks proposed by the validator
+	CommissionRate uint64           `json:"commissionRate" gencodec:"required"` // percentage of the gross reward kept by the owner
+	OwnerReward    *big.Int         `json:"ownerReward"    gencodec:"required"` // share of the owner, the commission and the rounding remainder
+	Voters         []common.Address `json:"voters"         gencodec:"required"`
+	VoterRewards   []*big.Int       `json:"voterRewards"   gencodec:"required"` // share of each voter, by the order of Voters
+}
+
+type validatorRewardMarshaling struct {
+	Epoch          hexutil.Uint64
+	BlockReward    *hexutil.Big
+	TxFee          *hexutil.Big
+	CommissionRate hexutil.Uint64
+	OwnerReward    *hexutil.Big
+	VoterRewards   []*hexutil.Big
+}
+
+// Involves returns true if the address is the validator, the owner or a voter of the reward.
+func (r *ValidatorReward) Involves(addr common.Address) bool {
+	if r.Validator == addr || r.Owner == addr {
+		return true
+	}
+	for _, voter := range r.Voters {
+		if voter == addr {
+			return true
+		}
+	}
+	return false
+}
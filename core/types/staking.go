@@ -0,0 +1,40 @@
+package types
+
+import (
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/common/hexutil"
+)
+
+// StakingInfo is the configuration of the staking contract at a block
+type StakingInfo struct {
+	Contract          common.Address `json:"contract"`
+	Admin             common.Address `json:"admin"`
+	StartBlock        *hexutil.Big   `json:"startBlock"`
+	EpochPeriod       *hexutil.Big   `json:"epochPeriod"`
+	MaxValidatorSize  *hexutil.Big   `json:"maxValidatorSize"`
+	MinValidatorStake *hexutil.Big   `json:"minValidatorStake"`
+	MinVoterCap       *hexutil.Big   `json:"minVoterCap"`
+}
+
+// StakingCandidate is the state of a candidate registered with the staking contract at a block
+type StakingCandidate struct {
+	Address        common.Address  `json:"address"`
+	Owner          common.Address  `json:"owner"`
+	TotalStake     *hexutil.Big    `json:"totalStake"`
+	Voters         hexutil.Uint64  `json:"voters"`
+	CommissionRate *hexutil.Uint64 `json:"commissionRate"` // nil if the candidate has not set one
+	Jailed         bool            `json:"jailed"`
+}
+
+// StakingVote is the stake of a voter for a candidate
+type StakingVote struct {
+	Candidate common.Address `json:"candidate"`
+	Voter     common.Address `json:"voter"`
+	Stake     *hexutil.Big   `json:"stake"`
+}
+
+// StakingWithdrawal is an unvoted stake which can be withdrawn from the epoch
+type StakingWithdrawal struct {
+	Epoch  *hexutil.Big `json:"epoch"`
+	Amount *hexutil.Big `json:"amount"`
+}
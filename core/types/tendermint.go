@@ -48,16 +48,64 @@ type TendermintExtra struct {
 	// Set of authorized validators at this moment
 	ValidatorAdds []byte
 	EvilProof     common.Hash
+	// Evidences are proofs of validators misbehaving, which are included by the proposer.
+	Evidences []*TendermintEvidence
+	// ValidatorPowers are the voting powers of validators in ValidatorAdds, in the same order.
+	// It is empty if the validators have the same voting power.
+	ValidatorPowers []uint64
+	// ValidatorBLSKeys are the BLS public keys of validators in ValidatorAdds, in the same order.
+	// If it is set, the blocks of the epoch are committed with an aggregated BLS seal instead of CommittedSeal.
+	ValidatorBLSKeys [][]byte
+	// AggregatedSeal is the aggregation of the BLS committed seals of the validators in SignersBitmap
+	AggregatedSeal []byte
+	// SignersBitmap marks the validators that signed AggregatedSeal, bit i is set if the i-th validator
+	// of the sorted validator set signed.
+	SignersBitmap []byte
+	// BLSPublicKey and BLSProof register the BLS public key of the proposer with its proof of possession.
+	BLSPublicKey []byte
+	BLSProof     []byte
 }
 
 // EncodeRLP serializes ist into the Evrynet RLP format.
+// The optional fields are encoded at the tail of the extra, so the encoding of a header without them is unchanged.
+// An optional field is only encoded if it or any optional field after it is set.
 func (te *TendermintExtra) EncodeRLP(w io.Writer) error {
-	return rlp.Encode(w, []interface{}{
+	fields := []interface{}{
 		te.Seal,
 		te.CommittedSeal,
 		te.ValidatorAdds,
 		te.EvilProof,
-	})
+	}
+	evidences := te.Evidences
+	if evidences == nil {
+		evidences = []*TendermintEvidence{}
+	}
+	optional := []interface{}{
+		evidences,
+		te.ValidatorPowers,
+		te.ValidatorBLSKeys,
+		te.AggregatedSeal,
+		te.SignersBitmap,
+		te.BLSPublicKey,
+		te.BLSProof,
+	}
+	isSet := []bool{
+		len(te.Evidences) > 0,
+		len(te.ValidatorPowers) > 0,
+		len(te.ValidatorBLSKeys) > 0,
+		len(te.AggregatedSeal) > 0,
+		len(te.SignersBitmap) > 0,
+		len(te.BLSPublicKey) > 0,
+		len(te.BLSProof) > 0,
+	}
+	last := -1
+	for i, set := range isSet {
+		if set {
+			last = i
+		}
+	}
+	fields = append(fields, optional[:last+1]...)
+	return rlp.Encode(w, fields)
 }
 
 // DecodeRLP implements rlp.Decoder, and load the tendermint fields from a RLP stream.
@@ -67,14 +115,52 @@ func (te *TendermintExtra) DecodeRLP(s *rlp.Stream) error {
 		CommittedSeal [][]byte
 		ValidatorAdds []byte
 		EvilProof     common.Hash
+		Optional      []rlp.RawValue `rlp:"tail"`
 	}
 	if err := s.Decode(&tendermintExtra); err != nil {
 		return err
 	}
-	te.Seal, te.CommittedSeal, te.ValidatorAdds, te.EvilProof = tendermintExtra.Seal, tendermintExtra.CommittedSeal, tendermintExtra.ValidatorAdds, tendermintExtra.EvilProof
+	*te = TendermintExtra{
+		Seal:          tendermintExtra.Seal,
+		CommittedSeal: tendermintExtra.CommittedSeal,
+		ValidatorAdds: tendermintExtra.ValidatorAdds,
+		EvilProof:     tendermintExtra.EvilProof,
+	}
+	optional := []interface{}{
+		&te.Evidences,
+		&te.ValidatorPowers,
+		&te.ValidatorBLSKeys,
+		&te.AggregatedSeal,
+		&te.SignersBitmap,
+		&te.BLSPublicKey,
+		&te.BLSProof,
+	}
+	for i, raw := range tendermintExtra.Optional {
+		if i >= len(optional) {
+			break
+		}
+		if err := rlp.DecodeBytes(raw, optional[i]); err != nil {
+			return err
+		}
+	}
+	if len(te.Evidences) == 0 {
+		te.Evidences = nil
+	}
 	return nil
 }
 
+// TendermintEvidence is the proof of a validator signing 2 conflicting votes at the same block number and round.
+// VoteA and VoteB are the signed consensus messages as they were sent over the network.
+type TendermintEvidence struct {
+	VoteA []byte
+	VoteB []byte
+}
+
+// Hash returns the keccak256 hash of the evidence's RLP encoding.
+func (e *TendermintEvidence) Hash() common.Hash {
+	return rlpHash(e)
+}
+
 // ExtractTendermintExtra extracts all values of the TendermintExtra from the header. It returns an
 // error if the length of the given extra-data is less than 32 bytes or the extra-data can not
 // be decoded.
@@ -106,6 +192,10 @@ func TendermintFilteredHeader(h *Header, keepSeal bool) *Header {
 	}
 	tendermintExtra.CommittedSeal = [][]byte{}
 	tendermintExtra.ValidatorAdds = []byte{}
+	tendermintExtra.ValidatorPowers = nil
+	tendermintExtra.ValidatorBLSKeys = nil
+	tendermintExtra.AggregatedSeal = nil
+	tendermintExtra.SignersBitmap = nil
 
 	payload, err := rlp.EncodeToBytes(&tendermintExtra)
 	if err != nil {
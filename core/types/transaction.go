@@ -37,6 +37,10 @@ const (
 	NormalTxType TransactionType = iota
 	AddProviderTxType
 	RemoveProviderTxType
+	SetProviderPolicyTxType
+	TransferOwnershipTxType
+	AcceptOwnershipTxType
+	SetOwnersTxType
 )
 
 var (
@@ -51,6 +55,8 @@ var (
 	ErrEmptyOwner = errors.New("owner is 0")
 	// ErrInvalidExtraDataType is returned if extra data type is invalid
 	ErrInvalidExtraDataType = errors.New("unsupported extra data type")
+	// ErrRedundantProviderScope is returned if the transaction has a provider signature scope but no provider signature.
+	ErrRedundantProviderScope = errors.New("redundant provider signature scope")
 )
 
 // CreateAccountOption contain extra parameter for Account creation
@@ -60,7 +66,9 @@ type CreateAccountOption struct {
 }
 
 type Transaction struct {
-	data txdata
+	data     txdata
+	typ      EnvelopeType // type of the transaction, set by its constructor or decoded
+	envelope bool         // encoded and signed as a typed transaction envelope if its type is not NormalEnvelope
 	// caches
 	hash atomic.Value
 	size atomic.Value
@@ -123,8 +131,12 @@ type txdata struct {
 	PR *big.Int `json:"pr"       rlp:"nil"`
 	PS *big.Int `json:"ps"       rlp:"nil"`
 
-	// This is only used when marshaling to JSON.
-	Hash *common.Hash `json:"hash" rlp:"-"`
+	// These are only used when marshaling to JSON.
+	Type *hexutil.Uint64 `json:"type,omitempty" rlp:"-"`
+	Hash *common.Hash    `json:"hash" rlp:"-"`
+
+	// Scope of the provider signature, at most one element, omitted if the provider signature is not bounded
+	Scope []ProviderScope `json:"providerScope,omitempty" rlp:"tail"`
 }
 
 func (d txdata) toEthTxData() ethTxData {
@@ -141,21 +153,17 @@ func (d txdata) toEthTxData() ethTxData {
 	}
 }
 
-func (d txdata) isEthTxData() bool {
-	if d.Owner != nil {
-		return false
-	}
-	if d.Provider != nil {
-		return false
-	}
-	if (d.PR != nil) || (d.PS != nil) || (d.PV != nil) {
-		return false
-	}
+//go:generate gencodec -type ProviderScope -field-override providerScopeMarshaling -out gen_provider_scope_json.go
 
-	if len(d.Extra) != 0 {
-		return false
-	}
-	return true
+// ProviderScope bounds the exposure of a provider on its signature, it is signed by the provider along with the transaction
+type ProviderScope struct {
+	ValidUntil uint64   `json:"validUntil" gencodec:"required"` // last block the transaction can be included in, no expiry if 0
+	MaxFee     *big.Int `json:"maxFee"     gencodec:"required"` // maximum fee paid by the provider, no cap if 0
+}
+
+type providerScopeMarshaling struct {
+	ValidUntil hexutil.Uint64
+	MaxFee     *hexutil.Big
 }
 
 // TransactionExtraData is info about special transaction
@@ -169,6 +177,39 @@ type ModifyProvidersMsg struct {
 	Provider common.Address
 }
 
+// ProviderPolicyMsg is the policy on the gas a provider pays for an enterprise contract,
+// a zero limit or an empty list means no restriction
+type ProviderPolicyMsg struct {
+	Provider       common.Address
+	EpochGasBudget uint64           // gas the provider pays for in an epoch
+	MaxGasPerTx    uint64           // maximum gas limit of a transaction the provider pays for
+	AllowedSenders []common.Address // senders the provider pays for
+	AllowedMethods [][4]byte        // method selectors the provider pays for
+}
+
+// IsEmpty returns true if the policy does not restrict the provider
+func (p *ProviderPolicyMsg) IsEmpty() bool {
+	return p.EpochGasBudget == 0 && p.MaxGasPerTx == 0 && len(p.AllowedSenders) == 0 && len(p.AllowedMethods) == 0
+}
+
+// OwnershipMsg is info about proposing or accepting a new owner of enterprise contract
+type OwnershipMsg struct {
+	Owner common.Address
+}
+
+// SetOwnersMsg is info about setting the owners of enterprise contract, any action of the owners
+// needs the approvals of Threshold owners
+type SetOwnersMsg struct {
+	Owners    []common.Address
+	Threshold uint64
+}
+
+// OwnerActionHash returns the hash identifying an action of the owners of enterprise contract,
+// the owners approve an action by sending the same transaction type and message
+func OwnerActionHash(txType TransactionType, msg interface{}) common.Hash {
+	return rlpHash([]interface{}{txType, msg})
+}
+
 type txdataMarshaling struct {
 	AccountNonce hexutil.Uint64
 	Price        *hexutil.Big
@@ -194,27 +235,56 @@ func NewContractCreation(nonce uint64, amount *big.Int, gasLimit uint64, gasPric
 	if len(opts) > 0 {
 		tx.data.Owner = opts[0].OwnerAddress
 		tx.data.Provider = opts[0].ProviderAddress
+		if tx.data.Owner != nil || tx.data.Provider != nil {
+			tx.typ = EnterpriseCreationEnvelope
+		}
 	}
 	return tx
 }
 
 // NewModifyProvidersTransaction create a new transaction to add/remove provider
 func NewModifyProvidersTransaction(nonce uint64, to common.Address, gasLimit uint64, gasPrice *big.Int, provider common.Address, isAdd bool) (*Transaction, error) {
-	addProviderMsg := &ModifyProvidersMsg{Provider: provider}
-	msg, err := rlp.EncodeToBytes(addProviderMsg)
-	if err != nil {
-		return nil, err
-	}
 	txType := AddProviderTxType
 	if !isAdd {
 		txType = RemoveProviderTxType
 	}
+	return newExtraDataTransaction(nonce, to, gasLimit, gasPrice, txType, &ModifyProvidersMsg{Provider: provider})
+}
+
+// NewProviderPolicyTransaction create a new transaction to set the policy of a provider, an empty policy removes it
+func NewProviderPolicyTransaction(nonce uint64, to common.Address, gasLimit uint64, gasPrice *big.Int, policy ProviderPolicyMsg) (*Transaction, error) {
+	return newExtraDataTransaction(nonce, to, gasLimit, gasPrice, SetProviderPolicyTxType, &policy)
+}
+
+// NewTransferOwnershipTransaction create a new transaction to propose a new owner, the transfer is done once the new owner accepts it.
+// A zero owner cancels the proposal.
+func NewTransferOwnershipTransaction(nonce uint64, to common.Address, gasLimit uint64, gasPrice *big.Int, owner common.Address) (*Transaction, error) {
+	return newExtraDataTransaction(nonce, to, gasLimit, gasPrice, TransferOwnershipTxType, &OwnershipMsg{Owner: owner})
+}
+
+// NewAcceptOwnershipTransaction create a new transaction for the proposed owner to accept the ownership
+func NewAcceptOwnershipTransaction(nonce uint64, to common.Address, gasLimit uint64, gasPrice *big.Int, owner common.Address) (*Transaction, error) {
+	return newExtraDataTransaction(nonce, to, gasLimit, gasPrice, AcceptOwnershipTxType, &OwnershipMsg{Owner: owner})
+}
+
+// NewSetOwnersTransaction create a new transaction to set the owners and the threshold of approvals of their actions
+func NewSetOwnersTransaction(nonce uint64, to common.Address, gasLimit uint64, gasPrice *big.Int, owners []common.Address, threshold uint64) (*Transaction, error) {
+	return newExtraDataTransaction(nonce, to, gasLimit, gasPrice, SetOwnersTxType, &SetOwnersMsg{Owners: owners, Threshold: threshold})
+}
+
+func newExtraDataTransaction(nonce uint64, to common.Address, gasLimit uint64, gasPrice *big.Int, txType TransactionType, data interface{}) (*Transaction, error) {
+	msg, err := rlp.EncodeToBytes(data)
+	if err != nil {
+		return nil, err
+	}
 	extraData := &TransactionExtraData{Type: txType, Msg: msg}
 	extra, err := rlp.EncodeToBytes(extraData)
 	if err != nil {
 		return nil, err
 	}
-	return newTransaction(nonce, &to, big.NewInt(0), gasLimit, gasPrice, nil, extra), nil
+	tx := newTransaction(nonce, &to, big.NewInt(0), gasLimit, gasPrice, nil, extra)
+	tx.typ = ModifyProvidersEnvelope
+	return tx, nil
 }
 
 func newTransaction(nonce uint64, to *common.Address, amount *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte, extra []byte) *Transaction {
@@ -269,8 +339,16 @@ func isProtectedV(V *big.Int) bool {
 
 // EncodeRLP implements rlp.Encoder
 func (tx *Transaction) EncodeRLP(w io.Writer) error {
-	//if txdata doesn't involve with Evrynet fee scheme, encode it as Evrynet-Node tx for backward compatibility
-	if tx.data.isEthTxData() {
+	// typed transaction envelope is encoded as a string of the type byte followed by the payload
+	if tx.Enveloped() {
+		envelope, err := tx.encodeEnvelope()
+		if err != nil {
+			return err
+		}
+		return rlp.Encode(w, envelope)
+	}
+	//if the transaction doesn't involve with Evrynet fee scheme, encode it as Evrynet-Node tx for backward compatibility
+	if tx.typ == NormalEnvelope {
 		d := tx.data.toEthTxData()
 		return rlp.Encode(w, &d)
 	}
@@ -289,11 +367,25 @@ func (tx *Transaction) DecodeRLP(s *rlp.Stream) error {
 		return err
 	}
 
+	kind, content, _, err := rlp.Split(raw)
+	if err != nil {
+		return err
+	}
+	if kind == rlp.String {
+		typ, data, err := decodeEnvelope(content)
+		if err != nil {
+			return err
+		}
+		tx.data, tx.typ, tx.envelope = data, typ, true
+		tx.size.Store(common.StorageSize(lenStream))
+		return nil
+	}
+
 	var dataWithProvider txdata
 	err = rlp.DecodeBytes(raw, &dataWithProvider)
 
 	if err == nil {
-		tx.data = dataWithProvider
+		tx.data, tx.typ = dataWithProvider, dataWithProvider.legacyType()
 		tx.size.Store(common.StorageSize(rlp.ListSize(lenStream)))
 		return nil
 	}
@@ -315,6 +407,10 @@ func (tx *Transaction) MarshalJSON() ([]byte, error) {
 	hash := tx.Hash()
 	data := tx.data
 	data.Hash = &hash
+	if tx.Enveloped() {
+		typ := hexutil.Uint64(tx.Type())
+		data.Type = &typ
+	}
 	return data.MarshalJSON()
 }
 
@@ -339,7 +435,15 @@ func (tx *Transaction) UnmarshalJSON(input []byte) error {
 		}
 	}
 
-	*tx = Transaction{data: dec}
+	if dec.Type == nil {
+		*tx = Transaction{data: dec, typ: dec.legacyType()}
+		return nil
+	}
+	typ := EnvelopeType(*dec.Type)
+	if typ == NormalEnvelope || !dec.fits(typ) {
+		return ErrInvalidEnvelope
+	}
+	*tx = Transaction{data: dec, typ: typ, envelope: true}
 	return nil
 }
 
@@ -361,13 +465,19 @@ func (tx *Transaction) To() *common.Address {
 	return &to
 }
 
-// Hash hashes the RLP encoding of tx.
+// Hash hashes the RLP encoding of tx, or the type byte followed by the RLP encoding
+// of the payload of a typed transaction envelope.
 // It uniquely identifies the transaction.
 func (tx *Transaction) Hash() common.Hash {
 	if hash := tx.hash.Load(); hash != nil {
 		return hash.(common.Hash)
 	}
-	v := rlpHash(tx)
+	var v common.Hash
+	if tx.Enveloped() {
+		v = prefixedRlpHash(byte(tx.Type()), tx.envelopePayload())
+	} else {
+		v = rlpHash(tx)
+	}
 	tx.hash.Store(v)
 	return v
 }
@@ -379,7 +489,11 @@ func (tx *Transaction) Size() common.StorageSize {
 		return size.(common.StorageSize)
 	}
 	c := writeCounter(0)
-	rlp.Encode(&c, &tx.data)
+	if tx.Enveloped() {
+		rlp.Encode(&c, tx)
+	} else {
+		rlp.Encode(&c, &tx.data)
+	}
 	tx.size.Store(common.StorageSize(c))
 	return common.StorageSize(c)
 }
@@ -435,7 +549,12 @@ func (tx *Transaction) AsMessage(s Signer) (Message, error) {
 	if provider != nil {
 		msg.hasProviderSignature = true
 		msg.gasPayer = *provider
+		msg.providerValidUntil = tx.ProviderValidUntil()
+		msg.providerMaxFee = tx.ProviderMaxFee()
 	} else {
+		if len(tx.data.Scope) != 0 {
+			return msg, ErrRedundantProviderScope
+		}
 		msg.gasPayer = msg.from
 	}
 
@@ -465,6 +584,24 @@ func decodeTransactionExtraData(data txdata) (TransactionType, interface{}, erro
 			return NormalTxType, nil, err
 		}
 		return extraData.Type, providerData, nil
+	case SetProviderPolicyTxType:
+		var policy ProviderPolicyMsg
+		if err := rlp.DecodeBytes(extraData.Msg, &policy); err != nil {
+			return NormalTxType, nil, err
+		}
+		return extraData.Type, policy, nil
+	case TransferOwnershipTxType, AcceptOwnershipTxType:
+		var ownershipData OwnershipMsg
+		if err := rlp.DecodeBytes(extraData.Msg, &ownershipData); err != nil {
+			return NormalTxType, nil, err
+		}
+		return extraData.Type, ownershipData, nil
+	case SetOwnersTxType:
+		var ownersData SetOwnersMsg
+		if err := rlp.DecodeBytes(extraData.Msg, &ownersData); err != nil {
+			return NormalTxType, nil, err
+		}
+		return extraData.Type, ownersData, nil
 	default:
 		return extraData.Type, nil, ErrInvalidExtraDataType
 	}
@@ -477,11 +614,16 @@ func (tx *Transaction) WithProviderSignature(signer Signer, sig []byte) (*Transa
 	if err != nil {
 		return nil, err
 	}
-	cpy := &Transaction{data: tx.data}
+	cpy := &Transaction{data: tx.data, typ: tx.sponsoredType(), envelope: tx.envelope}
 	cpy.data.PR, cpy.data.PS, cpy.data.PV = r, s, v
 	return cpy, nil
 }
 
+// HasProviderSignature returns true if the transaction is signed by a provider paying for its gas.
+func (tx *Transaction) HasProviderSignature() bool {
+	return tx.data.hasProviderSignature()
+}
+
 func (tx *Transaction) RawProviderSignatureValues() (*big.Int, *big.Int, *big.Int) {
 	return tx.data.PV, tx.data.PR, tx.data.PS
 }
@@ -490,6 +632,51 @@ func (tx *Transaction) Owner() *common.Address {
 	return tx.data.Owner
 }
 
+// ProviderScope returns a copy of the scope of the provider signature, nil if the provider signature is not bounded.
+func (tx *Transaction) ProviderScope() *ProviderScope {
+	if len(tx.data.Scope) == 0 {
+		return nil
+	}
+	scope := ProviderScope{ValidUntil: tx.data.Scope[0].ValidUntil, MaxFee: new(big.Int)}
+	if tx.data.Scope[0].MaxFee != nil {
+		scope.MaxFee.Set(tx.data.Scope[0].MaxFee)
+	}
+	return &scope
+}
+
+// ProviderValidUntil returns the last block the provider signature is valid in, 0 if it does not expire.
+func (tx *Transaction) ProviderValidUntil() uint64 {
+	if len(tx.data.Scope) == 0 {
+		return 0
+	}
+	return tx.data.Scope[0].ValidUntil
+}
+
+// ProviderMaxFee returns the maximum fee the provider pays for the transaction, nil if it is not capped.
+func (tx *Transaction) ProviderMaxFee() *big.Int {
+	if len(tx.data.Scope) == 0 || tx.data.Scope[0].MaxFee == nil || tx.data.Scope[0].MaxFee.Sign() == 0 {
+		return nil
+	}
+	return new(big.Int).Set(tx.data.Scope[0].MaxFee)
+}
+
+// WithProviderScope returns a new transaction whose provider signature is only valid until the block validUntil
+// and for a fee up to maxFee, zero values leave the signature unbounded. The provider signature is removed as
+// the scope is part of the signed hash, the transaction has to be signed by the provider afterwards.
+func (tx *Transaction) WithProviderScope(validUntil uint64, maxFee *big.Int) *Transaction {
+	cpy := &Transaction{data: tx.data, typ: tx.sponsoredType(), envelope: tx.envelope}
+	cpy.data.PV, cpy.data.PR, cpy.data.PS = nil, nil, nil
+	cpy.data.Scope = nil
+	if validUntil != 0 || (maxFee != nil && maxFee.Sign() != 0) {
+		scope := ProviderScope{ValidUntil: validUntil, MaxFee: new(big.Int)}
+		if maxFee != nil {
+			scope.MaxFee.Set(maxFee)
+		}
+		cpy.data.Scope = []ProviderScope{scope}
+	}
+	return cpy
+}
+
 func (tx *Transaction) Provider() *common.Address {
 	return tx.data.Provider
 }
@@ -518,7 +705,7 @@ func (tx *Transaction) WithSignature(signer Signer, sig []byte) (*Transaction, e
 	if err != nil {
 		return nil, err
 	}
-	cpy := &Transaction{data: tx.data}
+	cpy := &Transaction{data: tx.data, typ: tx.typ, envelope: tx.envelope}
 	cpy.data.R, cpy.data.S, cpy.data.V = r, s, v
 	return cpy, nil
 }
@@ -696,6 +883,8 @@ type Message struct {
 	// enterprise contract interaction params
 	gasPayer             common.Address
 	hasProviderSignature bool
+	providerValidUntil   uint64
+	providerMaxFee       *big.Int
 	// special data params
 	txType    TransactionType
 	extraData interface{}
@@ -732,3 +921,5 @@ func (m Message) CheckNonce() bool           { return m.checkNonce }
 func (m Message) TxType() TransactionType    { return m.txType }
 func (m Message) ExtraData() interface{}     { return m.extraData }
 func (m Message) HasProviderSignature() bool { return m.hasProviderSignature }
+func (m Message) ProviderValidUntil() uint64 { return m.providerValidUntil }
+func (m Message) ProviderMaxFee() *big.Int   { return m.providerMaxFee }
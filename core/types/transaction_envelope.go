@@ -0,0 +1,303 @@
+// Copyright 2020 The evrynet-node Authors
+// This file is part of the evrynet-node library.
+//
+// The evrynet-node library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The evrynet-node library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the evrynet-node library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/rlp"
+	"golang.org/x/crypto/sha3"
+)
+
+// EnvelopeType is the type of a transaction in the typed transaction envelope, which is
+// encoded as the type byte followed by the RLP encoded payload of the type.
+type EnvelopeType uint8
+
+const (
+	// NormalEnvelope is a plain transaction, it is always encoded as the legacy RLP list
+	NormalEnvelope EnvelopeType = iota
+	// EnterpriseCreationEnvelope creates an enterprise contract with an owner and a provider
+	EnterpriseCreationEnvelope
+	// ModifyProvidersEnvelope is a transaction of the owners of an enterprise contract carrying the type
+	// and the message of its action, e.g. modifying the providers
+	ModifyProvidersEnvelope
+	// SponsoredEnvelope is a transaction to an enterprise contract whose gas is paid by a provider
+	SponsoredEnvelope
+)
+
+var (
+	// ErrTxTypeNotSupported is returned if the typed transaction envelope is not supported by the signer or the chain.
+	ErrTxTypeNotSupported = errors.New("transaction type not supported")
+	// ErrInvalidEnvelope is returned if the payload of a typed transaction envelope does not match its type.
+	ErrInvalidEnvelope = errors.New("invalid typed transaction envelope")
+)
+
+// enterpriseCreationPayload is the payload of EnterpriseCreationEnvelope
+type enterpriseCreationPayload struct {
+	AccountNonce uint64
+	Price        *big.Int
+	GasLimit     uint64
+	Amount       *big.Int
+	Payload      []byte
+	Owner        *common.Address `rlp:"nil"`
+	Provider     *common.Address `rlp:"nil"`
+	V, R, S      *big.Int
+}
+
+// modifyProvidersPayload is the payload of ModifyProvidersEnvelope, the action is never a NormalTxType
+type modifyProvidersPayload struct {
+	AccountNonce uint64
+	Price        *big.Int
+	GasLimit     uint64
+	Recipient    common.Address
+	Action       TransactionType
+	Msg          []byte
+	V, R, S      *big.Int
+}
+
+// sponsoredPayload is the payload of SponsoredEnvelope
+type sponsoredPayload struct {
+	AccountNonce uint64
+	Price        *big.Int
+	GasLimit     uint64
+	Recipient    common.Address
+	Amount       *big.Int
+	Payload      []byte
+	V, R, S      *big.Int
+	PV, PR, PS   *big.Int
+	Scope        []ProviderScope `rlp:"tail"`
+}
+
+// hasProviderSignature returns true if the transaction data has non zero provider signature values
+func (d *txdata) hasProviderSignature() bool {
+	return (d.PV != nil && d.PV.Sign() != 0) || (d.PR != nil && d.PR.Sign() != 0) || (d.PS != nil && d.PS.Sign() != 0)
+}
+
+// legacyType returns the type of a transaction decoded from the legacy encoding, which does not carry it.
+// The transactions with any of the fields of the enterprise transactions keep the full legacy encoding.
+func (d *txdata) legacyType() EnvelopeType {
+	switch {
+	case d.Recipient == nil && (d.Owner != nil || d.Provider != nil):
+		return EnterpriseCreationEnvelope
+	case len(d.Extra) != 0:
+		return ModifyProvidersEnvelope
+	case d.Owner != nil || d.Provider != nil || d.PV != nil || d.PR != nil || d.PS != nil || len(d.Scope) != 0:
+		return SponsoredEnvelope
+	default:
+		return NormalEnvelope
+	}
+}
+
+// ownerAction decodes the type and the message of the action of the owners carried in the extra data
+func (d *txdata) ownerAction() (TransactionExtraData, error) {
+	var action TransactionExtraData
+	if err := rlp.DecodeBytes(d.Extra, &action); err != nil {
+		return TransactionExtraData{}, err
+	}
+	if action.Type == NormalTxType {
+		return TransactionExtraData{}, ErrInvalidExtraDataType
+	}
+	return action, nil
+}
+
+// fits returns true if the transaction data is a transaction of the type which can be carried by
+// the payload of the type without losing any field
+func (d *txdata) fits(typ EnvelopeType) bool {
+	switch typ {
+	case EnterpriseCreationEnvelope:
+		return d.Recipient == nil && (d.Owner != nil || d.Provider != nil) && len(d.Extra) == 0 && !d.hasProviderSignature() && len(d.Scope) == 0
+	case ModifyProvidersEnvelope:
+		if d.Recipient == nil || d.Owner != nil || d.Provider != nil || d.hasProviderSignature() || len(d.Scope) != 0 {
+			return false
+		}
+		if (d.Amount != nil && d.Amount.Sign() != 0) || len(d.Payload) != 0 {
+			return false
+		}
+		_, err := d.ownerAction()
+		return err == nil
+	case SponsoredEnvelope:
+		return d.Recipient != nil && d.Owner == nil && d.Provider == nil && len(d.Extra) == 0
+	default:
+		return false
+	}
+}
+
+// Type returns the type of the transaction, it is set when the transaction is created or decoded.
+func (tx *Transaction) Type() EnvelopeType {
+	return tx.typ
+}
+
+// Enveloped returns true if the transaction is encoded and signed as a typed transaction envelope,
+// false if it keeps the legacy encoding.
+func (tx *Transaction) Enveloped() bool {
+	return tx.envelope && tx.typ != NormalEnvelope && tx.data.fits(tx.typ)
+}
+
+// sponsoredEnveloped returns true if the provider signature of the transaction is the one of
+// a sponsored transaction envelope, the transaction may not be signed by the provider yet.
+func (tx *Transaction) sponsoredEnveloped() bool {
+	return tx.typ == SponsoredEnvelope && tx.Enveloped()
+}
+
+// withEnvelope returns the transaction marked to be encoded as a typed transaction envelope or not
+func (tx *Transaction) withEnvelope(envelope bool) *Transaction {
+	if tx.envelope == envelope {
+		return tx
+	}
+	return &Transaction{data: tx.data, typ: tx.typ, envelope: envelope}
+}
+
+// sponsoredType returns the type of the transaction once a provider pays for its gas,
+// a normal transaction becomes a sponsored transaction.
+func (tx *Transaction) sponsoredType() EnvelopeType {
+	if tx.typ == NormalEnvelope {
+		return SponsoredEnvelope
+	}
+	return tx.typ
+}
+
+// envelopePayload returns the payload of the typed transaction envelope
+func (tx *Transaction) envelopePayload() interface{} {
+	d := &tx.data
+	switch tx.typ {
+	case EnterpriseCreationEnvelope:
+		return &enterpriseCreationPayload{
+			AccountNonce: d.AccountNonce, Price: d.Price, GasLimit: d.GasLimit, Amount: d.Amount, Payload: d.Payload,
+			Owner: d.Owner, Provider: d.Provider,
+			V: d.V, R: d.R, S: d.S,
+		}
+	case ModifyProvidersEnvelope:
+		// the extra data is checked to decode before the transaction is enveloped
+		action, _ := d.ownerAction()
+		return &modifyProvidersPayload{
+			AccountNonce: d.AccountNonce, Price: d.Price, GasLimit: d.GasLimit, Recipient: *d.Recipient,
+			Action: action.Type, Msg: action.Msg,
+			V: d.V, R: d.R, S: d.S,
+		}
+	default:
+		return &sponsoredPayload{
+			AccountNonce: d.AccountNonce, Price: d.Price, GasLimit: d.GasLimit, Recipient: *d.Recipient, Amount: d.Amount, Payload: d.Payload,
+			V: d.V, R: d.R, S: d.S,
+			PV: d.PV, PR: d.PR, PS: d.PS,
+			Scope: d.Scope,
+		}
+	}
+}
+
+// encodeEnvelope returns the type byte followed by the RLP encoded payload
+func (tx *Transaction) encodeEnvelope() ([]byte, error) {
+	payload, err := rlp.EncodeToBytes(tx.envelopePayload())
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{byte(tx.typ)}, payload...), nil
+}
+
+// decodeEnvelope decodes the type byte followed by the RLP encoded payload of the type
+func decodeEnvelope(b []byte) (EnvelopeType, txdata, error) {
+	if len(b) == 0 {
+		return NormalEnvelope, txdata{}, ErrInvalidEnvelope
+	}
+	var (
+		typ = EnvelopeType(b[0])
+		d   txdata
+	)
+	switch typ {
+	case EnterpriseCreationEnvelope:
+		var p enterpriseCreationPayload
+		if err := rlp.DecodeBytes(b[1:], &p); err != nil {
+			return NormalEnvelope, txdata{}, err
+		}
+		d = txdata{
+			AccountNonce: p.AccountNonce, Price: p.Price, GasLimit: p.GasLimit, Amount: p.Amount, Payload: p.Payload,
+			Owner: p.Owner, Provider: p.Provider,
+			V: p.V, R: p.R, S: p.S,
+		}
+	case ModifyProvidersEnvelope:
+		var p modifyProvidersPayload
+		if err := rlp.DecodeBytes(b[1:], &p); err != nil {
+			return NormalEnvelope, txdata{}, err
+		}
+		extra, err := rlp.EncodeToBytes(&TransactionExtraData{Type: p.Action, Msg: p.Msg})
+		if err != nil {
+			return NormalEnvelope, txdata{}, err
+		}
+		d = txdata{
+			AccountNonce: p.AccountNonce, Price: p.Price, GasLimit: p.GasLimit, Recipient: &p.Recipient, Amount: new(big.Int),
+			Extra: extra,
+			V:     p.V, R: p.R, S: p.S,
+		}
+	case SponsoredEnvelope:
+		var p sponsoredPayload
+		if err := rlp.DecodeBytes(b[1:], &p); err != nil {
+			return NormalEnvelope, txdata{}, err
+		}
+		d = txdata{
+			AccountNonce: p.AccountNonce, Price: p.Price, GasLimit: p.GasLimit, Recipient: &p.Recipient, Amount: p.Amount, Payload: p.Payload,
+			V: p.V, R: p.R, S: p.S,
+			PV: p.PV, PR: p.PR, PS: p.PS,
+			Scope: p.Scope,
+		}
+	default:
+		return NormalEnvelope, txdata{}, ErrTxTypeNotSupported
+	}
+	// the fields of the payload must make a transaction of its type
+	if !d.fits(typ) {
+		return NormalEnvelope, txdata{}, ErrInvalidEnvelope
+	}
+	return typ, d, nil
+}
+
+// envelopeSigHash returns the hash to be signed by the sender of an enterprise creation or modify providers envelope,
+// the type is part of the hash so that the signature can not be replayed for another type.
+func (tx *Transaction) envelopeSigHash(chainID *big.Int) common.Hash {
+	d := &tx.data
+	if tx.typ == EnterpriseCreationEnvelope {
+		return prefixedRlpHash(byte(EnterpriseCreationEnvelope), []interface{}{
+			d.AccountNonce, d.Price, d.GasLimit, d.Amount, d.Payload,
+			d.Owner, d.Provider,
+			chainID,
+		})
+	}
+	action, _ := d.ownerAction()
+	return prefixedRlpHash(byte(ModifyProvidersEnvelope), []interface{}{
+		d.AccountNonce, d.Price, d.GasLimit, d.Recipient,
+		action.Type, action.Msg,
+		chainID,
+	})
+}
+
+// envelopeProviderSigHash returns the hash to be signed by the provider of a sponsored envelope
+func (tx *Transaction) envelopeProviderSigHash(chainID *big.Int, sender common.Address) common.Hash {
+	d := &tx.data
+	return prefixedRlpHash(byte(SponsoredEnvelope), withProviderScope([]interface{}{
+		d.AccountNonce, d.Price, d.GasLimit, d.Recipient, d.Amount, d.Payload,
+		chainID,
+		sender,
+	}, d.Scope...))
+}
+
+// prefixedRlpHash writes the prefix into the hasher before rlp-encoding x.
+func prefixedRlpHash(prefix byte, x interface{}) (h common.Hash) {
+	hw := sha3.NewLegacyKeccak256()
+	hw.Write([]byte{prefix})
+	rlp.Encode(hw, x)
+	hw.Sum(h[:0])
+	return h
+}
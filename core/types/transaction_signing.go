@@ -40,11 +40,22 @@ type sigCache struct {
 
 // MakeSigner returns a Signer based on the given chain config and block number.
 func MakeSigner(config *params.ChainConfig, blockNumber *big.Int) Signer {
+	if config.IsTypedTx(blockNumber) {
+		return NewTypedSigner(config.ChainID)
+	}
 	return NewOmahaSigner(config.ChainID)
 }
 
+// typedSigner returns true if the signer signs the transactions as typed transaction envelopes
+func typedSigner(s Signer) bool {
+	omaha, ok := s.(OmahaSigner)
+	return ok && omaha.typed
+}
+
 // ProviderSignTx signs the transaction using the given signer and private key
 func ProviderSignTx(tx *Transaction, s Signer, prv *ecdsa.PrivateKey) (*Transaction, error) {
+	// the transaction becomes sponsored, it stays enveloped if it is already enveloped by its sender
+	tx = &Transaction{data: tx.data, typ: tx.sponsoredType(), envelope: tx.envelope || typedSigner(s)}
 	h, err := s.HashWithSender(tx)
 	if err != nil {
 		return nil, err
@@ -58,6 +69,7 @@ func ProviderSignTx(tx *Transaction, s Signer, prv *ecdsa.PrivateKey) (*Transact
 
 // SignTx signs the transaction using the given signer and private key
 func SignTx(tx *Transaction, s Signer, prv *ecdsa.PrivateKey) (*Transaction, error) {
+	tx = tx.withEnvelope(typedSigner(s))
 	h := s.Hash(tx)
 	sig, err := crypto.Sign(h[:], prv)
 	if err != nil {
@@ -131,8 +143,11 @@ type Signer interface {
 	Equal(Signer) bool
 }
 
+// OmahaSigner recovers both the legacy transactions and the typed transaction envelopes,
+// it signs the transactions as typed transaction envelopes if it is created by NewTypedSigner.
 type OmahaSigner struct {
 	chainId, chainIdMul *big.Int
+	typed               bool
 }
 
 func NewOmahaSigner(chainId *big.Int) OmahaSigner {
@@ -145,6 +160,14 @@ func NewOmahaSigner(chainId *big.Int) OmahaSigner {
 	}
 }
 
+// NewTypedSigner returns a signer signing the enterprise transactions as typed transaction envelopes,
+// it is the signer of the blocks after the TypedTx fork.
+func NewTypedSigner(chainId *big.Int) OmahaSigner {
+	signer := NewOmahaSigner(chainId)
+	signer.typed = true
+	return signer
+}
+
 func (s OmahaSigner) Equal(s2 Signer) bool {
 	omaha, ok := s2.(OmahaSigner)
 	return ok && omaha.chainId.Cmp(s.chainId) == 0
@@ -198,6 +221,10 @@ func (s OmahaSigner) SignatureValues(tx *Transaction, sig []byte) (R, S, V *big.
 // Hash returns the hash to be signed by the sender.
 // It does not uniquely identify the transaction.
 func (s OmahaSigner) Hash(tx *Transaction) common.Hash {
+	// the sender of a sponsored envelope signs the same hash as for a normal transaction
+	if tx.Enveloped() && tx.Type() != SponsoredEnvelope {
+		return tx.envelopeSigHash(s.chainId)
+	}
 	if tx.data.Provider == nil && len(tx.data.Extra) == 0 {
 		return rlpHash([]interface{}{
 			tx.data.AccountNonce,
@@ -230,8 +257,11 @@ func (s OmahaSigner) HashWithSender(tx *Transaction) (common.Hash, error) {
 	if err != nil {
 		return common.Hash{}, err
 	}
+	if tx.sponsoredEnveloped() {
+		return tx.envelopeProviderSigHash(s.chainId, sender), nil
+	}
 
-	return rlpHash([]interface{}{
+	return rlpHash(withProviderScope([]interface{}{
 		tx.data.AccountNonce,
 		tx.data.Price,
 		tx.data.GasLimit,
@@ -243,7 +273,7 @@ func (s OmahaSigner) HashWithSender(tx *Transaction) (common.Hash, error) {
 		tx.data.Extra,
 		s.chainId, uint(0), uint(0),
 		sender,
-	}), nil
+	}, tx.data.Scope...)), nil
 }
 
 type BaseSigner struct{}
@@ -299,7 +329,7 @@ func (bs BaseSigner) HashWithSender(tx *Transaction) (common.Hash, error) {
 	if err != nil {
 		return common.Hash{}, nil
 	}
-	return rlpHash([]interface{}{
+	return rlpHash(withProviderScope([]interface{}{
 		tx.data.AccountNonce,
 		tx.data.Price,
 		tx.data.GasLimit,
@@ -309,11 +339,14 @@ func (bs BaseSigner) HashWithSender(tx *Transaction) (common.Hash, error) {
 		tx.data.Owner,
 		tx.data.Provider,
 		sender,
-	}), nil
+	}, tx.data.Scope...)), nil
 }
 
 //Provider return the Address of provider based on PV, PS, PR
 func (bs BaseSigner) Provider(tx *Transaction) (common.Address, error) {
+	if tx.sponsoredEnveloped() {
+		return common.Address{}, ErrTxTypeNotSupported
+	}
 	h, err := bs.HashWithSender(tx)
 	if err != nil {
 		return common.Address{}, err
@@ -322,9 +355,21 @@ func (bs BaseSigner) Provider(tx *Transaction) (common.Address, error) {
 }
 
 func (bs BaseSigner) Sender(tx *Transaction) (common.Address, error) {
+	if tx.Enveloped() {
+		return common.Address{}, ErrTxTypeNotSupported
+	}
 	return recoverPlain(bs.Hash(tx), tx.data.R, tx.data.S, tx.data.V, true)
 }
 
+// withProviderScope appends the scope of the provider signature to the fields signed by the provider,
+// the fields are unchanged if the signature is not bounded so that the existing signatures stay valid
+func withProviderScope(fields []interface{}, scope ...ProviderScope) []interface{} {
+	for _, s := range scope {
+		fields = append(fields, s)
+	}
+	return fields
+}
+
 func recoverPlain(sighash common.Hash, R, S, Vb *big.Int, base bool) (common.Address, error) {
 	if Vb == nil || Vb.BitLen() > 8 {
 		return common.Address{}, ErrInvalidSig
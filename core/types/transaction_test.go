@@ -302,6 +302,19 @@ func TestTransaction_AsMessage(t *testing.T) {
 	invalidAddProviderTx, err = ProviderSignTx(invalidAddProviderTx, signer, testKey2)
 	require.NoError(t, err)
 
+	policy := ProviderPolicyMsg{
+		Provider:       testAddr2,
+		EpochGasBudget: 1000000,
+		MaxGasPerTx:    100000,
+		AllowedSenders: []common.Address{testAddr},
+		AllowedMethods: [][4]byte{{0xa9, 0x05, 0x9c, 0xbb}},
+	}
+	providerPolicyTx, err := NewProviderPolicyTransaction(uint64(3), contractAddr, 1000000,
+		big.NewInt(params.GasPriceConfig), policy)
+	require.NoError(t, err)
+	providerPolicyTx, err = SignTx(providerPolicyTx, signer, testKey)
+	require.NoError(t, err)
+
 	var testCases = []struct {
 		tx                      *Transaction
 		expectedErr             error
@@ -344,6 +357,15 @@ func TestTransaction_AsMessage(t *testing.T) {
 				require.True(t, ok)
 				require.Equal(t, extraData.Provider, testAddr2)
 			},
+		}, {
+			tx:                      providerPolicyTx,
+			expectedErr:             nil,
+			expectedFromAddress:     testAddr,
+			expectedGasPayerAddress: testAddr,
+			assertFn: func(msg Message) {
+				require.Equal(t, msg.txType, SetProviderPolicyTxType)
+				require.Equal(t, msg.extraData, policy)
+			},
 		},
 	}
 
@@ -361,3 +383,164 @@ func TestTransaction_AsMessage(t *testing.T) {
 		}
 	}
 }
+
+// TestTransactionProviderScope tests that the scope of the provider signature is signed by the provider
+// and kept by the RLP and JSON encodings.
+func TestTransactionProviderScope(t *testing.T) {
+	signer := NewOmahaSigner(common.Big1)
+	tx, err := SignTx(NewTransaction(0, b, big.NewInt(1), 21000, big.NewInt(params.GasPriceConfig), nil), signer, testKey2)
+	require.NoError(t, err)
+
+	// an unbounded provider signature keeps its hash
+	unscoped := tx.WithProviderScope(0, nil)
+	require.Nil(t, unscoped.ProviderScope())
+	unscopedHash, err := signer.HashWithSender(unscoped)
+	require.NoError(t, err)
+	hash, err := signer.HashWithSender(tx)
+	require.NoError(t, err)
+	require.Equal(t, hash, unscopedHash)
+
+	maxFee := new(big.Int).Mul(big.NewInt(21000), big.NewInt(params.GasPriceConfig))
+	scoped, err := ProviderSignTx(tx.WithProviderScope(100, maxFee), signer, testKey)
+	require.NoError(t, err)
+	require.Equal(t, uint64(100), scoped.ProviderValidUntil())
+	require.Equal(t, maxFee, scoped.ProviderMaxFee())
+
+	enc, err := rlp.EncodeToBytes(scoped)
+	require.NoError(t, err)
+	var decoded Transaction
+	require.NoError(t, rlp.DecodeBytes(enc, &decoded))
+	require.Equal(t, scoped.Hash(), decoded.Hash())
+
+	data, err := json.Marshal(scoped)
+	require.NoError(t, err)
+	var parsed Transaction
+	require.NoError(t, json.Unmarshal(data, &parsed))
+	require.Equal(t, scoped.Hash(), parsed.Hash())
+
+	for _, tx := range []*Transaction{scoped, &decoded, &parsed} {
+		msg, err := tx.AsMessage(signer)
+		require.NoError(t, err)
+		require.Equal(t, testAddr, msg.GasPayer())
+		require.Equal(t, uint64(100), msg.ProviderValidUntil())
+		require.Equal(t, maxFee, msg.ProviderMaxFee())
+	}
+
+	// the provider signature does not cover another scope
+	tampered := &Transaction{data: scoped.data}
+	tampered.data.Scope = []ProviderScope{{ValidUntil: 200, MaxFee: maxFee}}
+	provider, err := Provider(signer, tampered)
+	require.True(t, err != nil || provider == nil || *provider != testAddr)
+
+	// the scope is not allowed without provider signature
+	_, err = tx.WithProviderScope(100, nil).AsMessage(signer)
+	require.Equal(t, ErrRedundantProviderScope, err)
+}
+
+// TestTransactionEnvelope tests that the transactions signed by the typed signer are encoded and signed
+// as typed transaction envelopes, and that the legacy encodings are still decoded.
+func TestTransactionEnvelope(t *testing.T) {
+	var (
+		legacySigner = NewOmahaSigner(common.Big1)
+		typedSigner  = NewTypedSigner(common.Big1)
+		gasPrice     = big.NewInt(params.GasPriceConfig)
+	)
+	modifyTx, err := NewModifyProvidersTransaction(1, b, 50000, gasPrice, testAddr2, true)
+	require.NoError(t, err)
+
+	tests := []struct {
+		tx          *Transaction
+		typ         EnvelopeType
+		enveloped   bool
+		providerKey *ecdsa.PrivateKey
+	}{
+		{NewTransaction(0, b, big.NewInt(1), 21000, gasPrice, nil), NormalEnvelope, false, nil},
+		{NewContractCreation(0, big.NewInt(0), 100000, gasPrice, []byte{0x01}, CreateAccountOption{OwnerAddress: &testAddr, ProviderAddress: &testAddr2}), EnterpriseCreationEnvelope, true, nil},
+		{modifyTx, ModifyProvidersEnvelope, true, nil},
+		{NewTransaction(2, b, big.NewInt(1), 21000, gasPrice, nil).WithProviderScope(100, nil), SponsoredEnvelope, true, testKey2},
+	}
+	for i, test := range tests {
+		legacy, err := SignTx(test.tx, legacySigner, testKey)
+		require.NoError(t, err)
+		typed, err := SignTx(test.tx, typedSigner, testKey)
+		require.NoError(t, err)
+		if test.providerKey != nil {
+			legacy, err = ProviderSignTx(legacy, legacySigner, test.providerKey)
+			require.NoError(t, err)
+			typed, err = ProviderSignTx(typed, typedSigner, test.providerKey)
+			require.NoError(t, err)
+		}
+		require.Equal(t, test.typ, typed.Type(), "test %d", i)
+		require.Equal(t, test.typ, legacy.Type(), "test %d", i)
+		require.False(t, legacy.Enveloped(), "test %d", i)
+		require.Equal(t, test.enveloped, typed.Enveloped(), "test %d", i)
+		if test.enveloped {
+			require.NotEqual(t, legacy.Hash(), typed.Hash(), "test %d", i)
+		}
+
+		for _, tx := range []*Transaction{legacy, typed} {
+			enc, err := rlp.EncodeToBytes(tx)
+			require.NoError(t, err)
+			var decoded Transaction
+			require.NoError(t, rlp.DecodeBytes(enc, &decoded), "test %d", i)
+			require.Equal(t, tx.Hash(), decoded.Hash(), "test %d", i)
+			require.Equal(t, tx.Enveloped(), decoded.Enveloped(), "test %d", i)
+			require.Equal(t, tx.Type(), decoded.Type(), "test %d", i)
+
+			data, err := json.Marshal(tx)
+			require.NoError(t, err)
+			var parsed Transaction
+			require.NoError(t, json.Unmarshal(data, &parsed), "test %d", i)
+			require.Equal(t, tx.Hash(), parsed.Hash(), "test %d", i)
+			require.Equal(t, tx.Enveloped(), parsed.Enveloped(), "test %d", i)
+			require.Equal(t, tx.Type(), parsed.Type(), "test %d", i)
+
+			// both signers recover the legacy transactions and the envelopes
+			for _, signer := range []Signer{legacySigner, typedSigner} {
+				for _, tx := range []*Transaction{&decoded, &parsed} {
+					sender, err := Sender(signer, tx)
+					require.NoError(t, err)
+					require.Equal(t, testAddr, sender, "test %d", i)
+					provider, err := Provider(signer, tx)
+					require.NoError(t, err)
+					if test.providerKey != nil {
+						require.Equal(t, testAddr2, *provider, "test %d", i)
+					} else {
+						require.Nil(t, provider, "test %d", i)
+					}
+				}
+			}
+		}
+	}
+
+	// the sender signature of an envelope can not be replayed as a legacy transaction
+	typed, err := SignTx(modifyTx, typedSigner, testKey)
+	require.NoError(t, err)
+	sender, err := Sender(legacySigner, typed.withEnvelope(false))
+	require.True(t, err != nil || sender != testAddr)
+
+	// the payload is decoded by the type byte, the action of the owners is carried by its own fields
+	enc, err := typed.encodeEnvelope()
+	require.NoError(t, err)
+	typ, data, err := decodeEnvelope(enc)
+	require.NoError(t, err)
+	require.Equal(t, ModifyProvidersEnvelope, typ)
+	require.Equal(t, typed.ExtraData(), data.Extra)
+	enc[0] = byte(SponsoredEnvelope)
+	_, _, err = decodeEnvelope(enc)
+	require.Error(t, err)
+	enc[0] = 0x7f
+	_, _, err = decodeEnvelope(enc)
+	require.Equal(t, ErrTxTypeNotSupported, err)
+
+	// a contract creation without owner nor provider is not an enterprise creation envelope
+	payload, err := rlp.EncodeToBytes(&enterpriseCreationPayload{Price: gasPrice, Amount: common.Big0, V: common.Big1, R: common.Big1, S: common.Big1})
+	require.NoError(t, err)
+	_, _, err = decodeEnvelope(append([]byte{byte(EnterpriseCreationEnvelope)}, payload...))
+	require.Equal(t, ErrInvalidEnvelope, err)
+	// an action of the owners is never a normal transaction
+	payload, err = rlp.EncodeToBytes(&modifyProvidersPayload{Price: gasPrice, Recipient: b, Action: NormalTxType, V: common.Big1, R: common.Big1, S: common.Big1})
+	require.NoError(t, err)
+	_, _, err = decodeEnvelope(append([]byte{byte(ModifyProvidersEnvelope)}, payload...))
+	require.Equal(t, ErrInvalidEnvelope, err)
+}
@@ -31,6 +31,13 @@ type StateDB interface {
 	GetProviders(common.Address) []common.Address
 	AddProvider(addr common.Address, from common.Address, providerAddress common.Address) error
 	RemoveProvider(addr common.Address, from common.Address, providerAddress common.Address) error
+	SetProviderPolicy(addr common.Address, from common.Address, policy types.ProviderPolicyMsg) error
+	UseProviderGas(addr common.Address, provider common.Address, from common.Address, data []byte, gas uint64, epoch uint64) error
+	RefundProviderGas(addr common.Address, provider common.Address, gas uint64, epoch uint64)
+	ApproveOwnerAction(addr common.Address, from common.Address, action common.Hash) (bool, error)
+	TransferOwnership(addr common.Address, from common.Address, owner common.Address) error
+	AcceptOwnership(addr common.Address, from common.Address) error
+	SetOwners(addr common.Address, from common.Address, owners []common.Address, threshold uint64) error
 
 	SubBalance(common.Address, *big.Int)
 	AddBalance(common.Address, *big.Int)
@@ -0,0 +1,280 @@
+// Copyright 2020 The evrynet-node Authors
+// This file is part of the evrynet-node library.
+//
+// The evrynet-node library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The evrynet-node library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the evrynet-node library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package bls implements BLS signatures over the bn256 curve.
+// Signatures are points of G1 and public keys are points of G2, so that signatures are short
+// and many signatures of the same message can be aggregated into one.
+package bls
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/big"
+
+	"github.com/Evrynetlabs/evrynet-node/common/math"
+	"github.com/Evrynetlabs/evrynet-node/crypto"
+	"github.com/Evrynetlabs/evrynet-node/crypto/bn256"
+)
+
+const (
+	// SecretKeyLength is the length in bytes of a marshaled secret key
+	SecretKeyLength = 32
+	// PublicKeyLength is the length in bytes of a marshaled public key
+	PublicKeyLength = 128
+	// SignatureLength is the length in bytes of a marshaled signature
+	SignatureLength = 64
+)
+
+var (
+	// ErrInvalidSecretKey is returned if the secret key is malformed or not in the range of the group order
+	ErrInvalidSecretKey = errors.New("invalid bls secret key")
+	// ErrInvalidPublicKey is returned if the public key is malformed or not in the prime order subgroup
+	ErrInvalidPublicKey = errors.New("invalid bls public key")
+	// ErrInvalidSignature is returned if the signature is malformed
+	ErrInvalidSignature = errors.New("invalid bls signature")
+	// ErrEmptyAggregation is returned when aggregating an empty list
+	ErrEmptyAggregation = errors.New("nothing to aggregate")
+)
+
+var (
+	// order is the order of G1 and G2
+	order, _ = new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+	// fieldModulus is the prime of the base field of the curve, it is 3 mod 4 so square roots are a single exponentiation
+	fieldModulus, _ = new(big.Int).SetString("21888242871839275222246405745257275088696311157297823662689037894645226208583", 10)
+	sqrtExponent    = new(big.Int).Rsh(new(big.Int).Add(fieldModulus, big.NewInt(1)), 2)
+	curveB          = big.NewInt(3)
+
+	g2Generator = new(bn256.G2).ScalarBaseMult(big.NewInt(1))
+
+	signatureDomain  = []byte("EVRYNET-BLS-SIG")
+	possessionDomain = []byte("EVRYNET-BLS-POP")
+	keyDerivation    = []byte("EVRYNET-BLS-KEY")
+)
+
+// SecretKey is a BLS secret key
+type SecretKey struct {
+	scalar *big.Int
+}
+
+// PublicKey is a BLS public key, a point of G2
+type PublicKey struct {
+	p *bn256.G2
+}
+
+// Signature is a BLS signature or an aggregation of BLS signatures, a point of G1
+type Signature struct {
+	p *bn256.G1
+}
+
+// GenerateKey generates a random secret key
+func GenerateKey(r io.Reader) (*SecretKey, error) {
+	if r == nil {
+		r = rand.Reader
+	}
+	for {
+		k, err := rand.Int(r, order)
+		if err != nil {
+			return nil, err
+		}
+		if k.Sign() > 0 {
+			return &SecretKey{scalar: k}, nil
+		}
+	}
+}
+
+// DeriveSecretKey deterministically derives a secret key from a seed, e.g for tests.
+// A validator's key should be generated randomly and stored separately, see SaveSecretKey.
+func DeriveSecretKey(seed []byte) *SecretKey {
+	for ctr := uint32(0); ; ctr++ {
+		var buf [4]byte
+		binary.BigEndian.PutUint32(buf[:], ctr)
+		k := new(big.Int).SetBytes(crypto.Keccak256(keyDerivation, seed, buf[:]))
+		k.Mod(k, order)
+		if k.Sign() > 0 {
+			return &SecretKey{scalar: k}
+		}
+	}
+}
+
+// Marshal returns the binary representation of sk
+func (sk *SecretKey) Marshal() []byte {
+	return math.PaddedBigBytes(sk.scalar, SecretKeyLength)
+}
+
+// UnmarshalSecretKey parses a secret key and checks that it is in the range of the group order
+func UnmarshalSecretKey(data []byte) (*SecretKey, error) {
+	if len(data) != SecretKeyLength {
+		return nil, ErrInvalidSecretKey
+	}
+	k := new(big.Int).SetBytes(data)
+	if k.Sign() == 0 || k.Cmp(order) >= 0 {
+		return nil, ErrInvalidSecretKey
+	}
+	return &SecretKey{scalar: k}, nil
+}
+
+// LoadSecretKey loads a secret key from the given file, the key is hex-encoded.
+func LoadSecretKey(file string) (*SecretKey, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	key, err := hex.DecodeString(string(data))
+	if err != nil {
+		return nil, err
+	}
+	return UnmarshalSecretKey(key)
+}
+
+// SaveSecretKey saves a secret key to the given file with restrictive permissions, the key is hex-encoded.
+func SaveSecretKey(file string, sk *SecretKey) error {
+	return ioutil.WriteFile(file, []byte(hex.EncodeToString(sk.Marshal())), 0600)
+}
+
+// PublicKey returns the public key of sk
+func (sk *SecretKey) PublicKey() *PublicKey {
+	return &PublicKey{p: new(bn256.G2).ScalarBaseMult(sk.scalar)}
+}
+
+// Sign signs msg
+func (sk *SecretKey) Sign(msg []byte) *Signature {
+	return &Signature{p: new(bn256.G1).ScalarMult(hashToG1(signatureDomain, msg), sk.scalar)}
+}
+
+// ProvePossession signs the public key of sk, proving that the owner of the public key knows its secret key.
+// Public keys must be registered with a proof of possession before being aggregated, to prevent rogue key attacks.
+func (sk *SecretKey) ProvePossession() *Signature {
+	return &Signature{p: new(bn256.G1).ScalarMult(hashToG1(possessionDomain, sk.PublicKey().Marshal()), sk.scalar)}
+}
+
+// Marshal returns the binary representation of pk
+func (pk *PublicKey) Marshal() []byte {
+	return pk.p.Marshal()
+}
+
+// UnmarshalPublicKey parses a public key and checks that it is a valid point of G2
+func UnmarshalPublicKey(data []byte) (*PublicKey, error) {
+	if len(data) != PublicKeyLength {
+		return nil, ErrInvalidPublicKey
+	}
+	p := new(bn256.G2)
+	if _, err := p.Unmarshal(data); err != nil {
+		return nil, ErrInvalidPublicKey
+	}
+	if isZero(p.Marshal()) || !isZero(new(bn256.G2).ScalarMult(p, order).Marshal()) {
+		return nil, ErrInvalidPublicKey
+	}
+	return &PublicKey{p: p}, nil
+}
+
+// Marshal returns the binary representation of sig
+func (sig *Signature) Marshal() []byte {
+	return sig.p.Marshal()
+}
+
+// UnmarshalSignature parses a signature and checks that it is a valid point of G1
+func UnmarshalSignature(data []byte) (*Signature, error) {
+	if len(data) != SignatureLength {
+		return nil, ErrInvalidSignature
+	}
+	p := new(bn256.G1)
+	if _, err := p.Unmarshal(data); err != nil {
+		return nil, ErrInvalidSignature
+	}
+	return &Signature{p: p}, nil
+}
+
+// Verify checks that sig is a signature of msg by pk.
+// If sig is an aggregated signature, pk must be the aggregation of the signers' public keys.
+func Verify(pk *PublicKey, msg []byte, sig *Signature) bool {
+	return verify(pk, hashToG1(signatureDomain, msg), sig)
+}
+
+// VerifyPossession checks the proof of possession of pk
+func VerifyPossession(pk *PublicKey, proof *Signature) bool {
+	return verify(pk, hashToG1(possessionDomain, pk.Marshal()), proof)
+}
+
+// verify checks e(sig, g2) == e(h, pk)
+func verify(pk *PublicKey, h *bn256.G1, sig *Signature) bool {
+	negH := new(bn256.G1).Neg(h)
+	return bn256.PairingCheck([]*bn256.G1{sig.p, negH}, []*bn256.G2{g2Generator, pk.p})
+}
+
+// AggregateSignatures aggregates signatures of the same message into one
+func AggregateSignatures(sigs []*Signature) (*Signature, error) {
+	if len(sigs) == 0 {
+		return nil, ErrEmptyAggregation
+	}
+	agg := new(bn256.G1).ScalarMult(sigs[0].p, big.NewInt(1))
+	for _, sig := range sigs[1:] {
+		agg = new(bn256.G1).Add(agg, sig.p)
+	}
+	return &Signature{p: agg}, nil
+}
+
+// AggregatePublicKeys aggregates public keys, the result verifies the aggregated signature of the keys' owners
+func AggregatePublicKeys(pks []*PublicKey) (*PublicKey, error) {
+	if len(pks) == 0 {
+		return nil, ErrEmptyAggregation
+	}
+	agg := new(bn256.G2).ScalarMult(pks[0].p, big.NewInt(1))
+	for _, pk := range pks[1:] {
+		agg = new(bn256.G2).Add(agg, pk.p)
+	}
+	return &PublicKey{p: agg}, nil
+}
+
+// hashToG1 maps a message to a point of G1 by the try-and-increment method.
+// As the cofactor of G1 is 1, every point of the curve is in G1.
+func hashToG1(domain []byte, msg []byte) *bn256.G1 {
+	for ctr := uint32(0); ; ctr++ {
+		var buf [4]byte
+		binary.BigEndian.PutUint32(buf[:], ctr)
+		x := new(big.Int).SetBytes(crypto.Keccak256(domain, msg, buf[:]))
+		x.Mod(x, fieldModulus)
+
+		// y^2 = x^3 + 3
+		y2 := new(big.Int).Exp(x, big.NewInt(3), fieldModulus)
+		y2.Add(y2, curveB).Mod(y2, fieldModulus)
+		y := new(big.Int).Exp(y2, sqrtExponent, fieldModulus)
+		if new(big.Int).Exp(y, big.NewInt(2), fieldModulus).Cmp(y2) != 0 {
+			continue
+		}
+
+		data := make([]byte, 64)
+		math.ReadBits(x, data[:32])
+		math.ReadBits(y, data[32:])
+		p := new(bn256.G1)
+		if _, err := p.Unmarshal(data); err != nil {
+			continue
+		}
+		return p
+	}
+}
+
+func isZero(data []byte) bool {
+	for _, b := range data {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
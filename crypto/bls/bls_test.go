@@ -0,0 +1,97 @@
+package bls
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	sk, err := GenerateKey(nil)
+	require.NoError(t, err)
+	msg := []byte("block hash")
+
+	sig := sk.Sign(msg)
+	assert.True(t, Verify(sk.PublicKey(), msg, sig))
+	assert.False(t, Verify(sk.PublicKey(), []byte("other block hash"), sig))
+
+	other, err := GenerateKey(nil)
+	require.NoError(t, err)
+	assert.False(t, Verify(other.PublicKey(), msg, sig))
+
+	// round trip through the binary representation
+	pk, err := UnmarshalPublicKey(sk.PublicKey().Marshal())
+	require.NoError(t, err)
+	decodedSig, err := UnmarshalSignature(sig.Marshal())
+	require.NoError(t, err)
+	assert.True(t, Verify(pk, msg, decodedSig))
+
+	_, err = UnmarshalPublicKey(make([]byte, PublicKeyLength))
+	assert.Equal(t, ErrInvalidPublicKey, err)
+	_, err = UnmarshalSignature(make([]byte, SignatureLength-1))
+	assert.Equal(t, ErrInvalidSignature, err)
+}
+
+func TestAggregate(t *testing.T) {
+	var (
+		msg  = []byte("block hash")
+		sigs []*Signature
+		pks  []*PublicKey
+	)
+	for i := 0; i < 4; i++ {
+		sk, err := GenerateKey(nil)
+		require.NoError(t, err)
+		sigs = append(sigs, sk.Sign(msg))
+		pks = append(pks, sk.PublicKey())
+	}
+	aggSig, err := AggregateSignatures(sigs)
+	require.NoError(t, err)
+	aggPk, err := AggregatePublicKeys(pks)
+	require.NoError(t, err)
+	assert.True(t, Verify(aggPk, msg, aggSig))
+
+	// the aggregated signature does not verify against a subset of signers
+	subsetPk, err := AggregatePublicKeys(pks[:3])
+	require.NoError(t, err)
+	assert.False(t, Verify(subsetPk, msg, aggSig))
+
+	_, err = AggregateSignatures(nil)
+	assert.Equal(t, ErrEmptyAggregation, err)
+}
+
+func TestProofOfPossession(t *testing.T) {
+	sk := DeriveSecretKey([]byte("seed"))
+	assert.Equal(t, sk.PublicKey().Marshal(), DeriveSecretKey([]byte("seed")).PublicKey().Marshal())
+
+	proof := sk.ProvePossession()
+	assert.True(t, VerifyPossession(sk.PublicKey(), proof))
+
+	// a signature of the public key as a normal message is not a proof of possession
+	assert.False(t, VerifyPossession(sk.PublicKey(), sk.Sign(sk.PublicKey().Marshal())))
+
+	other := DeriveSecretKey([]byte("other seed"))
+	assert.False(t, VerifyPossession(other.PublicKey(), proof))
+}
+
+func TestSaveAndLoadSecretKey(t *testing.T) {
+	sk, err := GenerateKey(nil)
+	require.NoError(t, err)
+
+	file, err := ioutil.TempFile("", "blskey")
+	require.NoError(t, err)
+	file.Close()
+	defer os.Remove(file.Name())
+
+	require.NoError(t, SaveSecretKey(file.Name(), sk))
+	loaded, err := LoadSecretKey(file.Name())
+	require.NoError(t, err)
+	assert.Equal(t, sk.PublicKey().Marshal(), loaded.PublicKey().Marshal())
+
+	_, err = UnmarshalSecretKey(make([]byte, SecretKeyLength))
+	assert.Equal(t, ErrInvalidSecretKey, err)
+	_, err = UnmarshalSecretKey(order.Bytes())
+	assert.Equal(t, ErrInvalidSecretKey, err)
+}
@@ -220,6 +220,10 @@ func (b *EvrAPIBackend) TxPoolContent() (map[common.Address]types.Transactions,
 	return b.evr.TxPool().Content()
 }
 
+func (b *EvrAPIBackend) TxPoolSponsored() map[common.Address]core.SponsoredCost {
+	return b.evr.TxPool().Sponsored()
+}
+
 func (b *EvrAPIBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subscription {
 	return b.evr.TxPool().SubscribeNewTxsEvent(ch)
 }
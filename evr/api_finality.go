@@ -0,0 +1,178 @@
+package evr
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Evrynetlabs/evrynet-node/common"
+	fconTypes "github.com/Evrynetlabs/evrynet-node/consensus/fconsensus/types"
+	"github.com/Evrynetlabs/evrynet-node/core"
+	"github.com/Evrynetlabs/evrynet-node/core/rawdb"
+	"github.com/Evrynetlabs/evrynet-node/core/types"
+	"github.com/Evrynetlabs/evrynet-node/rpc"
+)
+
+// maxEvilHeadersRange is the maximum number of final chain blocks scanned by a single GetEvilHeaders call
+const maxEvilHeadersRange = 10000
+
+var (
+	errUnknownFinalBlock  = errors.New("unknown final chain block")
+	errInvalidFinalRange  = errors.New("invalid final chain block range")
+	errFinalRangeTooLarge = errors.New("final chain block range too large")
+	errUnknownPackedBlock = errors.New("packed main chain block not found")
+)
+
+// PublicFinalityAPI provides an API to know which main chain blocks are finalized by the final chain.
+type PublicFinalityAPI struct {
+	e *Evrynet
+}
+
+// NewPublicFinalityAPI creates a new finality API.
+func NewPublicFinalityAPI(e *Evrynet) *PublicFinalityAPI {
+	return &PublicFinalityAPI{e}
+}
+
+// LatestFinalized returns the latest finalized main chain block, nil if no block is finalized yet.
+func (api *PublicFinalityAPI) LatestFinalized() (*types.Finality, error) {
+	header := api.e.fBlockchain.CurrentHeader()
+	if header.Number.Uint64() == 0 {
+		return nil, nil
+	}
+	return api.finalityOf(header)
+}
+
+// IsBlockFinalized returns true if the main chain block is canonical and finalized.
+func (api *PublicFinalityAPI) IsBlockFinalized(hash common.Hash) (bool, error) {
+	header := api.e.blockchain.GetHeaderByHash(hash)
+	if header == nil {
+		return false, nil
+	}
+	return api.isFinalized(header)
+}
+
+// IsTransactionFinalized returns true if the transaction is included in a canonical and finalized main chain block.
+func (api *PublicFinalityAPI) IsTransactionFinalized(hash common.Hash) (bool, error) {
+	tx, blockHash, _, _ := rawdb.ReadTransaction(api.e.ChainDb(), hash, false)
+	if tx == nil {
+		return false, nil
+	}
+	return api.IsBlockFinalized(blockHash)
+}
+
+// GetEvilHeaders returns the finality of the final chain blocks in the range [from, to] which detected an evil header.
+func (api *PublicFinalityAPI) GetEvilHeaders(from rpc.BlockNumber, to rpc.BlockNumber) ([]*types.Finality, error) {
+	current := api.e.fBlockchain.CurrentHeader().Number.Uint64()
+	begin, end := api.finalNumber(from, current), api.finalNumber(to, current)
+	if begin > end {
+		return nil, errInvalidFinalRange
+	}
+	if end-begin >= maxEvilHeadersRange {
+		return nil, errFinalRangeTooLarge
+	}
+	if begin == 0 {
+		begin = 1
+	}
+	evilHeaders := []*types.Finality{}
+	for number := begin; number <= end; number++ {
+		header := api.e.fBlockchain.GetHeaderByNumber(number)
+		if header == nil {
+			return nil, errUnknownFinalBlock
+		}
+		finality, err := api.finalityOf(header)
+		if err != nil {
+			return nil, err
+		}
+		if finality.EvilHeader != nil {
+			evilHeaders = append(evilHeaders, finality)
+		}
+	}
+	return evilHeaders, nil
+}
+
+// GetFraudProof returns the fraud proof of an evil block, which can be verified by re-executing it without any other state.
+func (api *PublicFinalityAPI) GetFraudProof(hash common.Hash) (*types.FraudProof, error) {
+	return api.e.blockchain.GetFraudProof(hash)
+}
+
+// NewFinalized creates a subscription that fires each time main chain blocks are finalized by a new final chain block.
+func (api *PublicFinalityAPI) NewFinalized(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		heads := make(chan core.ChainHeadEvent)
+		headsSub := api.e.fBlockchain.SubscribeChainHeadEvent(heads)
+
+		for {
+			select {
+			case ev := <-heads:
+				finality, err := api.finalityOf(ev.Block.Header())
+				if err != nil {
+					continue
+				}
+				notifier.Notify(rpcSub.ID, finality)
+			case <-rpcSub.Err():
+				headsSub.Unsubscribe()
+				return
+			case <-notifier.Closed():
+				headsSub.Unsubscribe()
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// finalityOf returns the main chain blocks finalized by the final chain header
+func (api *PublicFinalityAPI) finalityOf(header *types.Header) (*types.Finality, error) {
+	fce, err := fconTypes.ExtractFConExtra(header)
+	if err != nil {
+		return nil, err
+	}
+	number := fce.CurrentHeight
+	// the final blocks packed before the height was recorded only have the hash of the packed block
+	if number == 0 && fce.CurrentBlock != (common.Hash{}) {
+		packed := api.e.blockchain.GetHeaderByHash(fce.CurrentBlock)
+		if packed == nil {
+			return nil, errUnknownPackedBlock
+		}
+		number = packed.Number.Uint64()
+	}
+	return &types.Finality{
+		Number:      number,
+		Hash:        fce.CurrentBlock,
+		FinalNumber: header.Number.Uint64(),
+		FinalHash:   header.Hash(),
+		EvilHeader:  fce.EvilHeader,
+	}, nil
+}
+
+// isFinalized returns true if the main chain header is canonical and not after the latest finalized block
+func (api *PublicFinalityAPI) isFinalized(header *types.Header) (bool, error) {
+	finality, err := api.LatestFinalized()
+	if err != nil || finality == nil {
+		return false, err
+	}
+	number := header.Number.Uint64()
+	if number > finality.Number {
+		return false, nil
+	}
+	if rawdb.ReadCanonicalHash(api.e.ChainDb(), number, false) != header.Hash() {
+		return false, nil
+	}
+	// the finalized block itself must be on the canonical chain, otherwise the main chain is being reorganized
+	return rawdb.ReadCanonicalHash(api.e.ChainDb(), finality.Number, false) == finality.Hash, nil
+}
+
+// finalNumber resolves a final chain block number, the special numbers and the numbers after the current block resolve to the current block
+func (api *PublicFinalityAPI) finalNumber(number rpc.BlockNumber, current uint64) uint64 {
+	if number < 0 || uint64(number) > current {
+		return current
+	}
+	return uint64(number)
+}
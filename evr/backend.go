@@ -34,9 +34,12 @@ import (
 	"github.com/Evrynetlabs/evrynet-node/consensus/ethash"
 	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint"
 	tendermintBackend "github.com/Evrynetlabs/evrynet-node/consensus/tendermint/backend"
+	tendermintLight "github.com/Evrynetlabs/evrynet-node/consensus/tendermint/light"
+	tendermintSigner "github.com/Evrynetlabs/evrynet-node/consensus/tendermint/signer"
 	"github.com/Evrynetlabs/evrynet-node/core"
 	"github.com/Evrynetlabs/evrynet-node/core/bloombits"
 	"github.com/Evrynetlabs/evrynet-node/core/rawdb"
+	"github.com/Evrynetlabs/evrynet-node/core/state/staking"
 	"github.com/Evrynetlabs/evrynet-node/core/types"
 	"github.com/Evrynetlabs/evrynet-node/core/vm"
 	"github.com/Evrynetlabs/evrynet-node/event"
@@ -138,12 +141,16 @@ func New(ctx *node.ServiceContext, config *Config) (*Evrynet, error) {
 
 	log.Info("Initialised chain configuration", "config", chainConfig)
 
+	engine, err := CreateConsensusEngine(ctx, chainConfig, config, config.Miner.Notify, config.Miner.Noverify, chainDb)
+	if err != nil {
+		return nil, err
+	}
 	evr := &Evrynet{
 		config:         config,
 		chainDb:        chainDb,
 		eventMux:       ctx.EventMux,
 		accountManager: ctx.AccountManager,
-		engine:         CreateConsensusEngine(ctx, chainConfig, config, config.Miner.Notify, config.Miner.Noverify, chainDb),
+		engine:         engine,
 		shutdownChan:   make(chan bool),
 		networkID:      config.NetworkId,
 		gasPrice:       chainConfig.GasPrice,
@@ -185,9 +192,20 @@ func New(ctx *node.ServiceContext, config *Config) (*Evrynet, error) {
 	if err != nil {
 		return nil, err
 	}
+	// Check the layout of the staking contract before it is used to elect the validators
+	type stakingLayoutVerifier interface {
+		VerifyStakingLayout(chain consensus.FullChainReader) error
+	}
+	if verifier, ok := evr.engine.(stakingLayoutVerifier); ok {
+		if err := verifier.VerifyStakingLayout(evr.blockchain); err != nil {
+			return nil, err
+		}
+	}
 
 	conf := &params.FConConfig{}
-	if fchainConfig.Clique != nil {
+	if fchainConfig.FConsensus != nil {
+		*conf = *fchainConfig.FConsensus
+	} else if fchainConfig.Clique != nil {
 		conf.Epoch = fchainConfig.Clique.Epoch
 		conf.Period = fchainConfig.Clique.Period
 	}
@@ -207,7 +225,7 @@ func New(ctx *node.ServiceContext, config *Config) (*Evrynet, error) {
 	if err != nil {
 		return nil, err
 	}
-	//evr.fb = NewFBManager(evr.blockchain, evr.fBlockchain, fEngin, evr.EventMux())
+	//evr.fb = NewFBManager(evr.blockchain, evr.fBlockchain, fEngin, evr.EventMux(), config.Miner.GasFloor, config.Miner.GasCeil)
 	// Rewind the chain in case of an incompatible config upgrade.
 	if compat, ok := genesisErr.(*params.ConfigCompatError); ok {
 		log.Warn("Rewinding chain to upgrade configuration", "err", compat)
@@ -262,34 +280,98 @@ func makeExtraData(extra []byte) []byte {
 	return extra
 }
 
+// CreateLightConsensusEngine creates the consensus engine of a light client. For Tendermint, it is a header-only
+// verifier which tracks the validator sets from the checkpoint headers; the other engines are the same as full nodes'.
+func CreateLightConsensusEngine(ctx *node.ServiceContext, chainConfig *params.ChainConfig, config *Config, db evrdb.Database) (consensus.Engine, error) {
+	if chainConfig.Clique == nil && chainConfig.Tendermint != nil {
+		if err := SetTendermintConfig(&config.Tendermint, chainConfig); err != nil {
+			return nil, err
+		}
+		log.Info("Create Tendermint light consensus engine")
+		return tendermintLight.New(&config.Tendermint), nil
+	}
+	return CreateConsensusEngine(ctx, chainConfig, config, nil, false, db)
+}
+
+// SetTendermintConfig copies the Tendermint parameters of the chain config to the engine config
+func SetTendermintConfig(config *tendermint.Config, chainConfig *params.ChainConfig) error {
+	config.ProposerPolicy = tendermint.ProposerPolicy(chainConfig.Tendermint.ProposerPolicy)
+	config.Epoch = chainConfig.Tendermint.Epoch
+	config.StakingSCAddress = chainConfig.Tendermint.StakingSCAddress
+	config.FixedValidators = chainConfig.Tendermint.FixedValidators
+	config.BlockReward = chainConfig.Tendermint.BlockReward
+	config.StakeWeightedVoting = chainConfig.Tendermint.StakeWeightedVoting
+	config.BLSCommittedSeals = chainConfig.Tendermint.BLSCommittedSeals
+	config.SignedBlocksWindow = chainConfig.Tendermint.SignedBlocksWindow
+	config.MinSignedPerWindow = chainConfig.Tendermint.MinSignedPerWindow
+	config.DowntimeJailDuration = chainConfig.Tendermint.DowntimeJailDuration
+	indexConfigs, err := stakingIndexConfigs(config, chainConfig.Tendermint)
+	if err != nil {
+		return fmt.Errorf("failed to load the staking layout: %v", err)
+	}
+	config.IndexStateVariables = indexConfigs
+	return nil
+}
+
+// stakingIndexConfigs returns the layout of the staking contract, from the layout file of the engine config,
+// the layout of the genesis or the default layout in this order
+func stakingIndexConfigs(config *tendermint.Config, chainConfig *params.TendermintConfig) (*staking.IndexConfigs, error) {
+	switch {
+	case config.StakingLayoutFile != "":
+		return staking.LoadIndexConfigs(config.StakingLayoutFile)
+	case len(chainConfig.StakingLayout) > 0:
+		return staking.NewIndexConfigsFromStorageLayout(chainConfig.StakingLayout)
+	case config.IndexStateVariables != nil:
+		return config.IndexStateVariables, nil
+	}
+	return staking.DefaultConfig, nil
+}
+
+// datadirBLSKey is the path within the datadir to the Tendermint validator's BLS key
+const datadirBLSKey = "blskey"
+
 // CreateConsensusEngine creates the required type of consensus engine instance for an Evrynet service
-func CreateConsensusEngine(ctx *node.ServiceContext, chainConfig *params.ChainConfig, config *Config, notify []string, noverify bool, db evrdb.Database) consensus.Engine {
+func CreateConsensusEngine(ctx *node.ServiceContext, chainConfig *params.ChainConfig, config *Config, notify []string, noverify bool, db evrdb.Database) (consensus.Engine, error) {
 	// If proof-of-authority is requested, set it up
 	if chainConfig.Clique != nil {
-		return clique.New(chainConfig.Clique, db)
+		return clique.New(chainConfig.Clique, db), nil
 	}
 	// If Tendermint is requested, set it up
 	if chainConfig.Tendermint != nil {
-		config.Tendermint.ProposerPolicy = tendermint.ProposerPolicy(chainConfig.Tendermint.ProposerPolicy)
-		config.Tendermint.Epoch = chainConfig.Tendermint.Epoch
-		config.Tendermint.StakingSCAddress = chainConfig.Tendermint.StakingSCAddress
-		config.Tendermint.FixedValidators = chainConfig.Tendermint.FixedValidators
-		config.Tendermint.BlockReward = chainConfig.Tendermint.BlockReward
+		if err := SetTendermintConfig(&config.Tendermint, chainConfig); err != nil {
+			return nil, err
+		}
 		log.Info("Create Tendermint consensus engine")
-		return tendermintBackend.New(&config.Tendermint, ctx.NodeKey())
+		opts := []tendermintBackend.Option{tendermintBackend.WithDB(db)}
+		if config.Tendermint.RemoteSigner != "" {
+			remoteSigner, err := tendermintSigner.NewRemoteSigner(config.Tendermint.RemoteSigner)
+			if err != nil {
+				return nil, fmt.Errorf("failed to connect to the Tendermint signer %s: %v", config.Tendermint.RemoteSigner, err)
+			}
+			log.Info("Validator keys are held by the remote signer", "endpoint", config.Tendermint.RemoteSigner, "address", remoteSigner.Address())
+			opts = append(opts, tendermintBackend.WithSigner(remoteSigner))
+		} else if config.Tendermint.BLSCommittedSeals {
+			// The BLS key is stored separately from the node key, so that it can be rotated
+			blsKey, err := tendermintSigner.LoadBLSKey(ctx.ResolvePath(datadirBLSKey))
+			if err != nil {
+				return nil, fmt.Errorf("failed to load the BLS key: %v", err)
+			}
+			opts = append(opts, tendermintBackend.WithBLSKey(blsKey))
+		}
+		return tendermintBackend.New(&config.Tendermint, ctx.NodeKey(), opts...), nil
 	}
 
 	// Otherwise assume proof-of-work
 	switch config.Ethash.PowMode {
 	case ethash.ModeFake:
 		log.Warn("Ethash used in fake mode")
-		return ethash.NewFaker()
+		return ethash.NewFaker(), nil
 	case ethash.ModeTest:
 		log.Warn("Ethash used in test mode")
-		return ethash.NewTester(nil, noverify)
+		return ethash.NewTester(nil, noverify), nil
 	case ethash.ModeShared:
 		log.Warn("Ethash used in shared mode")
-		return ethash.NewShared()
+		return ethash.NewShared(), nil
 	default:
 		engine := ethash.New(ethash.Config{
 			CacheDir:       ctx.ResolvePath(config.Ethash.CacheDir),
@@ -300,7 +382,7 @@ func CreateConsensusEngine(ctx *node.ServiceContext, chainConfig *params.ChainCo
 			DatasetsOnDisk: config.Ethash.DatasetsOnDisk,
 		}, notify, noverify)
 		engine.SetThreads(-1) // Disable CPU mining
-		return engine
+		return engine, nil
 	}
 }
 
@@ -315,6 +397,7 @@ func (s *Evrynet) APIs() []rpc.API {
 	}
 	// Append any APIs exposed explicitly by the consensus engine
 	apis = append(apis, s.engine.APIs(s.BlockChain())...)
+	apis = append(apis, s.fEngine.APIs(s.fBlockchain)...)
 
 	// Append all the local APIs and return
 	return append(apis, []rpc.API{
@@ -342,6 +425,12 @@ func (s *Evrynet) APIs() []rpc.API {
 			Service:   NewPrivateMinerAPI(s),
 			Public:    false,
 		},
+		{
+			Namespace: "finality",
+			Version:   "1.0",
+			Service:   NewPublicFinalityAPI(s),
+			Public:    true,
+		},
 		{
 			Namespace: "evr",
 			Version:   "1.0",
@@ -17,19 +17,18 @@ import (
 	"github.com/Evrynetlabs/evrynet-node/core/vm"
 	"github.com/Evrynetlabs/evrynet-node/event"
 	"github.com/Evrynetlabs/evrynet-node/log"
+	"github.com/Evrynetlabs/evrynet-node/params"
 	"github.com/Evrynetlabs/evrynet-node/rlp"
 )
 
-const (
-	M = uint64(2)
-	K = uint64(2)
-)
-
 type FBManager struct {
 	mux                *event.TypeMux
 	engine             consensus.Engine
 	blockchain         *core.BlockChain
 	finaliseBlockchain *core.BlockChain
+	planner            *fconsensus.SectionPlanner
+	gasFloor           uint64 // Target gas floor of the final chain blocks
+	gasCeil            uint64 // Target gas ceiling of the final chain blocks
 	chainHeadCh        chan core.ChainHeadEvent
 	abort              chan struct{}
 	signer             common.Address      // Evrynet address of the signing key
@@ -40,11 +39,14 @@ var AuthorSinger common.Address
 
 //type SignerFn func(accounts.Account, string, []byte) ([]byte, error)
 
-func NewFBManager(bc, fbc *core.BlockChain, engine consensus.Engine, mux *event.TypeMux) *FBManager {
+func NewFBManager(bc, fbc *core.BlockChain, engine consensus.Engine, mux *event.TypeMux, gasFloor, gasCeil uint64) *FBManager {
 	fb := &FBManager{
 		engine:             engine,
 		blockchain:         bc,
 		finaliseBlockchain: fbc,
+		planner:            fconsensus.NewSectionPlanner(fbc.Config(), bc, params.DefaultFConPacking),
+		gasFloor:           gasFloor,
+		gasCeil:            gasCeil,
 		chainHeadCh:        make(chan core.ChainHeadEvent, 10),
 		abort:              make(chan struct{}),
 		mux:                mux,
@@ -62,31 +64,15 @@ func (fb *FBManager) Authorize(signer common.Address, signFn fconsensus.SignerFn
 	}
 }
 
-func (fb *FBManager) GetBlockSections(newBlock *types.Block) (uint64, uint64, bool) {
-	number := newBlock.Number().Uint64()
-	currentBlock := fb.finaliseBlockchain.CurrentBlock()
-	packedBlockNumber := uint64(0)
-	if currentBlock.Number().Uint64() > 0 {
-		fce, err := fconTypes.ExtractFConExtra(currentBlock.Header())
-		if err != nil {
-			log.Error("ExtractFConExtra failed", "err", err)
-			return 0, 0, false
-		}
-		packBlock := fb.blockchain.GetBlockByHash(fce.CurrentBlock)
-		packedBlockNumber = packBlock.Number().Uint64()
-	}
-
-	if packedBlockNumber+M+K > number {
+// GetBlockSections returns the section of main chain blocks to pack into the next final chain block.
+func (fb *FBManager) GetBlockSections() (uint64, uint64, bool) {
+	parent := fb.finaliseBlockchain.CurrentBlock()
+	start, end, trigger, err := fb.planner.Plan(parent.Header(), core.CalcGasLimit(parent, fb.gasFloor, fb.gasCeil))
+	if err != nil {
+		log.Error("FBManager: plan section failed", "err", err)
 		return 0, 0, false
 	}
-
-	end := packedBlockNumber + M
-	if end < number-K {
-		end = number - K
-	}
-
-	return packedBlockNumber + 1, end, true
-
+	return start, end, trigger
 }
 
 func (fb *FBManager) PrepareHeader() (*types.Header, error) {
@@ -112,7 +98,7 @@ func (fb *FBManager) PrepareHeader() (*types.Header, error) {
 	header := &types.Header{
 		ParentHash: parent.Hash(),
 		Number:     num.Add(num, common.Big1),
-		GasLimit:   core.CalcGasLimit(parent, 8000000, 8000000),
+		GasLimit:   core.CalcGasLimit(parent, fb.gasFloor, fb.gasCeil),
 		Time:       uint64(timestamp),
 		Coinbase:   common.Address{},
 		Nonce:      types.BlockNonce{},
@@ -185,7 +171,7 @@ func (fb *FBManager) CreateFinaliseBlock(newBlock *types.Block) *types.Block {
 		return nil
 	}
 
-	start, end, trigger := fb.GetBlockSections(newBlock)
+	start, end, trigger := fb.GetBlockSections()
 	if !trigger {
 		log.Info("FBManager: not trigger to create block")
 		return nil
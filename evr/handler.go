@@ -28,6 +28,7 @@ import (
 
 	"github.com/Evrynetlabs/evrynet-node/common"
 	"github.com/Evrynetlabs/evrynet-node/consensus"
+	"github.com/Evrynetlabs/evrynet-node/consensus/fconsensus"
 	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint"
 	"github.com/Evrynetlabs/evrynet-node/core"
 	"github.com/Evrynetlabs/evrynet-node/core/types"
@@ -42,6 +43,7 @@ import (
 	"github.com/Evrynetlabs/evrynet-node/params"
 	"github.com/Evrynetlabs/evrynet-node/rlp"
 	"github.com/Evrynetlabs/evrynet-node/trie"
+	lru "github.com/hashicorp/golang-lru"
 )
 
 const (
@@ -54,6 +56,13 @@ const (
 
 	// minimim number of peers to broadcast new blocks to
 	minBroadcastPeers = 4
+
+	// fsealChanSize is the size of channel listening to PendingSealEvent.
+	fsealChanSize = 10
+
+	// maxKnownSeals is the number of final chain seal requests and signatures to remember
+	// so that they are handled and relayed only once.
+	maxKnownSeals = 1024
 )
 
 var (
@@ -68,6 +77,21 @@ func errResp(code errCode, format string, v ...interface{}) error {
 	return fmt.Errorf("%v - %v", code, fmt.Sprintf(format, v...))
 }
 
+// finalSealer is implemented by the final chain engines which need the signatures of several signers
+// to seal a block, the signatures are gossiped over the protocol.
+type finalSealer interface {
+	// SubscribePendingSeal subscribes to the local blocks waiting for the signatures of other signers.
+	SubscribePendingSeal(ch chan<- fconsensus.PendingSealEvent) event.Subscription
+	// VerifyPendingHeader checks that a block waiting for signatures is proposed by an authorized signer.
+	VerifyPendingHeader(chain consensus.ChainReader, header *types.Header) error
+	// SignHeader signs a block proposed by another signer.
+	SignHeader(chain consensus.ChainReader, header *types.Header) ([]byte, error)
+	// VerifySignature checks that a signature of a seal hash is signed by an authorized signer.
+	VerifySignature(chain consensus.ChainReader, sealHash common.Hash, signature []byte) error
+	// AddSignature adds the signature of another signer to the local block waiting for signatures.
+	AddSignature(sealHash common.Hash, signature []byte) error
+}
+
 type ProtocolManager struct {
 	networkID uint64
 
@@ -95,6 +119,11 @@ type ProtocolManager struct {
 	txsSub        event.Subscription
 	minedBlockSub *event.TypeMuxSubscription
 
+	fsealer    finalSealer
+	fsealCh    chan fconsensus.PendingSealEvent
+	fsealSub   event.Subscription
+	knownSeals *lru.Cache // Seal requests and signatures which are already handled
+
 	whitelist map[uint64]common.Hash
 
 	// channels for fetcher, syncer, txsyncLoop
@@ -135,6 +164,10 @@ func NewProtocolManager(config *params.ChainConfig, fConfig *params.ChainConfig,
 	if handler, ok := engine.(consensus.Handler); ok {
 		handler.SetBroadcaster(manager)
 	}
+	if sealer, ok := fEngine.(finalSealer); ok {
+		manager.fsealer = sealer
+		manager.knownSeals, _ = lru.New(maxKnownSeals)
+	}
 	// If fast sync was requested and our database is empty, grant it
 	if mode == downloader.FastSync && blockchain.CurrentBlock().NumberU64() == 0 {
 		manager.fastSync = uint32(1)
@@ -278,6 +311,13 @@ func (pm *ProtocolManager) Start(maxPeers int) {
 	pm.minedBlockSub = pm.eventMux.Subscribe(core.NewMinedBlockEvent{})
 	go pm.minedBroadcastLoop()
 
+	// broadcast the final blocks waiting for signatures
+	if pm.fsealer != nil {
+		pm.fsealCh = make(chan fconsensus.PendingSealEvent, fsealChanSize)
+		pm.fsealSub = pm.fsealer.SubscribePendingSeal(pm.fsealCh)
+		go pm.fsealBroadcastLoop()
+	}
+
 	// start sync handlers
 	go pm.syncer()
 	go pm.txsyncLoop()
@@ -288,6 +328,9 @@ func (pm *ProtocolManager) Stop() {
 
 	pm.txsSub.Unsubscribe()        // quits txBroadcastLoop
 	pm.minedBlockSub.Unsubscribe() // quits blockBroadcastLoop
+	if pm.fsealSub != nil {
+		pm.fsealSub.Unsubscribe() // quits fsealBroadcastLoop
+	}
 
 	// Quit the sync loop.
 	// After this send has completed, no new peers will be accepted.
@@ -667,6 +710,45 @@ func (pm *ProtocolManager) HandleMsg(p *Peer) error {
 			log.Debug("Failed to deliver evil  bodies", "err", err)
 		}
 
+	case p.version >= eth66 && msg.Code == FSealRequestMsg:
+		// A final block waiting for signatures, sign it if we are one of the signers
+		var header types.Header
+		if err := msg.Decode(&header); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		if pm.fsealer == nil || !pm.markSeal(header.Hash()) {
+			break
+		}
+		// the block may be built on top of a final block we don't have yet, so don't drop the peer
+		if err := pm.fsealer.VerifyPendingHeader(pm.fblockchain, &header); err != nil {
+			log.Debug("Failed to verify final block seal request", "number", header.Number, "err", err)
+			break
+		}
+		pm.broadcastSeal(FSealRequestMsg, &header)
+		if signature, err := pm.fsealer.SignHeader(pm.fblockchain, &header); err == nil {
+			data := &fSealSignatureData{SealHash: fconsensus.SealHash(&header), Signature: signature}
+			pm.markSeal(data.hash())
+			pm.broadcastSeal(FSealSignatureMsg, data)
+		}
+
+	case p.version >= eth66 && msg.Code == FSealSignatureMsg:
+		// A signature of a final block waiting for signatures, add it if the block is ours
+		var data fSealSignatureData
+		if err := msg.Decode(&data); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		if pm.fsealer == nil || !pm.markSeal(data.hash()) {
+			break
+		}
+		if err := pm.fsealer.VerifySignature(pm.fblockchain, data.SealHash, data.Signature); err != nil {
+			log.Debug("Failed to verify final block signature", "sealhash", data.SealHash, "err", err)
+			break
+		}
+		pm.broadcastSeal(FSealSignatureMsg, &data)
+		if err := pm.fsealer.AddSignature(data.SealHash, data.Signature); err != nil {
+			log.Trace("Final block signature not added", "sealhash", data.SealHash, "err", err)
+		}
+
 	case p.version >= eth63 && (msg.Code == GetNodeDataMsg || msg.Code == GetFNodeDataMsg):
 		blockchain := pm.blockchain
 		if msg.Code == GetFNodeDataMsg {
@@ -925,6 +1007,36 @@ func (pm *ProtocolManager) minedBroadcastLoop() {
 	}
 }
 
+// markSeal marks a seal request or signature as handled, it returns false if it was already handled.
+func (pm *ProtocolManager) markSeal(hash common.Hash) bool {
+	known, _ := pm.knownSeals.ContainsOrAdd(hash, struct{}{})
+	return !known
+}
+
+// broadcastSeal relays a seal request or signature to all the peers supporting it.
+func (pm *ProtocolManager) broadcastSeal(code uint64, data interface{}) {
+	for _, peer := range pm.peers.Peers() {
+		if peer.version >= eth66 {
+			peer.AsyncSendSeal(code, data)
+		}
+	}
+}
+
+// fsealBroadcastLoop sends the local final blocks waiting for signatures to the other signers.
+func (pm *ProtocolManager) fsealBroadcastLoop() {
+	for {
+		select {
+		case ev := <-pm.fsealCh:
+			pm.markSeal(ev.Header.Hash())
+			pm.broadcastSeal(FSealRequestMsg, ev.Header)
+
+		// Err() channel will be closed when unsubscribing.
+		case <-pm.fsealSub.Err():
+			return
+		}
+	}
+}
+
 func (pm *ProtocolManager) txBroadcastLoop() {
 	for {
 		select {
@@ -695,6 +695,46 @@ outer:
 	}
 }
 
+// Tests that the seal requests and signatures of final blocks are only relayed to
+// the peers supporting the evr/66 messages.
+func TestBroadcastSeal(t *testing.T) {
+	pm, _ := newTestProtocolManagerMust(t, downloader.FullSync, 0, nil, nil)
+	defer pm.Stop()
+
+	// the peers are registered without handshake as it needs the final chain
+	newPeer := func(name string, version int) *p2p.MsgPipeRW {
+		app, net := p2p.MsgPipe()
+		var id enode.ID
+		rand.Read(id[:])
+		if err := pm.peers.Register(pm.NewPeer(version, p2p.NewPeer(id, name, nil), net)); err != nil {
+			t.Fatalf("failed to register peer: %v", err)
+		}
+		return app
+	}
+	oldApp := newPeer("old", eth65)
+	defer oldApp.Close()
+	newApp := newPeer("new", eth66)
+	defer newApp.Close()
+
+	header := &types.Header{Number: big.NewInt(1)}
+	pm.broadcastSeal(FSealRequestMsg, header)
+	if err := p2p.ExpectMsg(newApp, FSealRequestMsg, header); err != nil {
+		t.Fatalf("seal request mismatch: %v", err)
+	}
+
+	received := make(chan uint64, 1)
+	go func() {
+		if msg, err := oldApp.ReadMsg(); err == nil {
+			received <- msg.Code
+		}
+	}()
+	select {
+	case code := <-received:
+		t.Fatalf("message %#x relayed to an evr/65 peer", code)
+	case <-time.After(300 * time.Millisecond):
+	}
+}
+
 func TestFindPeers(t *testing.T) {
 	pm, _, err := newTestProtocolManager(downloader.FullSync, 0, nil, nil)
 	if pm != nil {
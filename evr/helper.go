@@ -136,7 +136,7 @@ func newTestProtocolManagerForTwoChain(mode downloader.SyncMode, n int, k int, s
 
 	engine := clique.New(params.AllCliqueProtocolChanges.Clique, db)
 	engine.Authorize(testBank, signFun)
-	conf := &params.FConConfig{params.FConsensusChainConfig.Clique.Period, params.FConsensusChainConfig.Clique.Epoch}
+	conf := &params.FConConfig{Period: params.FConsensusChainConfig.Clique.Period, Epoch: params.FConsensusChainConfig.Clique.Epoch}
 	fEngine := fconsensus.New(conf, db)
 	fEngine.Authorize(testBank, signFun)
 
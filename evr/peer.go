@@ -57,6 +57,11 @@ const (
 	// above some healthy uncle limit, so use that.
 	maxQueuedAnns = 4
 
+	// maxQueuedSeals is the maximum number of final chain seal requests and signatures
+	// to queue up before dropping broadcasts. Only the latest pending block is signed,
+	// so a few are enough.
+	maxQueuedSeals = 16
+
 	handshakeTimeout = 5 * time.Second
 )
 
@@ -80,6 +85,12 @@ type annsEvent struct {
 	isFinalChain bool
 }
 
+// sealEvent is a final chain seal request or signature, waiting for its turn in the broadcast queue.
+type sealEvent struct {
+	code uint64
+	data interface{}
+}
+
 type Peer struct {
 	id string
 
@@ -103,6 +114,7 @@ type Peer struct {
 	queuedTxs    chan []*types.Transaction // Queue of transactions to broadcast to the Peer
 	queuedProps  chan *propEvent           // Queue of blocks to broadcast to the Peer
 	queuedAnns   chan *annsEvent           // Queue of blocks to announce to the Peer
+	queuedSeals  chan *sealEvent           // Queue of final chain seal requests and signatures to relay to the Peer
 	term         chan struct{}             // Termination channel to stop the broadcaster
 }
 
@@ -118,6 +130,7 @@ func newPeer(version int, p *p2p.Peer, rw p2p.MsgReadWriter) *Peer {
 		queuedTxs:    make(chan []*types.Transaction, maxQueuedTxs),
 		queuedProps:  make(chan *propEvent, maxQueuedProps),
 		queuedAnns:   make(chan *annsEvent, maxQueuedAnns),
+		queuedSeals:  make(chan *sealEvent, maxQueuedSeals),
 		term:         make(chan struct{}),
 	}
 }
@@ -147,6 +160,12 @@ func (p *Peer) broadcast() {
 			}
 			p.Log().Trace("Announced block", "number", anns.block.Number(), "hash", anns.block.Hash())
 
+		case seal := <-p.queuedSeals:
+			if err := p2p.Send(p.rw, seal.code, seal.data); err != nil {
+				return
+			}
+			p.Log().Trace("Relayed final chain seal", "code", seal.code)
+
 		case <-p.term:
 			return
 		}
@@ -285,6 +304,16 @@ func (p *Peer) SendNewBlockHashes(hashes []common.Hash, numbers []uint64, isFina
 	return p2p.Send(p.rw, NewBlockHashesMsg, request)
 }
 
+// AsyncSendSeal queues a final chain seal request or signature for propagation to a
+// remote Peer. If the Peer's broadcast queue is full, the event is silently dropped.
+func (p *Peer) AsyncSendSeal(code uint64, data interface{}) {
+	select {
+	case p.queuedSeals <- &sealEvent{code: code, data: data}:
+	default:
+		p.Log().Debug("Dropping final chain seal propagation", "code", code)
+	}
+}
+
 // AsyncSendNewBlockHash queues the availability of a block for propagation to a
 // remote Peer. If the Peer's broadcast queue is full, the event is silently
 // dropped.
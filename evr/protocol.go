@@ -24,6 +24,7 @@ import (
 	"github.com/Evrynetlabs/evrynet-node/common"
 	"github.com/Evrynetlabs/evrynet-node/core"
 	"github.com/Evrynetlabs/evrynet-node/core/types"
+	"github.com/Evrynetlabs/evrynet-node/crypto"
 	"github.com/Evrynetlabs/evrynet-node/event"
 	"github.com/Evrynetlabs/evrynet-node/rlp"
 )
@@ -37,16 +38,18 @@ const (
 	//TODO: official declaration of this protocol with an EIP
 	eth64 = 64
 	eth65 = 65
+	// Version 66 contains the messages exchanging the signatures of final blocks sealed by several signers
+	eth66 = 66
 )
 
 // ProtocolName is the official short name of the protocol used during capability negotiation.
 var ProtocolName = "evr"
 
 // ProtocolVersions are the supported versions of the evr protocol (first is primary).
-var ProtocolVersions = []uint{eth65, eth64, eth63, eth62}
+var ProtocolVersions = []uint{eth66, eth65, eth64, eth63, eth62}
 
 // ProtocolLengths are the number of implemented message corresponding to different protocol versions.
-var ProtocolLengths = []uint64{51, 18, 17, 8}
+var ProtocolLengths = []uint64{53, 51, 18, 17, 8}
 
 const ProtocolMaxMsgSize = 10 * 1024 * 1024 // Maximum cap on the size of a protocol message
 
@@ -87,6 +90,9 @@ const (
 	FReceiptsMsg     = 0x30
 	FEvilBlockMsg    = 0x31
 	GetFEvilBlockMsg = 0x32
+	// Protocol messages belonging to evr/66
+	FSealRequestMsg   = 0x33
+	FSealSignatureMsg = 0x34
 )
 
 type errCode int
@@ -201,6 +207,17 @@ type newBlockData struct {
 	TD    *big.Int
 }
 
+// fSealSignatureData is the network packet for the signature of a final block waiting for signatures.
+type fSealSignatureData struct {
+	SealHash  common.Hash
+	Signature []byte
+}
+
+// hash returns the identifier of the signature used to relay it only once.
+func (d *fSealSignatureData) hash() common.Hash {
+	return crypto.Keccak256Hash(d.SealHash.Bytes(), d.Signature)
+}
+
 // blockBody represents the data content of a single block.
 type blockBody struct {
 	Transactions []*types.Transaction // Transactions contained within a block
@@ -405,6 +405,14 @@ func (ec *Client) CodeAt(ctx context.Context, account common.Address, blockNumbe
 	return result, err
 }
 
+// ProvidersAt returns the providers of the given enterprise contract, none if the account is not an enterprise contract.
+// The block number can be nil, in which case the providers are taken from the latest known block.
+func (ec *Client) ProvidersAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]common.Address, error) {
+	var result []common.Address
+	err := ec.c.CallContext(ctx, &result, "evr_getProviders", account, toBlockNumArg(blockNumber))
+	return result, err
+}
+
 // NonceAt returns the account nonce of the given account.
 // The block number can be nil, in which case the nonce is taken from the latest known block.
 func (ec *Client) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
@@ -481,6 +489,13 @@ func (ec *Client) PendingCodeAt(ctx context.Context, account common.Address) ([]
 	return result, err
 }
 
+// PendingProvidersAt returns the providers of the given enterprise contract in the pending state.
+func (ec *Client) PendingProvidersAt(ctx context.Context, account common.Address) ([]common.Address, error) {
+	var result []common.Address
+	err := ec.c.CallContext(ctx, &result, "evr_getProviders", account, "pending")
+	return result, err
+}
+
 // PendingNonceAt returns the account nonce of the given account in the pending state.
 // This is the nonce that should be used for the next transaction.
 func (ec *Client) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
@@ -592,6 +607,131 @@ func (ec *Client) ProviderSignTx(ctx context.Context, tx *types.Transaction, pro
 	return json.tx, nil
 }
 
+// ProviderSignTxWithScope requests the provider to sign the transaction for inclusion up to the block validUntil
+// and for a fee up to maxFee, zero values leave the provider signature unbounded.
+// Please note that the provider account must be unlocked prior to run this function
+func (ec *Client) ProviderSignTxWithScope(ctx context.Context, tx *types.Transaction, providerAddr *common.Address, validUntil uint64, maxFee *big.Int) (*types.Transaction, error) {
+	return ec.ProviderSignTx(ctx, tx.WithProviderScope(validUntil, maxFee), providerAddr)
+}
+
+// Finality
+
+// LatestFinalized returns the latest main chain block finalized by the final chain.
+// It returns nil if no block is finalized yet.
+func (ec *Client) LatestFinalized(ctx context.Context) (*types.Finality, error) {
+	var finality *types.Finality
+	err := ec.c.CallContext(ctx, &finality, "finality_latestFinalized")
+	return finality, err
+}
+
+// IsBlockFinalized returns true if the main chain block is canonical and finalized by the final chain.
+func (ec *Client) IsBlockFinalized(ctx context.Context, hash common.Hash) (bool, error) {
+	var finalized bool
+	err := ec.c.CallContext(ctx, &finalized, "finality_isBlockFinalized", hash)
+	return finalized, err
+}
+
+// IsTransactionFinalized returns true if the transaction is included in a canonical main chain block
+// finalized by the final chain.
+func (ec *Client) IsTransactionFinalized(ctx context.Context, txHash common.Hash) (bool, error) {
+	var finalized bool
+	err := ec.c.CallContext(ctx, &finalized, "finality_isTransactionFinalized", txHash)
+	return finalized, err
+}
+
+// EvilHeaders returns the evil headers detected by the final chain blocks in the range [from, to].
+// The final chain block numbers can be nil, in which case the latest final chain block is used.
+func (ec *Client) EvilHeaders(ctx context.Context, from, to *big.Int) ([]*types.Finality, error) {
+	var evilHeaders []*types.Finality
+	err := ec.c.CallContext(ctx, &evilHeaders, "finality_getEvilHeaders", toBlockNumArg(from), toBlockNumArg(to))
+	return evilHeaders, err
+}
+
+// FraudProof returns the fraud proof of an evil block, it can be verified with core.VerifyFraudProof.
+func (ec *Client) FraudProof(ctx context.Context, hash common.Hash) (*types.FraudProof, error) {
+	var proof *types.FraudProof
+	err := ec.c.CallContext(ctx, &proof, "finality_getFraudProof", hash)
+	if err == nil && proof == nil {
+		err = evrynetNode.NotFound
+	}
+	return proof, err
+}
+
+// Rewards returns the reward ledger records of the epochs from fromEpoch to toEpoch which involve the address,
+// as a validator, an owner or a voter.
+func (ec *Client) Rewards(ctx context.Context, address common.Address, fromEpoch, toEpoch uint64) ([]*types.ValidatorReward, error) {
+	var rewards []*types.ValidatorReward
+	err := ec.c.CallContext(ctx, &rewards, "tendermint_getRewards", address, fromEpoch, toEpoch)
+	return rewards, err
+}
+
+// ConsensusState returns the state of the running Tendermint core of the node.
+func (ec *Client) ConsensusState(ctx context.Context) (*types.ConsensusState, error) {
+	var state *types.ConsensusState
+	err := ec.c.CallContext(ctx, &state, "tendermint_dumpConsensusState")
+	return state, err
+}
+
+// RoundVotes returns the prevotes and precommits received by the running Tendermint core at the round of the block number.
+func (ec *Client) RoundVotes(ctx context.Context, blockNumber uint64, round int64) (*types.RoundVotes, error) {
+	var votes *types.RoundVotes
+	err := ec.c.CallContext(ctx, &votes, "tendermint_getRoundVotes", blockNumber, round)
+	return votes, err
+}
+
+// SubscribeRoundSteps subscribes to notifications about the step transitions of the running Tendermint core.
+func (ec *Client) SubscribeRoundSteps(ctx context.Context, ch chan<- types.RoundStep) (evrynetNode.Subscription, error) {
+	return ec.c.Subscribe(ctx, "tendermint", ch, "roundSteps")
+}
+
+// StakingInfo returns the configuration of the staking contract.
+// The block number can be nil, in which case the state is taken from the latest known block.
+func (ec *Client) StakingInfo(ctx context.Context, blockNumber *big.Int) (*types.StakingInfo, error) {
+	var info *types.StakingInfo
+	err := ec.c.CallContext(ctx, &info, "staking_getInfo", toBlockNumArg(blockNumber))
+	return info, err
+}
+
+// StakingCandidates returns the candidates registered with the staking contract, with their owner and total stake.
+func (ec *Client) StakingCandidates(ctx context.Context, blockNumber *big.Int) ([]*types.StakingCandidate, error) {
+	var candidates []*types.StakingCandidate
+	err := ec.c.CallContext(ctx, &candidates, "staking_getCandidates", toBlockNumArg(blockNumber))
+	return candidates, err
+}
+
+// StakingVoters returns the voters of a candidate with their stakes.
+func (ec *Client) StakingVoters(ctx context.Context, candidate common.Address, blockNumber *big.Int) ([]*types.StakingVote, error) {
+	var votes []*types.StakingVote
+	err := ec.c.CallContext(ctx, &votes, "staking_getVoters", candidate, toBlockNumArg(blockNumber))
+	return votes, err
+}
+
+// StakingVoterStakes returns the stakes of a voter across the candidates.
+func (ec *Client) StakingVoterStakes(ctx context.Context, voter common.Address, blockNumber *big.Int) ([]*types.StakingVote, error) {
+	var votes []*types.StakingVote
+	err := ec.c.CallContext(ctx, &votes, "staking_getVoterStakes", voter, toBlockNumArg(blockNumber))
+	return votes, err
+}
+
+// StakingWithdrawals returns the unvoted stakes of an address which are not withdrawn yet, by epoch.
+func (ec *Client) StakingWithdrawals(ctx context.Context, address common.Address, blockNumber *big.Int) ([]*types.StakingWithdrawal, error) {
+	var withdrawals []*types.StakingWithdrawal
+	err := ec.c.CallContext(ctx, &withdrawals, "staking_getWithdrawals", address, toBlockNumArg(blockNumber))
+	return withdrawals, err
+}
+
+// NextValidators returns the validator set elected by the state of the block.
+func (ec *Client) NextValidators(ctx context.Context, blockNumber *big.Int) ([]common.Address, error) {
+	var validators []common.Address
+	err := ec.c.CallContext(ctx, &validators, "staking_getNextValidators", toBlockNumArg(blockNumber))
+	return validators, err
+}
+
+// SubscribeFinalized subscribes to notifications about the main chain blocks finalized by new final chain blocks.
+func (ec *Client) SubscribeFinalized(ctx context.Context, ch chan<- *types.Finality) (evrynetNode.Subscription, error) {
+	return ec.c.Subscribe(ctx, "finality", ch, "newFinalized")
+}
+
 func toCallArg(msg evrynetNode.CallMsg) interface{} {
 	arg := map[string]interface{}{
 		"from": msg.From,
@@ -131,6 +131,10 @@ type CallMsg struct {
 	GasPrice *big.Int        // wei <-> gas exchange ratio
 	Value    *big.Int        // amount of wei sent along with the call
 	Data     []byte          // input data, usually an ABI-encoded contract method invocation
+
+	Owner    *common.Address // the owner of the enterprise contract to create (optional)
+	Provider *common.Address // the provider of the enterprise contract to create (optional)
+	GasPayer *common.Address // the provider paying the gas of the call to an enterprise contract (nil = sender)
 }
 
 // SendTxArgs represents the arguments to sumbit a new transaction into the transaction pool.
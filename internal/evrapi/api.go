@@ -148,11 +148,13 @@ func (s *PublicTxPoolAPI) Status() map[string]hexutil.Uint {
 }
 
 // Inspect retrieves the content of the transaction pool and flattens it into an
-// easily inspectable list.
+// easily inspectable list. The fees of the transactions sponsored by each provider
+// are summed up under "providers".
 func (s *PublicTxPoolAPI) Inspect() map[string]map[string]map[string]string {
 	content := map[string]map[string]map[string]string{
-		"pending": make(map[string]map[string]string),
-		"queued":  make(map[string]map[string]string),
+		"pending":   make(map[string]map[string]string),
+		"queued":    make(map[string]map[string]string),
+		"providers": make(map[string]map[string]string),
 	}
 	pending, queue := s.b.TxPoolContent()
 
@@ -179,6 +181,13 @@ func (s *PublicTxPoolAPI) Inspect() map[string]map[string]map[string]string {
 		}
 		content["queued"][account.String()] = dump
 	}
+	// Sum up the sponsored transactions by provider
+	for provider, cost := range s.b.TxPoolSponsored() {
+		content["providers"][provider.String()] = map[string]string{
+			"txs": fmt.Sprintf("%d", cost.Txs),
+			"fee": fmt.Sprintf("%v wei", cost.Fee),
+		}
+	}
 	return content
 }
 
@@ -788,6 +797,19 @@ func (s *PublicBlockChainAPI) GetCode(ctx context.Context, address common.Addres
 	return code, state.Error()
 }
 
+// GetProviders returns the providers of the enterprise contract at the given address in the state for the given block number,
+// none if the address is not an enterprise contract.
+func (s *PublicBlockChainAPI) GetProviders(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) ([]common.Address, error) {
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	if state.GetOwner(address) == nil {
+		return []common.Address{}, state.Error()
+	}
+	return state.GetProviders(address), state.Error()
+}
+
 // GetStorageAt returns the storage from the state at the given address, key and
 // block number. The rpc.LatestBlockNumber and rpc.PendingBlockNumber meta block
 // numbers are also allowed.
@@ -1175,6 +1197,10 @@ type RPCTransaction struct {
 	PV *hexutil.Big `json:"pv"`
 	PR *hexutil.Big `json:"pr"`
 	PS *hexutil.Big `json:"ps"`
+	// Scope of the provider signature
+	Scope []types.ProviderScope `json:"providerScope,omitempty"`
+	// Type of the typed transaction envelope, empty for the legacy encoding
+	Type *hexutil.Uint64 `json:"type,omitempty"`
 }
 
 // newRPCTransaction returns a transaction that will serialize to the RPC
@@ -1209,6 +1235,13 @@ func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber
 		Provider: tx.Provider(),
 		Extra:    hexutil.Bytes(tx.ExtraData()),
 	}
+	if scope := tx.ProviderScope(); scope != nil {
+		result.Scope = []types.ProviderScope{*scope}
+	}
+	if tx.Enveloped() {
+		typ := hexutil.Uint64(tx.Type())
+		result.Type = &typ
+	}
 
 	if blockHash != (common.Hash{}) {
 		result.BlockHash = blockHash
@@ -33,6 +33,7 @@ var Modules = map[string]string{
 	"swarmfs":    SwarmfsJs,
 	"txpool":     TxpoolJs,
 	"tendermint": TendermintJs,
+	"staking":    StakingJs,
 }
 
 const ChequebookJs = `
@@ -520,6 +521,12 @@ web3._extend({
 			params: 3,
 			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, web3._extend.formatters.inputBlockNumberFormatter]
 		}),
+		new web3._extend.Method({
+			name: 'getProviders',
+			call: 'evr_getProviders',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
 	],
 	properties: [
 		new web3._extend.Property({
@@ -806,6 +813,67 @@ web3._extend({
 			params: 1,
 			inputFormatter:[null]
 		}),
+		new web3._extend.Method({
+			name: 'getRewards',
+			call: 'tendermint_getRewards',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'dumpConsensusState',
+			call: 'tendermint_dumpConsensusState',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'getRoundVotes',
+			call: 'tendermint_getRoundVotes',
+			params: 2
+		}),
+	],
+	properties: []
+});
+`
+
+const StakingJs = `
+web3._extend({
+	property: 'staking',
+	methods: [
+		new web3._extend.Method({
+			name: 'getInfo',
+			call: 'staking_getInfo',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getCandidates',
+			call: 'staking_getCandidates',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getVoters',
+			call: 'staking_getVoters',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getVoterStakes',
+			call: 'staking_getVoterStakes',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getWithdrawals',
+			call: 'staking_getWithdrawals',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getNextValidators',
+			call: 'staking_getNextValidators',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
 	],
 	properties: []
 });
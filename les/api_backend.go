@@ -148,6 +148,11 @@ func (b *LesApiBackend) TxPoolContent() (map[common.Address]types.Transactions,
 	return b.evr.txPool.Content()
 }
 
+// TxPoolSponsored returns no sponsored cost, the light pool does not track the providers
+func (b *LesApiBackend) TxPoolSponsored() map[common.Address]core.SponsoredCost {
+	return make(map[common.Address]core.SponsoredCost)
+}
+
 func (b *LesApiBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subscription {
 	return b.evr.txPool.SubscribeNewTxsEvent(ch)
 }
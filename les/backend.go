@@ -89,6 +89,10 @@ func New(ctx *node.ServiceContext, config *evr.Config) (*LightEvrynet, error) {
 	}
 	log.Info("Initialised chain configuration", "config", chainConfig)
 
+	engine, err := evr.CreateLightConsensusEngine(ctx, chainConfig, config, chainDb)
+	if err != nil {
+		return nil, err
+	}
 	peers := newPeerSet()
 	quitSync := make(chan struct{})
 
@@ -103,7 +107,7 @@ func New(ctx *node.ServiceContext, config *evr.Config) (*LightEvrynet, error) {
 		peers:          peers,
 		reqDist:        newRequestDistributor(peers, quitSync, &mclock.System{}),
 		accountManager: ctx.AccountManager,
-		engine:         evr.CreateConsensusEngine(ctx, chainConfig, config, nil, false, chainDb),
+		engine:         engine,
 		shutdownChan:   make(chan bool),
 		networkId:      config.NetworkId,
 		bloomRequests:  make(chan chan *bloombits.Retrieval),
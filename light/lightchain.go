@@ -28,6 +28,7 @@ import (
 
 	"github.com/Evrynetlabs/evrynet-node/common"
 	"github.com/Evrynetlabs/evrynet-node/consensus"
+	"github.com/Evrynetlabs/evrynet-node/consensus/tendermint/utils"
 	"github.com/Evrynetlabs/evrynet-node/core"
 	"github.com/Evrynetlabs/evrynet-node/core/rawdb"
 	"github.com/Evrynetlabs/evrynet-node/core/state"
@@ -45,6 +46,11 @@ var (
 	blockCacheLimit = 256
 )
 
+// checkpointTruster is implemented by the consensus engines which track the validator sets from epoch checkpoint headers
+type checkpointTruster interface {
+	SetTrustedCheckpoint(header *types.Header) error
+}
+
 // LightChain represents a canonical chain that by default only handles block
 // headers, downloading block bodies and receipts on demand through an ODR
 // interface. It only does header validation during chain insertion.
@@ -474,12 +480,27 @@ func (lc *LightChain) SyncCht(ctx context.Context) bool {
 	latest := sections*lc.indexerConfig.ChtSize - 1
 	if clique := lc.hc.Config().Clique; clique != nil {
 		latest -= latest % clique.Epoch // epoch snapshot for clique
-	} else if tdmint := lc.hc.Config().Tendermint; tdmint != nil {
-		latest -= latest % tdmint.Epoch // epoch snapshot for tendermint
 	}
 	if head >= latest {
 		return false
 	}
+	// The validator set of the headers after the CHT head is stored in the epoch checkpoint header at or before it,
+	// which is proven by the CHT as well, so it can be trusted without its ancestors
+	var checkpoint *types.Header
+	if truster, ok := lc.engine.(checkpointTruster); ok {
+		if tdmint := lc.hc.Config().Tendermint; tdmint != nil && tdmint.Epoch != 0 {
+			var err error
+			checkpoint, err = GetHeaderByNumber(ctx, lc.odr, utils.GetCheckpointNumber(tdmint.Epoch, latest+1))
+			if err != nil {
+				log.Warn("Failed to retrieve the checkpoint of the CHT header", "number", latest, "err", err)
+				return false
+			}
+			if err := truster.SetTrustedCheckpoint(checkpoint); err != nil {
+				log.Warn("Failed to trust the checkpoint of the CHT header", "number", checkpoint.Number, "err", err)
+				return false
+			}
+		}
+	}
 	// Retrieve the latest useful header and update to it
 	if header, err := GetHeaderByNumber(ctx, lc.odr, latest); header != nil && err == nil {
 		lc.chainmu.Lock()
@@ -18,10 +18,12 @@ package light
 
 import (
 	"context"
+	"errors"
 	"math/big"
 	"testing"
 
 	"github.com/Evrynetlabs/evrynet-node/common"
+	"github.com/Evrynetlabs/evrynet-node/consensus"
 	"github.com/Evrynetlabs/evrynet-node/consensus/ethash"
 	"github.com/Evrynetlabs/evrynet-node/core"
 	"github.com/Evrynetlabs/evrynet-node/core/rawdb"
@@ -352,3 +354,93 @@ func TestReorgBadHeaderHashes(t *testing.T) {
 		t.Errorf("last header hash mismatch: have: %x, want %x", ncm.CurrentHeader().Hash(), headers[2].Hash())
 	}
 }
+
+// chtOdr is an ODR backend which serves the headers of a server database as if they were proven by its CHT.
+type chtOdr struct {
+	OdrBackend
+	sdb, ldb      evrdb.Database
+	indexer       *core.ChainIndexer
+	indexerConfig *IndexerConfig
+}
+
+func (odr *chtOdr) Database() evrdb.Database {
+	return odr.ldb
+}
+
+func (odr *chtOdr) ChtIndexer() *core.ChainIndexer {
+	return odr.indexer
+}
+
+func (odr *chtOdr) IndexerConfig() *IndexerConfig {
+	return odr.indexerConfig
+}
+
+func (odr *chtOdr) Retrieve(ctx context.Context, req OdrRequest) error {
+	if req, ok := req.(*ChtRequest); ok {
+		hash := rawdb.ReadCanonicalHash(odr.sdb, req.BlockNum, false)
+		req.Header = rawdb.ReadHeader(odr.sdb, hash, req.BlockNum, false)
+		req.Td = rawdb.ReadTd(odr.sdb, hash, req.BlockNum, false)
+	}
+	req.StoreResult(odr.ldb)
+	return nil
+}
+
+// checkpointEngine is a consensus engine which tracks the trusted epoch checkpoint headers.
+type checkpointEngine struct {
+	consensus.Engine
+	epoch   uint64
+	trusted *types.Header
+}
+
+func (e *checkpointEngine) SetTrustedCheckpoint(header *types.Header) error {
+	if header.Number.Uint64()%e.epoch != 0 {
+		return errors.New("header is not a checkpoint")
+	}
+	e.trusted = header
+	return nil
+}
+
+// Tests that syncing from a CHT head which is not an epoch boundary trusts the epoch
+// checkpoint header at or before it, and sets the CHT head as the current header.
+func TestSyncChtCheckpoint(t *testing.T) {
+	var (
+		sdb           = rawdb.NewMemoryDatabase()
+		ldb           = rawdb.NewMemoryDatabase()
+		gspec         = core.Genesis{Config: params.TestChainConfig}
+		genesis       = gspec.MustCommit(sdb)
+		indexerConfig = &IndexerConfig{ChtSize: 64, ChtConfirms: 1}
+		config        = *params.TestChainConfig
+		engine        = &checkpointEngine{Engine: ethash.NewFaker(), epoch: 10}
+	)
+	gspec.MustCommit(ldb)
+	config.Tendermint = &params.TendermintConfig{Epoch: engine.epoch}
+
+	headers := makeHeaderChain(genesis.Header(), 70, sdb, canonicalSeed)
+	td := new(big.Int).Set(genesis.Difficulty())
+	for _, header := range headers {
+		td.Add(td, header.Difficulty)
+		rawdb.WriteHeader(sdb, header, false)
+		rawdb.WriteTd(sdb, header.Hash(), header.Number.Uint64(), new(big.Int).Set(td), false)
+		rawdb.WriteCanonicalHash(sdb, header.Hash(), header.Number.Uint64(), false)
+	}
+	chtHead := headers[indexerConfig.ChtSize-2] // the header #63 closing the first section
+
+	odr := &chtOdr{sdb: sdb, ldb: ldb, indexerConfig: indexerConfig}
+	odr.indexer = NewChtIndexer(ldb, odr, indexerConfig.ChtSize, indexerConfig.ChtConfirms, false)
+	defer odr.indexer.Close()
+	odr.indexer.AddCheckpoint(0, chtHead.Hash())
+
+	lc, err := NewLightChain(odr, &config, engine)
+	if err != nil {
+		t.Fatalf("failed to create light chain: %v", err)
+	}
+	if !lc.SyncCht(context.Background()) {
+		t.Fatalf("failed to sync the CHT")
+	}
+	if lc.CurrentHeader().Hash() != chtHead.Hash() {
+		t.Errorf("current header mismatch: have #%d, want #%d", lc.CurrentHeader().Number, chtHead.Number)
+	}
+	if engine.trusted == nil || engine.trusted.Hash() != headers[59].Hash() {
+		t.Errorf("trusted checkpoint mismatch: have %v, want #%d", engine.trusted, headers[59].Number)
+	}
+}
@@ -77,14 +77,20 @@ const (
 
 	// staleThreshold is the maximum depth of the acceptable stale block.
 	staleThreshold = 7
-
-	M = uint64(5)
-	K = uint64(5)
 )
 
+// defaultPacking is the packing window of the final chain blocks sealed by the worker until a configured
+// window is activated, it keeps the 5/5 window the worker packed the existing final chains with.
+var defaultPacking = &params.FConPackingConfig{
+	Block:    big.NewInt(0),
+	Size:     5,
+	Distance: 5,
+}
+
 type AssistChainHandler interface {
 	consensus.FullChainReader
 	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
+	GenerateFraudProof(block *types.Block) (*types.FraudProof, error)
 }
 
 // environment is the worker's current environment and holds all of the current state information.
@@ -141,6 +147,7 @@ type worker struct {
 	evr         Backend
 	chain       *core.BlockChain
 	assistChain AssistChainHandler
+	planner     *fconsensus.SectionPlanner // Plans the main chain blocks packed by the final chain
 
 	// Subscriptions
 	mux             *event.TypeMux
@@ -221,6 +228,7 @@ func newWorker(config *Config, chainConfig *params.ChainConfig, engine consensus
 	if chainConfig.IsFinalChain {
 		worker.chain = evr.FBlockChain()
 		worker.assistChain = evr.BlockChain()
+		worker.planner = fconsensus.NewSectionPlanner(chainConfig, worker.assistChain, defaultPacking)
 	} else {
 		worker.chain = evr.BlockChain()
 		worker.assistChain = evr.FBlockChain()
@@ -884,7 +892,10 @@ func (w *worker) commitTransactionsForFinalChain(begin, end uint64, coinbase com
 	if w.current.gasPool == nil {
 		w.current.gasPool = new(core.GasPool).AddGas(w.current.header.GasLimit)
 	}
-	var evilHeader *types.Header
+	var (
+		evilHeader *types.Header
+		fraudProof common.Hash
+	)
 	for begin <= end {
 
 		if interrupt != nil && atomic.LoadInt32(interrupt) != commitInterruptNone {
@@ -924,6 +935,7 @@ func (w *worker) commitTransactionsForFinalChain(begin, end uint64, coinbase com
 				blockTerm.Number().String(), root.String(), blockTerm.Root().String())
 			log.Error("FBManager Apply transactions failed", "err", errStr)
 			evilHeader = headerTerm
+			fraudProof = w.generateFraudProof(blockTerm)
 			break
 		}
 		if (w.current.header.GasUsed - gasUsedPre) != blockTerm.GasUsed() {
@@ -931,6 +943,7 @@ func (w *worker) commitTransactionsForFinalChain(begin, end uint64, coinbase com
 				blockTerm.Number().String(), w.current.header.GasUsed-gasUsedPre, blockTerm.GasUsed())
 			log.Error("FBManager Apply transactions failed", "err", errStr)
 			evilHeader = headerTerm
+			fraudProof = w.generateFraudProof(blockTerm)
 			break
 		}
 		begin++
@@ -948,6 +961,7 @@ func (w *worker) commitTransactionsForFinalChain(begin, end uint64, coinbase com
 		return true
 	}
 	fce.EvilHeader = evilHeader
+	fce.FraudProof = fraudProof
 	fce.CurrentBlock = currentHash
 	fce.CurrentHeight = begin - 1
 	rlpBytes, err := rlp.EncodeToBytes(&fce)
@@ -959,48 +973,43 @@ func (w *worker) commitTransactionsForFinalChain(begin, end uint64, coinbase com
 	return false
 }
 
-// Get block pack section
-func (w *worker) GetBlockSections() (uint64, uint64, bool) {
-	number := w.assistChain.CurrentHeader().Number.Uint64()
-	currentBlock := w.chain.CurrentBlock()
-	packedBlockNumber := uint64(0)
-	if currentBlock.Number().Uint64() > 0 {
-		fce, err := fconTypes.ExtractFConExtra(currentBlock.Header())
-		if err != nil {
-			log.Error("ExtractFConExtra failed", "err", err)
-			return 0, 0, false
-		}
-		packedBlockNumber = fce.CurrentHeight
-	}
-
-	if packedBlockNumber+M+K > number {
-		return 0, 0, false
-	}
-	end := packedBlockNumber + M
-	if end < number-K {
-		end = number - K
+// generateFraudProof generates and stores the fraud proof of an evil block, it returns the hash of the proof
+// to be committed to by the final chain block.
+func (w *worker) generateFraudProof(block *types.Block) common.Hash {
+	proof, err := w.assistChain.GenerateFraudProof(block)
+	if err != nil {
+		log.Warn("FBManager failed to generate the fraud proof", "number", block.Number(), "hash", block.Hash(), "err", err)
+		return common.Hash{}
 	}
-	return packedBlockNumber + 1, end, true
-
+	log.Info("FBManager generated the fraud proof", "number", block.Number(), "hash", block.Hash(), "nodes", len(proof.Nodes))
+	return proof.Hash()
 }
 
 // commitNewWork generates several new sealing tasks based on the parent block.
 func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64) {
 	w.mu.RLock()
 	defer w.mu.RUnlock()
+	tstart := time.Now()
+	parent := w.chain.CurrentBlock()
+	gasLimit := core.CalcGasLimit(parent, w.config.GasFloor, w.config.GasCeil)
+
 	var begin, end uint64
-	var trigger bool
 	if w.chainConfig.IsFinalChain {
-		begin, end, trigger = w.GetBlockSections()
+		var (
+			trigger bool
+			err     error
+		)
+		begin, end, trigger, err = w.planner.Plan(parent.Header(), gasLimit)
+		if err != nil {
+			log.Error("Failed to plan the final chain section", "err", err)
+			return
+		}
 		if !trigger {
 			log.Info("Final chain commitNewWork not trigger to create block")
 			return
 		}
 	}
 
-	tstart := time.Now()
-	parent := w.chain.CurrentBlock()
-
 	if parent.Time() >= uint64(timestamp) {
 		timestamp = int64(parent.Time() + 1)
 	}
@@ -1015,7 +1024,7 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64)
 	header := &types.Header{
 		ParentHash: parent.Hash(),
 		Number:     num.Add(num, common.Big1),
-		GasLimit:   core.CalcGasLimit(parent, w.config.GasFloor, w.config.GasCeil),
+		GasLimit:   gasLimit,
 		Extra:      w.extra,
 		Time:       uint64(timestamp),
 	}
@@ -17,6 +17,7 @@
 package params
 
 import (
+	"encoding/json"
 	"fmt"
 	"math/big"
 
@@ -118,18 +119,18 @@ var (
 	//
 	// This configuration is intentionally not using keyed fields to force anyone
 	// adding flags to the config to also have to set these fields.
-	AllEthashProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(GasPriceConfig), nil, nil, new(EthashConfig), nil, nil, false}
+	AllEthashProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(GasPriceConfig), nil, nil, nil, nil, new(EthashConfig), nil, nil, nil, false}
 
 	// AllCliqueProtocolChanges contains every protocol change (EIPs) introduced
 	// and accepted by the Evrynet core developers into the Clique consensus.
 	//
 	// This configuration is intentionally not using keyed fields to force anyone
 	// adding flags to the config to also have to set these fields.
-	AllCliqueProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(GasPriceConfig), nil, nil, nil, &CliqueConfig{Period: 0, Epoch: 30000}, nil, false}
-	FConsensusChainConfig = &ChainConfig{big.NewInt(1337), big.NewInt(GasPriceConfig), nil, nil, nil, &CliqueConfig{Period: 0, Epoch: 30000}, nil, false}
+	AllCliqueProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(GasPriceConfig), nil, nil, nil, nil, nil, &CliqueConfig{Period: 0, Epoch: 30000}, nil, nil, false}
+	FConsensusChainConfig = &ChainConfig{big.NewInt(1337), big.NewInt(GasPriceConfig), nil, nil, nil, nil, nil, &CliqueConfig{Period: 0, Epoch: 30000}, nil, nil, false}
 
-	TestChainConfig           = &ChainConfig{big.NewInt(1), big.NewInt(GasPriceConfig), nil, nil, new(EthashConfig), nil, nil, false}
-	TendermintTestChainConfig = &ChainConfig{big.NewInt(1), big.NewInt(GasPriceConfig), nil, nil, nil, nil, new(TendermintConfig), false}
+	TestChainConfig           = &ChainConfig{big.NewInt(1), big.NewInt(GasPriceConfig), nil, nil, nil, nil, new(EthashConfig), nil, nil, nil, false}
+	TendermintTestChainConfig = &ChainConfig{big.NewInt(1), big.NewInt(GasPriceConfig), nil, nil, nil, nil, nil, nil, new(TendermintConfig), nil, false}
 	TestRules                 = TestChainConfig.Rules(new(big.Int))
 )
 
@@ -155,13 +156,16 @@ type ChainConfig struct {
 
 	GasPrice *big.Int `json:"gasPrice"` // gasPrice identified the gasPrice for each transaction
 
-	ViervilleBlock *big.Int `json:"viervilleBlock,omitempty"` // ViervilleBlock switch block(nil = no fork, 0 = already activated)
-	EWASMBlock     *big.Int `json:"ewasmBlock,omitempty"`     // EWASM switch block (nil = no fork, 0 = already activated)
+	ViervilleBlock  *big.Int `json:"viervilleBlock,omitempty"`  // ViervilleBlock switch block(nil = no fork, 0 = already activated)
+	EWASMBlock      *big.Int `json:"ewasmBlock,omitempty"`      // EWASM switch block (nil = no fork, 0 = already activated)
+	TypedTxBlock    *big.Int `json:"typedTxBlock,omitempty"`    // TypedTx switch block enabling the typed transaction envelope (nil = no fork, 0 = already activated)
+	EnterpriseBlock *big.Int `json:"enterpriseBlock,omitempty"` // Enterprise switch block enabling the provider policies and the owners of enterprise contracts (nil = no fork, 0 = already activated)
 
 	// Various consensus engines
 	Ethash       *EthashConfig     `json:"ethash,omitempty"`
 	Clique       *CliqueConfig     `json:"clique,omitempty"`
 	Tendermint   *TendermintConfig `json:"tendermint,omitempty"`
+	FConsensus   *FConConfig       `json:"fconsensus,omitempty"`
 	IsFinalChain bool              `json:"isFinalChain"`
 }
 
@@ -179,9 +183,50 @@ type CliqueConfig struct {
 	Epoch  uint64 `json:"epoch"`  // Epoch length to reset votes and checkpoint
 }
 
+// FConConfig is the consensus engine configs for the final chain.
 type FConConfig struct {
-	Period uint64 `json:"period"` // Number of seconds between blocks to enforce
-	Epoch  uint64 `json:"epoch"`  // Epoch length to reset votes and checkpoint
+	Period    uint64           `json:"period"`              // Number of seconds between blocks to enforce
+	Epoch     uint64           `json:"epoch"`               // Epoch length to reset votes and checkpoint
+	Signers   []common.Address `json:"signers,omitempty"`   // The initial authorized signers, read from the genesis extra-data if empty
+	Threshold uint64           `json:"threshold,omitempty"` // The number of signatures to seal a block from ThresholdBlock
+
+	ThresholdBlock *big.Int `json:"thresholdBlock,omitempty"` // Threshold switch block (nil = no fork, blocks are sealed by their proposer only)
+
+	Packing []*FConPackingConfig `json:"packing,omitempty"` // The packing windows of the main chain blocks, each one activated at its block
+}
+
+// FConPackingConfig is the window of main chain blocks packed into the final chain blocks.
+type FConPackingConfig struct {
+	Block     *big.Int `json:"block"`               // Final chain block number the window is activated at
+	Size      uint64   `json:"size"`                // Minimal number of main chain blocks packed into a final block (M)
+	Distance  uint64   `json:"distance"`            // Number of main chain blocks the final chain stays behind the main chain head (K)
+	MaxBlocks uint64   `json:"maxBlocks,omitempty"` // Maximal number of main chain blocks packed into a final block when catching up, 0 for no limit
+	MaxGas    uint64   `json:"maxGas,omitempty"`    // Maximal gas of the main chain blocks packed into a final block, 0 for the final block gas limit only
+	MaxSize   uint64   `json:"maxSize,omitempty"`   // Maximal size in bytes of the main chain blocks packed into a final block, 0 for no limit
+}
+
+// equal returns whether the window w is the same as other
+func (w *FConPackingConfig) equal(other *FConPackingConfig) bool {
+	return configNumEqual(w.Block, other.Block) && w.Size == other.Size && w.Distance == other.Distance &&
+		w.MaxBlocks == other.MaxBlocks && w.MaxGas == other.MaxGas && w.MaxSize == other.MaxSize
+}
+
+// DefaultFConPacking is the packing window of the final chains which do not configure one.
+var DefaultFConPacking = &FConPackingConfig{
+	Block:    big.NewInt(0),
+	Size:     2,
+	Distance: 2,
+}
+
+// IsThreshold returns whether num is either equal to the threshold fork block or greater.
+// Before the fork, or if no threshold is configured, a block is sealed by the signature of its proposer only.
+func (c *FConConfig) IsThreshold(num *big.Int) bool {
+	return c.Threshold > 0 && isForked(c.ThresholdBlock, num)
+}
+
+// String implements the stringer interface, returning the consensus engine details.
+func (c *FConConfig) String() string {
+	return "fconsensus"
 }
 
 // String implements the stringer interface, returning the consensus engine details.
@@ -196,6 +241,20 @@ type TendermintConfig struct {
 	BlockReward      *big.Int         `json:"blockReward"`      // TendermintBlockReward for accumulating reward
 	StakingSCAddress *common.Address  `json:"stakingSCAddress"` // The staking SC address for validating when deploy SC
 	FixedValidators  []common.Address `json:"fixedValidators"`
+
+	DoubleSignSlashPercentage uint64 `json:"doubleSignSlashPercentage,omitempty"` // The percentage of stake slashed from a candidate and its voters for double signing
+	StakeWeightedVoting       bool   `json:"stakeWeightedVoting,omitempty"`       // If true, the voting power of a validator is proportional to its total stake
+	BLSCommittedSeals         bool   `json:"blsCommittedSeals,omitempty"`         // If true, blocks are committed with an aggregated BLS seal once every validator registers a BLS key
+
+	SignedBlocksWindow   uint64 `json:"signedBlocksWindow,omitempty"`   // The number of recent blocks to track the liveness of the validators, 0 to disable the downtime jailing
+	MinSignedPerWindow   uint64 `json:"minSignedPerWindow,omitempty"`   // The minimum percentage of the window a validator must sign not to be jailed, 0 for 50%
+	DowntimeJailDuration uint64 `json:"downtimeJailDuration,omitempty"` // The number of blocks before a validator jailed for downtime can be unjailed, 0 for the window size
+
+	DefaultCommissionRate uint64 `json:"defaultCommissionRate,omitempty"` // The percentage of the rewards kept by the owner of a candidate which has not set one, 0 for 50%
+	MinCommissionRate     uint64 `json:"minCommissionRate,omitempty"`     // The minimum commission rate a candidate can set
+	MaxCommissionRate     uint64 `json:"maxCommissionRate,omitempty"`     // The maximum commission rate a candidate can set, 0 for 100%
+
+	StakingLayout json.RawMessage `json:"stakingLayout,omitempty"` // The solc storage layout of the staking contract, the default layout if empty
 }
 
 // String implements the stringer interface, returning the consensus engine details.
@@ -213,13 +272,17 @@ func (c *ChainConfig) String() string {
 		engine = c.Clique
 	case c.Tendermint != nil:
 		engine = c.Tendermint
+	case c.FConsensus != nil:
+		engine = c.FConsensus
 	default:
 		engine = "unknown"
 	}
-	return fmt.Sprintf("{ChainID: %v GasPrice: %v Vierville: %v Engine: %v}",
+	return fmt.Sprintf("{ChainID: %v GasPrice: %v Vierville: %v TypedTx: %v Enterprise: %v Engine: %v}",
 		c.ChainID,
 		c.GasPrice,
 		c.ViervilleBlock,
+		c.TypedTxBlock,
+		c.EnterpriseBlock,
 		engine,
 	)
 }
@@ -234,6 +297,55 @@ func (c *ChainConfig) IsEWASM(num *big.Int) bool {
 	return isForked(c.EWASMBlock, num)
 }
 
+// IsTypedTx returns whether num is either equal to the TypedTx fork block or greater,
+// the typed transaction envelopes are only valid in the blocks after the fork.
+func (c *ChainConfig) IsTypedTx(num *big.Int) bool {
+	return isForked(c.TypedTxBlock, num)
+}
+
+// IsEnterprise returns whether num is either equal to the Enterprise fork block or greater,
+// the owners of the enterprise contracts can only set the policies of their providers, transfer
+// the ownership or set multiple owners after the fork.
+func (c *ChainConfig) IsEnterprise(num *big.Int) bool {
+	return isForked(c.EnterpriseBlock, num)
+}
+
+// FConPacking returns the packing window of the final chain activated at the block num.
+func (c *ChainConfig) FConPacking(num *big.Int) *FConPackingConfig {
+	return c.FConPackingOrDefault(num, DefaultFConPacking)
+}
+
+// FConPackingOrDefault returns the packing window of the final chain activated at the block num,
+// or defaultPacking if no configured window is activated yet.
+func (c *ChainConfig) FConPackingOrDefault(num *big.Int, defaultPacking *FConPackingConfig) *FConPackingConfig {
+	packing := defaultPacking
+	if c.FConsensus == nil {
+		return packing
+	}
+	for _, window := range c.FConsensus.Packing {
+		if isForked(window.Block, num) && (packing == defaultPacking || window.Block.Cmp(packing.Block) >= 0) {
+			packing = window
+		}
+	}
+	return packing
+}
+
+// CheckPacking checks that the packing windows of the final chain are valid.
+func (c *ChainConfig) CheckPacking() error {
+	if c.FConsensus == nil {
+		return nil
+	}
+	for i, window := range c.FConsensus.Packing {
+		if window == nil || window.Block == nil {
+			return fmt.Errorf("missing activation block of the packing window %d", i)
+		}
+		if window.Size == 0 {
+			return fmt.Errorf("zero size of the packing window at block %v", window.Block)
+		}
+	}
+	return nil
+}
+
 // The returned GasTable's fields shouldn't, under any circumstances, be changed.
 func (c *ChainConfig) GasTable(num *big.Int) GasTable {
 	return GasTableOmaha
@@ -264,9 +376,57 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, head *big.Int) *Confi
 	if isForkIncompatible(c.EWASMBlock, newcfg.EWASMBlock, head) {
 		return newCompatError("ewasm fork block", c.EWASMBlock, newcfg.EWASMBlock)
 	}
+	if isForkIncompatible(c.TypedTxBlock, newcfg.TypedTxBlock, head) {
+		return newCompatError("TypedTx fork block", c.TypedTxBlock, newcfg.TypedTxBlock)
+	}
+	if isForkIncompatible(c.EnterpriseBlock, newcfg.EnterpriseBlock, head) {
+		return newCompatError("Enterprise fork block", c.EnterpriseBlock, newcfg.EnterpriseBlock)
+	}
+	if stored, changed := packingIncompatible(c.packingWindows(), newcfg.packingWindows(), head); stored != nil || changed != nil {
+		return newCompatError("final chain packing window", stored, changed)
+	}
 	return nil
 }
 
+// packingWindows returns the packing windows of the final chain configured by c
+func (c *ChainConfig) packingWindows() []*FConPackingConfig {
+	if c.FConsensus == nil {
+		return nil
+	}
+	return c.FConsensus.Packing
+}
+
+// packingIncompatible returns the activation blocks of a window of the stored windows s1 and of the new windows s2
+// which are activated at the head and have been changed, added or removed, nil if the windows are compatible.
+func packingIncompatible(s1, s2 []*FConPackingConfig, head *big.Int) (*big.Int, *big.Int) {
+	find := func(windows []*FConPackingConfig, block *big.Int) *FConPackingConfig {
+		for _, window := range windows {
+			if configNumEqual(window.Block, block) {
+				return window
+			}
+		}
+		return nil
+	}
+	for _, window := range s1 {
+		if !isForked(window.Block, head) {
+			continue
+		}
+		other := find(s2, window.Block)
+		if other == nil {
+			return window.Block, nil
+		}
+		if !other.equal(window) {
+			return window.Block, other.Block
+		}
+	}
+	for _, window := range s2 {
+		if isForked(window.Block, head) && find(s1, window.Block) == nil {
+			return nil, window.Block
+		}
+	}
+	return nil, nil
+}
+
 // isForkIncompatible returns true if a fork scheduled at s1 cannot be rescheduled to
 // block s2 because head is already past the fork.
 func isForkIncompatible(s1, s2, head *big.Int) bool {
@@ -328,8 +488,10 @@ func (err *ConfigCompatError) Error() string {
 // Rules is a one time interface meaning that it shouldn't be used in between transition
 // phases.
 type Rules struct {
-	ChainID     *big.Int
-	IsVierville bool
+	ChainID      *big.Int
+	IsVierville  bool
+	IsTypedTx    bool
+	IsEnterprise bool
 }
 
 // Rules ensures c's ChainID is not nil.
@@ -339,7 +501,9 @@ func (c *ChainConfig) Rules(num *big.Int) Rules {
 		chainID = new(big.Int)
 	}
 	return Rules{
-		ChainID:     new(big.Int).Set(chainID),
-		IsVierville: c.IsVierville(num),
+		ChainID:      new(big.Int).Set(chainID),
+		IsVierville:  c.IsVierville(num),
+		IsTypedTx:    c.IsTypedTx(num),
+		IsEnterprise: c.IsEnterprise(num),
 	}
 }
@@ -48,6 +48,56 @@ func TestCheckCompatible(t *testing.T) {
 				RewindTo:     9,
 			},
 		},
+		{
+			stored: &ChainConfig{TypedTxBlock: big.NewInt(10)},
+			new:    &ChainConfig{},
+			head:   15,
+			wantErr: &ConfigCompatError{
+				What:         "TypedTx fork block",
+				StoredConfig: big.NewInt(10),
+				NewConfig:    nil,
+				RewindTo:     9,
+			},
+		},
+		{
+			stored: &ChainConfig{EnterpriseBlock: big.NewInt(10)},
+			new:    &ChainConfig{},
+			head:   15,
+			wantErr: &ConfigCompatError{
+				What:         "Enterprise fork block",
+				StoredConfig: big.NewInt(10),
+				NewConfig:    nil,
+				RewindTo:     9,
+			},
+		},
+		{
+			stored:  &ChainConfig{FConsensus: &FConConfig{Packing: []*FConPackingConfig{{Block: big.NewInt(10), Size: 2, Distance: 2}}}},
+			new:     &ChainConfig{FConsensus: &FConConfig{Packing: []*FConPackingConfig{{Block: big.NewInt(10), Size: 4, Distance: 2}}}},
+			head:    9,
+			wantErr: nil,
+		},
+		{
+			stored: &ChainConfig{FConsensus: &FConConfig{Packing: []*FConPackingConfig{{Block: big.NewInt(10), Size: 2, Distance: 2}}}},
+			new:    &ChainConfig{FConsensus: &FConConfig{Packing: []*FConPackingConfig{{Block: big.NewInt(10), Size: 4, Distance: 2}}}},
+			head:   15,
+			wantErr: &ConfigCompatError{
+				What:         "final chain packing window",
+				StoredConfig: big.NewInt(10),
+				NewConfig:    big.NewInt(10),
+				RewindTo:     9,
+			},
+		},
+		{
+			stored: &ChainConfig{FConsensus: &FConConfig{}},
+			new:    &ChainConfig{FConsensus: &FConConfig{Packing: []*FConPackingConfig{{Block: big.NewInt(10), Size: 4, Distance: 2}}}},
+			head:   15,
+			wantErr: &ConfigCompatError{
+				What:         "final chain packing window",
+				StoredConfig: nil,
+				NewConfig:    big.NewInt(10),
+				RewindTo:     9,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -57,3 +107,21 @@ func TestCheckCompatible(t *testing.T) {
 		}
 	}
 }
+
+func TestCheckPacking(t *testing.T) {
+	tests := []struct {
+		packing []*FConPackingConfig
+		wantErr bool
+	}{
+		{packing: nil, wantErr: false},
+		{packing: []*FConPackingConfig{{Block: big.NewInt(0), Size: 2, Distance: 2}}, wantErr: false},
+		{packing: []*FConPackingConfig{{Block: big.NewInt(0), Size: 0, Distance: 2}}, wantErr: true},
+		{packing: []*FConPackingConfig{{Size: 2, Distance: 2}}, wantErr: true},
+	}
+	for i, test := range tests {
+		config := &ChainConfig{FConsensus: &FConConfig{Packing: test.packing}}
+		if err := config.CheckPacking(); (err != nil) != test.wantErr {
+			t.Errorf("test %d: error mismatch: have %v, want error %v", i, err, test.wantErr)
+		}
+	}
+}
@@ -88,6 +88,9 @@ const (
 
 	// TODO: change this to chainConfig
 	MaxProvider = 16 // Maximum of provider size for an enterprise contract
+	MaxOwner    = 16 // Maximum of owner size for an enterprise contract
+
+	ProviderGasEpochLength uint64 = 17280 // Number of blocks of an epoch of the gas budget of an enterprise contract provider
 )
 
 var (
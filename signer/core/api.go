@@ -57,7 +57,8 @@ type ExternalAPI interface {
 	// SignTransaction request to sign the specified transaction
 	SignTransaction(ctx context.Context, args SendTxArgs, methodSelector *string) (*evrapi.SignTransactionResult, error)
 	// ProviderSignTransaction request to sign the specified transaction from provider
-	ProviderSignTransaction(ctx context.Context, tx *types.Transaction, providerAddr common.Address, methodSelector *string) (*evrapi.SignTransactionResult, error)
+	// The optional scope bounds the provider signature to a validity window and a maximum fee
+	ProviderSignTransaction(ctx context.Context, tx *types.Transaction, providerAddr common.Address, methodSelector *string, scope *types.ProviderScope) (*evrapi.SignTransactionResult, error)
 	// SignData - request to sign the given data (plus prefix)
 	SignData(ctx context.Context, contentType string, addr common.MixedcaseAddress, data interface{}) (hexutil.Bytes, error)
 	// SignTypedData - request to sign the given structured data (plus prefix)
@@ -564,13 +565,18 @@ func (api *SignerAPI) SignTransaction(ctx context.Context, args SendTxArgs, meth
 
 }
 
-// ProviderSignTransaction signs the given Transaction and returns it both as json and rlp-encoded form
-func (api *SignerAPI) ProviderSignTransaction(ctx context.Context, tx *types.Transaction, providerAddr common.Address, methodSelector *string) (*evrapi.SignTransactionResult, error) {
+// ProviderSignTransaction signs the given Transaction and returns it both as json and rlp-encoded form.
+// If a scope is given, it replaces the scope of the transaction so that the provider signature is only valid
+// until its block and for a fee up to its maximum fee.
+func (api *SignerAPI) ProviderSignTransaction(ctx context.Context, tx *types.Transaction, providerAddr common.Address, methodSelector *string, scope *types.ProviderScope) (*evrapi.SignTransactionResult, error) {
 	var (
 		err    error
 		acc    accounts.Account
 		wallet accounts.Wallet
 	)
+	if scope != nil {
+		tx = tx.WithProviderScope(scope.ValidUntil, scope.MaxFee)
+	}
 	acc = accounts.Account{Address: providerAddr}
 	wallet, err = api.am.Find(acc)
 	if err != nil {
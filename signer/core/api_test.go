@@ -357,7 +357,7 @@ func TestProviderSignTx(t *testing.T) {
 	control.approveCh <- "Y"
 	control.inputCh <- "a_long_password"
 	providerAddr := list[0]
-	res2, err = api.ProviderSignTransaction(context.Background(), parsedTx, providerAddr, &methodSig)
+	res2, err = api.ProviderSignTransaction(context.Background(), parsedTx, providerAddr, &methodSig, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -373,4 +373,22 @@ func TestProviderSignTx(t *testing.T) {
 	if pv == nil || pr == nil || ps == nil {
 		t.Errorf("Expected pv,pr,ps not null")
 	}
+
+	// The provider bounds its signature to a validity window and a maximum fee
+	control.approveCh <- "Y"
+	control.inputCh <- "a_long_password"
+	scope := &types.ProviderScope{ValidUntil: 100, MaxFee: big.NewInt(1000000)}
+	res2, err = api.ProviderSignTransaction(context.Background(), parsedTx, providerAddr, &methodSig, scope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsedTx3 := &types.Transaction{}
+	rlp.Decode(bytes.NewReader(res2.Raw), parsedTx3)
+	if parsedTx3.ProviderValidUntil() != scope.ValidUntil || parsedTx3.ProviderMaxFee().Cmp(scope.MaxFee) != 0 {
+		t.Errorf("Expected scope %d %v, got %d %v", scope.ValidUntil, scope.MaxFee, parsedTx3.ProviderValidUntil(), parsedTx3.ProviderMaxFee())
+	}
+	provider, err := types.Provider(types.NewOmahaSigner(big.NewInt(1337)), parsedTx3)
+	if err != nil || provider == nil || *provider != providerAddr {
+		t.Errorf("Expected provider %x, got %v (%v)", providerAddr, provider, err)
+	}
 }
@@ -19,6 +19,7 @@ package core
 import (
 	"context"
 	"encoding/hex"
+	"fmt"
 
 	"github.com/Evrynetlabs/evrynet-node/common"
 	"github.com/Evrynetlabs/evrynet-node/common/hexutil"
@@ -63,17 +64,22 @@ func (l *AuditLogger) SignTransaction(ctx context.Context, args SendTxArgs, meth
 }
 
 // ProviderSignTransaction auditLogger function
-func (l *AuditLogger) ProviderSignTransaction(ctx context.Context, tx *types.Transaction, providerAddr common.Address, methodSelector *string) (*evrapi.SignTransactionResult, error) {
+func (l *AuditLogger) ProviderSignTransaction(ctx context.Context, tx *types.Transaction, providerAddr common.Address, methodSelector *string, scope *types.ProviderScope) (*evrapi.SignTransactionResult, error) {
 	sel := "<nil>"
 	if methodSelector != nil {
 		sel = *methodSelector
 	}
+	sc := "<nil>"
+	if scope != nil {
+		sc = fmt.Sprintf("validUntil=%d maxFee=%v", scope.ValidUntil, scope.MaxFee)
+	}
 	l.log.Info("ProviderSignTransaction", "type", "request", "metadata", MetadataFromContext(ctx).String(),
 		"tx", hex.EncodeToString(tx.Data()),
 		"providerAddr", providerAddr.String(),
-		"methodSelector", sel)
+		"methodSelector", sel,
+		"scope", sc)
 
-	res, e := l.api.ProviderSignTransaction(ctx, tx, providerAddr, methodSelector)
+	res, e := l.api.ProviderSignTransaction(ctx, tx, providerAddr, methodSelector, scope)
 	if res != nil {
 		l.log.Info("ProviderSignTransaction", "type", "response", "data", common.Bytes2Hex(res.Raw), "error", e)
 	} else {